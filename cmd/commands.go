@@ -0,0 +1,288 @@
+package main
+
+import (
+	"flag"    // コマンドライン引数の解析
+	"fmt"     // 文字列フォーマット
+	"log"     // ログ出力
+	"os"      // ファイル操作
+	"strconv" // 文字列と数値の変換
+	"strings" // 項目名の連結
+	"time"    // 期間の解析
+
+	"book-manager/internal/backup"     // バックアップの作成・復元
+	"book-manager/internal/database"   // データベースの種類の判定
+	"book-manager/internal/model"      // 保管庫の同期の設定
+	"book-manager/internal/repository" // データの保存・取得機能
+	"book-manager/internal/usecase"    // ビジネスロジック
+)
+
+// runCommand はサブコマンドを実行する関数
+// 例：book-manager backup、book-manager restore books-20240101-030000.000.db.gz
+func runCommand(name string, args []string, dbPath string) error {
+	switch name {
+	case "backup":
+		return runBackup(args, dbPath)
+	case "restore":
+		return runRestore(args, dbPath)
+	case "export":
+		return runExport(args, dbPath)
+	case "import":
+		return runImport(args, dbPath)
+	case "vault":
+		return runVault(args, dbPath)
+	default:
+		return fmt.Errorf("不明なコマンドです: %s（使用可能: serve, backup, restore, export, import, vault）", name)
+	}
+}
+
+// backupConfig は環境変数からバックアップ設定を読み込む関数
+// BACKUP_DIR：保存先、BACKUP_COMPRESS：gzip圧縮、BACKUP_KEEP：残す世代数、BACKUP_MAX_AGE：保存期間（例：720h）
+func backupConfig() (backup.Config, error) {
+	config := backup.Config{
+		Dir:      getEnv("BACKUP_DIR", defaultBackupDir),
+		Compress: getEnv("BACKUP_COMPRESS", "false") == "true",
+	}
+
+	keep, err := strconv.Atoi(getEnv("BACKUP_KEEP", strconv.Itoa(defaultBackupKeep)))
+	if err != nil {
+		return config, fmt.Errorf("BACKUP_KEEPの値が不正です: %w", err)
+	}
+	config.Keep = keep
+
+	if maxAge := getEnv("BACKUP_MAX_AGE", ""); maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil {
+			return config, fmt.Errorf("BACKUP_MAX_AGEの値が不正です: %w", err)
+		}
+		config.MaxAge = d
+	}
+	return config, nil
+}
+
+// runBackup はバックアップを1つ作成するコマンド
+// 使い方：book-manager backup [-dir ./backups] [-compress] [-keep 7]
+func runBackup(args []string, dbPath string) error {
+	config, err := backupConfig()
+	if err != nil {
+		return err
+	}
+
+	// 環境変数の設定をデフォルト値として、コマンドライン引数で上書きできるようにする
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	fs.StringVar(&config.Dir, "dir", config.Dir, "バックアップの保存先ディレクトリ")
+	fs.BoolVar(&config.Compress, "compress", config.Compress, "gzipで圧縮する")
+	fs.IntVar(&config.Keep, "keep", config.Keep, "残す世代数（0で無制限）")
+	fs.DurationVar(&config.MaxAge, "max-age", config.MaxAge, "保存期間（例：720h、0で無期限）")
+	fs.Parse(args)
+
+	db, err := openDB(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	info, err := backup.NewManager(db, config).Create()
+	if err != nil {
+		return err
+	}
+	log.Printf("バックアップを作成しました: %s（%dバイト）", info.Name, info.Size)
+	return nil
+}
+
+// runRestore はバックアップからデータベースを復元するコマンド
+// 使い方：book-manager restore [-check] <バックアップファイル>
+// 復元前に整合性チェックとスキーマバージョンの確認を行う。サーバーを停止してから実行すること
+func runRestore(args []string, dbPath string) error {
+	config, err := backupConfig()
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	fs.StringVar(&config.Dir, "dir", config.Dir, "バックアップの保存先ディレクトリ（ファイル名だけを指定した場合の検索先）")
+	checkOnly := fs.Bool("check", false, "検証だけ行い、復元はしない")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("復元するバックアップファイルを1つ指定してください")
+	}
+	path := backup.NewManager(nil, config).ResolvePath(fs.Arg(0))
+
+	if *checkOnly {
+		// 圧縮ファイルの検証は展開が必要なため、一時ディレクトリへの復元で確認する
+		if err := backup.Verify(path); err != nil {
+			return err
+		}
+		log.Printf("バックアップは正常です: %s", path)
+		return nil
+	}
+
+	// 復元はファイルの置き換えで行うため、SQLiteのデータベースファイルにだけ使える
+	if database.DetectDialect(dbPath) != database.SQLite {
+		return fmt.Errorf("復元はSQLite専用です（PostgreSQLは pg_restore を使ってください）")
+	}
+	if err := backup.Restore(path, dbPath); err != nil {
+		return err
+	}
+	log.Printf("データベースを復元しました: %s → %s", path, dbPath)
+	return nil
+}
+
+// openArchiveUsecase はデータベースを開いてマイグレーションし、アーカイブ用のユースケースを作る
+// 空のインスタンスへの取り込みでもテーブルが存在するように、先にマイグレーションを行う
+func openArchiveUsecase(dbPath string) (usecase.ArchiveUsecase, func() error, error) {
+	db, err := openDB(dbPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := db.Migrate(); err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return usecase.NewArchiveUsecase(repository.NewBookRepository(db), repository.NewArchiveRepository(db)), db.Close, nil
+}
+
+// runExport は移行用アーカイブを書き出すコマンド
+// 使い方：book-manager export -o archive.zip
+func runExport(args []string, dbPath string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "book-manager-archive.zip", "出力するアーカイブファイル")
+	fs.Parse(args)
+
+	archiveUsecase, closeDB, err := openArchiveUsecase(dbPath)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+	}
+	defer f.Close()
+
+	manifest, err := archiveUsecase.Export(f)
+	if err != nil {
+		return err
+	}
+	log.Printf("アーカイブを書き出しました: %s（書籍 %d件）", *output, manifest.Files["books.json"].Count)
+	return f.Close()
+}
+
+// runImport は移行用アーカイブを取り込むコマンド
+// 使い方：book-manager import [-policy skip|overwrite|duplicate] archive.zip
+func runImport(args []string, dbPath string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	policy := fs.String("policy", "skip", "同じIDの書籍がある場合の扱い（skip, overwrite, duplicate）")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("取り込むアーカイブファイルを1つ指定してください")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("アーカイブファイルを開けません: %w", err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("アーカイブファイルの情報を取得できません: %w", err)
+	}
+
+	archiveUsecase, closeDB, err := openArchiveUsecase(dbPath)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	result, err := archiveUsecase.Import(f, stat.Size(), *policy)
+	if err != nil {
+		return err
+	}
+	log.Printf("アーカイブを取り込みました: 取り込み %d件、置き換え %d件、別IDで追加 %d件、スキップ %d件",
+		result.Imported, result.Overwrote, result.Duplicated, result.Skipped)
+	if r := result.Related; r != nil {
+		log.Printf("関連データを取り込みました: 場所 %d件、貸し出し %d件、欲しい本 %d件（価格 %d件）、読書目標 %d件、ハイライト %d件、レビュー %d件（編集履歴 %d件）、予算 %d件、為替レート %d件、変更履歴 %d件",
+			r.Locations, r.Loans, r.WishlistItems, r.PriceRecords, r.Goals, r.Highlights, r.Reviews, r.ReviewRevisions, r.Budgets, r.ExchangeRates, r.AuditLogs)
+	}
+	return nil
+}
+
+// runVault は Obsidian などの保管庫（Markdownのノートのフォルダ）と書籍を同期するコマンド
+// 使い方：book-manager vault export|sync [-dir ./vault] [-user alice] [-dry-run] [-prefer file|db]
+// export は書籍をノートとして書き出し、sync はノートで編集したフロントマターを書籍に反映する
+func runVault(args []string, dbPath string) error {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "sync") {
+		return fmt.Errorf("vault の後に export か sync を指定してください")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("vault "+action, flag.ExitOnError)
+	dir := fs.String("dir", getEnv("VAULT_DIR", ""), "保管庫のフォルダ（環境変数 VAULT_DIR でも指定できる）")
+	username := fs.String("user", "", "対象の本棚のユーザー名（省略すると共有の本棚）")
+	dryRun := fs.Bool("dry-run", false, "書籍もノートも変更せず、結果だけを表示する")
+	prefer := fs.String("prefer", "", "競合の解決方法（file：ノートの内容で書籍を更新、db：書籍の内容でノートを書き直す）")
+	fs.Parse(args[1:])
+
+	db, err := openDB(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		return err
+	}
+
+	userID := 0
+	if *username != "" {
+		user, err := repository.NewUserRepository(db).GetByUsername(*username)
+		if err != nil {
+			return err
+		}
+		userID = user.ID
+	}
+
+	// 書籍の更新は変更履歴にも記録する（通貨は変えないため、為替レートは使わない）
+	bookRepo := repository.NewBookRepository(db)
+	bookUsecase := usecase.NewBookUsecase(bookRepo, nil, repository.NewAuditRepository(db), nil, nil)
+	vaultUsecase := usecase.NewVaultUsecase(bookRepo, repository.NewHighlightRepository(db), bookUsecase)
+
+	opts := model.VaultOptions{DryRun: *dryRun, Prefer: model.VaultPrefer(*prefer)}
+	var result *model.VaultResult
+	if action == "export" {
+		result, err = vaultUsecase.Export(userID, *dir, opts)
+	} else {
+		result, err = vaultUsecase.Sync(userID, *dir, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 変更のあったノートと、確認が必要なノートだけを1行ずつ表示する
+	for _, note := range result.Notes {
+		if note.State == model.VaultUnchanged {
+			continue
+		}
+		line := fmt.Sprintf("%-9s %s", note.State, note.Path)
+		if len(note.Fields) > 0 {
+			line += fmt.Sprintf("（%s）", strings.Join(note.Fields, ", "))
+		}
+		if note.Error != "" {
+			line += ": " + note.Error
+		}
+		log.Println(line)
+	}
+	prefix := ""
+	if result.DryRun {
+		prefix = "[dry-run] "
+	}
+	c := result.Counts
+	log.Printf("%s保管庫 %s: 書き出し %d件、書き直し %d件、書籍に反映 %d件、変更なし %d件、未反映の編集 %d件、競合 %d件、書籍なし %d件、エラー %d件",
+		prefix, result.Dir, c[model.VaultCreated], c[model.VaultWritten]+c[model.VaultRefreshed], c[model.VaultApplied],
+		c[model.VaultUnchanged], c[model.VaultPending], c[model.VaultConflict], c[model.VaultMissing], c[model.VaultFailed])
+	if c[model.VaultConflict] > 0 {
+		log.Printf("競合したノートは -prefer file（ノートを優先）か -prefer db（書籍を優先）を付けて実行し直してください")
+	}
+	return nil
+}
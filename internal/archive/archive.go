@@ -0,0 +1,434 @@
+// archiveパッケージ：インスタンス間でデータを移行するためのアーカイブを扱うファイル
+// アーカイブ：データの種類ごとのJSONファイルをまとめたZIPファイル
+// 書籍のIDや作成日時・更新日時を含めて全項目を失わずに書き出し・取り込みできる
+// 書籍の関連データ（貸し出し・ハイライト・レビュー）と、場所・欲しい本・読書目標・予算・為替レート・変更履歴も一緒に移す
+// データの読み書きはリポジトリのインターフェースだけを使うため、保存先の種類に依存しない
+package archive
+
+import (
+	"archive/zip"   // ZIPファイルの読み書き
+	"crypto/sha256" // ファイル内容のチェックサム
+	"encoding/hex"  // チェックサムの16進数表記
+	"encoding/json" // JSONの読み書き
+	"fmt"           // エラーメッセージの作成
+	"io"            // 読み書きの抽象化
+	"reflect"       // ファイルごとのレコード数の数え上げ
+	"sort"          // 書籍のID順の並び替え
+	"time"          // 書き出し日時
+
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 自作のデータアクセス層
+)
+
+// アーカイブ形式の定数
+const (
+	FormatName    = "book-manager-archive" // アーカイブ形式の名前（manifest.jsonで確認する）
+	FormatVersion = 3                      // アーカイブ形式のバージョン（形式を変えたら増やす。v2：関連データを追加、v3：予算・為替レート・変更履歴を追加）
+
+	manifestFile        = "manifest.json"         // アーカイブの目次
+	booksFile           = "books.json"            // 書籍データ
+	locationsFile       = "locations.json"        // 保管場所
+	loansFile           = "loans.json"            // 貸し出しの記録
+	wishlistFile        = "wishlist_items.json"   // 欲しい本
+	pricesFile          = "price_records.json"    // 欲しい本の価格の履歴
+	goalsFile           = "reading_goals.json"    // 読書目標
+	highlightsFile      = "highlights.json"       // ハイライト・引用
+	reviewsFile         = "reviews.json"          // レビュー
+	reviewRevisionsFile = "review_revisions.json" // レビューの編集履歴
+	budgetsFile         = "budgets.json"          // 予算
+	exchangeRatesFile   = "exchange_rates.json"   // 為替レート
+	auditLogsFile       = "audit_logs.json"       // 変更履歴
+)
+
+// Store は書き出し・取り込みの対象
+type Store struct {
+	Books   repository.BookRepository    // 書籍（本棚を限定したリポジトリならその本棚だけを扱う）
+	Related repository.ArchiveRepository // 書籍の関連データ・場所・欲しい本・読書目標・予算・為替レート・変更履歴（nilなら書籍だけを扱う）
+	OwnerID int                          // Related で扱う本棚（repository.AllOwners ならすべての本棚）
+}
+
+// ConflictPolicy は取り込み時に同じIDの書籍が既にある場合の扱い
+type ConflictPolicy string
+
+// 競合時の扱いの定数定義
+const (
+	PolicySkip      ConflictPolicy = "skip"      // 既存の書籍を残し、アーカイブの書籍は取り込まない
+	PolicyOverwrite ConflictPolicy = "overwrite" // アーカイブの内容で既存の書籍を置き換える
+	PolicyDuplicate ConflictPolicy = "duplicate" // 新しいIDを採番して別の書籍として取り込む
+)
+
+// ParsePolicy は文字列を競合時の扱いに変換する関数（空文字はskip）
+func ParsePolicy(s string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(s); p {
+	case "":
+		return PolicySkip, nil
+	case PolicySkip, PolicyOverwrite, PolicyDuplicate:
+		return p, nil
+	default:
+		return "", fmt.Errorf("競合時の扱いが無効です: %s（skip, overwrite, duplicate のいずれか）", s)
+	}
+}
+
+// FileEntry はアーカイブ内の1ファイルの情報（件数とチェックサム）
+type FileEntry struct {
+	Count  int    `json:"count"`  // 含まれるレコード数
+	SHA256 string `json:"sha256"` // 内容のSHA-256（取り込み時に破損を検出する）
+}
+
+// Manifest はアーカイブの目次（manifest.json）
+type Manifest struct {
+	Format     string               `json:"format"`      // アーカイブ形式の名前
+	Version    int                  `json:"version"`     // アーカイブ形式のバージョン
+	ExportedAt time.Time            `json:"exported_at"` // 書き出し日時
+	Files      map[string]FileEntry `json:"files"`       // 含まれるJSONファイル（v1のアーカイブは books.json だけ）
+}
+
+// ImportResult は取り込み結果
+type ImportResult struct {
+	Imported   int                    `json:"imported"`          // 同じIDで取り込んだ件数
+	Overwrote  int                    `json:"overwrote"`         // 既存の書籍を置き換えた件数
+	Duplicated int                    `json:"duplicated"`        // 新しいIDで取り込んだ件数
+	Skipped    int                    `json:"skipped"`           // 取り込まなかった件数
+	IDMap      map[int]int            `json:"id_map"`            // アーカイブのID → 取り込み後のID（関連データの付け替えに使う）
+	Related    *model.ArchivedRecords `json:"related,omitempty"` // 取り込んだ関連データの件数（関連データを扱わない場合はなし）
+}
+
+// Export は全書籍と関連データをアーカイブ（ZIP）として書き出す関数
+func Export(store Store, w io.Writer) (*Manifest, error) {
+	// List(nil, 0, 0)：フィルターなし、件数制限なしで全書籍を取得
+	books, err := store.Books.List(nil, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	// ゴミ箱の書籍も、ゴミ箱に入った状態のまま移す
+	deleted, err := store.Books.ListDeleted(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	books = append(books, deleted...)
+	// ID順に並べる（取り込み時に同じ順番で採番されるようにするため）
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+
+	files := []archiveFile{{booksFile, &books}}
+	if store.Related != nil {
+		related, err := store.Related.Related(store.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, relatedFiles(related)...)
+	}
+
+	manifest := &Manifest{
+		Format:     FormatName,
+		Version:    FormatVersion,
+		ExportedAt: time.Now(),
+		Files:      map[string]FileEntry{},
+	}
+	contents := make([][]byte, len(files))
+	for i, f := range files {
+		data, err := json.MarshalIndent(f.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("%s のJSON変換に失敗しました: %w", f.name, err)
+		}
+		contents[i] = data
+		manifest.Files[f.name] = FileEntry{Count: count(f.data), SHA256: checksum(data)}
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("目次のJSON変換に失敗しました: %w", err)
+	}
+
+	zw := zip.NewWriter(w)
+	// 目次を先頭に置く（取り込み時に最初に形式を確認できるように）
+	if err := writeFile(zw, manifestFile, manifestJSON); err != nil {
+		return nil, err
+	}
+	for i, f := range files {
+		if err := writeFile(zw, f.name, contents[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("アーカイブの作成に失敗しました: %w", err)
+	}
+	return manifest, nil
+}
+
+// archiveFile はアーカイブに含める1つのJSONファイル
+type archiveFile struct {
+	name string      // ファイル名
+	data interface{} // 書き出す・読み込むデータ（スライスへのポインタ）
+}
+
+// relatedFiles は関連データの種類ごとのファイルを返す（書き出しと取り込みで同じ一覧を使う）
+func relatedFiles(related *model.ArchiveRelated) []archiveFile {
+	return []archiveFile{
+		{locationsFile, &related.Locations},
+		{loansFile, &related.Loans},
+		{wishlistFile, &related.WishlistItems},
+		{pricesFile, &related.PriceRecords},
+		{goalsFile, &related.Goals},
+		{highlightsFile, &related.Highlights},
+		{reviewsFile, &related.Reviews},
+		{reviewRevisionsFile, &related.ReviewRevisions},
+		{budgetsFile, &related.Budgets},
+		{exchangeRatesFile, &related.ExchangeRates},
+		{auditLogsFile, &related.AuditLogs},
+	}
+}
+
+// count はスライスへのポインタが指すスライスの要素数を返す
+func count(v interface{}) int {
+	return reflect.ValueOf(v).Elem().Len()
+}
+
+// writeFile はアーカイブにファイルを1つ追加する
+func writeFile(zw *zip.Writer, name string, data []byte) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("アーカイブへの追加に失敗しました（%s）: %w", name, err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		return fmt.Errorf("アーカイブへの書き込みに失敗しました（%s）: %w", name, err)
+	}
+	return nil
+}
+
+// Import はアーカイブ（ZIP）から書籍と関連データを取り込む関数
+// 空のインスタンスに取り込むと、すべての書籍と関連データが同じIDで再現される
+// 同じIDの書籍が既にある場合は policy に従って扱い、関連データの書籍のIDは IDMap で付け替える
+// 場所・書籍・関連データは1つのトランザクションで取り込み、途中で失敗したら何も取り込まない
+func Import(store Store, r io.ReaderAt, size int64, policy ConflictPolicy) (*ImportResult, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("アーカイブを開けません: %w", err)
+	}
+
+	manifest := &Manifest{}
+	if err := readJSON(zr, manifestFile, nil, manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Format != FormatName {
+		return nil, fmt.Errorf("書籍管理アプリのアーカイブではありません（format: %q）", manifest.Format)
+	}
+	if manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("アーカイブのバージョン（%d）がこのアプリの対応バージョン（%d）より新しいため取り込めません", manifest.Version, FormatVersion)
+	}
+
+	// 何かを書き込む前に、すべてのファイルのチェックサムと件数を確認する
+	books := []*model.Book{}
+	if _, ok := manifest.Files[booksFile]; !ok {
+		return nil, fmt.Errorf("アーカイブの目次に %s がありません", booksFile)
+	}
+	if err := readFile(zr, manifest, archiveFile{booksFile, &books}); err != nil {
+		return nil, err
+	}
+	related := &model.ArchiveRelated{}
+	for _, f := range relatedFiles(related) {
+		if err := readFile(zr, manifest, f); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *ImportResult
+	err = store.Books.Transaction(func(repo repository.BookRepository) error {
+		tx := Store{Books: repo, OwnerID: store.OwnerID}
+		if store.Related != nil {
+			tx.Related = store.Related.Within(repo)
+		}
+		result, err = importRecords(tx, books, related, policy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importRecords は読み込んだアーカイブの書籍と関連データを、store（トランザクションの中のリポジトリ）に取り込む
+func importRecords(store Store, books []*model.Book, related *model.ArchiveRelated, policy ConflictPolicy) (*ImportResult, error) {
+	result := &ImportResult{IDMap: map[int]int{}}
+
+	// 場所を先に取り込み、書籍の保管場所を取り込み後の場所のIDに付け替える
+	locationIDs := map[int]int{}
+	createdLocations := 0
+	if store.Related != nil && len(related.Locations) > 0 {
+		var err error
+		if locationIDs, createdLocations, err = store.Related.ImportLocations(store.OwnerID, related.Locations); err != nil {
+			return nil, err
+		}
+	}
+
+	// 先にすべての書籍の扱いを決めてから書き込む
+	// 新しいIDで取り込む書籍は最後に書き込み、採番したIDがアーカイブの後の書籍のIDとぶつからないようにする
+	keep, renumber := []*model.Book{}, []*model.Book{}
+	overwrite := map[int]bool{} // 既存の書籍を置き換える書籍（アーカイブのID）
+	for _, book := range books {
+		// 場所を扱わない場合や、場所がアーカイブにない場合は場所の設定を外す
+		book.LocationID = remap(locationIDs, book.LocationID)
+
+		// 同じIDの書籍が既にあるかを、ゴミ箱の書籍と他のユーザーの書籍も含めて確認する
+		// （GetByID はゴミ箱の書籍を「見つからない」として扱うため、ここでは使わない）
+		exists, owned, err := store.Books.ExistsID(book.ID)
+		if err != nil {
+			return nil, fmt.Errorf("ID %d の書籍の確認に失敗しました: %w", book.ID, err)
+		}
+		switch {
+		case !exists:
+			keep = append(keep, book)
+			result.Imported++
+		case !owned:
+			// 他のユーザーの書籍が同じIDを使っている場合は新しいIDで取り込む
+			renumber = append(renumber, book)
+			result.Duplicated++
+		case policy == PolicyOverwrite:
+			keep = append(keep, book)
+			overwrite[book.ID] = true
+			result.Overwrote++
+		case policy == PolicyDuplicate:
+			renumber = append(renumber, book)
+			result.Duplicated++
+		default:
+			// skip：既存の書籍をそのまま使う（書籍の関連データは取り込まず、欲しい本からの参照だけ既存の書籍に向ける）
+			result.Skipped++
+			result.IDMap[book.ID] = book.ID
+		}
+	}
+
+	fresh := map[int]bool{} // 関連データも取り込む書籍（アーカイブのID）
+	replaced := []int{}     // アーカイブの内容で置き換えた書籍（取り込み後のID）
+	for _, group := range []struct {
+		books  []*model.Book
+		keepID bool
+	}{{keep, true}, {renumber, false}} {
+		for _, book := range group.books {
+			imported, err := store.Books.Import(book, group.keepID)
+			if err != nil {
+				return nil, fmt.Errorf("ID %d の書籍の取り込みに失敗しました: %w", book.ID, err)
+			}
+			result.IDMap[book.ID] = imported.ID
+			fresh[book.ID] = true
+			if overwrite[book.ID] {
+				replaced = append(replaced, imported.ID)
+			}
+		}
+	}
+	if store.Related == nil {
+		return result, nil
+	}
+	imported, err := store.Related.ImportRelated(store.OwnerID, remapRelated(related, result.IDMap, fresh), replaced)
+	if err != nil {
+		return nil, err
+	}
+	imported.Locations = createdLocations
+	result.Related = imported
+	return result, nil
+}
+
+// remapRelated は関連データの書籍のIDを、IDMap で取り込み後のIDに付け替える
+// 書籍ごとのデータ（貸し出し・ハイライト・レビュー・書籍の変更履歴）は、取り込んだ書籍（fresh）のものだけを残す
+// 欲しい本から購入した書籍への参照は、IDMap にない書籍（アーカイブにない書籍）なら外す
+func remapRelated(related *model.ArchiveRelated, ids map[int]int, fresh map[int]bool) *model.ArchiveRelated {
+	remapped := &model.ArchiveRelated{
+		Locations:       related.Locations,
+		PriceRecords:    related.PriceRecords,
+		Goals:           related.Goals,
+		ReviewRevisions: related.ReviewRevisions, // レビューのIDはリポジトリで付け替える
+		Budgets:         related.Budgets,
+		ExchangeRates:   related.ExchangeRates,
+	}
+	for _, loan := range related.Loans {
+		if fresh[loan.BookID] {
+			l := *loan
+			l.BookID = ids[loan.BookID]
+			remapped.Loans = append(remapped.Loans, &l)
+		}
+	}
+	for _, highlight := range related.Highlights {
+		if fresh[highlight.BookID] {
+			h := *highlight
+			h.BookID = ids[highlight.BookID]
+			remapped.Highlights = append(remapped.Highlights, &h)
+		}
+	}
+	for _, review := range related.Reviews {
+		if fresh[review.BookID] {
+			rv := *review
+			rv.BookID = ids[review.BookID]
+			remapped.Reviews = append(remapped.Reviews, &rv)
+		}
+	}
+	for _, item := range related.WishlistItems {
+		i := *item
+		i.BookID = remap(ids, item.BookID)
+		remapped.WishlistItems = append(remapped.WishlistItems, &i)
+	}
+	for _, entry := range related.AuditLogs {
+		if entry.EntityType != model.EntityBook {
+			remapped.AuditLogs = append(remapped.AuditLogs, entry)
+			continue
+		}
+		if fresh[entry.EntityID] {
+			e := *entry
+			e.EntityID = ids[entry.EntityID]
+			remapped.AuditLogs = append(remapped.AuditLogs, &e)
+		}
+	}
+	return remapped
+}
+
+// remap はIDを対応表で付け替える（nil と対応表にないIDは nil にする）
+func remap(ids map[int]int, id *int) *int {
+	if id == nil {
+		return nil
+	}
+	newID, ok := ids[*id]
+	if !ok {
+		return nil
+	}
+	return &newID
+}
+
+// readFile はアーカイブ内のJSONファイルを読み込み、件数を目次と照らし合わせる
+// 目次にないファイル（関連データを含まない古い形式のアーカイブ）は読み込まず、空のままにする
+func readFile(zr *zip.Reader, manifest *Manifest, f archiveFile) error {
+	entry, ok := manifest.Files[f.name]
+	if !ok {
+		return nil
+	}
+	if err := readJSON(zr, f.name, &entry, f.data); err != nil {
+		return err
+	}
+	if n := count(f.data); n != entry.Count {
+		return fmt.Errorf("%s の件数が目次と一致しません（目次: %d件、実際: %d件）", f.name, entry.Count, n)
+	}
+	return nil
+}
+
+// readJSON はアーカイブ内のJSONファイルを読み込む
+// entryが指定されていればチェックサムを確認してから読み込む
+func readJSON(zr *zip.Reader, name string, entry *FileEntry, v interface{}) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("アーカイブに %s がありません: %w", name, err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("%s の読み込みに失敗しました: %w", name, err)
+	}
+	if entry != nil && entry.SHA256 != checksum(data) {
+		return fmt.Errorf("%s のチェックサムが一致しません（アーカイブが破損している可能性があります）", name)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s の解析に失敗しました: %w", name, err)
+	}
+	return nil
+}
+
+// checksum はデータのSHA-256を16進数の文字列で返す
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,388 @@
+package archive_test
+
+import (
+	"bytes"         // 書き出したアーカイブの保持
+	"fmt"           // IDの一覧の比較
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 書籍の購入日・貸し出し日時・目標の期間
+
+	"book-manager/internal/archive"    // テスト対象のアーカイブ
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍と関連データのリポジトリ
+)
+
+// openSQLite はテスト用の空のSQLiteデータベースを作成する
+func openSQLite(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return db
+}
+
+// sharedStore はすべての本棚を扱う Store を返す（CLIの export / import と同じ）
+func sharedStore(db *database.DB) archive.Store {
+	return archive.Store{Books: repository.NewBookRepository(db), Related: repository.NewArchiveRepository(db), OwnerID: repository.AllOwners}
+}
+
+// seed は書き出し元のデータ（書籍2冊と、場所・貸し出し・ハイライト・レビュー・欲しい本・読書目標・予算・為替レート・変更履歴）を作成する
+func seed(t *testing.T, db *database.DB) {
+	t.Helper()
+	locations := repository.NewLocationRepository(db)
+	home, err := locations.Create(&model.Location{Kind: model.KindBuilding, Name: "自宅"})
+	if err != nil {
+		t.Fatalf("Create location: %v", err)
+	}
+	shelf, err := locations.Create(&model.Location{ParentID: &home.ID, Kind: model.KindShelf, Name: "本棚A"})
+	if err != nil {
+		t.Fatalf("Create location: %v", err)
+	}
+
+	books := repository.NewBookRepository(db)
+	first, err := books.Create(&model.CreateBookRequest{Title: "本1", Author: "著者", PurchaseDate: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("Create book: %v", err)
+	}
+	if _, err := books.SetLocation(first.ID, &shelf.ID); err != nil {
+		t.Fatalf("SetLocation: %v", err)
+	}
+	second, err := books.Create(&model.CreateBookRequest{Title: "本2", Author: "著者", PurchaseDate: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("Create book: %v", err)
+	}
+
+	if _, err := repository.NewLoanRepository(db).Create(&model.Loan{BookID: second.ID, BorrowerName: "友人", LentAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("Create loan: %v", err)
+	}
+	if _, err := repository.NewHighlightRepository(db).Create(&model.Highlight{BookID: first.ID, Kind: model.KindHighlight, Text: "引用"}); err != nil {
+		t.Fatalf("Create highlight: %v", err)
+	}
+	reviews := repository.NewReviewRepository(db)
+	review, err := reviews.Create(&model.Review{BookID: first.ID, ReadNumber: 1, Title: "感想", Body: "最初の版", Visibility: model.VisibilityPrivate}, 0)
+	if err != nil {
+		t.Fatalf("Create review: %v", err)
+	}
+	review.Body = "二つ目の版"
+	if _, err := reviews.Update(review, 0); err != nil {
+		t.Fatalf("Update review: %v", err)
+	}
+
+	wishlist := repository.NewWishlistRepository(db)
+	item, err := wishlist.Create(&model.WishlistItem{Title: "本2", Author: "著者", Priority: 3})
+	if err != nil {
+		t.Fatalf("Create wishlist item: %v", err)
+	}
+	if err := wishlist.MarkPurchased(item.ID, second.ID, time.Now().UTC()); err != nil {
+		t.Fatalf("MarkPurchased: %v", err)
+	}
+	if _, err := wishlist.AddPrice(&model.PriceRecord{ItemID: item.ID, Price: 1200, Source: "manual", RecordedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("AddPrice: %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := repository.NewGoalRepository(db).Create(&model.ReadingGoal{Title: "2026年", Metric: model.MetricBooks, Target: 12, Period: model.PeriodYear, StartDate: start, EndDate: start.AddDate(1, 0, -1)}); err != nil {
+		t.Fatalf("Create goal: %v", err)
+	}
+	if _, err := repository.NewBudgetRepository(db).Set(0, model.BudgetMonthly, 5000); err != nil {
+		t.Fatalf("Set budget: %v", err)
+	}
+	if err := repository.NewExchangeRateRepository(db).Set(&model.ExchangeRate{Currency: "USD", Base: "JPY", Date: start, Rate: 150}); err != nil {
+		t.Fatalf("Set exchange rate: %v", err)
+	}
+	if err := repository.NewAuditRepository(db).Record(&model.AuditLog{EntityType: model.EntityBook, EntityID: first.ID, Action: model.ActionCreate}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+}
+
+// export は db のすべての本棚をアーカイブに書き出す
+func export(t *testing.T, db *database.DB) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := archive.Export(sharedStore(db), &buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+// counts は書籍と関連データの件数を「書籍 場所 貸し出し ハイライト レビュー 編集履歴 欲しい本 価格 目標 予算 為替レート 変更履歴」の順に返す
+func counts(t *testing.T, db *database.DB) [12]int {
+	t.Helper()
+	books, err := repository.NewBookRepository(db).List(&model.BookFilter{}, 0, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	r, err := repository.NewArchiveRepository(db).Related(repository.AllOwners)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	return [12]int{len(books), len(r.Locations), len(r.Loans), len(r.Highlights), len(r.Reviews), len(r.ReviewRevisions),
+		len(r.WishlistItems), len(r.PriceRecords), len(r.Goals), len(r.Budgets), len(r.ExchangeRates), len(r.AuditLogs)}
+}
+
+// TestExportImport は関連データを含めて書き出し・取り込みでき、もう一度取り込んだときに policy に従って扱われることを確認する
+func TestExportImport(t *testing.T) {
+	src := openSQLite(t)
+	seed(t, src)
+	data := export(t, src)
+	want := counts(t, src)
+
+	tests := []struct {
+		policy  archive.ConflictPolicy
+		want    [12]int     // 2回目の取り込み後の件数
+		wantIDs map[int]int // 2回目の取り込みの IDMap
+	}{
+		// skip：書籍ごとの関連データは取り込まず、場所・欲しい本・目標も同じものがあるので増えない
+		{archive.PolicySkip, want, map[int]int{1: 1, 2: 2}},
+		// overwrite：置き換えた書籍の関連データはアーカイブの内容で置き換えられ、増えない
+		{archive.PolicyOverwrite, want, map[int]int{1: 1, 2: 2}},
+		// duplicate：書籍と書籍ごとの関連データ（書籍の変更履歴も含む）が増え、場所・欲しい本・目標・予算・為替レートは増えない
+		{archive.PolicyDuplicate, [12]int{4, 2, 2, 2, 2, 4, 1, 1, 1, 1, 1, 2}, map[int]int{1: 3, 2: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dst := openSQLite(t)
+			result, err := archive.Import(sharedStore(dst), data, data.Size(), archive.PolicySkip)
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			if result.Imported != 2 || result.Related == nil || result.Related.Locations != 2 || result.Related.ReviewRevisions != 2 {
+				t.Fatalf("空のデータベースへの取り込み = %+v, related %+v", result, result.Related)
+			}
+			if got := counts(t, dst); got != want {
+				t.Fatalf("空のデータベースへの取り込み後の件数 = %v, want %v", got, want)
+			}
+
+			result, err = archive.Import(sharedStore(dst), data, data.Size(), tt.policy)
+			if err != nil {
+				t.Fatalf("Import(%s): %v", tt.policy, err)
+			}
+			if got := counts(t, dst); got != tt.want {
+				t.Errorf("2回目の取り込み後の件数 = %v, want %v", got, tt.want)
+			}
+			for from, to := range tt.wantIDs {
+				if result.IDMap[from] != to {
+					t.Errorf("IDMap[%d] = %d, want %d", from, result.IDMap[from], to)
+				}
+			}
+			checkReferences(t, dst)
+		})
+	}
+}
+
+// checkReferences は取り込んだ関連データが、同じ内容の書籍と場所を指していることを確認する
+func checkReferences(t *testing.T, db *database.DB) {
+	t.Helper()
+	books, err := repository.NewBookRepository(db).List(&model.BookFilter{}, 0, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	titles := map[int]string{}
+	locations := map[int]*int{}
+	for _, book := range books {
+		titles[book.ID] = book.Title
+		locations[book.ID] = book.LocationID
+	}
+	r, err := repository.NewArchiveRepository(db).Related(repository.AllOwners)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	for _, loan := range r.Loans {
+		if titles[loan.BookID] != "本2" {
+			t.Errorf("貸し出しの書籍 = %d（%q）, want 本2", loan.BookID, titles[loan.BookID])
+		}
+	}
+	for _, h := range r.Highlights {
+		if titles[h.BookID] != "本1" {
+			t.Errorf("ハイライトの書籍 = %d（%q）, want 本1", h.BookID, titles[h.BookID])
+		}
+	}
+	for _, review := range r.Reviews {
+		if titles[review.BookID] != "本1" {
+			t.Errorf("レビューの書籍 = %d（%q）, want 本1", review.BookID, titles[review.BookID])
+		}
+	}
+	for _, item := range r.WishlistItems {
+		if item.BookID == nil || titles[*item.BookID] != "本2" {
+			t.Errorf("欲しい本から購入した書籍 = %v, want 本2", item.BookID)
+		}
+	}
+	for _, entry := range r.AuditLogs {
+		if titles[entry.EntityID] != "本1" {
+			t.Errorf("変更履歴の書籍 = %d（%q）, want 本1", entry.EntityID, titles[entry.EntityID])
+		}
+	}
+	names := map[int]string{}
+	for _, l := range r.Locations {
+		names[l.ID] = l.Name
+	}
+	for id, title := range titles {
+		if title == "本1" && (locations[id] == nil || names[*locations[id]] != "本棚A") {
+			t.Errorf("書籍 %d の場所 = %v, want 本棚A", id, locations[id])
+		}
+	}
+}
+
+// TestImportOtherOwnerID は他のユーザーの書籍と同じIDの書籍を新しいIDで取り込んでも、
+// 採番したIDがアーカイブの後の書籍のIDとぶつからないことを確認する
+func TestImportOtherOwnerID(t *testing.T) {
+	src := openSQLite(t)
+	seed(t, src)
+	data := export(t, src)
+
+	dst := openSQLite(t)
+	users := repository.NewUserRepository(dst)
+	ids := map[string]int{}
+	for _, name := range []string{"alice", "bob"} {
+		user, err := users.Register(&model.User{Username: name, PasswordHash: "x"}, func(int) error { return nil })
+		if err != nil {
+			t.Fatalf("Register(%s): %v", name, err)
+		}
+		ids[name] = user.ID
+	}
+	if _, err := repository.NewBookRepository(dst).WithOwner(ids["bob"]).Create(&model.CreateBookRequest{Title: "bobの本", Author: "著者", PurchaseDate: time.Now().UTC()}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	store := archive.Store{Books: repository.NewBookRepository(dst).WithOwner(ids["alice"]), Related: repository.NewArchiveRepository(dst), OwnerID: ids["alice"]}
+	result, err := archive.Import(store, data, data.Size(), archive.PolicySkip)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Imported != 1 || result.Duplicated != 1 || result.Skipped != 0 {
+		t.Fatalf("Import = %+v, want 同じIDで1件・新しいIDで1件", result)
+	}
+	if result.IDMap[2] != 2 || result.IDMap[1] == 1 || result.IDMap[1] == 2 {
+		t.Errorf("IDMap = %v, want 2→2 と、1→1・2以外のID", result.IDMap)
+	}
+	for from, want := range map[int]string{1: "本1", 2: "本2"} {
+		book, err := store.Books.GetByID(result.IDMap[from])
+		if err != nil {
+			t.Fatalf("GetByID(%d): %v", result.IDMap[from], err)
+		}
+		if book.Title != want {
+			t.Errorf("IDMap[%d] の書籍 = %q, want %q", from, book.Title, want)
+		}
+	}
+	if result.Related.Highlights != 1 || result.Related.Loans != 1 {
+		t.Errorf("取り込んだ関連データ = %+v, want ハイライト1件・貸し出し1件", result.Related)
+	}
+}
+
+// ids は関連データのIDを種類ごとに返す
+func ids(t *testing.T, db *database.DB) map[string][]int {
+	t.Helper()
+	r, err := repository.NewArchiveRepository(db).Related(repository.AllOwners)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	got := map[string][]int{}
+	for _, l := range r.Locations {
+		got["locations"] = append(got["locations"], l.ID)
+	}
+	for _, l := range r.Loans {
+		got["loans"] = append(got["loans"], l.ID)
+	}
+	for _, h := range r.Highlights {
+		got["highlights"] = append(got["highlights"], h.ID)
+	}
+	for _, rv := range r.Reviews {
+		got["reviews"] = append(got["reviews"], rv.ID)
+	}
+	for _, v := range r.ReviewRevisions {
+		got["review_revisions"] = append(got["review_revisions"], v.ID)
+	}
+	for _, w := range r.WishlistItems {
+		got["wishlist_items"] = append(got["wishlist_items"], w.ID)
+	}
+	for _, p := range r.PriceRecords {
+		got["price_records"] = append(got["price_records"], p.ID)
+	}
+	for _, g := range r.Goals {
+		got["reading_goals"] = append(got["reading_goals"], g.ID)
+	}
+	for _, b := range r.Budgets {
+		got["budgets"] = append(got["budgets"], b.ID)
+	}
+	for _, e := range r.ExchangeRates {
+		got["exchange_rates"] = append(got["exchange_rates"], e.ID)
+	}
+	for _, a := range r.AuditLogs {
+		got["audit_logs"] = append(got["audit_logs"], a.ID)
+	}
+	return got
+}
+
+// TestImportKeepsIDs は空のインスタンスに取り込むと関連データも同じIDで再現され、
+// その後に作成したデータのIDが取り込んだIDとぶつからないことを確認する
+func TestImportKeepsIDs(t *testing.T) {
+	src := openSQLite(t)
+	seed(t, src)
+	// 削除して欠番を作り、取り込み先で1から採番し直すと別のIDになるようにする
+	highlights := repository.NewHighlightRepository(src)
+	if _, err := highlights.Create(&model.Highlight{BookID: 1, Kind: model.KindQuote, Text: "メモ"}); err != nil {
+		t.Fatalf("Create highlight: %v", err)
+	}
+	if err := highlights.Delete(1); err != nil {
+		t.Fatalf("Delete highlight: %v", err)
+	}
+	data := export(t, src)
+
+	dst := openSQLite(t)
+	if _, err := archive.Import(sharedStore(dst), data, data.Size(), archive.PolicySkip); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	want, got := ids(t, src), ids(t, dst)
+	for table, wantIDs := range want {
+		if fmt.Sprint(got[table]) != fmt.Sprint(wantIDs) {
+			t.Errorf("%s のID = %v, want %v", table, got[table], wantIDs)
+		}
+	}
+
+	created, err := repository.NewHighlightRepository(dst).Create(&model.Highlight{BookID: 1, Kind: model.KindHighlight, Text: "新しい引用"})
+	if err != nil {
+		t.Fatalf("取り込み後のハイライトの作成: %v", err)
+	}
+	if created.ID <= want["highlights"][len(want["highlights"])-1] {
+		t.Errorf("取り込み後に作成したハイライトのID = %d, want %v より大きいID", created.ID, want["highlights"])
+	}
+}
+
+// TestImportRollback は途中で取り込めないデータがあると、それまでに取り込んだ場所や書籍も含めて何も取り込まないことを確認する
+func TestImportRollback(t *testing.T) {
+	src := openSQLite(t)
+	// 書籍を作る前に登録する（最初のユーザーは所有者のない書籍を引き継ぐため）
+	alice, err := repository.NewUserRepository(src).Register(&model.User{Username: "alice", PasswordHash: "x"}, func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	seed(t, src)
+	if _, err := repository.NewWishlistRepository(src).Create(&model.WishlistItem{OwnerID: alice.ID, Title: "aliceの欲しい本", Author: "著者", Priority: 1}); err != nil {
+		t.Fatalf("Create wishlist item: %v", err)
+	}
+	data := export(t, src)
+
+	// 取り込み先に alice がいないため、書籍を取り込んだ後の欲しい本の取り込みで失敗する
+	dst := openSQLite(t)
+	if _, err := archive.Import(sharedStore(dst), data, data.Size(), archive.PolicySkip); err == nil {
+		t.Fatal("所有者のいない欲しい本を含むアーカイブを取り込めました")
+	}
+	if got := counts(t, dst); got != [12]int{} {
+		t.Errorf("失敗した取り込みの後の件数 = %v, want すべて0", got)
+	}
+
+	if _, err := repository.NewUserRepository(dst).Register(&model.User{Username: "alice", PasswordHash: "x"}, func(int) error { return nil }); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := archive.Import(sharedStore(dst), data, data.Size(), archive.PolicySkip); err != nil {
+		t.Fatalf("ユーザーを作成してからの取り込み: %v", err)
+	}
+	if got, want := counts(t, dst), counts(t, src); got != want {
+		t.Errorf("取り込み後の件数 = %v, want %v", got, want)
+	}
+}
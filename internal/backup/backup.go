@@ -0,0 +1,359 @@
+// backupパッケージ：SQLiteデータベースのバックアップと復元を担当するファイル
+// バックアップの作成・圧縮・世代管理（古いものの削除）・定期実行・復元をまとめて扱う
+package backup
+
+import (
+	"compress/gzip" // バックアップの圧縮
+	"context"       // 定期実行の停止
+	"fmt"           // エラーメッセージの作成
+	"io"            // ファイルのコピー
+	"log"           // 定期実行時のログ出力
+	"os"            // ファイル操作
+	"path/filepath" // ファイルパスの操作
+	"sort"          // バックアップ一覧の並び替え
+	"strings"       // 文字列操作
+	"sync"          // 同時実行の防止
+	"time"          // 日時・期間
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+)
+
+// バックアップファイル名の規則：books-20240101-030000.123.db（圧縮時は .db.gz）
+// 同じ秒に手動と定期のバックアップが重なってもファイル名がぶつからないように、ミリ秒まで含める
+const (
+	filePrefix     = "books-"
+	fileTimeFormat = "20060102-150405.000"
+	fileExt        = ".db"
+	gzipExt        = ".gz"
+)
+
+// legacyTimeFormat はミリ秒を含めていなかった頃のバックアップファイル名の日時の形式（一覧と世代管理で引き続き扱う）
+const legacyTimeFormat = "20060102-150405"
+
+// Config はバックアップの設定
+type Config struct {
+	Dir      string        // バックアップの保存先ディレクトリ
+	Compress bool          // gzipで圧縮するか
+	Keep     int           // 残す世代数（0以下なら世代数では削除しない）
+	MaxAge   time.Duration // 保存期間（0なら期間では削除しない）
+}
+
+// Info はバックアップファイル1つ分の情報
+type Info struct {
+	Name       string    `json:"name"`       // ファイル名
+	Size       int64     `json:"size"`       // ファイルサイズ（バイト）
+	Compressed bool      `json:"compressed"` // gzip圧縮されているか
+	CreatedAt  time.Time `json:"created_at"` // 作成日時（ファイル名から取得）
+}
+
+// Manager はバックアップの作成と世代管理を行う構造体
+type Manager struct {
+	db     *database.DB // バックアップ元のデータベース
+	config Config       // バックアップ設定
+	mu     sync.Mutex   // 手動実行と定期実行が同時に走らないようにするロック
+}
+
+// NewManager は新しいManagerを作成する関数
+func NewManager(db *database.DB, config Config) *Manager {
+	return &Manager{db: db, config: config}
+}
+
+// Create はバックアップを1つ作成し、保存期間・世代数を超えた古いバックアップを削除する
+func (m *Manager) Create() (*Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("バックアップ先ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	// 同じミリ秒のバックアップ（別のプロセスで作ったものなど）があれば、ファイル名の日時を1ミリ秒ずつずらす
+	now := time.Now()
+	name, path := "", ""
+	for {
+		name = filePrefix + now.Format(fileTimeFormat) + fileExt
+		path = filepath.Join(m.config.Dir, name)
+		if !exists(path) && !exists(path+gzipExt) {
+			break
+		}
+		now = now.Add(time.Millisecond)
+	}
+
+	// VACUUM INTOで一貫したスナップショットを作成
+	if err := m.db.BackupTo(path); err != nil {
+		return nil, err
+	}
+
+	// 圧縮する場合は .gz ファイルを作り、元のファイルを削除する
+	if m.config.Compress {
+		if err := compressFile(path, path+gzipExt); err != nil {
+			os.Remove(path)
+			return nil, err
+		}
+		os.Remove(path)
+		name += gzipExt
+		path += gzipExt
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("バックアップファイルの確認に失敗しました: %w", err)
+	}
+
+	if _, err := m.prune(); err != nil {
+		return nil, err
+	}
+
+	return &Info{Name: name, Size: stat.Size(), Compressed: m.config.Compress, CreatedAt: now}, nil
+}
+
+// List はバックアップの一覧を新しい順に返す
+func (m *Manager) List() ([]Info, error) {
+	return list(m.config.Dir)
+}
+
+// Prune は保存期間・世代数の設定に従って古いバックアップを削除し、削除したファイル名を返す
+func (m *Manager) Prune() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.prune()
+}
+
+// prune はロックを取得済みの状態で古いバックアップを削除する
+// 最新の1つは設定に関係なく必ず残す
+func (m *Manager) prune() ([]string, error) {
+	backups, err := list(m.config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for i, b := range backups {
+		if i == 0 {
+			continue // 最新のバックアップは必ず残す
+		}
+		tooMany := m.config.Keep > 0 && i >= m.config.Keep
+		tooOld := m.config.MaxAge > 0 && time.Since(b.CreatedAt) > m.config.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.config.Dir, b.Name)); err != nil {
+			return removed, fmt.Errorf("古いバックアップの削除に失敗しました: %w", err)
+		}
+		removed = append(removed, b.Name)
+	}
+	return removed, nil
+}
+
+// Run は指定した間隔でバックアップを作成し続ける（ctxがキャンセルされるまで）
+// サーバーと並行して動かすため、ゴルーチンで呼び出す
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := m.Create()
+			if err != nil {
+				log.Printf("定期バックアップに失敗しました: %v", err)
+				continue
+			}
+			log.Printf("定期バックアップを作成しました: %s", info.Name)
+		}
+	}
+}
+
+// ResolvePath はバックアップの指定（パスまたはファイル名）を実際のファイルパスに変換する
+// ファイル名だけが指定された場合はバックアップ先ディレクトリから探す
+func (m *Manager) ResolvePath(name string) string {
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	return filepath.Join(m.config.Dir, filepath.Base(name))
+}
+
+// Restore はバックアップファイルをデータベースファイルとして復元する
+// 手順：
+//  1. 圧縮されていれば一時ファイルに展開する
+//  2. 整合性チェックとスキーマバージョンの確認を行う（問題があれば何も変更しない）
+//  3. 現在のデータベースを「.pre-restore-日時」という名前で退避する
+//  4. 一時ファイルをデータベースファイルの位置に移動する
+//
+// サーバーがデータベースを開いている間は実行しないこと（サーバーを停止してから実行する）
+func Restore(backupPath, dbPath string) error {
+	// 一時ファイルはデータベースと同じディレクトリに作る（最後のリネームを同じファイルシステム内で行うため）
+	tmp, err := os.CreateTemp(filepath.Dir(dbPath), ".restore-*.db")
+	if err != nil {
+		return fmt.Errorf("一時ファイルの作成に失敗しました: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath) // 成功時はリネーム済みなので何もしない
+
+	if err := extract(backupPath, tmpPath); err != nil {
+		return err
+	}
+
+	if _, err := database.VerifyBackup(tmpPath); err != nil {
+		return fmt.Errorf("バックアップの検証に失敗したため復元を中止しました: %w", err)
+	}
+
+	// 現在のデータベースを退避（存在しない場合は新規復元としてそのまま進める）
+	if _, err := os.Stat(dbPath); err == nil {
+		saved := dbPath + ".pre-restore-" + time.Now().Format(fileTimeFormat)
+		if err := os.Rename(dbPath, saved); err != nil {
+			return fmt.Errorf("現在のデータベースの退避に失敗しました: %w", err)
+		}
+	}
+	// WALモードの作業ファイルが残っていると古いデータが混ざるため削除する
+	os.Remove(dbPath + "-wal")
+	os.Remove(dbPath + "-shm")
+
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		return fmt.Errorf("データベースファイルの置き換えに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Verify はバックアップファイルを復元に使えるかを確認する（データベースは変更しない）
+// 圧縮されている場合は一時ディレクトリに展開してから確認する
+func Verify(backupPath string) error {
+	tmpDir, err := os.MkdirTemp("", "book-manager-verify-")
+	if err != nil {
+		return fmt.Errorf("一時ディレクトリの作成に失敗しました: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPath := filepath.Join(tmpDir, "verify.db")
+	if err := extract(backupPath, tmpPath); err != nil {
+		return err
+	}
+
+	_, err = database.VerifyBackup(tmpPath)
+	return err
+}
+
+// list はディレクトリ内のバックアップファイルを新しい順に返す
+func list(dir string) ([]Info, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Info{}, nil // ディレクトリがなければバックアップは0件
+		}
+		return nil, fmt.Errorf("バックアップ一覧の取得に失敗しました: %w", err)
+	}
+
+	backups := []Info{}
+	for _, entry := range entries {
+		name := entry.Name()
+		compressed := strings.HasSuffix(name, fileExt+gzipExt)
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !(compressed || strings.HasSuffix(name, fileExt)) {
+			continue
+		}
+		// ファイル名から作成日時を取り出す（規則に合わないファイルは無視）
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), gzipExt), fileExt)
+		createdAt, err := time.ParseInLocation(fileTimeFormat, stamp, time.Local)
+		if err != nil {
+			if createdAt, err = time.ParseInLocation(legacyTimeFormat, stamp, time.Local); err != nil {
+				continue
+			}
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Info{Name: name, Size: info.Size(), Compressed: compressed, CreatedAt: createdAt})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// exists はファイルが存在するかどうかを返す
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// extract はバックアップファイルを作業用のパスに展開する（圧縮されていなければコピーする）
+func extract(backupPath, dst string) error {
+	if strings.HasSuffix(backupPath, gzipExt) {
+		return decompressFile(backupPath, dst)
+	}
+	return copyFile(backupPath, dst)
+}
+
+// compressFile はファイルをgzip形式で圧縮する
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("バックアップファイルを開けません: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("圧縮ファイルの作成に失敗しました: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		return fmt.Errorf("バックアップの圧縮に失敗しました: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("バックアップの圧縮に失敗しました: %w", err)
+	}
+	return out.Close()
+}
+
+// decompressFile はgzip形式のファイルを展開する
+func decompressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("バックアップファイルを開けません: %w", err)
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("バックアップファイルの展開に失敗しました: %w", err)
+	}
+	defer gz.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("展開先ファイルの作成に失敗しました: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, gz); err != nil {
+		return fmt.Errorf("バックアップファイルの展開に失敗しました: %w", err)
+	}
+	return out.Close()
+}
+
+// copyFile はファイルをコピーする
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("バックアップファイルを開けません: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("コピー先ファイルの作成に失敗しました: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("バックアップファイルのコピーに失敗しました: %w", err)
+	}
+	return out.Close()
+}
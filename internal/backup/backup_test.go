@@ -0,0 +1,102 @@
+package backup
+
+import (
+	"os"            // テスト用のファイルの作成
+	"path/filepath" // ファイルパスの操作
+	"testing"       // テストの実行と結果の報告
+	"time"          // 保存期間
+
+	"book-manager/internal/database" // バックアップ元のデータベース
+)
+
+// newTestManager はマイグレーション済みのSQLiteデータベースと、一時ディレクトリに保存するManagerを作る
+func newTestManager(t *testing.T, config Config) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := database.NewDB(filepath.Join(dir, "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	config.Dir = filepath.Join(dir, "backups")
+	return NewManager(db, config)
+}
+
+// TestCreateSameSecond は続けてバックアップを作成しても、ファイル名がぶつからないことを確認する
+func TestCreateSameSecond(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		m := newTestManager(t, Config{Compress: compress})
+		names := map[string]bool{}
+		for i := 0; i < 5; i++ {
+			info, err := m.Create()
+			if err != nil {
+				t.Fatalf("Create（%d回目, 圧縮 %v）: %v", i+1, compress, err)
+			}
+			if names[info.Name] {
+				t.Fatalf("ファイル名が重複しています: %s", info.Name)
+			}
+			names[info.Name] = true
+		}
+		backups, err := m.List()
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(backups) != 5 {
+			t.Errorf("バックアップの数 = %d, want 5（圧縮 %v）", len(backups), compress)
+		}
+		if err := Verify(filepath.Join(m.config.Dir, backups[0].Name)); err != nil {
+			t.Errorf("作成したバックアップを検証できません: %v", err)
+		}
+	}
+}
+
+// TestListAndPrune はファイル名の日時（ミリ秒なしの古い形式も含む）で新しい順に並べ、世代数を超えたものを削除することを確認する
+func TestListAndPrune(t *testing.T) {
+	m := newTestManager(t, Config{Keep: 2})
+	if err := os.MkdirAll(m.config.Dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := []string{
+		"books-20240101-030000.db",        // ミリ秒なしの古い形式
+		"books-20240102-030000.500.db.gz", // 圧縮
+		"books-20240103-030000.000.db",
+		"books-latest.db", // 規則に合わないファイルは無視する
+		"notes.txt",
+	}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(m.config.Dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []string{"books-20240103-030000.000.db", "books-20240102-030000.500.db.gz", "books-20240101-030000.db"}
+	if len(backups) != len(want) {
+		t.Fatalf("List = %+v, want %v", backups, want)
+	}
+	for i, b := range backups {
+		if b.Name != want[i] {
+			t.Errorf("List[%d] = %s, want %s", i, b.Name, want[i])
+		}
+	}
+	if !backups[1].Compressed || backups[0].Compressed {
+		t.Errorf("圧縮の判定が違います: %+v", backups)
+	}
+	if got := backups[1].CreatedAt; !got.Equal(time.Date(2024, 1, 2, 3, 0, 0, 500*int(time.Millisecond), time.Local)) {
+		t.Errorf("CreatedAt = %v", got)
+	}
+
+	removed, err := m.Prune()
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "books-20240101-030000.db" {
+		t.Errorf("Prune = %v, want [books-20240101-030000.db]", removed)
+	}
+}
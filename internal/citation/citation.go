@@ -0,0 +1,174 @@
+// citationパッケージ：書籍情報を文献リスト用の形式に変換するファイル
+// 論文や社内資料で技術書を引用するときに使う書誌データ（BibTeX、RIS、CSL-JSON）と
+// 整形済みの参考文献表記（APA、SIST 02）を作成する
+package citation
+
+import (
+	"fmt"     // 文字列フォーマット
+	"sort"    // 書式名の並び替え
+	"strings" // 文字列操作
+	"unicode" // 文字種（漢字・かななど）の判定
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// Format は引用形式を表す型
+type Format string
+
+// 対応している引用形式の定数定義
+const (
+	FormatBibTeX  Format = "bibtex"   // BibTeX（LaTeX向け）
+	FormatRIS     Format = "ris"      // RIS（EndNote、Zoteroなど向け）
+	FormatCSLJSON Format = "csl-json" // CSL-JSON（Citation Style Language）
+	FormatAPA     Format = "apa"      // APA第7版の参考文献表記
+	FormatSIST02  Format = "sist02"   // SIST 02（科学技術情報流通技術基準）の参考文献表記
+)
+
+// formatInfo は各形式のレスポンス用情報（Content-Typeと拡張子）
+var formatInfo = map[Format]struct {
+	contentType string
+	extension   string
+}{
+	FormatBibTeX:  {"application/x-bibtex; charset=utf-8", "bib"},
+	FormatRIS:     {"application/x-research-info-systems; charset=utf-8", "ris"},
+	FormatCSLJSON: {"application/vnd.citationstyles.csl+json; charset=utf-8", "json"},
+	FormatAPA:     {"text/plain; charset=utf-8", "txt"},
+	FormatSIST02:  {"text/plain; charset=utf-8", "txt"},
+}
+
+// ParseFormat は文字列を引用形式に変換する関数
+// 空文字の場合はBibTeXを返す
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatBibTeX, nil
+	}
+	f := Format(strings.ToLower(s))
+	if _, ok := formatInfo[f]; !ok {
+		return "", fmt.Errorf("未対応の引用形式です: %s（対応形式: %s）", s, strings.Join(Formats(), ", "))
+	}
+	return f, nil
+}
+
+// Formats は対応している引用形式の一覧を返す関数
+func Formats() []string {
+	names := make([]string, 0, len(formatInfo))
+	for f := range formatInfo {
+		names = append(names, string(f))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ContentType は形式に対応するHTTPのContent-Typeを返す
+func (f Format) ContentType() string {
+	return formatInfo[f].contentType
+}
+
+// Extension は形式に対応するファイル拡張子を返す
+func (f Format) Extension() string {
+	return formatInfo[f].extension
+}
+
+// Render は複数の書籍を指定した形式の文字列に変換する関数
+// CSL-JSONは1つの配列、それ以外は1件ずつ空行で区切って出力する
+func Render(format Format, books []*model.Book) (string, error) {
+	switch format {
+	case FormatCSLJSON:
+		return CSLJSON(books)
+	case FormatBibTeX, FormatRIS, FormatAPA, FormatSIST02:
+		entries := make([]string, 0, len(books))
+		for _, book := range books {
+			entries = append(entries, renderOne(format, book))
+		}
+		return strings.Join(entries, "\n"), nil
+	default:
+		return "", fmt.Errorf("未対応の引用形式です: %s", format)
+	}
+}
+
+// renderOne は1冊分をテキスト系の形式に変換する
+func renderOne(format Format, book *model.Book) string {
+	switch format {
+	case FormatBibTeX:
+		return BibTeX(book)
+	case FormatRIS:
+		return RIS(book)
+	case FormatAPA:
+		return APA(book) + "\n"
+	default:
+		return SIST02(book) + "\n"
+	}
+}
+
+// Key は書籍の引用キーを作成する関数
+// 形式：著者名（ASCII部分）+ 出版年 + "-" + 書籍ID（例：martin2008-12）
+// IDを含めることで、同じ著者・同じ年の本があっても重複せず、何度出力しても同じキーになる
+func Key(book *model.Book) string {
+	prefix := "book"
+	names := splitAuthors(book.Author)
+	if len(names) > 0 {
+		family, _ := splitName(names[0])
+		// キーに使える英小文字・数字だけを残す（日本語名は"book"にフォールバック）
+		var b strings.Builder
+		for _, r := range strings.ToLower(family) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() > 0 {
+			prefix = b.String()
+		}
+	}
+	return fmt.Sprintf("%s%s-%d", prefix, year(book), book.ID)
+}
+
+// year は出版年を文字列で返す（出版日がない場合は"nd" = no date）
+func year(book *model.Book) string {
+	if book.PublishedDate == nil {
+		return "nd"
+	}
+	return fmt.Sprintf("%d", book.PublishedDate.Year())
+}
+
+// splitAuthors は著者欄を個々の著者名に分割する関数
+// 区切り文字：半角・全角カンマ、読点（、）、セミコロン、" and "
+// 中黒（・）はカタカナ表記の外国人名（ロバート・C・マーチン）で使われるため区切りにしない
+func splitAuthors(author string) []string {
+	normalized := strings.ReplaceAll(author, " and ", ",")
+	fields := strings.FieldsFunc(normalized, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、' || r == ';' || r == '；'
+	})
+	names := []string{}
+	for _, f := range fields {
+		if name := strings.TrimSpace(f); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// isCJKName は名前に漢字・ひらがな・カタカナが含まれるかを判定する
+// 日本語名は「姓 名」の順で書かれ、欧文名とは姓名の扱いが異なるため区別する
+func isCJKName(name string) bool {
+	for _, r := range name {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitName は著者名を姓（family）と名（given）に分ける関数
+// 日本語名：「山田 太郎」→ 姓=山田、名=太郎（空白がなければ全体を姓として扱う）
+// 欧文名：「Robert C. Martin」→ 姓=Martin、名=Robert C.
+func splitName(name string) (family, given string) {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "　", " "))
+	parts := strings.Fields(name)
+	if len(parts) <= 1 {
+		return name, ""
+	}
+	if isCJKName(name) {
+		return parts[0], strings.Join(parts[1:], " ")
+	}
+	return parts[len(parts)-1], strings.Join(parts[:len(parts)-1], " ")
+}
@@ -0,0 +1,199 @@
+package citation
+
+import (
+	"testing" // テストの実行と結果の報告
+	"time"    // 出版日
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// testBook はテスト用の書籍を作成する（published が空なら出版日なし）
+func testBook(id int, title, author, publisher, isbn, published string) *model.Book {
+	book := &model.Book{ID: id, Title: title, Author: author, Publisher: publisher, ISBN: isbn}
+	if published != "" {
+		d, _ := time.Parse("2006-01-02", published)
+		book.PublishedDate = &d
+	}
+	return book
+}
+
+var (
+	cleanCode = testBook(12, "Clean Code", "Robert C. Martin", "Prentice Hall", "9780132350884", "2008-08-01")
+	goBook    = testBook(3, "Go言語プログラミング", "山田 太郎、佐藤 花子", "技術出版社", "978-4-123-45678-9", "2023-04-10")
+	noDate    = testBook(5, "無題の本", "", "", "", "")
+)
+
+// TestSplitName は著者名を姓と名に分けられることを確認する
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantFamily string
+		wantGiven  string
+	}{
+		{"Robert C. Martin", "Martin", "Robert C."},
+		{"山田 太郎", "山田", "太郎"},
+		{"山田　太郎", "山田", "太郎"}, // 全角空白
+		{"山田太郎", "山田太郎", ""},
+		{"ロバート・C・マーチン", "ロバート・C・マーチン", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			family, given := splitName(tt.name)
+			if family != tt.wantFamily || given != tt.wantGiven {
+				t.Errorf("splitName(%q) = (%q, %q), want (%q, %q)", tt.name, family, given, tt.wantFamily, tt.wantGiven)
+			}
+		})
+	}
+}
+
+// TestSplitAuthors は著者欄を区切り文字で分割し、中黒では分割しないことを確認する
+func TestSplitAuthors(t *testing.T) {
+	tests := []struct {
+		author string
+		want   []string
+	}{
+		{"", []string{}},
+		{"Kent Beck and Martin Fowler", []string{"Kent Beck", "Martin Fowler"}},
+		{"山田 太郎、佐藤 花子", []string{"山田 太郎", "佐藤 花子"}},
+		{"A，B；C; D", []string{"A", "B", "C", "D"}},
+		{"ロバート・C・マーチン", []string{"ロバート・C・マーチン"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.author, func(t *testing.T) {
+			got := splitAuthors(tt.author)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitAuthors(%q) = %q, want %q", tt.author, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("splitAuthors(%q) = %q, want %q", tt.author, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestKey は引用キーが著者名のASCII部分・出版年・IDから作られることを確認する
+func TestKey(t *testing.T) {
+	tests := []struct {
+		book *model.Book
+		want string
+	}{
+		{cleanCode, "martin2008-12"},
+		{goBook, "book2023-3"}, // 日本語名は book にする
+		{noDate, "booknd-5"},   // 出版日がなければ年は nd
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Key(tt.book); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.book.Title, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRender は各形式の出力を確認する
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		book   *model.Book
+		want   string
+	}{
+		{"BibTeX", FormatBibTeX, cleanCode, "@book{martin2008-12,\n" +
+			"  author = {Martin, Robert C.},\n" +
+			"  title = {{Clean Code}},\n" +
+			"  publisher = {Prentice Hall},\n" +
+			"  year = {2008},\n" +
+			"  month = aug,\n" +
+			"  isbn = {9780132350884},\n" +
+			"}\n"},
+		{"BibTeXのエスケープ", FormatBibTeX, testBook(1, "C# & Go_100%", "山田太郎", "", "", ""), "@book{booknd-1,\n" +
+			"  author = {{山田太郎}},\n" +
+			"  title = {{C\\# \\& Go\\_100\\%}},\n" +
+			"}\n"},
+		{"RIS", FormatRIS, goBook, "TY  - BOOK\n" +
+			"ID  - book2023-3\n" +
+			"AU  - 山田, 太郎\n" +
+			"AU  - 佐藤, 花子\n" +
+			"TI  - Go言語プログラミング\n" +
+			"PB  - 技術出版社\n" +
+			"PY  - 2023\n" +
+			"DA  - 2023/04/10/\n" +
+			"SN  - 978-4-123-45678-9\n" +
+			"ER  - \n"},
+		{"RISは改行を取り除く", FormatRIS, testBook(2, "一行目\n二行目", "", "", "", ""), "TY  - BOOK\n" +
+			"ID  - booknd-2\n" +
+			"TI  - 一行目 二行目\n" +
+			"ER  - \n"},
+		{"APA", FormatAPA, cleanCode, "Martin, R. C. (2008). Clean Code. Prentice Hall.\n"},
+		{"APAの複数の著者", FormatAPA, testBook(4, "Refactoring", "Kent Beck, Martin Fowler, John Brant", "", "", "1999-07-08"), "Beck, K., Fowler, M., & Brant, J. (1999). Refactoring.\n"},
+		{"APAの和文の著者", FormatAPA, goBook, "山田 太郎・佐藤 花子 (2023). Go言語プログラミング. 技術出版社.\n"},
+		{"APAの著者なし", FormatAPA, noDate, "無題の本. (n.d.).\n"},
+		{"SIST 02", FormatSIST02, goBook, "山田太郎; 佐藤花子. Go言語プログラミング. 技術出版社, 2023, ISBN978-4-123-45678-9.\n"},
+		{"SIST 02の欧文名", FormatSIST02, cleanCode, "Martin, Robert C. Clean Code. Prentice Hall, 2008, ISBN9780132350884.\n"},
+		{"CSL-JSON", FormatCSLJSON, goBook, `[
+  {
+    "id": "book2023-3",
+    "type": "book",
+    "title": "Go言語プログラミング",
+    "author": [
+      {
+        "family": "山田",
+        "given": "太郎"
+      },
+      {
+        "family": "佐藤",
+        "given": "花子"
+      }
+    ],
+    "publisher": "技術出版社",
+    "issued": {
+      "date-parts": [
+        [
+          2023,
+          4,
+          10
+        ]
+      ]
+    },
+    "ISBN": "978-4-123-45678-9",
+    "language": "ja"
+  }
+]
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.format, []*model.Book{tt.book})
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render(%s) =\n%s\nwant\n%s", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseFormat は形式名の解釈と、未対応の形式がエラーになることを確認する
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatBibTeX, false},
+		{"RIS", FormatRIS, false},
+		{"csl-json", FormatCSLJSON, false},
+		{"mla", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseFormat(tt.s)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseFormat(%q) = (%q, %v), want %q (エラー: %v)", tt.s, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
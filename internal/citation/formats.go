@@ -0,0 +1,271 @@
+package citation
+
+import (
+	"encoding/json" // CSL-JSONの出力
+	"fmt"           // 文字列フォーマット
+	"strings"       // 文字列操作
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// bibtexEscaper はBibTeXで特別な意味を持つ文字をエスケープする置換器
+// 例：「C# & Go」→「C\# \& Go」
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// bibtexName は著者名をBibTeXの「姓, 名」形式に変換する関数
+// 日本語名は空白で区切られていなければ {山田太郎} のように波括弧で囲み、
+// BibTeXが名前を勝手に分割・大文字小文字変換しないようにする
+func bibtexName(name string) string {
+	family, given := splitName(name)
+	family = bibtexEscaper.Replace(family)
+	given = bibtexEscaper.Replace(given)
+	if given == "" {
+		return "{" + family + "}"
+	}
+	return family + ", " + given
+}
+
+// BibTeX は書籍を1件のBibTeXエントリ（@book）に変換する関数
+func BibTeX(book *model.Book) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@book{%s,\n", Key(book))
+
+	// 複数の著者は" and "で結合する（BibTeXの決まり）
+	names := []string{}
+	for _, name := range splitAuthors(book.Author) {
+		names = append(names, bibtexName(name))
+	}
+	fields := [][2]string{
+		{"author", strings.Join(names, " and ")},
+		// タイトルは二重の波括弧で囲み、スタイルによる大文字小文字の変換を防ぐ
+		{"title", "{" + bibtexEscaper.Replace(book.Title) + "}"},
+		{"publisher", bibtexEscaper.Replace(book.Publisher)},
+	}
+	if book.PublishedDate != nil {
+		fields = append(fields,
+			[2]string{"year", year(book)},
+			[2]string{"month", strings.ToLower(book.PublishedDate.Month().String()[:3])},
+		)
+	}
+	fields = append(fields, [2]string{"isbn", bibtexEscaper.Replace(book.ISBN)})
+
+	for _, f := range fields {
+		if f[1] == "" {
+			continue // 空の項目は出力しない
+		}
+		// monthは@string定義（jan, feb...）を使うので波括弧で囲まない
+		if f[0] == "month" {
+			fmt.Fprintf(&b, "  %s = %s,\n", f[0], f[1])
+			continue
+		}
+		fmt.Fprintf(&b, "  %s = {%s},\n", f[0], f[1])
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// risValue はRISの値から改行を取り除く（RISは1行1項目の形式のため）
+func risValue(s string) string {
+	return strings.Join(strings.Fields(strings.NewReplacer("\r", " ", "\n", " ").Replace(s)), " ")
+}
+
+// RIS は書籍を1件のRISレコードに変換する関数
+// RISの各行は「タグ2文字 + 空白2つ + ハイフン + 空白 + 値」の形式
+func RIS(book *model.Book) string {
+	var b strings.Builder
+	line := func(tag, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s  - %s\n", tag, risValue(value))
+		}
+	}
+
+	line("TY", "BOOK")
+	line("ID", Key(book))
+	for _, name := range splitAuthors(book.Author) {
+		family, given := splitName(name)
+		if given == "" {
+			line("AU", family)
+		} else {
+			line("AU", family+", "+given)
+		}
+	}
+	line("TI", book.Title)
+	line("PB", book.Publisher)
+	if book.PublishedDate != nil {
+		line("PY", year(book))
+		line("DA", book.PublishedDate.Format("2006/01/02/"))
+	}
+	line("SN", book.ISBN)
+	b.WriteString("ER  - \n")
+	return b.String()
+}
+
+// cslName はCSL-JSONの著者名オブジェクト
+// 姓名に分けられない名前（空白のない日本語名など）はliteralに入れる
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+// cslDate はCSL-JSONの日付オブジェクト（[[年, 月, 日]]の形式）
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// cslItem はCSL-JSONの1件分の書誌データ
+type cslItem struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Author    []cslName `json:"author,omitempty"`
+	Publisher string    `json:"publisher,omitempty"`
+	Issued    *cslDate  `json:"issued,omitempty"`
+	ISBN      string    `json:"ISBN,omitempty"`
+	Language  string    `json:"language,omitempty"`
+}
+
+// CSLJSON は書籍リストをCSL-JSON（配列）に変換する関数
+func CSLJSON(books []*model.Book) (string, error) {
+	items := make([]cslItem, 0, len(books))
+	for _, book := range books {
+		item := cslItem{
+			ID:        Key(book),
+			Type:      "book",
+			Title:     book.Title,
+			Publisher: book.Publisher,
+			ISBN:      book.ISBN,
+		}
+		for _, name := range splitAuthors(book.Author) {
+			family, given := splitName(name)
+			if given == "" {
+				item.Author = append(item.Author, cslName{Literal: family})
+			} else {
+				item.Author = append(item.Author, cslName{Family: family, Given: given})
+			}
+		}
+		if book.PublishedDate != nil {
+			d := book.PublishedDate
+			item.Issued = &cslDate{DateParts: [][]int{{d.Year(), int(d.Month()), d.Day()}}}
+		}
+		// 日本語の書籍には言語を明示して、引用スタイル側で和文の扱いができるようにする
+		if isCJKName(book.Title) || isCJKName(book.Author) {
+			item.Language = "ja"
+		}
+		items = append(items, item)
+	}
+
+	// SetEscapeHTML(false)：「&」などを\u0026に変換せず、そのまま出力する
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(items); err != nil {
+		return "", fmt.Errorf("CSL-JSONの作成に失敗しました: %w", err)
+	}
+	return b.String(), nil
+}
+
+// apaName は著者名をAPA形式（姓, 名のイニシャル）に変換する関数
+// 例：「Robert C. Martin」→「Martin, R. C.」、日本語名は省略せずそのまま使う
+func apaName(name string) string {
+	family, given := splitName(name)
+	if given == "" || isCJKName(name) {
+		return strings.TrimSpace(family + " " + given)
+	}
+	initials := []string{}
+	for _, part := range strings.Fields(given) {
+		initials = append(initials, string([]rune(part)[0])+".")
+	}
+	return family + ", " + strings.Join(initials, " ")
+}
+
+// APA は書籍をAPA第7版の参考文献表記に変換する関数
+// 例：Martin, R. C. (2008). Clean code. Prentice Hall.
+func APA(book *model.Book) string {
+	names := []string{}
+	for _, name := range splitAuthors(book.Author) {
+		names = append(names, apaName(name))
+	}
+
+	var authors string
+	switch {
+	case len(names) == 0:
+		authors = ""
+	case isCJKName(book.Author):
+		authors = strings.Join(names, "・") // 和文の著者は中黒でつなぐ
+	case len(names) == 1:
+		authors = names[0]
+	default:
+		authors = strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1]
+	}
+
+	yearText := year(book)
+	if yearText == "nd" {
+		yearText = "n.d."
+	}
+
+	parts := []string{}
+	if authors != "" {
+		parts = append(parts, fmt.Sprintf("%s (%s).", authors, yearText))
+	} else {
+		parts = append(parts, fmt.Sprintf("%s. (%s).", book.Title, yearText))
+	}
+	if authors != "" {
+		parts = append(parts, book.Title+".")
+	}
+	if book.Publisher != "" {
+		parts = append(parts, book.Publisher+".")
+	}
+	return strings.Join(parts, " ")
+}
+
+// SIST02 は書籍をSIST 02形式の参考文献表記に変換する関数
+// 形式：著者名. 書名. 出版者, 出版年, ISBN.
+// 例：山田太郎. Go言語プログラミング. 技術出版社, 2023, ISBN978-4-123-45678-9.
+func SIST02(book *model.Book) string {
+	names := []string{}
+	for _, name := range splitAuthors(book.Author) {
+		family, given := splitName(name)
+		switch {
+		case given == "":
+			names = append(names, family)
+		case isCJKName(name):
+			names = append(names, family+given) // 和名は姓名を続けて書く
+		default:
+			names = append(names, family+", "+given) // 欧文名は「姓, 名」の順
+		}
+	}
+
+	var b strings.Builder
+	if len(names) > 0 {
+		b.WriteString(strings.TrimSuffix(strings.Join(names, "; "), ".") + ". ")
+	}
+	b.WriteString(book.Title + ".")
+
+	tail := []string{}
+	if book.Publisher != "" {
+		tail = append(tail, book.Publisher)
+	}
+	if book.PublishedDate != nil {
+		tail = append(tail, year(book))
+	}
+	if book.ISBN != "" {
+		tail = append(tail, "ISBN"+book.ISBN)
+	}
+	if len(tail) > 0 {
+		b.WriteString(" " + strings.Join(tail, ", ") + ".")
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+// currencyパッケージ：通貨コード（ISO 4217）と金額の単位を扱うファイル
+// 金額はすべて通貨の最小単位の整数（円なら1円、ドルなら1セント）で保存し、浮動小数点の誤差を避ける
+package currency
+
+import (
+	"fmt"     // 金額の表示
+	"math"    // 換算結果の四捨五入
+	"strings" // 通貨コードの正規化
+)
+
+// Default は通貨を指定しなかった場合の通貨（既存の書籍の価格はすべて円）
+const Default = "JPY"
+
+// exponents は通貨の最小単位の桁数（ISO 4217 の minor unit）
+// ここにない通貨は2桁（セント単位）として扱う
+var exponents = map[string]int{
+	"JPY": 0, "KRW": 0, "VND": 0, "CLP": 0, "ISK": 0, "UGX": 0, "PYG": 0, "XAF": 0, "XOF": 0,
+	"BHD": 3, "KWD": 3, "OMR": 3, "JOD": 3, "TND": 3, "LYD": 3, "IQD": 3,
+}
+
+// symbols は表示に使う通貨記号（ここにない通貨は通貨コードで表示する）
+var symbols = map[string]string{
+	"JPY": "¥", "USD": "$", "EUR": "€", "GBP": "£",
+}
+
+// Normalize は通貨コードを大文字の3文字にそろえ、形式が正しいかを確認する関数
+// 空文字の場合は Default を返す
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return Default, nil
+	}
+	if len(code) != 3 {
+		return "", fmt.Errorf("通貨コードはISO 4217の3文字で指定してください: %s", code)
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", fmt.Errorf("通貨コードはISO 4217の3文字で指定してください: %s", code)
+		}
+	}
+	return code, nil
+}
+
+// Exponent は通貨の最小単位の桁数を返す関数（円は0、ドルは2）
+func Exponent(code string) int {
+	if e, ok := exponents[code]; ok {
+		return e
+	}
+	return 2
+}
+
+// Exponents は最小単位が2桁でない通貨の桁数の一覧を返す関数（SQLで換算するときに使う）
+func Exponents() map[string]int {
+	result := make(map[string]int, len(exponents))
+	for code, e := range exponents {
+		result[code] = e
+	}
+	return result
+}
+
+// Scale は from の最小単位の金額を to の最小単位に直すときに掛ける数を返す関数（為替レートは含まない）
+// 例：USD（セント）から JPY（円）なら 0.01
+func Scale(from, to string) float64 {
+	return math.Pow10(Exponent(to) - Exponent(from))
+}
+
+// Convert は from の最小単位の金額を、為替レート（from の1単位が to のいくつになるか）で to の最小単位に換算する関数
+func Convert(amount int, from, to string, rate float64) int {
+	if from == to {
+		return amount
+	}
+	return int(math.Round(float64(amount) * rate * Scale(from, to)))
+}
+
+// Format は最小単位の金額を表示用の文字列にする関数（例：¥1,200、$12.34、CHF 5.00）
+func Format(amount int, code string) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	exp := Exponent(code)
+	unit := int(math.Pow10(exp))
+	s := groupThousands(amount / unit)
+	if exp > 0 {
+		s += fmt.Sprintf(".%0*d", exp, amount%unit)
+	}
+	if symbol, ok := symbols[code]; ok {
+		return sign + symbol + s
+	}
+	return sign + code + " " + s
+}
+
+// groupThousands は整数を3桁区切りの文字列にする関数
+func groupThousands(v int) string {
+	s := fmt.Sprint(v)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package currency
+
+import "testing" // テストの実行と結果の報告
+
+// TestNormalize は通貨コードの正規化と、形式の確認を行うことを確認する
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"", Default, false},
+		{"usd", "USD", false},
+		{" eur ", "EUR", false},
+		{"US", "", true},
+		{"USDX", "", true},
+		{"U$D", "", true},
+		{"円円円", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := Normalize(tt.code)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Normalize(%q) = (%q, %v), want %q (エラー: %v)", tt.code, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestConvert は最小単位の桁数の違いをそろえて換算し、四捨五入することを確認する
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int
+		from, to string
+		rate     float64
+		want     int
+	}{
+		{"同じ通貨はレートを使わない", 1200, "JPY", "JPY", 2, 1200},
+		{"ドル（セント）から円", 1234, "USD", "JPY", 150, 1851},   // $12.34 × 150 = ¥1,851
+		{"円からドル（セント）", 1000, "JPY", "USD", 0.0067, 670}, // ¥1,000 × 0.0067 = $6.70
+		{"ユーロからドル", 1000, "EUR", "USD", 1.085, 1085},    // €10.00 × 1.085 = $10.85
+		{"3桁の通貨から円", 1500, "KWD", "JPY", 487.25, 731},   // 1.500 KWD × 487.25 = ¥730.875
+		{"一覧にない通貨は2桁", 500, "CHF", "JPY", 170.4, 852},   // CHF 5.00 × 170.4 = ¥852
+		{"四捨五入", 1, "USD", "JPY", 149.5, 1},             // $0.01 × 149.5 = ¥1.495
+		{"負の金額", -1234, "USD", "JPY", 150, -1851},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Convert(tt.amount, tt.from, tt.to, tt.rate); got != tt.want {
+				t.Errorf("Convert(%d, %s, %s, %v) = %d, want %d", tt.amount, tt.from, tt.to, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormat は通貨ごとの桁数と記号で金額を表示することを確認する
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount int
+		code   string
+		want   string
+	}{
+		{1200, "JPY", "¥1,200"},
+		{1234567, "JPY", "¥1,234,567"},
+		{0, "JPY", "¥0"},
+		{1234, "USD", "$12.34"},
+		{5, "EUR", "€0.05"},
+		{500, "CHF", "CHF 5.00"},
+		{1500, "KWD", "KWD 1.500"},
+		{-123456, "GBP", "-£1,234.56"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Format(tt.amount, tt.code); got != tt.want {
+				t.Errorf("Format(%d, %s) = %q, want %q", tt.amount, tt.code, got, tt.want)
+			}
+		})
+	}
+}
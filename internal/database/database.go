@@ -5,27 +5,78 @@ import (
 	"embed"
 	"fmt"
 	"path/filepath"
+	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	_ "github.com/lib/pq"           // PostgreSQLドライバー
+	_ "github.com/mattn/go-sqlite3" // SQLiteドライバー
 )
 
-//go:embed migration.sql
+//go:embed migration.sql migration_postgres.sql
 var migrationSQL embed.FS
 
+// SchemaVersion は現在のデータベーススキーマのバージョン
+// マイグレーション時に PRAGMA user_version（PostgreSQLでは schema_version テーブル）に記録し、バックアップの復元時に互換性を確認する
+// テーブル構成を変更したらこの値を1つ増やす
+const SchemaVersion = 16
+
+// addedColumns は最初のスキーマより後に追加したカラムの一覧
+// CREATE TABLE IF NOT EXISTS は既存のテーブルを変更しないため、古いデータベースにはここからカラムを追加する
+// 新しいカラムは末尾に追加し、SchemaVersion を1つ増やすこと
+var addedColumns = []struct {
+	table, column    string
+	sqlite, postgres string // カラムの型と制約（データベースごとの書き方）
+	index            string // 追加後に作成するインデックス（不要なら空）
+}{
+	// v2：書籍の所有者（NULLはユーザー登録前からある共有の本棚）
+	{"books", "owner_id", "INTEGER", "INTEGER", "CREATE INDEX IF NOT EXISTS idx_books_owner_id ON books(owner_id)"},
+	// v6：書籍の保管場所（NULLは場所が未設定）
+	{"books", "location_id", "INTEGER", "INTEGER", "CREATE INDEX IF NOT EXISTS idx_books_location_id ON books(location_id)"},
+	// v8：書籍のページ数（NULLは未登録）
+	{"books", "page_count", "INTEGER CHECK (page_count > 0)", "INTEGER CHECK (page_count > 0)", ""},
+	// v9：購入した店・購入方法・書籍の形態（空文字は未設定）
+	{"books", "store", "TEXT NOT NULL DEFAULT ''", "TEXT NOT NULL DEFAULT ''", ""},
+	{"books", "purchase_channel", "TEXT NOT NULL DEFAULT ''", "TEXT NOT NULL DEFAULT ''", ""},
+	{"books", "format", "TEXT NOT NULL DEFAULT ''", "TEXT NOT NULL DEFAULT ''", ""},
+	// v10：購入価格の通貨（それまでの価格はすべて円）
+	{"books", "currency", "TEXT NOT NULL DEFAULT 'JPY'", "TEXT NOT NULL DEFAULT 'JPY'", ""},
+	// v12：電子書籍リーダーから取り込んだハイライトの取り込み元でのキー（空文字は手入力。再取り込みで重複させないために使う）
+	{"highlights", "source_key", "TEXT NOT NULL DEFAULT ''", "TEXT NOT NULL DEFAULT ''",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_highlights_source_key ON highlights(book_id, source_key) WHERE source_key <> ''"},
+	// v14：書籍をゴミ箱に移した日時（NULLはゴミ箱に入っていない。一定期間が過ぎたら完全に削除する）
+	{"books", "deleted_at", "DATETIME", "TIMESTAMPTZ", "CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books(deleted_at)"},
+	// v15：変更履歴を記録したAPIリクエストのID（空文字はコマンドや定期処理。1つのリクエストによる変更をまとめて探すために使う）
+	{"audit_logs", "request_id", "TEXT NOT NULL DEFAULT ''", "TEXT NOT NULL DEFAULT ''",
+		"CREATE INDEX IF NOT EXISTS idx_audit_logs_request_id ON audit_logs(request_id) WHERE request_id <> ''"},
+	// v16：サーバーの管理者（バックアップ・アーカイブ・為替レートなど、全ユーザーに関わる操作ができる）
+	{"users", "is_admin", "INTEGER NOT NULL DEFAULT 0 CHECK (is_admin IN (0, 1))", "BOOLEAN NOT NULL DEFAULT FALSE", ""},
+}
+
 // DB はデータベース接続を管理する構造体
 type DB struct {
 	*sql.DB
+	Dialect Dialect // データベースの種類（SQLの書き方の切り替えに使う）
 }
 
 // NewDB は新しいデータベース接続を作成する
+// データベースの種類はDSNから判定する（postgres://... ならPostgreSQL、それ以外はSQLiteのファイルパス）
 func NewDB(dataSourceName string) (*DB, error) {
-	// SQLiteデータベースファイルのディレクトリを作成
-	dir := filepath.Dir(dataSourceName)
-	if dir != "." && dir != "" {
-		// ディレクトリが存在しない場合は作成する（実際の運用では適切な権限設定が必要）
+	return Open(DetectDialect(dataSourceName), dataSourceName)
+}
+
+// Open はデータベースの種類を指定して接続を作成する
+func Open(dialect Dialect, dataSourceName string) (*DB, error) {
+	if dialect == SQLite {
+		// SQLiteデータベースファイルのディレクトリを作成
+		dir := filepath.Dir(dataSourceName)
+		if dir != "." && dir != "" {
+			// ディレクトリが存在しない場合は作成する（実際の運用では適切な権限設定が必要）
+		}
+		// SQLiteは接続ごとに外部キーの制約が無効になっているため、DSNで有効にする
+		// これがないと REFERENCES ... ON DELETE CASCADE / SET NULL が何もせず、削除した行を参照するデータが残る
+		dataSourceName = withForeignKeys(dataSourceName)
 	}
 
-	db, err := sql.Open("sqlite3", dataSourceName)
+	db, err := sql.Open(dialect.driverName(), dataSourceName)
 	if err != nil {
 		return nil, fmt.Errorf("データベースのオープンに失敗しました: %w", err)
 	}
@@ -34,12 +85,25 @@ func NewDB(dataSourceName string) (*DB, error) {
 		return nil, fmt.Errorf("データベースへの接続に失敗しました: %w", err)
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db, Dialect: dialect}, nil
+}
+
+// withForeignKeys はSQLiteのDSNに外部キーを有効にするオプションを付ける
+// すでに ?mode=ro などのオプションがあれば & でつなぐ（DSNで指定すると、接続プールのすべての接続に効く）
+func withForeignKeys(dsn string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_foreign_keys=on"
+	}
+	return dsn + "?_foreign_keys=on"
 }
 
 // Migrate はデータベースマイグレーションを実行する
 func (db *DB) Migrate() error {
-	migrationContent, err := migrationSQL.ReadFile("migration.sql")
+	file := "migration.sql"
+	if db.Dialect == Postgres {
+		file = "migration_postgres.sql"
+	}
+	migrationContent, err := migrationSQL.ReadFile(file)
 	if err != nil {
 		return fmt.Errorf("マイグレーションファイルの読み込みに失敗しました: %w", err)
 	}
@@ -48,10 +112,86 @@ func (db *DB) Migrate() error {
 		return fmt.Errorf("マイグレーションの実行に失敗しました: %w", err)
 	}
 
+	for _, c := range addedColumns {
+		if err := db.addColumn(c.table, c.column, c.sqlite, c.postgres); err != nil {
+			return fmt.Errorf("カラム %s.%s の追加に失敗しました: %w", c.table, c.column, err)
+		}
+		if c.index != "" {
+			if _, err := db.Exec(c.index); err != nil {
+				return fmt.Errorf("インデックスの作成に失敗しました: %w", err)
+			}
+		}
+	}
+
+	// 管理者が1人もいなければ、最初に登録したユーザーを管理者にする（v16より前のデータベースのため）
+	if _, err := db.Exec("UPDATE users SET is_admin = TRUE WHERE id = (SELECT MIN(id) FROM users) AND NOT EXISTS (SELECT 1 FROM users WHERE is_admin)"); err != nil {
+		return fmt.Errorf("管理者の設定に失敗しました: %w", err)
+	}
+
+	if err := db.setSchemaVersion(SchemaVersion); err != nil {
+		return fmt.Errorf("スキーマバージョンの記録に失敗しました: %w", err)
+	}
+
 	return nil
 }
 
+// addColumn はカラムがまだなければ追加する（何度実行しても同じ結果になる）
+func (db *DB) addColumn(table, column, sqliteDef, postgresDef string) error {
+	if db.Dialect == Postgres {
+		_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, column, postgresDef))
+		return err
+	}
+
+	// SQLiteは ADD COLUMN IF NOT EXISTS に対応していないため、PRAGMA table_info で確認してから追加する
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, sqliteDef))
+	return err
+}
+
+// setSchemaVersion はスキーマバージョンを記録する
+func (db *DB) setSchemaVersion(version int) error {
+	if db.Dialect == Postgres {
+		// schema_versionテーブルは常に1行だけにする
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if _, err := tx.Exec("DELETE FROM schema_version"); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO schema_version (version) VALUES ($1)", version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	// PRAGMAはプレースホルダーを使えないため文字列で組み立てる
+	_, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version))
+	return err
+}
+
+// SchemaVersion はデータベースに記録されているスキーマバージョンを取得する
+func (db *DB) SchemaVersion() (int, error) {
+	query := "PRAGMA user_version"
+	if db.Dialect == Postgres {
+		query = "SELECT COALESCE(MAX(version), 0) FROM schema_version"
+	}
+
+	var version int
+	if err := db.QueryRow(query).Scan(&version); err != nil {
+		return 0, fmt.Errorf("スキーマバージョンの取得に失敗しました: %w", err)
+	}
+	return version, nil
+}
+
 // Close はデータベース接続を閉じる
 func (db *DB) Close() error {
 	return db.DB.Close()
-}
\ No newline at end of file
+}
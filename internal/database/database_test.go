@@ -0,0 +1,47 @@
+package database
+
+import (
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+)
+
+// TestForeignKeys はSQLiteでも外部キーの制約が有効になり、ON DELETE CASCADE が働くことを確認する
+func TestForeignKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  func(path string) string
+	}{
+		{"ファイルパス", func(path string) string { return path }},
+		{"オプション付きのURI", func(path string) string { return "file:" + path + "?cache=shared" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := NewDB(tt.dsn(filepath.Join(t.TempDir(), "books.db")))
+			if err != nil {
+				t.Fatalf("NewDB: %v", err)
+			}
+			defer db.Close()
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+
+			if _, err := db.Exec("INSERT INTO users (id, username, password_hash) VALUES (1, 'alice', 'x')"); err != nil {
+				t.Fatalf("ユーザーの登録: %v", err)
+			}
+			if _, err := db.Exec("INSERT INTO sessions (token_hash, user_id, expires_at) VALUES ('h', 1, CURRENT_TIMESTAMP)"); err != nil {
+				t.Fatalf("セッションの保存: %v", err)
+			}
+			if _, err := db.Exec("INSERT INTO sessions (token_hash, user_id, expires_at) VALUES ('x', 99, CURRENT_TIMESTAMP)"); err == nil {
+				t.Error("存在しないユーザーのセッションを保存できました")
+			}
+
+			if _, err := db.Exec("DELETE FROM users WHERE id = 1"); err != nil {
+				t.Fatalf("ユーザーの削除: %v", err)
+			}
+			var n int
+			if err := db.QueryRow("SELECT COUNT(*) FROM sessions").Scan(&n); err != nil || n != 0 {
+				t.Errorf("削除したユーザーのセッション = %d件（%v）, want 0件", n, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"strconv"      // プレースホルダー番号の文字列化
+	"strings"      // 文字列操作
+)
+
+// Dialect はデータベースの種類（SQLの方言）
+// 方言：データベースごとに少しずつ異なるSQLの書き方
+type Dialect string
+
+// 対応しているデータベースの定数定義
+const (
+	SQLite   Dialect = "sqlite"   // ファイル1つで動くデータベース（既定）
+	Postgres Dialect = "postgres" // 複数人で共有するサーバー向けのデータベース
+)
+
+// driverName は database/sql に登録されているドライバー名を返す
+func (d Dialect) driverName() string {
+	if d == Postgres {
+		return "postgres" // github.com/lib/pq
+	}
+	return "sqlite3" // github.com/mattn/go-sqlite3
+}
+
+// ParseDialect は設定値（DB_DRIVER）をDialectに変換する関数（空文字はDSNから判定）
+func ParseDialect(s string) (Dialect, error) {
+	switch strings.ToLower(s) {
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	case "postgres", "postgresql", "pgx":
+		return Postgres, nil
+	default:
+		return "", fmt.Errorf("対応していないデータベースです: %s（sqlite または postgres）", s)
+	}
+}
+
+// DetectDialect はDSN（接続先の指定）からデータベースの種類を判定する関数
+// postgres:// または postgresql:// で始まればPostgreSQL、それ以外はSQLiteのファイルパスとみなす
+func DetectDialect(dsn string) Dialect {
+	lower := strings.ToLower(dsn)
+	if strings.HasPrefix(lower, "postgres://") || strings.HasPrefix(lower, "postgresql://") {
+		return Postgres
+	}
+	return SQLite
+}
+
+// Rebind は「?」のプレースホルダーをデータベースに合わせた書き方に変換する
+// SQLite：? のまま、PostgreSQL：$1, $2, ... の番号付き
+// リポジトリのSQLは「?」で書き、実行直前にこの関数を通す
+func (db *DB) Rebind(query string) string {
+	if db.Dialect != Postgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	inString := false // '...' の文字列リテラル内の ? は置き換えない
+	for _, c := range query {
+		switch {
+		case c == '\'':
+			inString = !inString
+		case c == '?' && !inString:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Like は大文字・小文字を区別しない部分一致の演算子を返す
+// SQLiteのLIKEは英字の大文字・小文字を区別しないが、PostgreSQLのLIKEは区別するためILIKEを使う
+func (db *DB) Like() string {
+	if db.Dialect == Postgres {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// LikeEscape はLIKEのパターンで「\」をエスケープ文字にする指定（Like() の「?」の後に付ける）
+// SQLiteもPostgreSQLも、指定しないと % と _ を文字どおりに探せないため、EscapeLike と組み合わせて使う
+const LikeEscape = " ESCAPE '\\'"
+
+// EscapeLike はLIKEのパターンの中で特別な意味を持つ文字（% と _ と \）をエスケープする
+// 利用者が入力した語をそのままの文字として部分一致で探すために使う（SQLには LikeEscape を付けること）
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// likeEscaper は EscapeLike の置き換え規則
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EpochDay は日時の式を1970年1月1日からの日数（UTC）に変換するSQLの式を返す
+// 日付の差（読み終えるまでの日数など）や連続した日の判定を、データベースの種類によらず整数の計算で行うために使う
+func (db *DB) EpochDay(expr string) string {
+	if db.Dialect == Postgres {
+		return "CAST(FLOOR(EXTRACT(EPOCH FROM " + expr + ") / 86400) AS INTEGER)"
+	}
+	return "(CAST(strftime('%s', " + expr + ") AS INTEGER) / 86400)"
+}
+
+// DateLabel は日時の式を集計の単位（day・week・month・year）の文字列（UTC）に変換するSQLの式を返す
+// day：2026-01-31、week：その週の月曜日の日付、month：2026-01、year：2026
+// 文字列のまま大小を比較できる形式にしている
+func (db *DB) DateLabel(expr, unit string) (string, error) {
+	if db.Dialect == Postgres {
+		utc := "(" + expr + " AT TIME ZONE 'UTC')"
+		switch unit {
+		case "day":
+			return "to_char(" + utc + ", 'YYYY-MM-DD')", nil
+		case "week":
+			return "to_char(date_trunc('week', " + utc + "), 'YYYY-MM-DD')", nil
+		case "month":
+			return "to_char(" + utc + ", 'YYYY-MM')", nil
+		case "year":
+			return "to_char(" + utc + ", 'YYYY')", nil
+		}
+	} else {
+		switch unit {
+		case "day":
+			return "date(" + expr + ")", nil
+		case "week":
+			// weekday 0 で次の日曜日（日曜日ならその日）に進めてから6日戻すと、その週の月曜日になる
+			return "date(" + expr + ", 'weekday 0', '-6 days')", nil
+		case "month":
+			return "strftime('%Y-%m', " + expr + ")", nil
+		case "year":
+			return "strftime('%Y', " + expr + ")", nil
+		}
+	}
+	return "", fmt.Errorf("対応していない集計の単位です: %s", unit)
+}
+
+// Instr は文字列の中で部分文字列が最初に現れる位置（1始まり、なければ0）を返すSQLの式を返す
+func (db *DB) Instr(haystack, needle string) string {
+	if db.Dialect == Postgres {
+		return "strpos(" + haystack + ", " + needle + ")"
+	}
+	return "instr(" + haystack + ", " + needle + ")"
+}
+
+// Execer はSQLを実行できるもの（*sql.DB と *sql.Tx の共通部分）
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// InsertReturningID はINSERT文を実行し、自動採番されたIDを返す
+// SQLite：LastInsertId() で取得、PostgreSQL：LastInsertId() に対応していないため RETURNING id で取得
+// queryは「?」のプレースホルダーで書く（内部でRebindする）
+func (db *DB) InsertReturningID(ex Execer, query string, args ...interface{}) (int64, error) {
+	if db.Dialect == Postgres {
+		var id int64
+		if err := ex.QueryRow(db.Rebind(query)+" RETURNING id", args...).Scan(&id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	result, err := ex.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// SyncSequence はIDを指定して挿入した後に、自動採番の続きを既存の最大IDに合わせる
+// PostgreSQLのSERIALはIDを明示して挿入しても採番が進まず、次のINSERTでIDが重複してしまうため
+// SQLiteのAUTOINCREMENTは自動で追従するので何もしない
+func (db *DB) SyncSequence(ex Execer, table string) error {
+	if db.Dialect != Postgres {
+		return nil
+	}
+	// テーブル名はプレースホルダーにできないため、呼び出し側で固定の名前だけを渡すこと
+	query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), GREATEST((SELECT MAX(id) FROM %s), 1))", table, table)
+	if _, err := ex.Exec(query); err != nil {
+		return fmt.Errorf("ID採番の同期に失敗しました: %w", err)
+	}
+	return nil
+}
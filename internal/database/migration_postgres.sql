@@ -0,0 +1,264 @@
+-- 書籍管理アプリ用のPostgreSQLデータベーススキーマ
+-- migration.sql（SQLite版）と同じテーブル構成を、PostgreSQLの型と書き方で定義する
+-- 日付はSQLite版と同じく時刻まで保持するため TIMESTAMPTZ（タイムゾーン付き日時）を使う
+
+-- 書籍テーブル
+CREATE TABLE IF NOT EXISTS books (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    author TEXT NOT NULL,
+    isbn TEXT,
+    publisher TEXT,
+    published_date TIMESTAMPTZ,
+    purchase_date TIMESTAMPTZ NOT NULL,
+    purchase_price INTEGER DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'not_started' CHECK (status IN ('not_started', 'reading', 'completed', 'dropped')),
+    start_read_date TIMESTAMPTZ,
+    end_read_date TIMESTAMPTZ,
+    rating INTEGER CHECK (rating >= 1 AND rating <= 5),
+    notes TEXT,
+    tags TEXT, -- カンマ区切りのタグ
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- インデックス作成
+CREATE INDEX IF NOT EXISTS idx_books_status ON books(status);
+CREATE INDEX IF NOT EXISTS idx_books_author ON books(author);
+CREATE INDEX IF NOT EXISTS idx_books_publisher ON books(publisher);
+CREATE INDEX IF NOT EXISTS idx_books_purchase_date ON books(purchase_date);
+CREATE INDEX IF NOT EXISTS idx_books_rating ON books(rating);
+
+-- 更新日時の自動更新用トリガー
+-- PostgreSQLではBEFORE UPDATEトリガーで、保存される行（NEW）の値を書き換える
+CREATE OR REPLACE FUNCTION update_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_books_updated_at ON books;
+CREATE TRIGGER update_books_updated_at
+    BEFORE UPDATE ON books
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+-- ユーザーテーブル（書籍は owner_id でユーザーごとの本棚に分かれる）
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL, -- bcryptでハッシュ化したパスワード（平文は保存しない）
+    display_name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ログインセッションテーブル
+CREATE TABLE IF NOT EXISTS sessions (
+    token_hash TEXT PRIMARY KEY, -- セッショントークンのSHA-256（トークンそのものは保存しない）
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- 個人用アクセストークンテーブル（スクリプトなどから Authorization: Bearer で使う）
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE, -- トークンのSHA-256（トークンそのものは保存しない）
+    scopes TEXT NOT NULL, -- カンマ区切りの権限（read, write, admin）
+    expires_at TIMESTAMPTZ, -- NULLは無期限
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id);
+
+-- 本棚の共有設定テーブル（書籍はコピーせず、持ち主の書籍を共有相手が扱えるようにする）
+CREATE TABLE IF NOT EXISTS shares (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    grantee_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'editor')),
+    tag TEXT NOT NULL DEFAULT '', -- 共有する範囲のタグ（空文字は本棚全体）
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (owner_id, grantee_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shares_grantee_id ON shares(grantee_id);
+
+-- 変更履歴テーブル（誰が・いつ・何を・どう変えたか）
+-- ユーザーや書籍を削除しても履歴は残すため、外部キーは付けない
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER, -- 変更した人（NULLは未ログインまたはコマンド）
+    owner_id INTEGER, -- 変更されたデータの持ち主（NULLは共有の本棚）
+    entity_type TEXT NOT NULL,
+    entity_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    before_data TEXT, -- 変更前のデータ（JSON）
+    after_data TEXT, -- 変更後のデータ（JSON）
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_owner_id ON audit_logs(owner_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs(entity_type, entity_id);
+
+-- 貸し出しテーブル（返却後も削除せず、書籍ごとの貸し出し履歴として残す）
+CREATE TABLE IF NOT EXISTS loans (
+    id SERIAL PRIMARY KEY,
+    book_id INTEGER NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    borrower_name TEXT NOT NULL,
+    borrower_id INTEGER REFERENCES users(id) ON DELETE SET NULL, -- 借りた人がユーザーの場合
+    lent_at TIMESTAMPTZ NOT NULL,
+    due_at TIMESTAMPTZ, -- NULLは期限なし
+    returned_at TIMESTAMPTZ, -- NULLは貸し出し中
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_book_id ON loans(book_id);
+-- 1冊の書籍を同時に2人へ貸し出さないように、貸し出し中（未返却）の行は書籍ごとに1件だけにする
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_active_book_id ON loans(book_id) WHERE returned_at IS NULL;
+
+-- 保管場所テーブル（建物 > 部屋 > 本棚 > 段 のような階層構造。書籍は books.location_id で場所に置く）
+CREATE TABLE IF NOT EXISTS locations (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- NULLは共有の本棚の場所
+    parent_id INTEGER REFERENCES locations(id), -- NULLは最上位の場所
+    kind TEXT NOT NULL CHECK (kind IN ('building', 'room', 'shelf', 'position')),
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_locations_owner_id ON locations(owner_id);
+CREATE INDEX IF NOT EXISTS idx_locations_parent_id ON locations(parent_id);
+
+-- 欲しい本リスト（まだ購入していない書籍。購入すると books に書籍を作成し、book_id に記録する）
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- NULLは共有の本棚の欲しい本
+    title TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    isbn TEXT NOT NULL DEFAULT '',
+    publisher TEXT NOT NULL DEFAULT '',
+    priority INTEGER NOT NULL DEFAULT 3 CHECK (priority >= 1 AND priority <= 5), -- 1が最優先
+    target_price INTEGER, -- この価格以下なら買う（NULLは指定なし）
+    where_seen TEXT NOT NULL DEFAULT '', -- 見かけた場所（書店名、URLなど）
+    notes TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '',
+    book_id INTEGER REFERENCES books(id) ON DELETE SET NULL, -- 購入して作成した書籍
+    purchased_at TIMESTAMPTZ, -- NULLは未購入
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_owner_id ON wishlist_items(owner_id);
+
+-- 欲しい本の価格の履歴（手入力、または価格の取得元から記録する）
+CREATE TABLE IF NOT EXISTS price_records (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES wishlist_items(id) ON DELETE CASCADE,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    source TEXT NOT NULL, -- manual（手入力）または価格の取得元の名前
+    url TEXT NOT NULL DEFAULT '',
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_records_item_id ON price_records(item_id);
+
+-- 読書目標テーブル（期間内に読み終えた書籍の冊数・ページ数の目標。達成状況は books の end_read_date から計算する）
+CREATE TABLE IF NOT EXISTS reading_goals (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- NULLは共有の本棚の目標
+    title TEXT NOT NULL,
+    metric TEXT NOT NULL CHECK (metric IN ('books', 'pages')), -- 冊数またはページ数
+    target INTEGER NOT NULL CHECK (target > 0),
+    period TEXT NOT NULL CHECK (period IN ('year', 'month', 'custom')),
+    start_date DATE NOT NULL, -- 期間の初日
+    end_date DATE NOT NULL, -- 期間の最終日（この日も含む）
+    tag TEXT NOT NULL DEFAULT '', -- 空でなければ、このタグの付いた書籍だけを数える
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_goals_owner_id ON reading_goals(owner_id);
+
+-- 予算テーブル（1か月・1年ごとに使える金額。実際の支出は books の purchase_date と purchase_price から集計する）
+-- 所有者と期間の種類の組み合わせごとに1件（共有の本棚は owner_id が NULL のため、一意性はアプリ側で保つ）
+CREATE TABLE IF NOT EXISTS budgets (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- NULLは共有の本棚の予算
+    period TEXT NOT NULL CHECK (period IN ('month', 'year')), -- 毎月の予算または毎年の予算
+    amount INTEGER NOT NULL CHECK (amount >= 0), -- 予算（円）
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_owner_id ON budgets(owner_id);
+
+-- 為替レートテーブル（全ユーザー共通。1 currency が rate base にあたる。統計・予算は購入日以前で最新のレートで換算する）
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id SERIAL PRIMARY KEY,
+    currency TEXT NOT NULL, -- 換算元の通貨（ISO 4217）
+    base TEXT NOT NULL, -- 換算先の通貨（登録時の基準通貨）
+    rate_date DATE NOT NULL, -- レートの日付（この日以降の購入に使う）
+    rate DOUBLE PRECISION NOT NULL CHECK (rate > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (currency, base, rate_date)
+);
+
+-- ハイライト・引用テーブル（書籍ごとの印象に残った文章。書籍を削除するとハイライトも削除される）
+CREATE TABLE IF NOT EXISTS highlights (
+    id SERIAL PRIMARY KEY,
+    book_id INTEGER NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL DEFAULT 'highlight' CHECK (kind IN ('highlight', 'quote')), -- ハイライトまたは引用
+    text TEXT NOT NULL, -- 本文
+    note TEXT NOT NULL DEFAULT '', -- 自分のメモ
+    page INTEGER CHECK (page > 0), -- ページ（NULLは未登録）
+    location TEXT NOT NULL DEFAULT '', -- 電子書籍の位置（例：1234-1236）
+    chapter TEXT NOT NULL DEFAULT '', -- 章
+    tags TEXT NOT NULL DEFAULT '', -- カンマ区切りのタグ
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_highlights_book_id ON highlights(book_id);
+
+-- レビューテーブル（読書1回ごとの感想。書籍の notes とは分け、本文はMarkdownで書く）
+CREATE TABLE IF NOT EXISTS reviews (
+    id SERIAL PRIMARY KEY,
+    book_id INTEGER NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    read_number INTEGER NOT NULL DEFAULT 1 CHECK (read_number > 0), -- 何回目の読書のレビューか
+    read_on DATE, -- 読み終えた日（NULLは未登録）
+    title TEXT NOT NULL DEFAULT '', -- 見出し
+    body TEXT NOT NULL, -- 本文（Markdown）
+    spoiler BOOLEAN NOT NULL DEFAULT FALSE, -- ネタバレを含むか
+    visibility TEXT NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'public')), -- 公開範囲
+    version INTEGER NOT NULL DEFAULT 1, -- 現在の版（本文を編集するたびに1つ増える）
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (book_id, read_number)
+);
+
+-- レビューの編集履歴テーブル（版ごとの見出し・本文・ネタバレの有無。最新の版も含む）
+CREATE TABLE IF NOT EXISTS review_revisions (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    spoiler BOOLEAN NOT NULL DEFAULT FALSE,
+    edited_by INTEGER REFERENCES users(id) ON DELETE SET NULL, -- 編集した人（NULLは未ログインまたは削除されたユーザー）
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (review_id, version)
+);
+
+-- 既存のテーブルに後から追加したカラム（books.owner_id など）は database.go の addedColumns で追加する
+
+-- スキーマバージョンの記録用テーブル（SQLiteの PRAGMA user_version の代わり）
+CREATE TABLE IF NOT EXISTS schema_version (
+    version INTEGER NOT NULL
+);
@@ -0,0 +1,43 @@
+// ereaderパッケージ：電子書籍リーダーのハイライト・メモのファイルを読み込むファイル
+// Kindle の「My Clippings.txt」と Kobo の「KoboReader.sqlite」を、共通の Clipping に変換する
+// どの書籍のハイライトかは、タイトルと著者のあいまい一致（match.go）で本棚の書籍と結びつける
+package ereader
+
+import (
+	"crypto/sha1"  // 取り込み元のキーの作成
+	"encoding/hex" // ハッシュ値の文字列化
+	"strings"      // キーの組み立て
+	"time"         // ハイライトの作成日時
+)
+
+// 取り込み元の名前（ハイライトの source_key の先頭に付ける）
+const (
+	SourceKindle = "kindle" // Kindle の My Clippings.txt
+	SourceKobo   = "kobo"   // Kobo の KoboReader.sqlite
+)
+
+// Clipping は電子書籍リーダーから読み込んだハイライト1件
+type Clipping struct {
+	Key       string    // 取り込み元での一意なキー（再取り込みで重複させないために使う）
+	Title     string    // 書籍のタイトル（リーダーに表示されていたもの）
+	Author    string    // 著者（リーダーに表示されていたもの。不明なら空文字）
+	Text      string    // ハイライトした本文
+	Note      string    // ハイライトに付けたメモ
+	Page      *int      // ページ（不明なら nil）
+	Location  string    // 電子書籍の位置（例：1234-1236）
+	Chapter   string    // 章（不明なら空文字）
+	CreatedAt time.Time // ハイライトした日時（不明ならゼロ値）
+}
+
+// Result はファイルの読み込み結果
+type Result struct {
+	Clippings []*Clipping // 取り込むハイライト
+	Skipped   []string    // 取り込まない項目（ブックマーク、結びつくハイライトのないメモなど）とその理由
+}
+
+// key は取り込み元と項目を表す値から、取り込み元での一意なキーを作る関数
+// 値をそのまま使うと長くなるため、SHA-1のハッシュ値の先頭20文字にする
+func key(source string, parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return source + ":" + hex.EncodeToString(sum[:])[:20]
+}
@@ -0,0 +1,234 @@
+package ereader
+
+import (
+	"database/sql"  // テスト用の KoboReader.sqlite の作成
+	"fmt"           // 結果の文字列化
+	"path/filepath" // テスト用のファイルのパス
+	"strings"       // テスト用の My Clippings.txt の組み立て
+	"testing"       // テストの実行と結果の報告
+	"time"          // ハイライトの作成日時
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// clippings は My Clippings.txt の項目を区切りの行でつないだ文字列を作る
+func clippings(entries ...string) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(entry + "\r\n" + kindleSeparator + "\r\n")
+	}
+	return b.String()
+}
+
+// summary はハイライトを比較しやすい1行の文字列にする
+func summary(c *Clipping) string {
+	page := "-"
+	if c.Page != nil {
+		page = fmt.Sprint(*c.Page)
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|p%s|%s", c.Title, c.Author, c.Text, c.Note, page, c.Location)
+}
+
+// TestParseKindle は英語・日本語の My Clippings.txt の項目の解析と、メモ・修正したハイライトの扱いを確認する
+func TestParseKindle(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		want        []string // summary の結果
+		wantSkipped int
+	}{
+		{"英語のハイライト", clippings(
+			"\ufeffClean Code (Robert C. Martin)\n- Your Highlight on page 12 | Location 123-125 | Added on Monday, January 1, 2024 10:00:00 AM\n\nLeave the campground cleaner.",
+		), []string{"Clean Code|Robert C. Martin|Leave the campground cleaner.||p12|123-125"}, 0},
+		{"日本語のハイライト", clippings(
+			"吾輩は猫である (夏目 漱石)\n- 12ページ|位置No. 123-125のハイライト |作成日: 2024年1月1日月曜日 午後3:04:05\n\n名前はまだ無い。",
+		), []string{"吾輩は猫である|夏目 漱石|名前はまだ無い。||p12|123-125"}, 0},
+		{"省略された終わりの位置を補う", clippings(
+			"Book (A)\n- Highlight Loc. 1234-36 | Added on Monday, January 1, 2024 10:00:00 AM\n\nText",
+		), []string{"Book|A|Text||p-|1234-1236"}, 0},
+		{"メモは位置が重なるハイライトに付ける", clippings(
+			"Book (A)\n- Your Highlight on Location 100-110\n\nText",
+			"Book (A)\n- Your Note on Location 110\n\n大事",
+		), []string{"Book|A|Text|大事|p-|100-110"}, 0},
+		{"先に記録されたメモも付ける", clippings(
+			"Book (A)\n- Your Note on Location 105\n\n大事",
+			"Book (A)\n- Your Highlight on Location 100-110\n\nText",
+		), []string{"Book|A|Text|大事|p-|100-110"}, 0},
+		{"結びつかないメモは取り込まない", clippings(
+			"Book (A)\n- Your Highlight on Location 100-110\n\nText",
+			"Other (B)\n- Your Note on Location 105\n\n別の本",
+		), []string{"Book|A|Text||p-|100-110"}, 1},
+		{"修正したハイライトは新しい方だけ", clippings(
+			"Book (A)\n- Your Highlight on Location 100-105\n\nTex",
+			"Book (A)\n- Your Highlight on Location 100-110\n\nText",
+		), []string{"Book|A|Text||p-|100-110"}, 0},
+		{"ブックマークとクリップは取り込まない", clippings(
+			"Book (A)\n- Your Bookmark on Location 100\n\n",
+			"Web (B)\n- Clip This Article on Location 1\n\nArticle",
+		), []string{}, 1},
+		{"種類がわからない・本文がない項目", clippings(
+			"Book (A)\n- Something on Location 1\n\nText",
+			"Book (A)\n- Your Highlight on Location 1\n\n",
+			"タイトルだけ",
+		), []string{}, 3},
+		{"括弧のないタイトル", clippings(
+			"著者のない本\n- Your Highlight on page 3\n\nText",
+		), []string{"著者のない本||Text||p3|"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseKindle(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseKindle: %v", err)
+			}
+			got := []string{}
+			for _, c := range result.Clippings {
+				got = append(got, summary(c))
+				if !strings.HasPrefix(c.Key, SourceKindle+":") {
+					t.Errorf("Key = %q, want %s: で始まる", c.Key, SourceKindle)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Clippings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if len(result.Skipped) != tt.wantSkipped {
+				t.Errorf("Skipped = %q, want %d件", result.Skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+// TestParseKindleKey は修正したハイライトが同じキーになり、別のハイライトは別のキーになることを確認する
+func TestParseKindleKey(t *testing.T) {
+	parse := func(entry string) string {
+		t.Helper()
+		result, err := ParseKindle(strings.NewReader(clippings(entry)))
+		if err != nil || len(result.Clippings) != 1 {
+			t.Fatalf("ParseKindle(%q) = %v, %v", entry, result, err)
+		}
+		return result.Clippings[0].Key
+	}
+	base := parse("Book (A)\n- Your Highlight on page 1 | Location 100-105\n\nTex")
+	tests := []struct {
+		name  string
+		entry string
+		same  bool
+	}{
+		{"終わりの位置と本文を修正", "Book (A)\n- Your Highlight on page 1 | Location 100-110\n\nText", true},
+		{"始まりの位置が違う", "Book (A)\n- Your Highlight on page 1 | Location 101-105\n\nTex", false},
+		{"別の書籍", "Other (A)\n- Your Highlight on page 1 | Location 100-105\n\nTex", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parse(tt.entry) == base; got != tt.same {
+				t.Errorf("同じキーか = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+// TestParseKindleDate は英語・日本語の作成日時の書式を読み取れることを確認する
+func TestParseKindleDate(t *testing.T) {
+	tests := []struct {
+		meta string
+		want time.Time
+	}{
+		{"- Your Highlight | Added on Monday, January 1, 2024 10:00:00 AM", time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)},
+		{"- Your Highlight | Added on Monday, January 1, 2024, 3:04 PM", time.Date(2024, 1, 1, 15, 4, 0, 0, time.Local)},
+		{"- Your Highlight | Added on Monday, 1 January 2024 15:04:05", time.Date(2024, 1, 1, 15, 4, 5, 0, time.Local)},
+		{"- ハイライト |作成日: 2024年1月1日月曜日 午後3:04:05", time.Date(2024, 1, 1, 15, 4, 5, 0, time.Local)},
+		{"- ハイライト |作成日: 2024年1月1日月曜日 午前12:30", time.Date(2024, 1, 1, 0, 30, 0, 0, time.Local)},
+		{"- ハイライト |作成日: 2024年12月31日 23:59:59", time.Date(2024, 12, 31, 23, 59, 59, 0, time.Local)},
+		{"- Your Highlight | Added on someday", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.meta, func(t *testing.T) {
+			if got := parseKindleDate(tt.meta); !got.Equal(tt.want) {
+				t.Errorf("parseKindleDate(%q) = %v, want %v", tt.meta, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseKobo は KoboReader.sqlite のハイライトを読み込み、しおりやメモだけの項目を除くことを確認する
+func TestParseKobo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "KoboReader.sqlite")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE content (ContentID TEXT, Title TEXT, Attribution TEXT)`,
+		`CREATE TABLE Bookmark (BookmarkID TEXT, VolumeID TEXT, ContentID TEXT, Text TEXT, Annotation TEXT, DateCreated TEXT)`,
+		`INSERT INTO content VALUES ('book1', '吾輩は猫である', '夏目 漱石'), ('book1#ch1', '一', NULL)`,
+		`INSERT INTO Bookmark VALUES
+			('b1', 'book1', 'book1#ch1', ' 名前はまだ無い。 ', 'メモ', '2024-01-01T10:00:00.000'),
+			('b2', 'book1', 'book1#ch2', '章のないハイライト', NULL, '2024-01-02T10:00:00Z'),
+			('b3', 'book1', 'book1#ch1', NULL, NULL, '2024-01-03T10:00:00'),
+			('b4', 'book1', 'book1#ch1', '', '本文のないメモ', '2024-01-04 10:00:00'),
+			('b5', 'missing', 'missing', '書籍のないハイライト', NULL, '')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Exec(%s): %v", stmt, err)
+		}
+	}
+	db.Close()
+
+	result, err := ParseKobo(path)
+	if err != nil {
+		t.Fatalf("ParseKobo: %v", err)
+	}
+	tests := []struct {
+		want    string
+		chapter string
+		created time.Time
+	}{
+		{"吾輩は猫である|夏目 漱石|名前はまだ無い。|メモ|p-|", "一", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"吾輩は猫である|夏目 漱石|章のないハイライト||p-|", "", time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	if len(result.Clippings) != len(tests) || len(result.Skipped) != 2 {
+		t.Fatalf("Clippings = %d件, Skipped = %q, want %d件・2件", len(result.Clippings), result.Skipped, len(tests))
+	}
+	for i, tt := range tests {
+		c := result.Clippings[i]
+		if summary(c) != tt.want || c.Chapter != tt.chapter || !c.CreatedAt.Equal(tt.created) || !strings.HasPrefix(c.Key, SourceKobo+":") {
+			t.Errorf("%d件目 = %s（章 %q、%v、%s）, want %s（章 %q、%v）", i+1, summary(c), c.Chapter, c.CreatedAt, c.Key, tt.want, tt.chapter, tt.created)
+		}
+	}
+
+	if _, err := ParseKobo(filepath.Join(t.TempDir(), "empty.sqlite")); err == nil {
+		t.Error("Kobo のデータベースではないファイルでエラーになりません")
+	}
+}
+
+// TestMatcher はリーダーのタイトル・著者の表記の揺れを無視して本棚の書籍に結びつけることを確認する
+func TestMatcher(t *testing.T) {
+	books := []*model.Book{
+		{ID: 1, Title: "吾輩は猫である", Author: "夏目 漱石"},
+		{ID: 2, Title: "Clean Code: A Handbook of Agile Software Craftsmanship", Author: "Robert C. Martin"},
+		{ID: 3, Title: "ノルウェイの森 上", Author: "村上 春樹"},
+	}
+	matcher := NewMatcher(books)
+	tests := []struct {
+		name          string
+		title, author string
+		want          int // 結びつく書籍のID（0は結びつかない）
+	}{
+		{"そのまま", "吾輩は猫である", "夏目 漱石", 1},
+		{"レーベル名付き", "吾輩は猫である（新潮文庫）", "夏目漱石", 1},
+		{"副題なし・姓, 名の順", "Clean Code", "Martin, Robert C.", 2},
+		{"著者が不明", "ノルウェイの森 上", "", 3},
+		{"本棚にない書籍", "坊っちゃん", "夏目 漱石", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := 0
+			if book := matcher.Match(tt.title, tt.author); book != nil {
+				got = book.ID
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q, %q) = %d, want %d", tt.title, tt.author, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package ereader
+
+import (
+	"bufio"   // ファイルを1行ずつ読む
+	"fmt"     // 読み飛ばした理由の作成
+	"io"      // 読み込み元
+	"regexp"  // 情報の行の解析
+	"strconv" // ページ・位置の数値の変換
+	"strings" // 文字列操作
+	"time"    // ハイライトの作成日時
+)
+
+// kindleSeparator は My Clippings.txt の項目の区切りの行
+const kindleSeparator = "=========="
+
+// Kindle の情報の行（2行目）の解析に使う正規表現
+// 英語：「- Your Highlight on page 12 | Location 123-125 | Added on Monday, January 1, 2024 10:00:00 AM」
+// 日本語：「- 12ページ|位置No. 123-125のハイライト |作成日: 2024年1月1日月曜日 10:00:00」
+var (
+	kindleTitleLine  = regexp.MustCompile(`^(.*?)\s*\(([^()]*)\)\s*$`)                         // タイトル（著者）
+	kindlePageEN     = regexp.MustCompile(`(?i)\bpage\s+(\d+)`)                                // page 12
+	kindlePageJA     = regexp.MustCompile(`(\d+)\s*ページ`)                                       // 12ページ
+	kindleLocationEN = regexp.MustCompile(`(?i)\b(?:location|loc\.)\s+(\d+)(?:\s*-\s*(\d+))?`) // Location 123-125、Loc. 123-25
+	kindleLocationJA = regexp.MustCompile(`位置No\.\s*(\d+)(?:\s*-\s*(\d+))?`)                   // 位置No. 123-125
+	kindleDateEN     = regexp.MustCompile(`(?i)added on\s+(.+)$`)                              // Added on ...
+	kindleDateJA     = regexp.MustCompile(`(\d{4})年(\d{1,2})月(\d{1,2})日.*?(午前|午後)?\s*(\d{1,2}):(\d{2})(?::(\d{2}))?`)
+)
+
+// kindleDateLayouts は英語の日時の書式（端末や地域の設定によって異なる）
+var kindleDateLayouts = []string{
+	"Monday, January 2, 2006 3:04:05 PM",
+	"Monday, January 2, 2006, 3:04 PM",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, 2 January 2006 3:04:05 PM",
+	"Monday, January 2, 2006 15:04:05",
+	"Monday, 2 January 06 15:04:05",
+}
+
+// kindleKind は Kindle の項目の種類
+type kindleKind int
+
+// Kindle の項目の種類の定数定義
+const (
+	kindleHighlight kindleKind = iota // ハイライト
+	kindleNote                        // メモ
+	kindleBookmark                    // ブックマーク
+	kindleClip                        // 記事のクリップ
+)
+
+// kindleEntry は My Clippings.txt の1項目
+type kindleEntry struct {
+	kind       kindleKind // 項目の種類
+	clipping   *Clipping  // 項目の内容
+	start, end int        // 位置の範囲（位置がなければ0）
+}
+
+// ParseKindle は Kindle の My Clippings.txt を読み込む関数
+// 英語と日本語の表示の両方に対応する。日時は端末の時刻のため、サーバーのタイムゾーンの時刻として扱う
+// メモは同じ書籍の位置が重なるハイライトに付け、ハイライトを修正した場合に追記される古い項目は新しい方だけを残す
+func ParseKindle(r io.Reader) (*Result, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024) // ハイライトの本文は長い場合がある
+
+	result := &Result{Clippings: []*Clipping{}, Skipped: []string{}}
+	entries := []*kindleEntry{}
+	lines := []string{}
+	number := 0 // 項目の番号（読み飛ばした理由の表示用）
+	flush := func() {
+		if len(lines) == 0 {
+			return
+		}
+		number++
+		entry, err := parseKindleEntry(lines)
+		lines = lines[:0]
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%d件目: %v", number, err))
+			return
+		}
+		entries = append(entries, entry)
+	}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == kindleSeparator {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("My Clippings.txt の読み込みに失敗しました: %w", err)
+	}
+	flush()
+
+	// ハイライトを修正すると、同じ位置から始まる項目が後ろに追記されるため、最後のものだけを残す
+	latest := map[string]int{}
+	for i, entry := range entries {
+		if entry.kind == kindleHighlight && entry.start > 0 {
+			latest[entry.clipping.Title+"\x00"+strconv.Itoa(entry.start)] = i
+		}
+	}
+	highlights := []*kindleEntry{}
+	for i, entry := range entries {
+		switch entry.kind {
+		case kindleHighlight:
+			if entry.start > 0 && latest[entry.clipping.Title+"\x00"+strconv.Itoa(entry.start)] != i {
+				continue
+			}
+			highlights = append(highlights, entry)
+		case kindleNote:
+			if target := noteTarget(highlights, entries[i+1:], entry); target != nil {
+				target.clipping.Note = strings.TrimSpace(target.clipping.Note + "\n" + entry.clipping.Text)
+			} else {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: 位置 %s のメモは、結びつくハイライトがないため取り込みません",
+					entry.clipping.Title, entry.clipping.Location))
+			}
+		case kindleBookmark:
+			// ブックマークには本文がないため取り込まない（件数が多くなるため理由も残さない）
+		case kindleClip:
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: 記事のクリップは取り込みません", entry.clipping.Title))
+		}
+	}
+	for _, entry := range highlights {
+		result.Clippings = append(result.Clippings, entry.clipping)
+	}
+	return result, nil
+}
+
+// noteTarget はメモを付けるハイライトを探す関数
+// メモの位置はハイライトの範囲の終わりに記録されるため、同じ書籍で位置の範囲にメモの位置を含むハイライトを選ぶ
+// メモが先に記録されることもあるため、前のハイライトで見つからなければ後ろの項目も探す
+func noteTarget(before []*kindleEntry, after []*kindleEntry, note *kindleEntry) *kindleEntry {
+	matches := func(entry *kindleEntry) bool {
+		return entry.kind == kindleHighlight && entry.clipping.Title == note.clipping.Title &&
+			note.start > 0 && entry.start <= note.start && note.start <= entry.end
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		if matches(before[i]) {
+			return before[i]
+		}
+	}
+	for _, entry := range after {
+		if matches(entry) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// parseKindleEntry は1項目（タイトルの行・情報の行・空行・本文）を解析する関数
+func parseKindleEntry(lines []string) (*kindleEntry, error) {
+	// 先頭の空行（ファイルの先頭のBOMも含む）を除く
+	for len(lines) > 0 && strings.TrimSpace(strings.TrimPrefix(lines[0], "\ufeff")) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("タイトルと情報の行がありません")
+	}
+
+	clipping := &Clipping{}
+	titleLine := strings.TrimSpace(strings.TrimPrefix(lines[0], "\ufeff"))
+	clipping.Title = titleLine
+	if m := kindleTitleLine.FindStringSubmatch(titleLine); m != nil && strings.TrimSpace(m[1]) != "" {
+		clipping.Title, clipping.Author = strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	}
+
+	meta := strings.TrimSpace(lines[1])
+	entry := &kindleEntry{clipping: clipping}
+	switch lower := strings.ToLower(meta); {
+	case strings.Contains(lower, "highlight") || strings.Contains(meta, "ハイライト"):
+		entry.kind = kindleHighlight
+	case strings.Contains(lower, "note") || strings.Contains(meta, "メモ"):
+		entry.kind = kindleNote
+	case strings.Contains(lower, "bookmark") || strings.Contains(meta, "ブックマーク"):
+		entry.kind = kindleBookmark
+	case strings.Contains(lower, "clip") || strings.Contains(meta, "クリップ"):
+		entry.kind = kindleClip
+	default:
+		return nil, fmt.Errorf("%s: 項目の種類がわかりません: %s", clipping.Title, meta)
+	}
+
+	// ページ
+	for _, re := range []*regexp.Regexp{kindlePageJA, kindlePageEN} {
+		if m := re.FindStringSubmatch(meta); m != nil {
+			if page, err := strconv.Atoi(m[1]); err == nil && page > 0 {
+				clipping.Page = &page
+				break
+			}
+		}
+	}
+	// 位置（古い端末の「Loc. 1234-36」のように、終わりの位置の上の桁が省略されている場合は補う）
+	for _, re := range []*regexp.Regexp{kindleLocationJA, kindleLocationEN} {
+		if m := re.FindStringSubmatch(meta); m != nil {
+			entry.start, _ = strconv.Atoi(m[1])
+			entry.end = entry.start
+			clipping.Location = m[1]
+			if m[2] != "" {
+				entry.end = expandLocation(m[1], m[2])
+				clipping.Location = fmt.Sprintf("%d-%d", entry.start, entry.end)
+			}
+			break
+		}
+	}
+	clipping.CreatedAt = parseKindleDate(meta)
+
+	// 3行目は空行で、4行目以降が本文
+	body := []string{}
+	if len(lines) > 2 {
+		body = lines[2:]
+	}
+	clipping.Text = strings.TrimSpace(strings.Join(body, "\n"))
+	if entry.kind == kindleHighlight || entry.kind == kindleNote {
+		if clipping.Text == "" {
+			return nil, fmt.Errorf("%s: 本文がありません", clipping.Title)
+		}
+	}
+
+	page := ""
+	if clipping.Page != nil {
+		page = strconv.Itoa(*clipping.Page)
+	}
+	// ハイライトを修正すると終わりの位置と本文が変わるため、始まりの位置とページで区別する
+	// 位置もページもない項目は、本文でほかのハイライトと区別する
+	if entry.start == 0 && page == "" {
+		clipping.Key = key(SourceKindle, clipping.Title, clipping.Author, clipping.Text)
+	} else {
+		clipping.Key = key(SourceKindle, clipping.Title, clipping.Author, strconv.Itoa(entry.start), page)
+	}
+	return entry, nil
+}
+
+// expandLocation は終わりの位置の省略された上の桁を、始まりの位置から補う関数（例：1234 と 36 → 1236）
+func expandLocation(start, end string) int {
+	if len(end) < len(start) {
+		end = start[:len(start)-len(end)] + end
+	}
+	n, _ := strconv.Atoi(end)
+	if s, _ := strconv.Atoi(start); n < s {
+		return s
+	}
+	return n
+}
+
+// parseKindleDate は情報の行から作成日時を読み取る関数（読み取れなければゼロ値）
+func parseKindleDate(meta string) time.Time {
+	if m := kindleDateJA.FindStringSubmatch(meta); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		hour, _ := strconv.Atoi(m[5])
+		minute, _ := strconv.Atoi(m[6])
+		second, _ := strconv.Atoi(m[7])
+		if m[4] == "午後" && hour < 12 {
+			hour += 12
+		} else if m[4] == "午前" && hour == 12 {
+			hour = 0
+		}
+		return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.Local)
+	}
+	if m := kindleDateEN.FindStringSubmatch(meta); m != nil {
+		value := strings.TrimSpace(m[1])
+		for _, layout := range kindleDateLayouts {
+			if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,80 @@
+package ereader
+
+import (
+	"book-manager/internal/model"     // 自作のデータ構造定義
+	"book-manager/internal/textmatch" // 表記の揺れを無視した比較
+)
+
+// matchThreshold は書籍と結びつけるのに必要な一致度（0〜1）
+const matchThreshold = 0.8
+
+// Matcher はリーダーのタイトル・著者を本棚の書籍に結びつける構造体
+// リーダーのタイトルには副題やレーベル名（「（講談社文庫）」など）が付くことがあり、
+// 著者名も「姓, 名」の順になることがあるため、表記をそろえてからあいまいに比べる
+type Matcher struct {
+	books []*matchBook
+	cache map[string]*model.Book // 同じタイトル・著者の結果（ハイライトは同じ書籍が続くため）
+}
+
+// matchBook は表記をそろえた書籍のタイトルと著者
+type matchBook struct {
+	book        *model.Book
+	title, base string // 表記をそろえたタイトルと、副題・括弧書きを除いたタイトル
+	author      string // 表記をそろえた著者名
+}
+
+// NewMatcher は本棚の書籍からMatcherを作成する関数
+func NewMatcher(books []*model.Book) *Matcher {
+	m := &Matcher{cache: map[string]*model.Book{}}
+	for _, book := range books {
+		m.books = append(m.books, &matchBook{
+			book:   book,
+			title:  textmatch.Normalize(book.Title),
+			base:   textmatch.Normalize(textmatch.BaseTitle(book.Title)),
+			author: textmatch.NormalizeAuthor(book.Author),
+		})
+	}
+	return m
+}
+
+// Match はタイトルと著者に最も近い書籍を返す（一致度が足りなければ nil）
+func (m *Matcher) Match(title, author string) *model.Book {
+	cacheKey := title + "\x00" + author
+	if book, ok := m.cache[cacheKey]; ok {
+		return book
+	}
+	t, b, a := textmatch.Normalize(title), textmatch.Normalize(textmatch.BaseTitle(title)), textmatch.NormalizeAuthor(author)
+	var best *model.Book
+	bestScore := 0.0
+	for _, candidate := range m.books {
+		if score := candidate.score(t, b, a); score > bestScore {
+			best, bestScore = candidate.book, score
+		}
+	}
+	if bestScore < matchThreshold {
+		best = nil
+	}
+	m.cache[cacheKey] = best
+	return best
+}
+
+// score はタイトルと著者の一致度（0〜1）を計算する
+// タイトルの一致度を中心にし、両方に著者があれば著者の一致度も2割の重みで加える
+func (b *matchBook) score(title, base, author string) float64 {
+	if title == "" || b.title == "" {
+		return 0
+	}
+	score := max(textmatch.Similarity(b.title, title), textmatch.Similarity(b.base, base))
+	// 片方のタイトルがもう片方を含む場合（副題の有無など）は、短い方が十分に長ければほぼ一致とみなす
+	if textmatch.Contains(b.title, title) || textmatch.Contains(b.base, base) {
+		score = max(score, 0.9)
+	}
+	if author != "" && b.author != "" {
+		authorScore := textmatch.Similarity(b.author, author)
+		if textmatch.Contains(b.author, author) {
+			authorScore = 1
+		}
+		score = score*0.8 + authorScore*0.2
+	}
+	return score
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"bytes"    // 受け取ったアーカイブをメモリ上で扱う
+	"fmt"      // 文字列フォーマット
+	"io"       // リクエストボディの読み込み
+	"net/http" // HTTPサーバー機能
+	"time"     // ファイル名の日時
+
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// maxArchiveSize は取り込めるアーカイブの最大サイズ（256MB）
+const maxArchiveSize = 256 << 20
+
+// ArchiveHandler は移行用アーカイブの書き出し・取り込みを処理する構造体
+type ArchiveHandler struct {
+	archiveUsecase usecase.ArchiveUsecase // アーカイブのビジネスロジック
+}
+
+// NewArchiveHandler は新しいArchiveHandlerを作成する関数
+func NewArchiveHandler(archiveUsecase usecase.ArchiveUsecase) *ArchiveHandler {
+	return &ArchiveHandler{archiveUsecase: archiveUsecase}
+}
+
+// archives はリクエストしたユーザーの本棚を書き出し・取り込みするユースケースを返す
+func (h *ArchiveHandler) archives(r *http.Request) usecase.ArchiveUsecase {
+	return h.archiveUsecase.ForUser(currentUserID(r))
+}
+
+// ExportArchive はアーカイブ（ZIP）をダウンロードさせるHTTPハンドラ関数
+// GET /api/v1/admin/archive のリクエストを処理
+func (h *ArchiveHandler) ExportArchive(w http.ResponseWriter, r *http.Request) {
+	// 途中でエラーになった場合にエラーレスポンスを返せるよう、いったんメモリに書き出す
+	var buf bytes.Buffer
+	if _, err := h.archives(r).Export(&buf); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "アーカイブの作成に失敗しました", err)
+		return
+	}
+
+	filename := fmt.Sprintf("book-manager-%s.zip", time.Now().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// ImportArchive はアーカイブ（ZIP）を取り込むHTTPハンドラ関数
+// POST /api/v1/admin/archive?policy=skip のリクエストを処理（リクエストボディにZIPファイルを送る）
+// policy：同じIDの書籍がある場合の扱い（skip（デフォルト）、overwrite、duplicate）
+func (h *ArchiveHandler) ImportArchive(w http.ResponseWriter, r *http.Request) {
+	// MaxBytesReader：大きすぎるファイルでメモリを使い切らないように上限を設ける
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxArchiveSize))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "アーカイブの受信に失敗しました", err)
+		return
+	}
+
+	result, err := h.archives(r).Import(bytes.NewReader(data), int64(len(data)), r.URL.Query().Get("policy"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "アーカイブの取り込みに失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "アーカイブを取り込みました", result)
+}
+
+// RegisterRoutes はアーカイブAPIのルートを登録する関数
+func (h *ArchiveHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/admin/archive", h.ExportArchive).Methods("GET")  // アーカイブの書き出し
+	router.HandleFunc("/admin/archive", h.ImportArchive).Methods("POST") // アーカイブの取り込み
+}
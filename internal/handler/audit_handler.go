@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // クエリパラメータの数値変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// AuditHandler は変更履歴のHTTPリクエストを処理する構造体
+type AuditHandler struct {
+	auditUsecase usecase.AuditUsecase // 変更履歴のビジネスロジック
+}
+
+// NewAuditHandler は新しいAuditHandlerを作成する関数
+func NewAuditHandler(auditUsecase usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{auditUsecase: auditUsecase}
+}
+
+// ListAuditLogsResponse は変更履歴一覧のレスポンス
+type ListAuditLogsResponse struct {
+	Entries    []*model.AuditLog `json:"entries"`     // 変更履歴
+	Total      int               `json:"total"`       // 総件数
+	Page       int               `json:"page"`        // 現在のページ番号
+	Limit      int               `json:"limit"`       // 1ページあたりの件数
+	TotalPages int               `json:"total_pages"` // 総ページ数
+}
+
+// ListAuditLogs は自分のデータの変更履歴を返すHTTPハンドラ関数
+// GET /api/v1/audit?entity_type=book&entity_id=1&action=update&actor_id=2&request_id=...&page=1&limit=20 のリクエストを処理
+// 共有相手（editor）が自分の書籍を変更した履歴も含まれる
+func (h *AuditHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &model.AuditFilter{
+		OwnerID:    user.ID,
+		EntityType: query.Get("entity_type"),
+		Action:     query.Get("action"),
+		RequestID:  query.Get("request_id"),
+	}
+	filter.EntityID, _ = strconv.Atoi(query.Get("entity_id"))
+	filter.ActorID, _ = strconv.Atoi(query.Get("actor_id"))
+	page, limit := parsePagination(query)
+
+	entries, total, err := h.auditUsecase.List(filter, page, limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "変更履歴の取得に失敗しました", err)
+		return
+	}
+	writeAuditLogs(w, entries, total, page, limit)
+}
+
+// ListBookAuditLogs は1冊の書籍の変更履歴を返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/audit?page=1&limit=20 のリクエストを処理
+// 共有された書籍は、持ち主や他の共有相手による変更も含まれる
+func (h *AuditHandler) ListBookAuditLogs(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+	page, limit := parsePagination(r.URL.Query())
+
+	entries, total, err := h.auditUsecase.ListForBook(currentUserID(r), id, page, limit)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "変更履歴の取得に失敗しました", err)
+		return
+	}
+	writeAuditLogs(w, entries, total, page, limit)
+}
+
+// RevertBook は書籍を変更履歴の版に戻すHTTPハンドラ関数
+// POST /api/v1/books/{id}/revert のリクエストを処理
+// リクエスト例：{"audit_id": 12}（その変更の直後の状態に戻す。削除の履歴なら削除の直前の状態）
+func (h *AuditHandler) RevertBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+	var req model.RevertBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	book, err := h.auditUsecase.Revert(currentUserID(r), RequestID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusBadRequest), "書籍を以前の版に戻せませんでした", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "書籍を以前の版に戻しました", book)
+}
+
+// writeAuditLogs は変更履歴の一覧をページング情報付きで返す
+func writeAuditLogs(w http.ResponseWriter, entries []*model.AuditLog, total, page, limit int) {
+	writeSuccessResponse(w, http.StatusOK, "", ListAuditLogsResponse{
+		Entries:    entries,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// RegisterRoutes は変更履歴APIのルートを登録する関数
+func (h *AuditHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/audit", h.ListAuditLogs).Methods("GET")                       // 変更履歴の一覧
+	router.HandleFunc("/books/{id:[0-9]+}/audit", h.ListBookAuditLogs).Methods("GET") // 1冊の書籍の変更履歴
+	router.HandleFunc("/books/{id:[0-9]+}/revert", h.RevertBook).Methods("POST")      // 書籍を以前の版に戻す
+}
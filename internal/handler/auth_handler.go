@@ -0,0 +1,354 @@
+package handler
+
+import (
+	"context"       // リクエストにログイン中のユーザーを持たせる
+	"encoding/json" // JSONの解析
+	"errors"        // エラーの判定
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // URLのIDの変換
+	"strings"       // Authorizationヘッダーの解析
+	"time"          // Cookieの有効期限
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// sessionCookieName はセッショントークンを保存するCookieの名前
+const sessionCookieName = "book_manager_session"
+
+// contextKey はリクエストのコンテキストに値を保存するときのキーの型
+// 他のパッケージのキーと衝突しないように、独自の型を使う
+type contextKey string
+
+// コンテキストのキーの定数定義
+const (
+	userContextKey   contextKey = "user"   // ログイン中のユーザー
+	scopesContextKey contextKey = "scopes" // リクエストに与えられた権限
+	tokenContextKey  contextKey = "token"  // アクセストークンで認証したか
+)
+
+// sessionScopes はパスワードやセッションでログインしたときの権限
+// サーバーの管理（/admin/... など）は権限ではなく、ユーザーが管理者かどうか（User.IsAdmin）で判断する
+var sessionScopes = []model.Scope{model.ScopeRead, model.ScopeWrite}
+
+// currentUser はリクエストのログイン中のユーザーを返す（ログインしていなければnil）
+func currentUser(r *http.Request) *model.User {
+	user, _ := r.Context().Value(userContextKey).(*model.User)
+	return user
+}
+
+// currentUserID はリクエストのログイン中のユーザーIDを返す
+// ログインしていなければ0（ユーザー登録前からある共有の本棚）を返す
+func currentUserID(r *http.Request) int {
+	if user := currentUser(r); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// IsAuthenticated はリクエストがログイン済み（またはトークン付き）かどうかを返す関数
+func IsAuthenticated(r *http.Request) bool {
+	return currentUser(r) != nil
+}
+
+// IsAdmin はリクエストのユーザーがサーバーの管理者かどうかを返す関数
+func IsAdmin(r *http.Request) bool {
+	user := currentUser(r)
+	return user != nil && user.IsAdmin
+}
+
+// UsesToken はリクエストをアクセストークンで認証したかどうかを返す関数
+// パスワードやセッションでのログインは本人の操作なので、自分のアクセストークンを管理できる
+func UsesToken(r *http.Request) bool {
+	token, _ := r.Context().Value(tokenContextKey).(bool)
+	return token
+}
+
+// HasScope はリクエストに required の権限が与えられているかどうかを返す関数
+// パスワードやセッションでログインした場合は read と write、トークンの場合はトークンの権限を持つ
+func HasScope(r *http.Request, required model.Scope) bool {
+	scopes, _ := r.Context().Value(scopesContextKey).([]model.Scope)
+	for _, s := range scopes {
+		if s.Includes(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthHandler はユーザー登録・ログイン・ログアウト・アクセストークンのHTTPリクエストを処理する構造体
+type AuthHandler struct {
+	userUsecase  usecase.UserUsecase  // アカウント機能のビジネスロジック
+	tokenUsecase usecase.TokenUsecase // 個人用アクセストークンのビジネスロジック
+}
+
+// NewAuthHandler は新しいAuthHandlerを作成する関数
+func NewAuthHandler(userUsecase usecase.UserUsecase, tokenUsecase usecase.TokenUsecase) *AuthHandler {
+	return &AuthHandler{userUsecase: userUsecase, tokenUsecase: tokenUsecase}
+}
+
+// LoginResponse はログイン成功時のレスポンス
+// tokenはCookieを使えないクライアント（スクリプトなど）が Authorization: Bearer で送るために返す
+type LoginResponse struct {
+	Token     string      `json:"token"`      // セッショントークン
+	ExpiresAt time.Time   `json:"expires_at"` // 有効期限
+	User      *model.User `json:"user"`       // ログインしたユーザー
+}
+
+// Register はユーザーを登録するHTTPハンドラ関数
+// POST /api/v1/auth/register のリクエストを処理
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req model.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	user, err := h.userUsecase.Register(&req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrSignupDisabled) {
+			status = http.StatusForbidden
+		}
+		writeErrorResponse(w, status, "ユーザー登録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "ユーザーを登録しました", user)
+}
+
+// Login はログインするHTTPハンドラ関数
+// POST /api/v1/auth/login のリクエストを処理
+// 成功するとセッショントークンをCookie（HttpOnly）に設定し、レスポンスでも返す
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req model.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	token, session, user, err := h.userUsecase.Login(&req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			status = http.StatusUnauthorized
+		}
+		writeErrorResponse(w, status, "ログインに失敗しました", err)
+		return
+	}
+
+	// HttpOnly：JavaScriptからCookieを読めないようにする（XSSでトークンを盗まれないため）
+	// SameSite=Lax：他のサイトからのPOSTリクエストにCookieを付けない（CSRF対策）
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	writeSuccessResponse(w, http.StatusOK, "ログインしました", LoginResponse{Token: token, ExpiresAt: session.ExpiresAt, User: user})
+}
+
+// Logout はログアウトするHTTPハンドラ関数
+// POST /api/v1/auth/logout のリクエストを処理
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if token := sessionToken(r); token != "" {
+		if err := h.userUsecase.Logout(token); err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, "ログアウトに失敗しました", err)
+			return
+		}
+	}
+	clearSessionCookie(w)
+	writeSuccessResponse(w, http.StatusOK, "ログアウトしました", nil)
+}
+
+// Me はログイン中のユーザーを返すHTTPハンドラ関数
+// GET /api/v1/auth/me のリクエストを処理
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", user)
+}
+
+// CreateToken は個人用アクセストークンを作成するHTTPハンドラ関数
+// POST /api/v1/auth/tokens のリクエストを処理
+// トークンはこのレスポンスでしか返さない（データベースにはハッシュだけを保存する）
+func (h *AuthHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	var req model.CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	created, err := h.tokenUsecase.Create(user.ID, &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "トークンの作成に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "トークンを作成しました（トークンは再表示できないため、安全な場所に保存してください）", created)
+}
+
+// ListTokens はログイン中のユーザーのトークン一覧を返すHTTPハンドラ関数
+// GET /api/v1/auth/tokens のリクエストを処理
+func (h *AuthHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	tokens, err := h.tokenUsecase.List(user.ID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "トークン一覧の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", tokens)
+}
+
+// RevokeToken はトークンを取り消すHTTPハンドラ関数
+// DELETE /api/v1/auth/tokens/{id} のリクエストを処理
+func (h *AuthHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なトークンIDです", err)
+		return
+	}
+	if err := h.tokenUsecase.Revoke(user.ID, id); err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "トークンの取り消しに失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "トークンを取り消しました", nil)
+}
+
+// Middleware はリクエストからログイン中のユーザーを特定するミドルウェア
+// 次の順番で確認し、見つかったユーザーと権限をリクエストのコンテキストに保存する
+//  1. Authorization: Basic（Cookieを使えないOPDSリーダー向け。パスワードの代わりにアクセストークンも使える）
+//  2. Authorization: Bearer <アクセストークン または セッショントークン>
+//  3. セッションCookie
+//
+// 認証情報がなければ未ログイン（共有の本棚、権限なし）として処理を続ける
+func (h *AuthHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var user *model.User
+		var scopes []model.Scope
+		var viaToken bool
+
+		if username, password, ok := r.BasicAuth(); ok {
+			u, s, err := h.authenticateBasic(username, password)
+			if err != nil {
+				// 明示的に送られた認証情報が間違っている場合は未ログインとして扱わずに拒否する
+				w.Header().Set("WWW-Authenticate", `Basic realm="book-manager", charset="UTF-8"`)
+				writeErrorResponse(w, http.StatusUnauthorized, "認証に失敗しました", err)
+				return
+			}
+			user, scopes, viaToken = u, s, usecase.IsAPIToken(password)
+		} else if token := bearerToken(r); usecase.IsAPIToken(token) {
+			u, apiToken, err := h.tokenUsecase.Authenticate(token)
+			if err != nil {
+				writeErrorResponse(w, http.StatusUnauthorized, "認証に失敗しました", err)
+				return
+			}
+			user, scopes, viaToken = u, apiToken.Scopes, true
+		} else if token := sessionToken(r); token != "" {
+			u, err := h.userUsecase.Authenticate(token)
+			switch {
+			case err == nil:
+				user, scopes = u, sessionScopes
+			case bearerToken(r) != "":
+				writeErrorResponse(w, http.StatusUnauthorized, "認証に失敗しました", err)
+				return
+			default:
+				// 期限切れのCookieは削除して未ログインとして続ける（再ログインできるように）
+				clearSessionCookie(w)
+			}
+		}
+
+		if user != nil {
+			ctx := context.WithValue(r.Context(), userContextKey, user)
+			ctx = context.WithValue(ctx, scopesContextKey, scopes)
+			r = r.WithContext(context.WithValue(ctx, tokenContextKey, viaToken))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticateBasic はBasic認証のユーザー名とパスワードからユーザーと権限を特定する
+// パスワードの代わりにアクセストークンが送られた場合は、トークンの持ち主とユーザー名が一致するかも確認する
+func (h *AuthHandler) authenticateBasic(username, password string) (*model.User, []model.Scope, error) {
+	if usecase.IsAPIToken(password) {
+		user, apiToken, err := h.tokenUsecase.Authenticate(password)
+		if err != nil {
+			return nil, nil, err
+		}
+		if user.Username != username {
+			return nil, nil, usecase.ErrInvalidCredentials
+		}
+		return user, apiToken.Scopes, nil
+	}
+
+	user, err := h.userUsecase.AuthenticatePassword(username, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, sessionScopes, nil
+}
+
+// RegisterRoutes はアカウントAPIのルートを登録する関数
+func (h *AuthHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/auth/register", h.Register).Methods("POST")         // ユーザー登録
+	router.HandleFunc("/auth/login", h.Login).Methods("POST")               // ログイン
+	router.HandleFunc("/auth/logout", h.Logout).Methods("POST")             // ログアウト
+	router.HandleFunc("/auth/me", h.Me).Methods("GET")                      // ログイン中のユーザー
+	router.HandleFunc("/auth/tokens", h.CreateToken).Methods("POST")        // アクセストークンの作成
+	router.HandleFunc("/auth/tokens", h.ListTokens).Methods("GET")          // アクセストークンの一覧
+	router.HandleFunc("/auth/tokens/{id}", h.RevokeToken).Methods("DELETE") // アクセストークンの取り消し
+}
+
+// sessionToken はリクエストからセッショントークンを取り出す（Bearerを優先し、なければCookie）
+func sessionToken(r *http.Request) string {
+	if token := bearerToken(r); token != "" {
+		return token
+	}
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// bearerToken は Authorization: Bearer <トークン> ヘッダーからトークンを取り出す
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// clearSessionCookie はセッションCookieを削除する
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http" // HTTPサーバー機能
+
+	"book-manager/internal/backup" // バックアップの作成・一覧
+	"github.com/gorilla/mux"       // URLルーティングライブラリ
+)
+
+// BackupHandler は管理用のバックアップAPIを処理する構造体
+// 復元はデータベースファイルを置き換えるため、サーバー停止中にCLI（book-manager restore）で行う
+type BackupHandler struct {
+	manager *backup.Manager // バックアップの作成・世代管理
+}
+
+// NewBackupHandler は新しいBackupHandlerを作成する関数
+func NewBackupHandler(manager *backup.Manager) *BackupHandler {
+	return &BackupHandler{manager: manager}
+}
+
+// CreateBackup はバックアップを作成するHTTPハンドラ関数
+// POST /api/v1/admin/backups のリクエストを処理
+func (h *BackupHandler) CreateBackup(w http.ResponseWriter, r *http.Request) {
+	info, err := h.manager.Create()
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "バックアップの作成に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "バックアップを作成しました", info)
+}
+
+// ListBackups はバックアップの一覧を返すHTTPハンドラ関数
+// GET /api/v1/admin/backups のリクエストを処理
+func (h *BackupHandler) ListBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.manager.List()
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "バックアップ一覧の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", backups)
+}
+
+// RegisterRoutes はバックアップAPIのルートを登録する関数
+func (h *BackupHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/admin/backups", h.CreateBackup).Methods("POST") // バックアップ作成
+	router.HandleFunc("/admin/backups", h.ListBackups).Methods("GET")   // バックアップ一覧
+}
@@ -5,7 +5,9 @@ package handler
 // import：他のパッケージ（機能）を使うための宣言
 import (
 	"encoding/json"                      // JSONデータのエンコード（変換）・デコード（解析）
+	"errors"                            // エラーの判定
 	"net/http"                          // HTTPサーバー機能（リクエスト・レスポンス処理）
+	"net/url"                           // URLクエリパラメータの型（url.Values）
 	"strconv"                           // 文字列と数値の変換（"123" → 123など）
 
 	"book-manager/internal/model"        // 自作のデータ構造定義
@@ -25,6 +27,12 @@ func NewBookHandler(bookUsecase usecase.BookUsecase) *BookHandler {
 	return &BookHandler{bookUsecase: bookUsecase} // ユースケースを設定したハンドラを返す
 }
 
+// library はリクエストしたユーザーの本棚を扱うユースケースを返す
+// ログインしていなければユーザー登録前からある共有の本棚になる
+func (h *BookHandler) library(r *http.Request) usecase.BookUsecase {
+	return h.bookUsecase.ForUser(currentUserID(r)).WithRequestID(RequestID(r))
+}
+
 // ErrorResponse はエラーレスポンスの構造体
 // エラー発生時にクライアントに返すJSONデータの形式
 type ErrorResponse struct {
@@ -63,7 +71,7 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ユースケースでビジネスロジックを実行（バリデーション、データ保存）
-	book, err := h.bookUsecase.CreateBook(&req)
+	book, err := h.library(r).CreateBook(&req)
 	if err != nil {
 		// ビジネスロジックエラーの場合は400 Bad Requestでエラーレスポンスを返す
 		h.sendErrorResponse(w, http.StatusBadRequest, "書籍の作成に失敗しました", err)
@@ -92,7 +100,7 @@ func (h *BookHandler) GetBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ユースケースで書籍情報を取得
-	book, err := h.bookUsecase.GetBook(id)
+	book, err := h.library(r).GetBook(id)
 	if err != nil {
 		// 書籍が見つからない場合は404 Not Found
 		h.sendErrorResponse(w, http.StatusNotFound, "書籍が見つかりません", err)
@@ -123,6 +131,44 @@ func (h *BookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// フィルター条件を構築（検索、絞り込み条件）
+	filter := parseBookFilter(query)
+
+	// ユースケースで書籍一覧を取得（フィルター、ページング付き）
+	books, total, err := h.library(r).ListBooks(filter, page, limit)
+	if err != nil {
+		// サーバー内部エラーの場合は500 Internal Server Error
+		h.sendErrorResponse(w, http.StatusInternalServerError, "書籍一覧の取得に失敗しました", err)
+		return
+	}
+
+	// 総ページ数を計算（割り算の切り上げ）
+	// (total + limit - 1) / limit：切り上げ除算のテクニック
+	totalPages := (total + limit - 1) / limit
+	// ページング情報を含むレスポンスを構築
+	response := ListBooksResponse{
+		Books:      books,      // 書籍データの配列
+		Total:      total,      // 総件数
+		Page:       page,       // 現在ページ
+		Limit:      limit,      // 1ページあたりの件数
+		TotalPages: totalPages, // 総ページ数
+	}
+
+	// 成功時は200 OKでページング情報付き一覧を返す
+	h.sendSuccessResponse(w, http.StatusOK, "", response)
+}
+
+// errorStatus はエラーに応じたHTTPステータスコードを返す関数
+// 共有された書籍を役割で許可されていない方法で操作した場合は403 Forbidden、それ以外は status を返す
+func errorStatus(err error, status int) int {
+	if errors.Is(err, usecase.ErrPermissionDenied) {
+		return http.StatusForbidden
+	}
+	return status
+}
+
+// parseBookFilter はURLクエリパラメータから書籍の絞り込み条件を作る関数
+// 書籍一覧だけでなく、引用形式のエクスポートなど一覧系の処理で共通して使う
+func parseBookFilter(query url.Values) *model.BookFilter {
 	filter := &model.BookFilter{}
 
 	// 各種フィルターパラメータをチェックして設定
@@ -149,6 +195,16 @@ func (h *BookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
 		filter.Search = &search  // タイトル・著者の部分一致検索
 	}
 
+	// 貸し出し中かどうか（on_loan=true / on_loan=false）
+	if onLoan, err := strconv.ParseBool(query.Get("on_loan")); err == nil {
+		filter.OnLoan = &onLoan
+	}
+
+	// 保管場所（location_id=3 なら場所3とその下の場所にある書籍）
+	if locationID, err := strconv.Atoi(query.Get("location_id")); err == nil {
+		filter.LocationID = &locationID
+	}
+
 	// 評価パラメータは数値バリデーションが必要
 	if ratingStr := query.Get("rating"); ratingStr != "" {
 		// 数値変換と範囲チェック（1-5の範囲内のみ有効）
@@ -157,28 +213,7 @@ func (h *BookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// ユースケースで書籍一覧を取得（フィルター、ページング付き）
-	books, total, err := h.bookUsecase.ListBooks(filter, page, limit)
-	if err != nil {
-		// サーバー内部エラーの場合は500 Internal Server Error
-		h.sendErrorResponse(w, http.StatusInternalServerError, "書籍一覧の取得に失敗しました", err)
-		return
-	}
-
-	// 総ページ数を計算（割り算の切り上げ）
-	// (total + limit - 1) / limit：切り上げ除算のテクニック
-	totalPages := (total + limit - 1) / limit
-	// ページング情報を含むレスポンスを構築
-	response := ListBooksResponse{
-		Books:      books,      // 書籍データの配列
-		Total:      total,      // 総件数
-		Page:       page,       // 現在ページ
-		Limit:      limit,      // 1ページあたりの件数
-		TotalPages: totalPages, // 総ページ数
-	}
-
-	// 成功時は200 OKでページング情報付き一覧を返す
-	h.sendSuccessResponse(w, http.StatusOK, "", response)
+	return filter
 }
 
 // UpdateBook は書籍情報を更新するHTTPハンドラ関数
@@ -203,9 +238,9 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ユースケースで書籍情報を更新
-	book, err := h.bookUsecase.UpdateBook(id, &req)
+	book, err := h.library(r).UpdateBook(id, &req)
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusBadRequest, "書籍の更新に失敗しました", err)
+		h.sendErrorResponse(w, errorStatus(err, http.StatusBadRequest), "書籍の更新に失敗しました", err)
 		return
 	}
 
@@ -228,14 +263,14 @@ func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ユースケースで書籍を削除
-	if err := h.bookUsecase.DeleteBook(id); err != nil {
+	if err := h.library(r).DeleteBook(id); err != nil {
 		// 書籍が見つからないまたは削除失敗の場合は404 Not Found
-		h.sendErrorResponse(w, http.StatusNotFound, "書籍の削除に失敗しました", err)
+		h.sendErrorResponse(w, errorStatus(err, http.StatusNotFound), "書籍の削除に失敗しました", err)
 		return
 	}
 
 	// 成功時は200 OKでメッセージを返す（データはnil）
-	h.sendSuccessResponse(w, http.StatusOK, "書籍が正常に削除されました", nil)
+	h.sendSuccessResponse(w, http.StatusOK, "書籍をゴミ箱に移しました", nil)
 }
 
 // StartReading は読書を開始するHTTPハンドラ関数
@@ -253,10 +288,10 @@ func (h *BookHandler) StartReading(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ユースケースで読書を開始（ステータスを読書中に変更）
-	book, err := h.bookUsecase.StartReading(id)
+	book, err := h.library(r).StartReading(id)
 	if err != nil {
 		// ビジネスルールエラー（既に読書中など）の場合は400 Bad Request
-		h.sendErrorResponse(w, http.StatusBadRequest, "読書開始に失敗しました", err)
+		h.sendErrorResponse(w, errorStatus(err, http.StatusBadRequest), "読書開始に失敗しました", err)
 		return
 	}
 
@@ -293,10 +328,10 @@ func (h *BookHandler) FinishReading(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ユースケースで読書を完了（ステータスを完了に変更、評価設定）
-	book, err := h.bookUsecase.FinishReading(id, reqBody.Rating)
+	book, err := h.library(r).FinishReading(id, reqBody.Rating)
 	if err != nil {
 		// ビジネスルールエラー（読書中でないなど）の場合は400 Bad Request
-		h.sendErrorResponse(w, http.StatusBadRequest, "読書完了に失敗しました", err)
+		h.sendErrorResponse(w, errorStatus(err, http.StatusBadRequest), "読書完了に失敗しました", err)
 		return
 	}
 
@@ -308,7 +343,7 @@ func (h *BookHandler) FinishReading(w http.ResponseWriter, r *http.Request) {
 // GET /api/v1/statistics のリクエストを処理
 func (h *BookHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
 	// ユースケースで統計情報を取得（合計金額、平均評価など）
-	stats, err := h.bookUsecase.GetStatistics()
+	stats, err := h.library(r).GetStatistics()
 	if err != nil {
 		// サーバー内部エラーの場合は500 Internal Server Error
 		h.sendErrorResponse(w, http.StatusInternalServerError, "統計情報の取得に失敗しました", err)
@@ -332,37 +367,13 @@ func (h *BookHandler) Health(w http.ResponseWriter, r *http.Request) {
 // sendErrorResponse はエラーレスポンスを送信するヘルパー関数
 // 共通のエラー処理をまとめて、コードの重複を防ぐ
 func (h *BookHandler) sendErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
-	// HTTPレスポンスヘッダーを設定（JSON形式で返すことを明示）
-	w.Header().Set("Content-Type", "application/json")
-	// HTTPステータスコードを設定（400, 404, 500など）
-	w.WriteHeader(statusCode)
-
-	// エラーレスポンス構造体を作成
-	response := ErrorResponse{
-		Error:   message,    // ユーザー向けエラーメッセージ
-		Message: err.Error(), // 詳細なエラー内容（デバッグ用）
-	}
-
-	// JSON形式でレスポンスを送信
-	json.NewEncoder(w).Encode(response)
+	writeErrorResponse(w, statusCode, message, err)
 }
 
 // sendSuccessResponse は成功レスポンスを送信するヘルパー関数
 // 共通の成功処理をまとめて、コードの重複を防ぐ
 func (h *BookHandler) sendSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
-	// HTTPレスポンスヘッダーを設定（JSON形式で返すことを明示）
-	w.Header().Set("Content-Type", "application/json")
-	// HTTPステータスコードを設定（200, 201など）
-	w.WriteHeader(statusCode)
-
-	// 成功レスポンス構造体を作成
-	response := SuccessResponse{
-		Message: message, // 成功メッセージ
-		Data:    data,    // 実際のデータ（interface{}は任意の型を受け入れる）
-	}
-
-	// JSON形式でレスポンスを送信
-	json.NewEncoder(w).Encode(response)
+	writeSuccessResponse(w, statusCode, message, data)
 }
 
 // RegisterRoutes はHTTPルートを登録する関数
@@ -381,6 +392,10 @@ func (h *BookHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/books/{id:[0-9]+}/start-reading", h.StartReading).Methods("POST")   // 読書開始
 	router.HandleFunc("/books/{id:[0-9]+}/finish-reading", h.FinishReading).Methods("POST") // 読書完了
 
+	// 引用形式でのエクスポート（BibTeX、RIS、CSL-JSON、APA、SIST 02）
+	router.HandleFunc("/books/citations", h.ExportCitations).Methods("GET")                // 一括エクスポート
+	router.HandleFunc("/books/{id:[0-9]+}/citation", h.GetCitation).Methods("GET")        // 1冊分の引用
+
 	// 統計情報取得
 	router.HandleFunc("/statistics", h.GetStatistics).Methods("GET")  // 書籍統計情報
 
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // 年・月の変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// BudgetHandler は予算のHTTPリクエストを処理する構造体
+type BudgetHandler struct {
+	budgetUsecase usecase.BudgetUsecase // 予算のビジネスロジック
+}
+
+// NewBudgetHandler は新しいBudgetHandlerを作成する関数
+func NewBudgetHandler(budgetUsecase usecase.BudgetUsecase) *BudgetHandler {
+	return &BudgetHandler{budgetUsecase: budgetUsecase}
+}
+
+// GetBudget は予算と実際の支出の報告を返すHTTPハンドラ関数
+// GET /api/v1/budget?year=2026&month=10 のリクエストを処理（year・month を省略すると今月）
+func (h *BudgetHandler) GetBudget(w http.ResponseWriter, r *http.Request) {
+	var period [2]int // 年・月（0は省略）
+	for i, name := range []string{"year", "month"} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, name+" は数値で指定してください", err)
+			return
+		}
+		period[i] = n
+	}
+
+	report, err := h.budgetUsecase.Report(currentUserID(r), period[0], period[1])
+	if err != nil {
+		writeErrorResponse(w, statsErrorStatus(err), "予算の報告の作成に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", report)
+}
+
+// SetBudget は毎月または毎年の予算を設定するHTTPハンドラ関数
+// PUT /api/v1/budget/{period} のリクエストを処理（period は month か year）
+// リクエスト例：{"amount": 5000}
+func (h *BudgetHandler) SetBudget(w http.ResponseWriter, r *http.Request) {
+	var req model.SetBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	budget, err := h.budgetUsecase.Set(currentUserID(r), model.BudgetPeriod(mux.Vars(r)["period"]), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "予算の設定に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "予算を設定しました", budget)
+}
+
+// DeleteBudget は予算を削除するHTTPハンドラ関数
+// DELETE /api/v1/budget/{period} のリクエストを処理
+func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	if err := h.budgetUsecase.Delete(currentUserID(r), model.BudgetPeriod(mux.Vars(r)["period"])); err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "予算の削除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "予算を削除しました", nil)
+}
+
+// RegisterRoutes は予算APIのルートを登録する関数
+func (h *BudgetHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/budget", h.GetBudget).Methods("GET")                           // 予算と実際の支出の報告
+	router.HandleFunc("/budget/{period:month|year}", h.SetBudget).Methods("PUT")       // 予算の設定
+	router.HandleFunc("/budget/{period:month|year}", h.DeleteBudget).Methods("DELETE") // 予算の削除
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"net/http"      // HTTPサーバー機能
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// BulkHandler は複数の書籍にまとめて行う操作のHTTPリクエストを処理する構造体
+type BulkHandler struct {
+	bulkUsecase usecase.BulkUsecase // まとめての操作のビジネスロジック
+}
+
+// NewBulkHandler は新しいBulkHandlerを作成する関数
+func NewBulkHandler(bulkUsecase usecase.BulkUsecase) *BulkHandler {
+	return &BulkHandler{bulkUsecase: bulkUsecase}
+}
+
+// ApplyBulk は書籍にまとめて操作を行うHTTPハンドラ関数
+// POST /api/v1/books/bulk のリクエストを処理
+// リクエスト例：{"ids": [3, 7], "operation": "add_tag", "tag": "積読"}
+// 1冊でも操作できなかった場合は、どの書籍も変更せずに 422 と書籍ごとの結果を返す
+func (h *BulkHandler) ApplyBulk(w http.ResponseWriter, r *http.Request) {
+	var req model.BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	result, err := h.bulkUsecase.Apply(currentUserID(r), RequestID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "まとめての操作に失敗しました", err)
+		return
+	}
+	switch {
+	case result.Failed > 0:
+		writeSuccessResponse(w, http.StatusUnprocessableEntity, "操作できない書籍があったため、どの書籍も変更しませんでした", result)
+	case result.DryRun:
+		writeSuccessResponse(w, http.StatusOK, "確認だけを行い、変更は取り消しました", result)
+	default:
+		writeSuccessResponse(w, http.StatusOK, "書籍をまとめて操作しました", result)
+	}
+}
+
+// RegisterRoutes はまとめての操作のAPIのルートを登録する関数
+func (h *BulkHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/books/bulk", h.ApplyBulk).Methods("POST") // 書籍のまとめての操作
+}
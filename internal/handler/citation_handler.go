@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"      // 文字列フォーマット
+	"net/http" // HTTPサーバー機能
+	"strconv"  // 文字列と数値の変換
+	"strings"  // 文字列操作
+
+	"book-manager/internal/citation" // 引用形式への変換
+	"book-manager/internal/model"    // 自作のデータ構造定義
+	"book-manager/internal/usecase"  // 自作のビジネスロジック層
+	"github.com/gorilla/mux"         // URLルーティングライブラリ
+)
+
+// exportPageSize は一括エクスポート時に1回で取得する件数（ListBooksの上限に合わせる）
+const exportPageSize = 100
+
+// GetCitation は1冊分の引用データを返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/citation?format=bibtex のリクエストを処理
+// format：bibtex（デフォルト）、ris、csl-json、apa、sist02
+func (h *BookHandler) GetCitation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	format, err := citation.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "引用形式が無効です", err)
+		return
+	}
+
+	book, err := h.library(r).GetBook(id)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusNotFound, "書籍が見つかりません", err)
+		return
+	}
+
+	h.sendCitation(w, format, []*model.Book{book}, citation.Key(book))
+}
+
+// ExportCitations は複数の書籍の引用データをまとめて返すHTTPハンドラ関数
+// GET /api/v1/books/citations?format=bibtex&ids=1,2,3 のリクエストを処理
+// idsを省略した場合は、書籍一覧と同じ絞り込み条件（status、tagなど）に一致する全書籍を出力する
+func (h *BookHandler) ExportCitations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format, err := citation.ParseFormat(query.Get("format"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "引用形式が無効です", err)
+		return
+	}
+
+	var books []*model.Book
+	if idsParam := query.Get("ids"); idsParam != "" {
+		// ID指定：指定された順番で1冊ずつ取得
+		for _, idStr := range strings.Split(idsParam, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(idStr))
+			if err != nil {
+				h.sendErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+				return
+			}
+			book, err := h.library(r).GetBook(id)
+			if err != nil {
+				h.sendErrorResponse(w, http.StatusNotFound, "書籍が見つかりません", err)
+				return
+			}
+			books = append(books, book)
+		}
+	} else {
+		// 絞り込み条件：ページを順にたどって全件を集める
+		books, err = listAllBooks(h.library(r), parseBookFilter(query))
+		if err != nil {
+			h.sendErrorResponse(w, http.StatusInternalServerError, "書籍一覧の取得に失敗しました", err)
+			return
+		}
+	}
+
+	h.sendCitation(w, format, books, "books")
+}
+
+// listAllBooks は絞り込み条件に一致する書籍をページングしながら全件取得する関数
+// 引用エクスポートやOPDSカタログなど、一覧を丸ごと扱う処理で共通して使う
+func listAllBooks(bookUsecase usecase.BookUsecase, filter *model.BookFilter) ([]*model.Book, error) {
+	all := []*model.Book{}
+	for page := 1; ; page++ {
+		books, total, err := bookUsecase.ListBooks(filter, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, books...)
+		// 取得件数が総件数に達したか、空のページが返ったら終了
+		if len(books) == 0 || len(all) >= total {
+			return all, nil
+		}
+	}
+}
+
+// sendCitation は引用データをファイルとして送信するヘルパー関数
+// JSONのレスポンス形式ではなく、文献管理ソフトにそのまま読み込める形式で返す
+func (h *BookHandler) sendCitation(w http.ResponseWriter, format citation.Format, books []*model.Book, filename string) {
+	body, err := citation.Render(format, books)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, "引用データの作成に失敗しました", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	// inline：ブラウザで直接表示しつつ、保存時のファイル名も指定する
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.%s"`, filename, format.Extension()))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body))
+}
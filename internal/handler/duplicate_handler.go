@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // 重複らしさの下限の変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// DuplicateHandler は重複した書籍の検出と統合のHTTPリクエストを処理する構造体
+type DuplicateHandler struct {
+	duplicateUsecase usecase.DuplicateUsecase // 重複の検出と統合のビジネスロジック
+}
+
+// NewDuplicateHandler は新しいDuplicateHandlerを作成する関数
+func NewDuplicateHandler(duplicateUsecase usecase.DuplicateUsecase) *DuplicateHandler {
+	return &DuplicateHandler{duplicateUsecase: duplicateUsecase}
+}
+
+// ListDuplicates は重複の候補の組を返すHTTPハンドラ関数
+// GET /api/v1/duplicates?min_score=0.85 のリクエストを処理（min_score を省略すると 0.85）
+func (h *DuplicateHandler) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	minScore := usecase.DefaultDuplicateScore
+	if value := r.URL.Query().Get("min_score"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "min_score は数値で指定してください", err)
+			return
+		}
+		minScore = score
+	}
+
+	candidates, err := h.duplicateUsecase.Find(currentUserID(r), minScore)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "重複の検出に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", candidates)
+}
+
+// MergeBooks は2冊の書籍を1冊に統合するHTTPハンドラ関数
+// POST /api/v1/books/merge のリクエストを処理
+// リクエスト例：{"target_id": 3, "source_id": 7, "fields": {"title": "source", "notes": "both"}}
+func (h *DuplicateHandler) MergeBooks(w http.ResponseWriter, r *http.Request) {
+	var req model.MergeBooksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	result, err := h.duplicateUsecase.Merge(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusBadRequest), "書籍の統合に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "書籍を統合しました", result)
+}
+
+// RegisterRoutes は重複の検出と統合のAPIのルートを登録する関数
+func (h *DuplicateHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/duplicates", h.ListDuplicates).Methods("GET") // 重複の候補の一覧
+	router.HandleFunc("/books/merge", h.MergeBooks).Methods("POST")   // 書籍の統合
+}
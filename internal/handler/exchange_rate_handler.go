@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // IDの変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// maxExchangeRateCSVSize は取り込めるCSVファイルの最大サイズ（10MB）
+const maxExchangeRateCSVSize = 10 << 20
+
+// ExchangeRateHandler は為替レートのHTTPリクエストを処理する構造体
+type ExchangeRateHandler struct {
+	rateUsecase usecase.ExchangeRateUsecase // 為替レートのビジネスロジック
+}
+
+// NewExchangeRateHandler は新しいExchangeRateHandlerを作成する関数
+func NewExchangeRateHandler(rateUsecase usecase.ExchangeRateUsecase) *ExchangeRateHandler {
+	return &ExchangeRateHandler{rateUsecase: rateUsecase}
+}
+
+// ListRates は基準通貨へのレートの一覧を返すHTTPハンドラ関数
+// GET /api/v1/exchange-rates?currency=USD のリクエストを処理（currency を省略するとすべての通貨）
+func (h *ExchangeRateHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.rateUsecase.List(r.URL.Query().Get("currency"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "為替レート一覧の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", map[string]interface{}{
+		"base":  h.rateUsecase.BaseCurrency(),
+		"rates": rates,
+	})
+}
+
+// SetRate は為替レートを登録するHTTPハンドラ関数
+// POST /api/v1/exchange-rates のリクエストを処理（同じ通貨・日付のレートは置き換える）
+// リクエスト例：{"currency": "USD", "date": "2026-10-01T00:00:00Z", "rate": 149.5}
+func (h *ExchangeRateHandler) SetRate(w http.ResponseWriter, r *http.Request) {
+	var req model.SetExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	rate, err := h.rateUsecase.Set(&req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "為替レートの登録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "為替レートを登録しました", rate)
+}
+
+// ImportRates はCSVファイルから為替レートを取り込むHTTPハンドラ関数
+// POST /api/v1/exchange-rates/import のリクエストを処理（ボディはCSV：date,currency,rate）
+func (h *ExchangeRateHandler) ImportRates(w http.ResponseWriter, r *http.Request) {
+	result, err := h.rateUsecase.ImportCSV(http.MaxBytesReader(w, r.Body, maxExchangeRateCSVSize))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "為替レートの取り込みに失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "為替レートを取り込みました", result)
+}
+
+// DeleteRate は為替レートを削除するHTTPハンドラ関数
+// DELETE /api/v1/exchange-rates/{id} のリクエストを処理
+func (h *ExchangeRateHandler) DeleteRate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な為替レートIDです", err)
+		return
+	}
+
+	if err := h.rateUsecase.Delete(id); err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "為替レートの削除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "為替レートを削除しました", nil)
+}
+
+// RegisterRoutes は為替レートAPIのルートを登録する関数
+func (h *ExchangeRateHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/exchange-rates", h.ListRates).Methods("GET")                 // レートの一覧
+	router.HandleFunc("/exchange-rates", h.SetRate).Methods("POST")                  // レートの登録
+	router.HandleFunc("/exchange-rates/import", h.ImportRates).Methods("POST")       // CSVファイルからの取り込み
+	router.HandleFunc("/exchange-rates/{id:[0-9]+}", h.DeleteRate).Methods("DELETE") // レートの削除
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // URLのIDの変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// GoalHandler は読書目標のHTTPリクエストを処理する構造体
+type GoalHandler struct {
+	goalUsecase usecase.GoalUsecase // 読書目標のビジネスロジック
+}
+
+// NewGoalHandler は新しいGoalHandlerを作成する関数
+func NewGoalHandler(goalUsecase usecase.GoalUsecase) *GoalHandler {
+	return &GoalHandler{goalUsecase: goalUsecase}
+}
+
+// CreateGoal は読書目標を作成するHTTPハンドラ関数
+// POST /api/v1/goals のリクエストを処理
+// リクエスト例：{"metric": "books", "target": 50, "period": "year", "year": 2026, "tag": "チーム課題"}
+func (h *GoalHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	progress, err := h.goalUsecase.Create(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "読書目標の作成に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "読書目標を作成しました", progress)
+}
+
+// ListGoals は読書目標と達成状況の一覧を返すHTTPハンドラ関数
+// GET /api/v1/goals のリクエストを処理
+func (h *GoalHandler) ListGoals(w http.ResponseWriter, r *http.Request) {
+	progress, err := h.goalUsecase.List(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "読書目標一覧の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", progress)
+}
+
+// GetGoal は読書目標の達成状況を返すHTTPハンドラ関数
+// GET /api/v1/goals/{id} のリクエストを処理
+func (h *GoalHandler) GetGoal(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な読書目標IDです", err)
+		return
+	}
+
+	progress, err := h.goalUsecase.Progress(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "読書目標が見つかりません", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", progress)
+}
+
+// DeleteGoal は読書目標を削除するHTTPハンドラ関数
+// DELETE /api/v1/goals/{id} のリクエストを処理
+func (h *GoalHandler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な読書目標IDです", err)
+		return
+	}
+
+	if err := h.goalUsecase.Delete(currentUserID(r), id); err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "読書目標の削除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "読書目標を削除しました", nil)
+}
+
+// RegisterRoutes は読書目標APIのルートを登録する関数
+func (h *GoalHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/goals", h.CreateGoal).Methods("POST")               // 目標の作成
+	router.HandleFunc("/goals", h.ListGoals).Methods("GET")                 // 目標と達成状況の一覧
+	router.HandleFunc("/goals/{id:[0-9]+}", h.GetGoal).Methods("GET")       // 目標の達成状況
+	router.HandleFunc("/goals/{id:[0-9]+}", h.DeleteGoal).Methods("DELETE") // 目標の削除
+}
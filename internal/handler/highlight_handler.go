@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"fmt"           // ファイル名の作成
+	"io"            // 取り込むファイルの受信
+	"net/http"      // HTTPサーバー機能
+	"net/url"       // ファイル名のエンコード
+	"os"            // Kobo のデータベースの一時ファイル
+	"strconv"       // URLのIDの変換
+
+	"book-manager/internal/markdown" // Markdownのファイル名とContent-Type
+	"book-manager/internal/model"    // 自作のデータ構造定義
+	"book-manager/internal/usecase"  // 自作のビジネスロジック層
+	"github.com/gorilla/mux"         // URLルーティングライブラリ
+)
+
+// 取り込めるファイルの最大サイズ
+const (
+	maxKindleClippingsSize = 64 << 20  // My Clippings.txt（64MB）
+	maxKoboDatabaseSize    = 256 << 20 // KoboReader.sqlite（256MB。書籍の情報も含むため大きい）
+)
+
+// HighlightHandler はハイライト・引用のHTTPリクエストを処理する構造体
+type HighlightHandler struct {
+	highlightUsecase usecase.HighlightUsecase // ハイライトのビジネスロジック
+}
+
+// NewHighlightHandler は新しいHighlightHandlerを作成する関数
+func NewHighlightHandler(highlightUsecase usecase.HighlightUsecase) *HighlightHandler {
+	return &HighlightHandler{highlightUsecase: highlightUsecase}
+}
+
+// SearchHighlightsResponse はハイライトの検索結果のレスポンス構造体
+type SearchHighlightsResponse struct {
+	Highlights []*model.Highlight `json:"highlights"`  // ハイライト（新しい順）
+	Total      int                `json:"total"`       // 総件数
+	Page       int                `json:"page"`        // 現在のページ番号
+	Limit      int                `json:"limit"`       // 1ページあたりの件数
+	TotalPages int                `json:"total_pages"` // 総ページ数
+}
+
+// highlightIDs はURLの書籍IDとハイライトIDを取り出す関数
+func highlightIDs(r *http.Request) (bookID, id int, err error) {
+	vars := mux.Vars(r)
+	if bookID, err = strconv.Atoi(vars["id"]); err != nil {
+		return 0, 0, err
+	}
+	if id, err = strconv.Atoi(vars["highlightID"]); err != nil {
+		return 0, 0, err
+	}
+	return bookID, id, nil
+}
+
+// CreateHighlight はハイライトを登録するHTTPハンドラ関数
+// POST /api/v1/books/{id}/highlights のリクエストを処理
+// リクエスト例：{"text": "本文", "page": 42, "chapter": "第3章", "tags": "設計,名言"}
+func (h *HighlightHandler) CreateHighlight(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	var req model.CreateHighlightRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	highlight, err := h.highlightUsecase.Create(currentUserID(r), bookID, &req)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusBadRequest), "ハイライトの登録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "ハイライトを登録しました", highlight)
+}
+
+// ListHighlights は書籍のハイライトの一覧をページ順に返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/highlights のリクエストを処理
+func (h *HighlightHandler) ListHighlights(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	highlights, err := h.highlightUsecase.List(currentUserID(r), bookID)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "ハイライトの取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", highlights)
+}
+
+// GetHighlight はハイライトを1件返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/highlights/{highlightID} のリクエストを処理
+func (h *HighlightHandler) GetHighlight(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := highlightIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	highlight, err := h.highlightUsecase.Get(currentUserID(r), bookID, id)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "ハイライトが見つかりません", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", highlight)
+}
+
+// UpdateHighlight はハイライトを更新するHTTPハンドラ関数
+// PUT /api/v1/books/{id}/highlights/{highlightID} のリクエストを処理（送った項目だけを更新する）
+func (h *HighlightHandler) UpdateHighlight(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := highlightIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	var req model.UpdateHighlightRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	highlight, err := h.highlightUsecase.Update(currentUserID(r), bookID, id, &req)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusBadRequest), "ハイライトの更新に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "ハイライトを更新しました", highlight)
+}
+
+// DeleteHighlight はハイライトを削除するHTTPハンドラ関数
+// DELETE /api/v1/books/{id}/highlights/{highlightID} のリクエストを処理
+func (h *HighlightHandler) DeleteHighlight(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := highlightIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	if err := h.highlightUsecase.Delete(currentUserID(r), bookID, id); err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "ハイライトの削除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "ハイライトを削除しました", nil)
+}
+
+// ExportMarkdown は書籍のハイライトをMarkdownのファイルとして返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/highlights/markdown のリクエストを処理
+func (h *HighlightHandler) ExportMarkdown(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	book, body, err := h.highlightUsecase.Markdown(currentUserID(r), bookID)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "ハイライトの書き出しに失敗しました", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", markdown.ContentType)
+	// ファイル名は日本語のタイトルになるため、RFC 5987 の形式（filename*）でも指定する
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="highlights-%d.md"; filename*=UTF-8''%s`,
+		book.ID, url.PathEscape(markdown.Filename(book))))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body))
+}
+
+// SearchHighlights は自分の本棚のハイライトを検索するHTTPハンドラ関数
+// GET /api/v1/highlights?q=設計 原則&tag=名言&kind=quote&book_id=1&page=1&limit=20 のリクエストを処理
+// q は空白で区切った語をすべて含むハイライトを探す（本文・メモ・章・タグ・書籍のタイトルが対象）
+func (h *HighlightHandler) SearchHighlights(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &model.HighlightFilter{Query: query.Get("q"), Tag: query.Get("tag"), Kind: model.HighlightKind(query.Get("kind"))}
+	if value := query.Get("book_id"); value != "" {
+		bookID, err := strconv.Atoi(value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+			return
+		}
+		filter.BookID = bookID
+	}
+	page, limit := parsePagination(query)
+
+	highlights, total, err := h.highlightUsecase.Search(currentUserID(r), filter, page, limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ハイライトの検索に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", SearchHighlightsResponse{
+		Highlights: highlights,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// ImportKindle は Kindle の My Clippings.txt のハイライトを取り込むHTTPハンドラ関数
+// POST /api/v1/highlights/import/kindle?dry_run=true のリクエストを処理（リクエストボディにファイルの中身をそのまま送る）
+// dry_run=true なら保存せずに、取り込める件数と書籍に結びつかなかったタイトルだけを返す
+func (h *HighlightHandler) ImportKindle(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	result, err := h.highlightUsecase.ImportKindle(currentUserID(r), http.MaxBytesReader(w, r.Body, maxKindleClippingsSize), dryRun)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ハイライトの取り込みに失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, importMessage(result), result)
+}
+
+// ImportKobo は Kobo の KoboReader.sqlite のハイライトを取り込むHTTPハンドラ関数
+// POST /api/v1/highlights/import/kobo?dry_run=true のリクエストを処理（リクエストボディにデータベースファイルをそのまま送る）
+// SQLiteはファイルからしか開けないため、受け取った内容を一時ファイルに書き出してから読み込む
+func (h *HighlightHandler) ImportKobo(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	tmp, err := os.CreateTemp("", "kobo-*.sqlite")
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "一時ファイルの作成に失敗しました", err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, http.MaxBytesReader(w, r.Body, maxKoboDatabaseSize))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "KoboReader.sqlite の受信に失敗しました", err)
+		return
+	}
+
+	result, err := h.highlightUsecase.ImportKobo(currentUserID(r), tmp.Name(), dryRun)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ハイライトの取り込みに失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, importMessage(result), result)
+}
+
+// importMessage は取り込み結果のメッセージを作る関数
+func importMessage(result *model.HighlightImport) string {
+	if result.DryRun {
+		return fmt.Sprintf("%d件のハイライトを取り込めます（確認のみ）", result.Imported)
+	}
+	return fmt.Sprintf("%d件のハイライトを取り込みました", result.Imported)
+}
+
+// RegisterRoutes はハイライトAPIのルートを登録する関数
+func (h *HighlightHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/books/{id:[0-9]+}/highlights", h.ListHighlights).Methods("GET")                          // 書籍のハイライトの一覧
+	router.HandleFunc("/books/{id:[0-9]+}/highlights", h.CreateHighlight).Methods("POST")                        // ハイライトの登録
+	router.HandleFunc("/books/{id:[0-9]+}/highlights/markdown", h.ExportMarkdown).Methods("GET")                 // Markdownへの書き出し
+	router.HandleFunc("/books/{id:[0-9]+}/highlights/{highlightID:[0-9]+}", h.GetHighlight).Methods("GET")       // ハイライトの取得
+	router.HandleFunc("/books/{id:[0-9]+}/highlights/{highlightID:[0-9]+}", h.UpdateHighlight).Methods("PUT")    // ハイライトの更新
+	router.HandleFunc("/books/{id:[0-9]+}/highlights/{highlightID:[0-9]+}", h.DeleteHighlight).Methods("DELETE") // ハイライトの削除
+	router.HandleFunc("/highlights", h.SearchHighlights).Methods("GET")                                          // 本棚のハイライトの検索
+	router.HandleFunc("/highlights/import/kindle", h.ImportKindle).Methods("POST")                               // Kindle の My Clippings.txt の取り込み
+	router.HandleFunc("/highlights/import/kobo", h.ImportKobo).Methods("POST")                                   // Kobo の KoboReader.sqlite の取り込み
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"errors"        // エラーの種類の判定
+	"io"            // 空のリクエストボディの判定
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // URLのIDの変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// LoanHandler は書籍の貸し出しのHTTPリクエストを処理する構造体
+// 現在の貸し出しは書籍のレスポンス（loan）にも含まれる
+type LoanHandler struct {
+	loanUsecase usecase.LoanUsecase // 貸し出しのビジネスロジック
+}
+
+// NewLoanHandler は新しいLoanHandlerを作成する関数
+func NewLoanHandler(loanUsecase usecase.LoanUsecase) *LoanHandler {
+	return &LoanHandler{loanUsecase: loanUsecase}
+}
+
+// loanErrorStatus は貸し出しのエラーに対応するHTTPステータスコードを返す関数
+// 貸し出し中の書籍の貸し出し・貸し出し中でない書籍の返却は、書籍の状態と矛盾するため 409 Conflict にする
+func loanErrorStatus(err error, status int) int {
+	if errors.Is(err, usecase.ErrAlreadyOnLoan) || errors.Is(err, usecase.ErrNotOnLoan) {
+		return http.StatusConflict
+	}
+	return errorStatus(err, status)
+}
+
+// LendBook は書籍を貸し出すHTTPハンドラ関数
+// POST /api/v1/books/{id}/lend のリクエストを処理
+// リクエスト例：{"borrower_name": "山田さん", "due_at": "2024-02-01T00:00:00Z"}
+func (h *LoanHandler) LendBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	var req model.LendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	loan, err := h.loanUsecase.Lend(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, loanErrorStatus(err, http.StatusBadRequest), "貸し出しに失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "貸し出しました", loan)
+}
+
+// ReturnBook は書籍の返却を記録するHTTPハンドラ関数
+// POST /api/v1/books/{id}/return のリクエストを処理
+// リクエストボディは省略でき、その場合は現在時刻で返却を記録する
+func (h *LoanHandler) ReturnBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	var req model.ReturnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	loan, err := h.loanUsecase.Return(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, loanErrorStatus(err, http.StatusBadRequest), "返却の記録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "返却を記録しました", loan)
+}
+
+// ListBookLoans は書籍の貸し出し履歴を返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/loans のリクエストを処理
+func (h *LoanHandler) ListBookLoans(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	loans, err := h.loanUsecase.History(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "貸し出し履歴の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", loans)
+}
+
+// ListLoans は自分の本棚の貸し出し中の一覧を返却期限の近い順に返すHTTPハンドラ関数
+// GET /api/v1/loans のリクエストを処理
+func (h *LoanHandler) ListLoans(w http.ResponseWriter, r *http.Request) {
+	loans, err := h.loanUsecase.ListActive(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "貸し出し一覧の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", loans)
+}
+
+// ListOverdueLoans は自分の本棚の返却期限を過ぎた貸し出しの一覧を返すHTTPハンドラ関数
+// GET /api/v1/loans/overdue のリクエストを処理
+func (h *LoanHandler) ListOverdueLoans(w http.ResponseWriter, r *http.Request) {
+	loans, err := h.loanUsecase.ListOverdue(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "期限切れの貸し出しの取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", loans)
+}
+
+// RegisterRoutes は貸し出しAPIのルートを登録する関数
+func (h *LoanHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/books/{id:[0-9]+}/lend", h.LendBook).Methods("POST")      // 貸し出し
+	router.HandleFunc("/books/{id:[0-9]+}/return", h.ReturnBook).Methods("POST")  // 返却
+	router.HandleFunc("/books/{id:[0-9]+}/loans", h.ListBookLoans).Methods("GET") // 書籍の貸し出し履歴
+	router.HandleFunc("/loans", h.ListLoans).Methods("GET")                       // 貸し出し中の一覧
+	router.HandleFunc("/loans/overdue", h.ListOverdueLoans).Methods("GET")        // 期限切れの一覧
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"errors"        // エラーの種類の判定
+	"fmt"           // ファイル名の作成
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // URLのIDの変換
+
+	"book-manager/internal/inventory" // 棚卸し表の出力形式への変換
+	"book-manager/internal/model"     // 自作のデータ構造定義
+	"book-manager/internal/usecase"   // 自作のビジネスロジック層
+	"github.com/gorilla/mux"          // URLルーティングライブラリ
+)
+
+// LocationHandler は書籍の保管場所のHTTPリクエストを処理する構造体
+// 場所にある書籍の一覧は、通常の書籍一覧（GET /books?location_id=...）で取得できる
+type LocationHandler struct {
+	locationUsecase usecase.LocationUsecase // 保管場所のビジネスロジック
+}
+
+// NewLocationHandler は新しいLocationHandlerを作成する関数
+func NewLocationHandler(locationUsecase usecase.LocationUsecase) *LocationHandler {
+	return &LocationHandler{locationUsecase: locationUsecase}
+}
+
+// MoveBooksResponse は書籍の一括移動のレスポンス
+type MoveBooksResponse struct {
+	Moved int `json:"moved"` // 移動した冊数
+}
+
+// CreateLocation は保管場所を作成するHTTPハンドラ関数
+// POST /api/v1/locations のリクエストを処理
+// リクエスト例：{"parent_id": 1, "kind": "room", "name": "書斎"}
+func (h *LocationHandler) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	location, err := h.locationUsecase.Create(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "場所の作成に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "場所を作成しました", location)
+}
+
+// ListLocations は本棚の保管場所を階層順に返すHTTPハンドラ関数
+// GET /api/v1/locations のリクエストを処理
+func (h *LocationHandler) ListLocations(w http.ResponseWriter, r *http.Request) {
+	locations, err := h.locationUsecase.List(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "場所一覧の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", locations)
+}
+
+// GetLocation は保管場所を1件返すHTTPハンドラ関数
+// GET /api/v1/locations/{id} のリクエストを処理
+func (h *LocationHandler) GetLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な場所IDです", err)
+		return
+	}
+
+	location, err := h.locationUsecase.Get(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "場所が見つかりません", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", location)
+}
+
+// UpdateLocation は保管場所の親・種類・名前を変更するHTTPハンドラ関数
+// PUT /api/v1/locations/{id} のリクエストを処理
+func (h *LocationHandler) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な場所IDです", err)
+		return
+	}
+
+	var req model.UpdateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	location, err := h.locationUsecase.Update(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "場所の更新に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "場所を更新しました", location)
+}
+
+// DeleteLocation は空の保管場所を削除するHTTPハンドラ関数
+// DELETE /api/v1/locations/{id} のリクエストを処理
+func (h *LocationHandler) DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な場所IDです", err)
+		return
+	}
+
+	if err := h.locationUsecase.Delete(currentUserID(r), id); err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, usecase.ErrLocationNotEmpty) {
+			status = http.StatusConflict
+		}
+		writeErrorResponse(w, status, "場所の削除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "場所を削除しました", nil)
+}
+
+// MoveBooks は書籍をまとめて別の場所に移動するHTTPハンドラ関数
+// POST /api/v1/locations/move のリクエストを処理
+// リクエスト例：{"book_ids": [1, 2, 3], "location_id": 5}（location_id が null なら場所の設定を外す）
+func (h *LocationHandler) MoveBooks(w http.ResponseWriter, r *http.Request) {
+	var req model.MoveBooksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	moved, err := h.locationUsecase.MoveBooks(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusBadRequest), "書籍の移動に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, fmt.Sprintf("%d冊を移動しました", moved), MoveBooksResponse{Moved: moved})
+}
+
+// GetInventory は保管場所の棚卸し表を返すHTTPハンドラ関数
+// GET /api/v1/locations/{id}/inventory?format=html のリクエストを処理
+// format：html（印刷用、デフォルト）、csv、json
+func (h *LocationHandler) GetInventory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な場所IDです", err)
+		return
+	}
+	format, err := inventory.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "出力形式が無効です", err)
+		return
+	}
+
+	report, err := h.locationUsecase.Inventory(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "棚卸し表の作成に失敗しました", err)
+		return
+	}
+
+	switch format {
+	case inventory.FormatJSON:
+		writeSuccessResponse(w, http.StatusOK, "", report)
+	case inventory.FormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="inventory-%d.csv"`, id))
+		inventory.WriteCSV(w, report)
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		inventory.WriteHTML(w, report)
+	}
+}
+
+// RegisterRoutes は保管場所APIのルートを登録する関数
+func (h *LocationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/locations", h.CreateLocation).Methods("POST")                    // 場所の作成
+	router.HandleFunc("/locations", h.ListLocations).Methods("GET")                      // 場所の一覧
+	router.HandleFunc("/locations/move", h.MoveBooks).Methods("POST")                    // 書籍の一括移動
+	router.HandleFunc("/locations/{id:[0-9]+}", h.GetLocation).Methods("GET")            // 場所の取得
+	router.HandleFunc("/locations/{id:[0-9]+}", h.UpdateLocation).Methods("PUT")         // 場所の更新
+	router.HandleFunc("/locations/{id:[0-9]+}", h.DeleteLocation).Methods("DELETE")      // 場所の削除
+	router.HandleFunc("/locations/{id:[0-9]+}/inventory", h.GetInventory).Methods("GET") // 棚卸し表
+}
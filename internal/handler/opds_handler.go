@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"fmt"      // 文字列フォーマット
+	"net/http" // HTTPサーバー機能
+	"net/url"  // URLの組み立て（クエリパラメータのエスケープ）
+	"sort"     // ナビゲーション項目の並び替え
+	"strconv"  // 文字列と数値の変換
+	"time"     // フィードの更新日時
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/opds"    // OPDSフィードの作成
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// OPDSカタログの設定値
+const (
+	opdsBasePath = "/opds" // OPDSカタログのURL（main.goでこのパスに登録する）
+	opdsPageSize = 50      // 書籍一覧フィードの1ページあたりの件数
+)
+
+// opdsVersion はOPDS 1.2（Atom）とOPDS 2.0（JSON）の違いをまとめた構造体
+// 同じハンドラ関数で両方のバージョンのフィードを返すために使う
+type opdsVersion struct {
+	prefix          string                             // URLの先頭（/opds または /opds/v2）
+	navigationType  string                             // ナビゲーションフィードのメディアタイプ
+	acquisitionType string                             // 書籍一覧フィードのメディアタイプ
+	render          func(f *opds.Feed) ([]byte, error) // フィードをバイト列に変換する関数
+}
+
+// OPDS 1.2 と 2.0 の設定
+var (
+	opdsV1 = opdsVersion{
+		prefix:          opdsBasePath,
+		navigationType:  opds.TypeNavigation,
+		acquisitionType: opds.TypeAcquisition,
+		render:          (*opds.Feed).Atom,
+	}
+	opdsV2 = opdsVersion{
+		prefix:          opdsBasePath + "/v2",
+		navigationType:  opds.TypeOPDS2,
+		acquisitionType: opds.TypeOPDS2,
+		render:          (*opds.Feed).JSON,
+	}
+)
+
+// statusLabels は読書ステータスの表示名（ナビゲーションフィード用）
+var statusLabels = []struct {
+	status model.ReadingStatus
+	label  string
+}{
+	{model.StatusNotStarted, "未読"},
+	{model.StatusReading, "読書中"},
+	{model.StatusCompleted, "読了"},
+	{model.StatusDropped, "中断"},
+}
+
+// OPDSHandler はOPDSカタログのHTTPリクエストを処理する構造体
+// KOReaderなどの電子書籍リーダーアプリから書籍一覧を閲覧できるようにする
+type OPDSHandler struct {
+	bookUsecase usecase.BookUsecase // 書籍一覧の取得に使うユースケース
+}
+
+// NewOPDSHandler は新しいOPDSHandlerを作成する関数
+func NewOPDSHandler(bookUsecase usecase.BookUsecase) *OPDSHandler {
+	return &OPDSHandler{bookUsecase: bookUsecase}
+}
+
+// library はリクエストしたユーザーの本棚を扱うユースケースを返す（OPDSリーダーはBasic認証でログインする）
+func (h *OPDSHandler) library(r *http.Request) usecase.BookUsecase {
+	return h.bookUsecase.ForUser(currentUserID(r))
+}
+
+// RegisterRoutes はOPDSカタログのルートを登録する関数
+// router：/opds のサブルーター
+func (h *OPDSHandler) RegisterRoutes(router *mux.Router) {
+	// OpenSearch記述文書（検索方法の説明）
+	router.HandleFunc("/opensearch.xml", h.OpenSearch).Methods("GET")
+
+	// OPDS 1.2（Atom）と OPDS 2.0（JSON）で同じ構成のフィードを提供する
+	for _, v := range []opdsVersion{opdsV1, opdsV2} {
+		sub := router
+		if v.prefix != opdsBasePath {
+			sub = router.PathPrefix(v.prefix[len(opdsBasePath):]).Subrouter()
+		}
+		sub.HandleFunc("", h.root(v)).Methods("GET")                                   // カタログのトップ
+		sub.HandleFunc("/", h.root(v)).Methods("GET")                                  // 末尾スラッシュ付き
+		sub.HandleFunc("/status", h.statuses(v)).Methods("GET")                        // ステータス別
+		sub.HandleFunc("/{facet:tags|authors|publishers}", h.facets(v)).Methods("GET") // タグ・著者・出版社別
+		sub.HandleFunc("/books", h.books(v)).Methods("GET")                            // 書籍一覧（絞り込み・検索・ページング）
+	}
+}
+
+// root はカタログのトップ（ナビゲーションフィード）を返すハンドラを作る
+func (h *OPDSHandler) root(v opdsVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		feed := h.newFeed(v, "urn:book-manager:catalog", "書籍管理カタログ", v.prefix, v.navigationType)
+		feed.Navigation = []opds.NavEntry{
+			{ID: "urn:book-manager:books", Title: "すべての書籍", Href: v.prefix + "/books"},
+			{ID: "urn:book-manager:status", Title: "ステータス別", Href: v.prefix + "/status", Type: v.navigationType},
+			{ID: "urn:book-manager:tags", Title: "タグ別", Href: v.prefix + "/tags", Type: v.navigationType},
+			{ID: "urn:book-manager:authors", Title: "著者別", Href: v.prefix + "/authors", Type: v.navigationType},
+			{ID: "urn:book-manager:publishers", Title: "出版社別", Href: v.prefix + "/publishers", Type: v.navigationType},
+		}
+		h.sendFeed(w, v, feed, v.navigationType)
+	}
+}
+
+// statuses は読書ステータス別のナビゲーションフィードを返すハンドラを作る
+func (h *OPDSHandler) statuses(v opdsVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		feed := h.newFeed(v, "urn:book-manager:status", "ステータス別", v.prefix+"/status", v.navigationType)
+		feed.Navigation = []opds.NavEntry{}
+		for _, s := range statusLabels {
+			status := s.status
+			// ListBooks の総件数を冊数として使う（1件だけ取得して件数を得る）
+			_, count, err := h.library(r).ListBooks(&model.BookFilter{Status: &status}, 1, 1)
+			if err != nil {
+				writeErrorResponse(w, http.StatusInternalServerError, "書籍一覧の取得に失敗しました", err)
+				return
+			}
+			feed.Navigation = append(feed.Navigation, opds.NavEntry{
+				ID:    "urn:book-manager:status:" + string(status),
+				Title: s.label,
+				Href:  v.prefix + "/books?status=" + url.QueryEscape(string(status)),
+				Count: count,
+			})
+		}
+		h.sendFeed(w, v, feed, v.navigationType)
+	}
+}
+
+// facets はタグ・著者・出版社ごとのナビゲーションフィードを返すハンドラを作る
+// 全書籍から値ごとの冊数を数え、冊数の多い順に並べる
+func (h *OPDSHandler) facets(v opdsVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		facet := mux.Vars(r)["facet"]
+
+		// facetごとの設定：タイトル、絞り込みパラメータ名、書籍から値を取り出す関数
+		var title, param string
+		var values func(book *model.Book) []string
+		switch facet {
+		case "tags":
+			title, param = "タグ別", "tag"
+			values = func(book *model.Book) []string { return opds.Tags(book.Tags) }
+		case "authors":
+			title, param = "著者別", "author"
+			values = func(book *model.Book) []string { return []string{book.Author} }
+		default:
+			title, param = "出版社別", "publisher"
+			values = func(book *model.Book) []string { return []string{book.Publisher} }
+		}
+
+		books, err := listAllBooks(h.library(r), nil)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, "書籍一覧の取得に失敗しました", err)
+			return
+		}
+
+		// 値ごとの冊数を集計（空の値は除外）
+		counts := map[string]int{}
+		for _, book := range books {
+			for _, value := range values(book) {
+				if value != "" {
+					counts[value]++
+				}
+			}
+		}
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		// 冊数の多い順、同数なら名前順
+		sort.Slice(names, func(i, j int) bool {
+			if counts[names[i]] != counts[names[j]] {
+				return counts[names[i]] > counts[names[j]]
+			}
+			return names[i] < names[j]
+		})
+
+		feed := h.newFeed(v, "urn:book-manager:"+facet, title, v.prefix+"/"+facet, v.navigationType)
+		feed.Navigation = []opds.NavEntry{}
+		for _, name := range names {
+			feed.Navigation = append(feed.Navigation, opds.NavEntry{
+				ID:    fmt.Sprintf("urn:book-manager:%s:%s", param, url.PathEscape(name)),
+				Title: name,
+				Href:  v.prefix + "/books?" + url.Values{param: {name}}.Encode(),
+				Count: counts[name],
+			})
+		}
+		h.sendFeed(w, v, feed, v.navigationType)
+	}
+}
+
+// books は書籍一覧フィード（OPDSの acquisition feed）を返すハンドラを作る
+// クエリパラメータ：status、tag、author、publisher、rating、q/query（検索語）、page
+func (h *OPDSHandler) books(v opdsVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		// OpenSearch（q）とOPDS 2.0のテンプレート（query）の検索語を書籍一覧の search に合わせる
+		for _, key := range []string{"q", "query"} {
+			if term := query.Get(key); term != "" {
+				query.Set("search", term)
+			}
+		}
+		filter := parseBookFilter(query)
+
+		page, _ := strconv.Atoi(query.Get("page"))
+		if page < 1 {
+			page = 1
+		}
+
+		books, total, err := h.library(r).ListBooks(filter, page, opdsPageSize)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, "書籍一覧の取得に失敗しました", err)
+			return
+		}
+
+		// ページングのリンクで絞り込み条件を引き継ぐため、page以外のパラメータを残したURLを作る
+		hrefForPage := func(p int) string {
+			params := url.Values{}
+			for key, vals := range query {
+				if key != "page" && key != "q" && key != "query" {
+					params[key] = vals
+				}
+			}
+			params.Set("page", strconv.Itoa(p))
+			return v.prefix + "/books?" + params.Encode()
+		}
+
+		feed := h.newFeed(v, "urn:book-manager:books", "書籍一覧", hrefForPage(page), v.acquisitionType)
+		feed.Publications = books
+		feed.Total = total
+		feed.Page = page
+		feed.PerPage = opdsPageSize
+		feed.BookHref = func(book *model.Book) string {
+			return fmt.Sprintf("/api/v1/books/%d", book.ID)
+		}
+		feed.Links = append(feed.Links, opds.PageLinks(page, opdsPageSize, total, v.acquisitionType, hrefForPage)...)
+		h.sendFeed(w, v, feed, v.acquisitionType)
+	}
+}
+
+// OpenSearch はOpenSearch記述文書を返すHTTPハンドラ関数
+// GET /opds/opensearch.xml のリクエストを処理
+func (h *OPDSHandler) OpenSearch(w http.ResponseWriter, r *http.Request) {
+	body, err := opds.OpenSearch(opdsV1.prefix + "/books?q={searchTerms}")
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "OpenSearch記述の作成に失敗しました", err)
+		return
+	}
+	w.Header().Set("Content-Type", opds.TypeOpenSearch+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// newFeed は共通のリンク（self、start、search）を設定したフィードを作成する
+func (h *OPDSHandler) newFeed(v opdsVersion, id, title, self, selfType string) *opds.Feed {
+	feed := &opds.Feed{
+		ID:      id,
+		Title:   title,
+		Updated: time.Now(),
+		Links: []opds.Link{
+			{Rel: opds.RelSelf, Href: self, Type: selfType},
+			{Rel: opds.RelStart, Href: v.prefix, Type: v.navigationType},
+		},
+	}
+	// 検索リンク：OPDS 1.2はOpenSearch記述文書、OPDS 2.0はURLテンプレートを使う
+	if v.prefix == opdsV1.prefix {
+		feed.Links = append(feed.Links, opds.Link{Rel: opds.RelSearch, Href: opdsBasePath + "/opensearch.xml", Type: opds.TypeOpenSearch})
+	} else {
+		feed.Links = append(feed.Links, opds.Link{Rel: opds.RelSearch, Href: v.prefix + "/books{?query}", Type: opds.TypeOPDS2, Templated: true})
+	}
+	return feed
+}
+
+// sendFeed はフィードを各バージョンの形式に変換して送信する
+func (h *OPDSHandler) sendFeed(w http.ResponseWriter, v opdsVersion, feed *opds.Feed, contentType string) {
+	body, err := v.render(feed)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "OPDSフィードの作成に失敗しました", err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"      // リクエストのコンテキストへの保存
+	"crypto/rand"  // リクエストIDの生成
+	"encoding/hex" // リクエストIDの文字列化
+	"net/http"     // HTTPサーバー機能
+)
+
+// RequestIDHeader はリクエストIDを受け渡しするHTTPヘッダー
+// クライアント（またはリバースプロキシ）が付けたIDはそのまま使い、なければサーバーで作る
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDContextKey はリクエストIDをコンテキストに保存するときのキー
+const requestIDContextKey contextKey = "request_id"
+
+// maxRequestIDLength は受け付けるリクエストIDの最大の長さ（長すぎるIDはログや変更履歴を汚すため作り直す）
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware はリクエストごとにIDを決めてコンテキストとレスポンスヘッダーに設定するミドルウェア関数
+// 同じIDがアクセスログと変更履歴に記録されるため、どのリクエストでどの変更が行われたかを追える
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey, id)))
+	})
+}
+
+// RequestID はリクエストのIDを返す関数（RequestIDMiddleware を通っていなければ空文字）
+func RequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDContextKey).(string)
+	return id
+}
+
+// validRequestID はクライアントが付けたリクエストIDをそのまま使えるかを判定する
+// 英数字と「-」「_」「.」だけを受け付ける（ログに改行などを混ぜられないようにするため）
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID は新しいリクエストID（16バイトの乱数の16進数）を作る
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json" // JSONデータのエンコード
+	"net/http"      // HTTPサーバー機能
+	"net/url"       // URLクエリパラメータの型（url.Values）
+	"strconv"       // ページ番号の変換
+)
+
+// writeErrorResponse はエラーレスポンスを送信する関数
+// 各ハンドラ（書籍、OPDSなど）で共通のエラー形式（ErrorResponse）を使うためにまとめている
+func writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	// HTTPレスポンスヘッダーを設定（JSON形式で返すことを明示）
+	w.Header().Set("Content-Type", "application/json")
+	// HTTPステータスコードを設定（400, 404, 500など）
+	w.WriteHeader(statusCode)
+
+	// エラーレスポンス構造体を作成
+	response := ErrorResponse{
+		Error:   message,     // ユーザー向けエラーメッセージ
+		Message: err.Error(), // 詳細なエラー内容（デバッグ用）
+	}
+
+	// JSON形式でレスポンスを送信
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeSuccessResponse は成功レスポンスを送信する関数
+// 各ハンドラで共通の成功形式（SuccessResponse）を使うためにまとめている
+func writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	// HTTPレスポンスヘッダーを設定（JSON形式で返すことを明示）
+	w.Header().Set("Content-Type", "application/json")
+	// HTTPステータスコードを設定（200, 201など）
+	w.WriteHeader(statusCode)
+
+	// 成功レスポンス構造体を作成
+	response := SuccessResponse{
+		Message: message, // 成功メッセージ
+		Data:    data,    // 実際のデータ（interface{}は任意の型を受け入れる）
+	}
+
+	// JSON形式でレスポンスを送信
+	json.NewEncoder(w).Encode(response)
+}
+
+// parsePagination はURLクエリパラメータ（page、limit）からページ番号と1ページあたりの件数を取り出す関数
+// 書籍一覧と同じく、ページ番号は最低1、件数は1〜100件（省略時は20件）にする
+func parsePagination(query url.Values) (page, limit int) {
+	page, _ = strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ = strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"errors"        // エラーの判定
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // URLのIDの変換
+
+	"book-manager/internal/markdown" // HTMLのContent-Type
+	"book-manager/internal/model"    // 自作のデータ構造定義
+	"book-manager/internal/usecase"  // 自作のビジネスロジック層
+	"github.com/gorilla/mux"         // URLルーティングライブラリ
+)
+
+// ReviewHandler はレビューのHTTPリクエストを処理する構造体
+type ReviewHandler struct {
+	reviewUsecase usecase.ReviewUsecase // レビューのビジネスロジック
+}
+
+// NewReviewHandler は新しいReviewHandlerを作成する関数
+func NewReviewHandler(reviewUsecase usecase.ReviewUsecase) *ReviewHandler {
+	return &ReviewHandler{reviewUsecase: reviewUsecase}
+}
+
+// PreviewReviewRequest はレビューのプレビューのリクエスト構造体
+type PreviewReviewRequest struct {
+	Body string `json:"body"` // 本文（Markdown）
+}
+
+// reviewErrorStatus はレビューのエラーに対応するHTTPステータスコードを返す関数
+// 同じ読書の回のレビューが既にある場合は 409 Conflict にする
+func reviewErrorStatus(err error, status int) int {
+	if errors.Is(err, usecase.ErrReviewExists) {
+		return http.StatusConflict
+	}
+	return errorStatus(err, status)
+}
+
+// reviewIDs はURLの書籍IDとレビューIDを取り出す関数
+func reviewIDs(r *http.Request) (bookID, id int, err error) {
+	vars := mux.Vars(r)
+	if bookID, err = strconv.Atoi(vars["id"]); err != nil {
+		return 0, 0, err
+	}
+	if id, err = strconv.Atoi(vars["reviewID"]); err != nil {
+		return 0, 0, err
+	}
+	return bookID, id, nil
+}
+
+// writeHTML はHTMLの断片を返す関数
+// nosniff：ブラウザにContent-Typeを推測させない（HTML以外として扱わせないため）
+func writeHTML(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", markdown.HTMLContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body))
+}
+
+// CreateReview はレビューを書くHTTPハンドラ関数
+// POST /api/v1/books/{id}/reviews のリクエストを処理
+// リクエスト例：{"read_number": 2, "title": "再読して", "body": "**二度目**の方が面白い", "spoiler": true, "visibility": "public"}
+func (h *ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	var req model.CreateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	review, err := h.reviewUsecase.Create(currentUserID(r), bookID, &req)
+	if err != nil {
+		writeErrorResponse(w, reviewErrorStatus(err, http.StatusBadRequest), "レビューの登録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "レビューを登録しました", review)
+}
+
+// ListReviews は書籍のレビューの一覧を返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/reviews のリクエストを処理
+func (h *ReviewHandler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	reviews, err := h.reviewUsecase.List(currentUserID(r), bookID)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "レビューの取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", reviews)
+}
+
+// GetReview はレビューを1件返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/reviews/{reviewID} のリクエストを処理
+func (h *ReviewHandler) GetReview(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := reviewIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	review, err := h.reviewUsecase.Get(currentUserID(r), bookID, id)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "レビューが見つかりません", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", review)
+}
+
+// UpdateReview はレビューを更新するHTTPハンドラ関数
+// PUT /api/v1/books/{id}/reviews/{reviewID} のリクエストを処理（送った項目だけを更新する）
+func (h *ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := reviewIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	var req model.UpdateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	review, err := h.reviewUsecase.Update(currentUserID(r), bookID, id, &req)
+	if err != nil {
+		writeErrorResponse(w, reviewErrorStatus(err, http.StatusBadRequest), "レビューの更新に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "レビューを更新しました", review)
+}
+
+// DeleteReview はレビューを削除するHTTPハンドラ関数
+// DELETE /api/v1/books/{id}/reviews/{reviewID} のリクエストを処理
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := reviewIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	if err := h.reviewUsecase.Delete(currentUserID(r), bookID, id); err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "レビューの削除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "レビューを削除しました", nil)
+}
+
+// GetReviewHTML はレビューを安全なHTMLの断片で返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/reviews/{reviewID}/html のリクエストを処理
+// 本文中のHTMLはエスケープし、危険なリンクは取り除くため、Web画面にそのまま埋め込める
+func (h *ReviewHandler) GetReviewHTML(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := reviewIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	body, err := h.reviewUsecase.HTML(currentUserID(r), bookID, id)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "レビューが見つかりません", err)
+		return
+	}
+	writeHTML(w, body)
+}
+
+// PreviewReview は保存する前のレビューの本文をHTMLの断片で返すHTTPハンドラ関数
+// POST /api/v1/reviews/preview のリクエストを処理（リクエスト例：{"body": "# 感想\n**面白い**"}）
+func (h *ReviewHandler) PreviewReview(w http.ResponseWriter, r *http.Request) {
+	var req PreviewReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+	writeHTML(w, h.reviewUsecase.Preview(req.Body))
+}
+
+// ListRevisions はレビューの編集履歴を新しい順に返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/reviews/{reviewID}/revisions のリクエストを処理
+func (h *ReviewHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := reviewIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+
+	revisions, err := h.reviewUsecase.Revisions(currentUserID(r), bookID, id)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "レビューの編集履歴の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", revisions)
+}
+
+// GetRevision はレビューの版を1件返すHTTPハンドラ関数
+// GET /api/v1/books/{id}/reviews/{reviewID}/revisions/{version} のリクエストを処理
+func (h *ReviewHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := reviewIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+	version, err := strconv.Atoi(mux.Vars(r)["version"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な版の番号です", err)
+		return
+	}
+
+	revision, err := h.reviewUsecase.Revision(currentUserID(r), bookID, id, version)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "レビューの版が見つかりません", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", revision)
+}
+
+// RestoreRevision はレビューを以前の版の内容に戻すHTTPハンドラ関数
+// POST /api/v1/books/{id}/reviews/{reviewID}/revisions/{version}/restore のリクエストを処理
+// 戻した内容は新しい版として記録するため、戻す前の版も編集履歴に残る
+func (h *ReviewHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	bookID, id, err := reviewIDs(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効なIDです", err)
+		return
+	}
+	version, err := strconv.Atoi(mux.Vars(r)["version"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な版の番号です", err)
+		return
+	}
+
+	review, err := h.reviewUsecase.Restore(currentUserID(r), bookID, id, version)
+	if err != nil {
+		writeErrorResponse(w, errorStatus(err, http.StatusNotFound), "レビューを以前の版に戻せませんでした", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "レビューを以前の版の内容に戻しました", review)
+}
+
+// RegisterRoutes はレビューAPIのルートを登録する関数
+func (h *ReviewHandler) RegisterRoutes(router *mux.Router) {
+	const review = "/books/{id:[0-9]+}/reviews/{reviewID:[0-9]+}"
+	router.HandleFunc("/books/{id:[0-9]+}/reviews", h.ListReviews).Methods("GET")                      // 書籍のレビューの一覧
+	router.HandleFunc("/books/{id:[0-9]+}/reviews", h.CreateReview).Methods("POST")                    // レビューの登録
+	router.HandleFunc(review, h.GetReview).Methods("GET")                                              // レビューの取得
+	router.HandleFunc(review, h.UpdateReview).Methods("PUT")                                           // レビューの更新
+	router.HandleFunc(review, h.DeleteReview).Methods("DELETE")                                        // レビューの削除
+	router.HandleFunc(review+"/html", h.GetReviewHTML).Methods("GET")                                  // 安全なHTMLでの表示
+	router.HandleFunc(review+"/revisions", h.ListRevisions).Methods("GET")                             // 編集履歴
+	router.HandleFunc(review+"/revisions/{version:[0-9]+}", h.GetRevision).Methods("GET")              // 版の取得
+	router.HandleFunc(review+"/revisions/{version:[0-9]+}/restore", h.RestoreRevision).Methods("POST") // 以前の版に戻す
+	router.HandleFunc("/reviews/preview", h.PreviewReview).Methods("POST")                             // 保存前のプレビュー
+}
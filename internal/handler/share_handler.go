@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // URLのIDの変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// ShareHandler は本棚の共有のHTTPリクエストを処理する構造体
+// 共有された書籍の取得・更新・削除は、通常の書籍API（/books/{id}）で役割に応じて行える
+type ShareHandler struct {
+	shareUsecase usecase.ShareUsecase // 共有のビジネスロジック
+}
+
+// NewShareHandler は新しいShareHandlerを作成する関数
+func NewShareHandler(shareUsecase usecase.ShareUsecase) *ShareHandler {
+	return &ShareHandler{shareUsecase: shareUsecase}
+}
+
+// CreateShare は本棚を他のユーザーに共有するHTTPハンドラ関数
+// POST /api/v1/shares のリクエストを処理
+func (h *ShareHandler) CreateShare(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	var req model.CreateShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	share, err := h.shareUsecase.Grant(user.ID, &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "共有に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "共有しました", share)
+}
+
+// ListShares は自分が共有した設定と、自分に共有された設定の一覧を返すHTTPハンドラ関数
+// GET /api/v1/shares のリクエストを処理
+func (h *ShareHandler) ListShares(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	shares, err := h.shareUsecase.List(user.ID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "共有設定の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", shares)
+}
+
+// DeleteShare は共有をやめるHTTPハンドラ関数
+// DELETE /api/v1/shares/{id} のリクエストを処理
+func (h *ShareHandler) DeleteShare(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な共有設定IDです", err)
+		return
+	}
+	if err := h.shareUsecase.Revoke(user.ID, id); err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "共有の解除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "共有を解除しました", nil)
+}
+
+// ListSharedBooks は自分に共有された書籍の一覧を返すHTTPハンドラ関数
+// GET /api/v1/shares/{id}/books?page=1&limit=20 のリクエストを処理
+func (h *ShareHandler) ListSharedBooks(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	if user == nil {
+		writeErrorResponse(w, http.StatusUnauthorized, "ログインしていません", usecase.ErrInvalidCredentials)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な共有設定IDです", err)
+		return
+	}
+	page, limit := parsePagination(r.URL.Query())
+
+	books, total, err := h.shareUsecase.ListBooks(user.ID, id, page, limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "共有された書籍の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", ListBooksResponse{
+		Books:      books,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// RegisterRoutes は共有APIのルートを登録する関数
+func (h *ShareHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/shares", h.CreateShare).Methods("POST")                      // 共有
+	router.HandleFunc("/shares", h.ListShares).Methods("GET")                        // 共有設定の一覧
+	router.HandleFunc("/shares/{id:[0-9]+}", h.DeleteShare).Methods("DELETE")        // 共有の解除
+	router.HandleFunc("/shares/{id:[0-9]+}/books", h.ListSharedBooks).Methods("GET") // 共有された書籍の一覧
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"   // エラーの種類の判定
+	"fmt"      // エラーメッセージの作成
+	"net/http" // HTTPサーバー機能
+	"strconv"  // 件数の変換
+	"time"     // 期間の解析
+
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/usecase"    // 自作のビジネスロジック層
+	"book-manager/internal/yearreview" // 年間の振り返りのHTML出力
+	"github.com/gorilla/mux"           // URLルーティングライブラリ
+)
+
+// StatisticsHandler は書籍の詳しい統計のHTTPリクエストを処理する構造体
+// 合計だけの統計（GET /statistics）は BookHandler が扱う
+type StatisticsHandler struct {
+	statsUsecase usecase.StatisticsUsecase // 統計のビジネスロジック
+}
+
+// NewStatisticsHandler は新しいStatisticsHandlerを作成する関数
+func NewStatisticsHandler(statsUsecase usecase.StatisticsUsecase) *StatisticsHandler {
+	return &StatisticsHandler{statsUsecase: statsUsecase}
+}
+
+// parseDateParam はURLクエリパラメータの日付（2026-01-31 またはRFC3339形式）を解析する関数（空ならnil）
+func parseDateParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("日付は 2006-01-02 の形式で指定してください: %s", value)
+}
+
+// statsErrorStatus は統計のエラーに応じたHTTPステータスコードを返す関数
+func statsErrorStatus(err error) int {
+	if errors.Is(err, usecase.ErrInvalidStatsQuery) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetTimeSeries は時系列の統計を返すHTTPハンドラ関数
+// GET /api/v1/statistics/timeseries?interval=month&metric=purchased&from=2026-01-01&to=2026-12-31 のリクエストを処理
+func (h *StatisticsHandler) GetTimeSeries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := parseDateParam(query.Get("from"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な期間の指定です", err)
+		return
+	}
+	to, err := parseDateParam(query.Get("to"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な期間の指定です", err)
+		return
+	}
+
+	metric := model.SeriesMetric(query.Get("metric"))
+	if metric == "" {
+		metric = model.SeriesPurchased
+	}
+	series, err := h.statsUsecase.TimeSeries(currentUserID(r), model.StatsInterval(query.Get("interval")), metric, from, to)
+	if err != nil {
+		writeErrorResponse(w, statsErrorStatus(err), "時系列の統計の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", series)
+}
+
+// GetBreakdown はタグ・出版社・著者・評価・店・購入方法・形態ごとの内訳を返すHTTPハンドラ関数
+// GET /api/v1/statistics/breakdown?by=store&sort=spent&from=2026-01-01&to=2026-12-31&limit=20 のリクエストを処理
+// from・to は購入日の範囲（任意）、sort は books（デフォルト）か spent
+func (h *StatisticsHandler) GetBreakdown(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := parseDateParam(query.Get("from"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な期間の指定です", err)
+		return
+	}
+	to, err := parseDateParam(query.Get("to"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な期間の指定です", err)
+		return
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	items, err := h.statsUsecase.Breakdown(currentUserID(r), &model.BreakdownQuery{
+		By:    model.BreakdownDimension(query.Get("by")),
+		From:  from,
+		To:    to,
+		Sort:  model.BreakdownSort(query.Get("sort")),
+		Limit: limit,
+	})
+	if err != nil {
+		writeErrorResponse(w, statsErrorStatus(err), "内訳の統計の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", items)
+}
+
+// GetInsights は読了までの平均日数・積読・連続読書日数を返すHTTPハンドラ関数
+// GET /api/v1/statistics/insights のリクエストを処理
+func (h *StatisticsHandler) GetInsights(w http.ResponseWriter, r *http.Request) {
+	insights, err := h.statsUsecase.Insights(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "読書の傾向の統計の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", insights)
+}
+
+// GetYearInReview は1年間の読書の振り返りを返すHTTPハンドラ関数
+// GET /api/v1/statistics/year-in-review?year=2026&format=html のリクエストを処理
+// year：省略すると今年、format：html（グラフ付き、デフォルト）、json
+func (h *StatisticsHandler) GetYearInReview(w http.ResponseWriter, r *http.Request) {
+	year := time.Now().Year()
+	if value := r.URL.Query().Get("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "year は数値で指定してください", err)
+			return
+		}
+		year = parsed
+	}
+	format, err := yearreview.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "出力形式が無効です", err)
+		return
+	}
+
+	review, err := h.statsUsecase.YearInReview(currentUserID(r), year)
+	if err != nil {
+		writeErrorResponse(w, statsErrorStatus(err), "年間の振り返りの作成に失敗しました", err)
+		return
+	}
+
+	if format == yearreview.FormatJSON {
+		writeSuccessResponse(w, http.StatusOK, "", review)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	yearreview.WriteHTML(w, review)
+}
+
+// RegisterRoutes は詳しい統計APIのルートを登録する関数
+func (h *StatisticsHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/statistics/timeseries", h.GetTimeSeries).Methods("GET")       // 時系列の統計
+	router.HandleFunc("/statistics/breakdown", h.GetBreakdown).Methods("GET")         // 内訳の統計
+	router.HandleFunc("/statistics/insights", h.GetInsights).Methods("GET")           // 読了までの日数・積読・連続読書日数
+	router.HandleFunc("/statistics/year-in-review", h.GetYearInReview).Methods("GET") // 1年間の読書の振り返り（HTML・JSON）
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http" // HTTPサーバー機能
+	"strconv"  // 書籍IDの変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// TrashHandler はゴミ箱（削除した書籍の一覧と復元）のHTTPリクエストを処理する構造体
+type TrashHandler struct {
+	trashUsecase usecase.TrashUsecase // ゴミ箱のビジネスロジック
+}
+
+// NewTrashHandler は新しいTrashHandlerを作成する関数
+func NewTrashHandler(trashUsecase usecase.TrashUsecase) *TrashHandler {
+	return &TrashHandler{trashUsecase: trashUsecase}
+}
+
+// ListTrashResponse はゴミ箱の一覧のレスポンス構造体
+type ListTrashResponse struct {
+	Books      []*model.TrashedBook `json:"books"`       // ゴミ箱の書籍（ゴミ箱に移した日時の新しい順）
+	Total      int                  `json:"total"`       // 総件数
+	Page       int                  `json:"page"`        // 現在のページ
+	Limit      int                  `json:"limit"`       // 1ページあたりの件数
+	TotalPages int                  `json:"total_pages"` // 総ページ数
+}
+
+// ListTrash はゴミ箱の書籍の一覧を返すHTTPハンドラ関数
+// GET /api/v1/trash?page=1&limit=20 のリクエストを処理
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r.URL.Query())
+
+	books, total, err := h.trashUsecase.List(currentUserID(r), page, limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "ゴミ箱の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", ListTrashResponse{
+		Books:      books,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// RestoreBook はゴミ箱の書籍を元に戻すHTTPハンドラ関数
+// POST /api/v1/books/{id}/restore のリクエストを処理
+func (h *TrashHandler) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な書籍IDです", err)
+		return
+	}
+
+	book, err := h.trashUsecase.Restore(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "書籍の復元に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "書籍をゴミ箱から戻しました", book)
+}
+
+// RegisterRoutes はゴミ箱のAPIのルートを登録する関数
+func (h *TrashHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/trash", h.ListTrash).Methods("GET")                        // ゴミ箱の一覧
+	router.HandleFunc("/books/{id:[0-9]+}/restore", h.RestoreBook).Methods("POST") // ゴミ箱から戻す
+}
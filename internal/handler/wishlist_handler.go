@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json" // JSONの解析
+	"errors"        // エラーの種類の判定
+	"io"            // 空のリクエストボディの判定
+	"net/http"      // HTTPサーバー機能
+	"strconv"       // URLのIDの変換
+
+	"book-manager/internal/model"   // 自作のデータ構造定義
+	"book-manager/internal/usecase" // 自作のビジネスロジック層
+	"github.com/gorilla/mux"        // URLルーティングライブラリ
+)
+
+// WishlistHandler は欲しい本リストのHTTPリクエストを処理する構造体
+type WishlistHandler struct {
+	wishlistUsecase usecase.WishlistUsecase // 欲しい本リストのビジネスロジック
+}
+
+// NewWishlistHandler は新しいWishlistHandlerを作成する関数
+func NewWishlistHandler(wishlistUsecase usecase.WishlistUsecase) *WishlistHandler {
+	return &WishlistHandler{wishlistUsecase: wishlistUsecase}
+}
+
+// PriceSourcesResponse は価格の取得元の一覧のレスポンス
+type PriceSourcesResponse struct {
+	Sources []string `json:"sources"` // 登録されている価格の取得元の名前
+}
+
+// wishlistItemID はURLの欲しい本のIDを取得する関数
+func wishlistItemID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "無効な欲しい本IDです", err)
+		return 0, false
+	}
+	return id, true
+}
+
+// CreateItem は欲しい本を登録するHTTPハンドラ関数
+// POST /api/v1/wishlist のリクエストを処理
+// リクエスト例：{"title": "プログラミング言語Go", "author": "Alan Donovan", "priority": 1, "target_price": 3000, "where_seen": "駅前の書店"}
+func (h *WishlistHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateWishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	item, err := h.wishlistUsecase.Create(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "欲しい本の登録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "欲しい本を登録しました", item)
+}
+
+// ListItems は欲しい本リストを優先度順に返すHTTPハンドラ関数
+// GET /api/v1/wishlist?include_purchased=true のリクエストを処理
+// include_purchased を省略すると、まだ購入していない本だけを返す
+func (h *WishlistHandler) ListItems(w http.ResponseWriter, r *http.Request) {
+	includePurchased, _ := strconv.ParseBool(r.URL.Query().Get("include_purchased"))
+
+	items, err := h.wishlistUsecase.List(currentUserID(r), includePurchased)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "欲しい本リストの取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", items)
+}
+
+// GetItem は欲しい本を1件返すHTTPハンドラ関数
+// GET /api/v1/wishlist/{id} のリクエストを処理
+func (h *WishlistHandler) GetItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := wishlistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.wishlistUsecase.Get(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "欲しい本が見つかりません", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", item)
+}
+
+// UpdateItem は欲しい本を更新するHTTPハンドラ関数
+// PUT /api/v1/wishlist/{id} のリクエストを処理
+func (h *WishlistHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := wishlistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateWishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	item, err := h.wishlistUsecase.Update(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "欲しい本の更新に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "欲しい本を更新しました", item)
+}
+
+// DeleteItem は欲しい本を削除するHTTPハンドラ関数
+// DELETE /api/v1/wishlist/{id} のリクエストを処理
+func (h *WishlistHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := wishlistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.wishlistUsecase.Delete(currentUserID(r), id); err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "欲しい本の削除に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "欲しい本を削除しました", nil)
+}
+
+// PurchaseItem は欲しい本を購入済みにして、本棚に書籍を作成するHTTPハンドラ関数
+// POST /api/v1/wishlist/{id}/purchase のリクエストを処理
+// リクエストボディは省略でき、その場合は現在時刻・最後に記録した価格で購入を記録する
+func (h *WishlistHandler) PurchaseItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := wishlistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.PurchaseWishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	book, err := h.wishlistUsecase.Purchase(currentUserID(r), id, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrAlreadyPurchased) {
+			status = http.StatusConflict
+		}
+		writeErrorResponse(w, status, "購入の記録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "購入した書籍を本棚に追加しました", book)
+}
+
+// ListPrices は欲しい本の価格の履歴を返すHTTPハンドラ関数
+// GET /api/v1/wishlist/{id}/prices のリクエストを処理
+func (h *WishlistHandler) ListPrices(w http.ResponseWriter, r *http.Request) {
+	id, ok := wishlistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := h.wishlistUsecase.Prices(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, "価格の履歴の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusOK, "", records)
+}
+
+// RecordPrice は欲しい本の価格を手入力で記録するHTTPハンドラ関数
+// POST /api/v1/wishlist/{id}/prices のリクエストを処理
+// リクエスト例：{"price": 2980, "url": "https://example.com/books/123"}
+func (h *WishlistHandler) RecordPrice(w http.ResponseWriter, r *http.Request) {
+	id, ok := wishlistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.RecordPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエストの解析に失敗しました", err)
+		return
+	}
+
+	record, err := h.wishlistUsecase.RecordPrice(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "価格の記録に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "価格を記録しました", record)
+}
+
+// RefreshPrice は価格の取得元から現在の価格を取得して記録するHTTPハンドラ関数
+// POST /api/v1/wishlist/{id}/prices/refresh?source=名前 のリクエストを処理
+// 取得元で価格が見つからなかった場合は 404、取得元への問い合わせに失敗した場合は 502 を返す
+func (h *WishlistHandler) RefreshPrice(w http.ResponseWriter, r *http.Request) {
+	id, ok := wishlistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := h.wishlistUsecase.RefreshPrice(r.Context(), currentUserID(r), id, r.URL.Query().Get("source"))
+	if err != nil {
+		status := http.StatusNotFound // 欲しい本が見つからない、または取得元で価格が見つからない
+		if errors.Is(err, usecase.ErrUnknownPriceSource) {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, usecase.ErrPriceSource) {
+			status = http.StatusBadGateway
+		}
+		writeErrorResponse(w, status, "価格の取得に失敗しました", err)
+		return
+	}
+	writeSuccessResponse(w, http.StatusCreated, "価格を記録しました", record)
+}
+
+// ListPriceSources は登録されている価格の取得元を返すHTTPハンドラ関数
+// GET /api/v1/wishlist/price-sources のリクエストを処理
+func (h *WishlistHandler) ListPriceSources(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponse(w, http.StatusOK, "", PriceSourcesResponse{Sources: h.wishlistUsecase.Sources()})
+}
+
+// RegisterRoutes は欲しい本リストAPIのルートを登録する関数
+func (h *WishlistHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/wishlist", h.CreateItem).Methods("POST")                              // 欲しい本の登録
+	router.HandleFunc("/wishlist", h.ListItems).Methods("GET")                                // 欲しい本リスト
+	router.HandleFunc("/wishlist/price-sources", h.ListPriceSources).Methods("GET")           // 価格の取得元の一覧
+	router.HandleFunc("/wishlist/{id:[0-9]+}", h.GetItem).Methods("GET")                      // 欲しい本の取得
+	router.HandleFunc("/wishlist/{id:[0-9]+}", h.UpdateItem).Methods("PUT")                   // 欲しい本の更新
+	router.HandleFunc("/wishlist/{id:[0-9]+}", h.DeleteItem).Methods("DELETE")                // 欲しい本の削除
+	router.HandleFunc("/wishlist/{id:[0-9]+}/purchase", h.PurchaseItem).Methods("POST")       // 購入して書籍に変換
+	router.HandleFunc("/wishlist/{id:[0-9]+}/prices", h.ListPrices).Methods("GET")            // 価格の履歴
+	router.HandleFunc("/wishlist/{id:[0-9]+}/prices", h.RecordPrice).Methods("POST")          // 価格の手入力
+	router.HandleFunc("/wishlist/{id:[0-9]+}/prices/refresh", h.RefreshPrice).Methods("POST") // 価格の取得元から記録
+}
@@ -0,0 +1,119 @@
+// inventoryパッケージ：保管場所の棚卸し表を印刷・表計算ソフト向けの形式に変換するファイル
+// 本棚の前で紙に印刷してチェックしたり、表計算ソフトで照合したりするために使う
+package inventory
+
+import (
+	"encoding/csv"  // CSV形式の書き出し
+	"fmt"           // 文字列フォーマット
+	"html/template" // HTMLの作成（値は自動でエスケープされる）
+	"io"            // 書き出し先
+	"strings"       // 出力形式の名前の正規化
+	"time"          // 作成日時の表示
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// Format は棚卸し表の出力形式を表す型
+type Format string
+
+// 対応している出力形式の定数定義
+const (
+	FormatHTML Format = "html" // 印刷用のHTML（デフォルト）
+	FormatCSV  Format = "csv"  // 表計算ソフト向けのCSV
+	FormatJSON Format = "json" // APIのJSONレスポンス
+)
+
+// ParseFormat は文字列を出力形式に変換する関数
+// 空文字の場合は印刷用のHTMLを返す
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatHTML:
+		return FormatHTML, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("未対応の出力形式です: %s（html、csv、json のいずれかを指定してください）", s)
+	}
+}
+
+// WriteCSV は棚卸し表をCSV形式で書き出す関数
+// 1行に1冊ずつ、置かれている場所の道のりと一緒に出力する（Excelで文字化けしないようにBOMを付ける）
+func WriteCSV(w io.Writer, report *model.InventoryReport) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"場所", "ID", "タイトル", "著者", "ISBN", "出版社", "読書ステータス", "タグ", "確認"})
+	for _, section := range report.Sections {
+		for _, book := range section.Books {
+			writer.Write([]string{
+				section.Location.Path,
+				fmt.Sprint(book.ID),
+				book.Title,
+				book.Author,
+				book.ISBN,
+				book.Publisher,
+				string(book.Status),
+				book.Tags,
+				"", // 棚の前で確認した印を書き込む欄
+			})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteHTML は棚卸し表を印刷用のHTMLで書き出す関数
+// 場所ごとに見出しと表を作り、各書籍の行に確認用のチェック欄を付ける
+func WriteHTML(w io.Writer, report *model.InventoryReport) error {
+	return htmlTemplate.Execute(w, report)
+}
+
+// htmlTemplate は印刷用HTMLのテンプレート
+// @media print：印刷時は余白をなくし、1冊分の行が2ページに分かれないようにする
+var htmlTemplate = template.Must(template.New("inventory").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>棚卸し表：{{.Location.Path}}</title>
+<style>
+  body { font-family: sans-serif; font-size: 11pt; margin: 2em; }
+  h1 { font-size: 16pt; margin-bottom: 0.2em; }
+  h2 { font-size: 12pt; margin: 1.5em 0 0.4em; border-bottom: 1px solid #333; }
+  .meta { color: #555; font-size: 9pt; }
+  table { width: 100%; border-collapse: collapse; }
+  th, td { border: 1px solid #999; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+  th { background: #eee; }
+  td.check { width: 2.5em; text-align: center; }
+  .empty { color: #777; font-style: italic; }
+  @media print {
+    body { margin: 0; }
+    tr { page-break-inside: avoid; }
+  }
+</style>
+</head>
+<body>
+<h1>棚卸し表：{{.Location.Path}}</h1>
+<p class="meta">{{.TotalBooks}}冊・作成日時 {{date .GeneratedAt}}</p>
+{{range .Sections}}
+<h2>{{.Location.Path}}（{{len .Books}}冊）</h2>
+{{if .Books}}
+<table>
+  <thead><tr><th>確認</th><th>ID</th><th>タイトル</th><th>著者</th><th>ISBN</th><th>出版社</th></tr></thead>
+  <tbody>
+  {{range .Books}}
+    <tr><td class="check">☐</td><td>{{.ID}}</td><td>{{.Title}}</td><td>{{.Author}}</td><td>{{.ISBN}}</td><td>{{.Publisher}}</td></tr>
+  {{end}}
+  </tbody>
+</table>
+{{else}}
+<p class="empty">この場所に直接置かれている書籍はありません</p>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
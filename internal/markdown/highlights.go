@@ -0,0 +1,102 @@
+// markdownパッケージ：書籍のハイライト・引用をMarkdownの文書に変換するファイル
+// ノートアプリやブログにそのまま貼り付けられるよう、見出し・引用ブロック・タグだけの素直な書式にする
+package markdown
+
+import (
+	"fmt"     // 文字列フォーマット
+	"strings" // 文書の組み立て
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// ContentType はMarkdownの文書のContent-Type
+const ContentType = "text/markdown; charset=utf-8"
+
+// Highlights は1冊分のハイライトをMarkdownの文書にする関数
+// highlights はページ順に並んでいるものとし、章が変わるところに章の見出しを入れる（最初から章がなければ見出しは入れない）
+func Highlights(book *model.Book, highlights []*model.Highlight) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", oneLine(book.Title))
+	for _, field := range []struct{ label, value string }{
+		{"著者", book.Author},
+		{"出版社", book.Publisher},
+		{"ISBN", book.ISBN},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", field.label, oneLine(field.value))
+		}
+	}
+	fmt.Fprintf(&b, "- ハイライト: %d件\n", len(highlights))
+
+	WriteHighlights(&b, highlights, 2)
+	return b.String()
+}
+
+// WriteHighlights はハイライトを章ごとに書き出す関数
+// level は章の見出しのレベル（2なら "##"）。ほかの文書の一部として書き出すときは、その文書の見出しに合わせる
+func WriteHighlights(b *strings.Builder, highlights []*model.Highlight, level int) {
+	chapter := ""
+	for _, h := range highlights {
+		if h.Chapter != chapter {
+			// 章のあるハイライトの後に章のないハイライトが続く場合は、前の章に含まれて見えないよう見出しを入れる
+			title := oneLine(h.Chapter)
+			if title == "" {
+				title = "章の指定なし"
+			}
+			fmt.Fprintf(b, "\n%s %s\n", strings.Repeat("#", level), title)
+		}
+		chapter = h.Chapter
+		b.WriteString("\n")
+		writeHighlight(b, h)
+	}
+}
+
+// writeHighlight はハイライト1件を引用ブロックと補足の行として書き出す関数
+func writeHighlight(b *strings.Builder, h *model.Highlight) {
+	for _, line := range strings.Split(strings.TrimSpace(strings.ReplaceAll(h.Text, "\r\n", "\n")), "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
+
+	// 補足の行：種類（引用のみ）・ページ・位置・日付・タグ
+	meta := []string{}
+	if h.Kind == model.KindQuote {
+		meta = append(meta, "引用")
+	}
+	if h.Page != nil {
+		meta = append(meta, fmt.Sprintf("p.%d", *h.Page))
+	}
+	if h.Location != "" {
+		meta = append(meta, "位置 "+oneLine(h.Location))
+	}
+	meta = append(meta, h.CreatedAt.Format("2006-01-02"))
+	for _, tag := range strings.Split(h.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			// 空白を含むタグはノートアプリでタグとして認識されないため、_ に置き換える
+			meta = append(meta, "#"+strings.Join(strings.Fields(tag), "_"))
+		}
+	}
+	fmt.Fprintf(b, "\n— %s\n", strings.Join(meta, " · "))
+
+	if note := strings.TrimSpace(h.Note); note != "" {
+		fmt.Fprintf(b, "\n**メモ**: %s\n", note)
+	}
+}
+
+// Filename は書籍のMarkdownのファイル名を返す関数（タイトルからファイル名に使えない文字を除く）
+func Filename(book *model.Book) string {
+	name := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '-'
+		}
+		return r
+	}, book.Title))
+	if name == "" {
+		name = fmt.Sprintf("book-%d", book.ID)
+	}
+	return name + ".md"
+}
+
+// oneLine は改行を空白に置き換えて1行にする関数（見出しや箇条書きが崩れないようにする）
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
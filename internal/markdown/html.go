@@ -0,0 +1,439 @@
+package markdown
+
+import (
+	"fmt"     // 文字列フォーマット
+	"html"    // HTMLのエスケープ
+	"net/url" // リンク先の確認
+	"regexp"  // ブロックの判定
+	"strconv" // 番号付きリストの開始番号
+	"strings" // 文字列操作
+	"unicode" // 単語の境界の判定
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// HTMLContentType はHTMLの断片のContent-Type
+const HTMLContentType = "text/html; charset=utf-8"
+
+// ブロックの判定に使う正規表現
+var (
+	headingLine = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`) // # 見出し
+	ruleLine    = regexp.MustCompile(`^ {0,3}([-*_])(?:\s*[-*_]){2,}\s*$`)         // --- 区切り線
+	fenceLine   = regexp.MustCompile("^ {0,3}(```+|~~~+)\\s*([A-Za-z0-9_+-]*)")    // ``` コードブロック
+	listLine    = regexp.MustCompile(`^ {0,3}([-*+]|\d{1,9}[.)])(?:\s+(.*))?$`)    // - 箇条書き、1. 番号付きリスト
+	quoteLine   = regexp.MustCompile(`^ {0,3}>`)                                   // > 引用
+)
+
+// ToHTML はMarkdownの文書を、そのまま表示しても安全なHTMLの断片に変換する関数
+//
+// 書いた人以外も見るレビューなどに使うため、次のように安全性を優先する
+//   - 文書中のHTMLのタグは解釈せず、すべて文字としてエスケープする（<script> などを埋め込ませない）
+//   - リンク先は http・https・mailto と相対URLだけを許可し、javascript: などは文字として表示する
+//   - 画像は外部への読み込み（閲覧の追跡など）を防ぐため、画像ではなくリンクにする
+//
+// 対応する書式は、見出し・段落・強調（** と *）・取り消し線（~~）・コード・コードブロック・引用・箇条書き・番号付きリスト・区切り線・リンク
+// 段落の中の改行は、日本語の文章で書いたとおりに表示されるよう <br> にする
+func ToHTML(src string) string {
+	src = strings.ReplaceAll(strings.ReplaceAll(src, "\r\n", "\n"), "\t", "    ")
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"))
+	return b.String()
+}
+
+// Review はレビューをHTMLの断片にする関数
+// ネタバレを含むレビューは、開くまで本文が見えないよう <details> で折りたたむ
+func Review(review *model.Review) string {
+	var b strings.Builder
+	b.WriteString(`<article class="review">` + "\n")
+	if review.Title != "" {
+		fmt.Fprintf(&b, "<h2>%s</h2>\n", html.EscapeString(oneLine(review.Title)))
+	}
+	body := ToHTML(review.Body)
+	if review.Spoiler {
+		body = `<details class="spoiler">` + "\n<summary>ネタバレを含みます</summary>\n" + body + "</details>\n"
+	}
+	b.WriteString(body)
+	b.WriteString("</article>\n")
+	return b.String()
+}
+
+// renderBlocks は行の並びをブロック（見出し・段落・リストなど）ごとにHTMLにする関数
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fenceLine.MatchString(line):
+			i = renderFence(b, lines, i)
+		case headingLine.MatchString(line):
+			m := headingLine.FindStringSubmatch(line)
+			level := len(m[1])
+			fmt.Fprintf(b, "<h%d>%s</h%d>\n", level, renderInline(strings.TrimSpace(m[2])), level)
+			i++
+		case ruleLine.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case quoteLine.MatchString(line):
+			i = renderQuote(b, lines, i)
+		case listLine.MatchString(line):
+			i = renderList(b, lines, i)
+		default:
+			i = renderParagraph(b, lines, i)
+		}
+	}
+}
+
+// startsBlock は段落を終わらせる行（段落以外のブロックの始まり）かどうかを判定する関数
+func startsBlock(line string) bool {
+	return fenceLine.MatchString(line) || headingLine.MatchString(line) || ruleLine.MatchString(line) ||
+		quoteLine.MatchString(line) || listLine.MatchString(line)
+}
+
+// renderParagraph は段落を書き出し、次の行の位置を返す関数
+func renderParagraph(b *strings.Builder, lines []string, i int) int {
+	paragraph := []string{}
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		if len(paragraph) > 0 && startsBlock(lines[i]) {
+			break
+		}
+		paragraph = append(paragraph, renderInline(strings.TrimSpace(lines[i])))
+	}
+	fmt.Fprintf(b, "<p>%s</p>\n", strings.Join(paragraph, "<br>\n"))
+	return i
+}
+
+// renderFence はコードブロックを書き出し、次の行の位置を返す関数（閉じる行がなければ文書の最後まで）
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	m := fenceLine.FindStringSubmatch(lines[i])
+	fence, lang := m[1], m[2]
+	code := []string{}
+	for i++; i < len(lines); i++ {
+		if trimmed := strings.TrimSpace(lines[i]); strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	if lang != "" {
+		// 言語名は英数字と一部の記号だけを正規表現で許可しているため、属性に入れても安全
+		fmt.Fprintf(b, `<pre><code class="language-%s">`, lang)
+	} else {
+		b.WriteString("<pre><code>")
+	}
+	b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	if len(code) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderQuote は引用を書き出し、次の行の位置を返す関数（引用の中身もブロックとして解釈する）
+func renderQuote(b *strings.Builder, lines []string, i int) int {
+	inner := []string{}
+	for ; i < len(lines) && quoteLine.MatchString(lines[i]); i++ {
+		line := strings.TrimLeft(lines[i], " ")
+		line = strings.TrimPrefix(strings.TrimPrefix(line, ">"), " ")
+		inner = append(inner, line)
+	}
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, inner)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+// renderList は箇条書き・番号付きリストを書き出し、次の行の位置を返す関数
+// 項目の次の行からの字下げした行は項目の続き（入れ子のリストなど）として扱う
+func renderList(b *strings.Builder, lines []string, i int) int {
+	first := listLine.FindStringSubmatch(lines[i])
+	ordered := first[1][0] >= '0' && first[1][0] <= '9'
+	if ordered {
+		start, _ := strconv.Atoi(first[1][:len(first[1])-1])
+		if start != 1 {
+			fmt.Fprintf(b, "<ol start=\"%d\">\n", start)
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	for i < len(lines) {
+		m := listLine.FindStringSubmatch(lines[i])
+		if m == nil || (m[1][0] >= '0' && m[1][0] <= '9') != ordered {
+			break
+		}
+		item := []string{m[2]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// 空行の後に字下げした行が続けば項目の続き、そうでなければリストの終わり
+				if i+1 < len(lines) && indented(lines[i+1]) {
+					item = append(item, "")
+					continue
+				}
+				break
+			}
+			if indented(line) {
+				item = append(item, dedent(line))
+				continue
+			}
+			if startsBlock(line) {
+				break
+			}
+			item = append(item, line) // 字下げしていない続きの行（項目の文章の折り返し）
+		}
+
+		b.WriteString("<li>")
+		// 項目の最初の文章は <p> で囲まずに書き、残り（入れ子のリストなど）はブロックとして書く
+		text := []string{}
+		rest := item
+		for len(rest) > 0 && strings.TrimSpace(rest[0]) != "" && (len(text) == 0 || !startsBlock(rest[0])) {
+			text = append(text, renderInline(strings.TrimSpace(rest[0])))
+			rest = rest[1:]
+		}
+		b.WriteString(strings.Join(text, "<br>\n"))
+		if len(rest) > 0 {
+			b.WriteString("\n")
+			renderBlocks(b, rest)
+		}
+		b.WriteString("</li>\n")
+
+		// 項目の間の空行は読み飛ばす
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" && i+1 < len(lines) && listLine.MatchString(lines[i+1]) {
+			i++
+		}
+	}
+
+	if ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+// indented は行が項目の続きとして字下げされているか（空白2つ以上で始まるか）を判定する関数
+func indented(line string) bool {
+	return strings.HasPrefix(line, "  ") && strings.TrimSpace(line) != ""
+}
+
+// dedent は項目の続きの行の字下げを取り除く関数（入れ子のリストを解釈できるよう、最大4文字分）
+func dedent(line string) string {
+	for n := 0; n < 4 && strings.HasPrefix(line, " "); n++ {
+		line = line[1:]
+	}
+	return line
+}
+
+// renderInline は1行の中の書式（強調・コード・リンクなど）をHTMLにする関数
+// 書式として解釈しない文字はすべてエスケープする
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.IndexByte("\\`*_{}[]()#+-.!~>|<", rest[1]) >= 0:
+			// バックスラッシュの後の記号は書式ではなく文字として扱う
+			b.WriteString(html.EscapeString(rest[1:2]))
+			i += 2
+		case rest[0] == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			ticks := rest[:n]
+			if end := strings.Index(rest[n:], ticks); end >= 0 {
+				fmt.Fprintf(&b, "<code>%s</code>", html.EscapeString(strings.TrimSpace(rest[n:n+end])))
+				i += n + end + n
+				continue
+			}
+			b.WriteString(ticks)
+			i += n
+		case strings.HasPrefix(rest, "![") || rest[0] == '[':
+			text, target, n := parseLink(rest)
+			if n == 0 {
+				b.WriteString(html.EscapeString(rest[:1]))
+				i++
+				continue
+			}
+			label := renderInline(text)
+			if strings.HasPrefix(rest, "!") && text == "" {
+				label = html.EscapeString(target)
+			}
+			b.WriteString(link(target, label))
+			i += n
+		case rest[0] == '<':
+			// <https://...> の形の自動リンク（それ以外の < はエスケープする）
+			if end := strings.IndexByte(rest, '>'); end > 0 && isURL(rest[1:end]) {
+				b.WriteString(link(rest[1:end], html.EscapeString(rest[1:end])))
+				i += end + 1
+				continue
+			}
+			b.WriteString("&lt;")
+			i++
+		case (strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://")) && wordStart(s, i):
+			// 文章中にそのまま書いたURL（末尾の句読点や括弧はURLに含めない）
+			end := strings.IndexFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == '<' || r > unicode.MaxASCII })
+			if end < 0 {
+				end = len(rest)
+			}
+			target := strings.TrimRight(rest[:end], ".,;:!?)'\"")
+			b.WriteString(link(target, html.EscapeString(target)))
+			i += len(target)
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__") || strings.HasPrefix(rest, "~~"):
+			tag := map[string]string{"**": "strong", "__": "strong", "~~": "del"}[rest[:2]]
+			if inner, n := emphasis(s, i, rest[:2]); n > 0 {
+				fmt.Fprintf(&b, "<%s>%s</%s>", tag, renderInline(inner), tag)
+				i += n
+				continue
+			}
+			b.WriteString(html.EscapeString(rest[:2]))
+			i += 2
+		case rest[0] == '*' || rest[0] == '_':
+			if inner, n := emphasis(s, i, rest[:1]); n > 0 {
+				fmt.Fprintf(&b, "<em>%s</em>", renderInline(inner))
+				i += n
+				continue
+			}
+			b.WriteByte(rest[0])
+			i++
+		default:
+			// 次の書式の候補の文字までをまとめてエスケープする
+			n := strings.IndexAny(rest[1:], "\\`[!<*_~h")
+			if n < 0 {
+				n = len(rest) - 1
+			}
+			b.WriteString(html.EscapeString(rest[:n+1]))
+			i += n + 1
+		}
+	}
+	return b.String()
+}
+
+// emphasis は s[i:] が delim で始まる強調のとき、中身と強調全体の長さを返す関数（強調でなければ長さ0）
+// 「* 」のように区切りの直後が空白のものや、「snake_case」のように単語の途中の _ は強調として扱わない
+func emphasis(s string, i int, delim string) (string, int) {
+	rest := s[i+len(delim):]
+	if rest == "" || rest[0] == ' ' {
+		return "", 0
+	}
+	if delim[0] == '_' && !wordStart(s, i) {
+		return "", 0
+	}
+	for from := 0; from < len(rest); {
+		end := strings.Index(rest[from:], delim)
+		if end < 0 {
+			return "", 0
+		}
+		end += from
+		after := end + len(delim)
+		closing := end > 0 && rest[end-1] != ' '
+		if delim[0] == '_' && after < len(rest) && isWordByte(rest[after]) {
+			closing = false
+		}
+		// 「**」の中の「*」のように、同じ記号が続く場合は閉じる区切りとしない
+		if len(delim) == 1 && after < len(rest) && rest[after] == delim[0] {
+			closing = false
+		}
+		if closing {
+			return rest[:end], len(delim) + after
+		}
+		from = end + 1
+	}
+	return "", 0
+}
+
+// parseLink は [文字](URL) または ![代替テキスト](URL) を読み取り、文字・URL・全体の長さを返す関数（リンクでなければ長さ0）
+func parseLink(s string) (string, string, int) {
+	start := strings.IndexByte(s, '[')
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if i+1 >= len(s) || s[i+1] != '(' {
+					return "", "", 0
+				}
+				end := closingParen(s[i+2:])
+				if end < 0 {
+					return "", "", 0
+				}
+				target := strings.TrimSpace(s[i+2 : i+2+end])
+				// リンクのタイトル（[文字](URL "タイトル")）は使わない
+				if space := strings.IndexAny(target, " \t"); space >= 0 {
+					target = target[:space]
+				}
+				target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+				return s[start+1 : i], target, i + 3 + end
+			}
+		}
+	}
+	return "", "", 0
+}
+
+// closingParen はURLの終わりの「)」の位置を返す関数（URLの中の対になった括弧は飛ばす。なければ -1）
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// link はリンクのHTMLを返す関数
+// 安全でないリンク先（javascript: など）の場合は、リンクにせずに文字だけを返す
+func link(target, label string) string {
+	if !safeURL(target) {
+		return label
+	}
+	return fmt.Sprintf(`<a href="%s" rel="nofollow noopener noreferrer">%s</a>`, html.EscapeString(target), label)
+}
+
+// safeURL はリンク先として許可するURLかどうかを判定する関数
+// http・https・mailto と、スキームのない相対URL（/books/1、#section など）だけを許可する
+func safeURL(target string) bool {
+	if target == "" {
+		return false
+	}
+	u, err := url.Parse(target) // 制御文字を含むURLはエラーになる
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "mailto":
+		return true
+	case "":
+		// 「//example.com」のようなスキームの省略はhttpと同じだが、判定を単純にするため許可しない
+		return !strings.HasPrefix(target, "//") && !strings.HasPrefix(target, `\\`)
+	}
+	return false
+}
+
+// isURL は自動リンクにする文字列（http・https・mailto のURL）かどうかを判定する関数
+func isURL(s string) bool {
+	lower := strings.ToLower(s)
+	return (strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")) &&
+		!strings.ContainsAny(s, " \t<")
+}
+
+// wordStart は s[i] が単語の始まり（直前が英数字ではない）かどうかを判定する関数
+func wordStart(s string, i int) bool {
+	return i == 0 || !isWordByte(s[i-1])
+}
+
+// isWordByte は英数字かどうかを判定する関数
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
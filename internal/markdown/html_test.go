@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"strings" // 出力に含まれる文字列の確認
+	"testing" // テストの実行と結果の報告
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// TestToHTMLSanitize は文書中のHTMLや危険なリンクが、そのまま出力されないことを確認する
+func TestToHTMLSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"scriptタグ", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"属性付きのタグ", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{"javascript:のリンク", "[押す](javascript:alert(1))", "<p>押す</p>\n"},
+		{"大文字のjavascript:", "[押す](JavaScript:alert(1))", "<p>押す</p>\n"},
+		{"data:のリンク", "[押す](data:text/html;base64,PHNjcmlwdD4=)", "<p>押す</p>\n"},
+		{"制御文字を含むリンク", "[押す](java\x01script:alert(1))", "<p>押す</p>\n"},
+		{"スキームを省略したリンク", "[押す](//evil.example)", "<p>押す</p>\n"},
+		{"javascript:の自動リンク", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>\n"},
+		{"リンク先の引用符", `[a](https://example.com/"onmouseover="x)`, `<p><a href="https://example.com/&#34;onmouseover=&#34;x" rel="nofollow noopener noreferrer">a</a></p>` + "\n"},
+		{"リンクの文字のHTML", "[<b>太字</b>](https://example.com)", `<p><a href="https://example.com" rel="nofollow noopener noreferrer">&lt;b&gt;太字&lt;/b&gt;</a></p>` + "\n"},
+		{"画像はリンクにする", "![表紙](https://example.com/a.png)", `<p><a href="https://example.com/a.png" rel="nofollow noopener noreferrer">表紙</a></p>` + "\n"},
+		{"コードブロックの中のHTML", "```html\n<script>\n```", "<pre><code class=\"language-html\">&lt;script&gt;\n</code></pre>\n"},
+		{"コードブロックの言語名に記号", "```\"><script>\nx\n```", "<pre><code>x\n</code></pre>\n"},
+		{"インラインコードの中のHTML", "`<b>`", "<p><code>&lt;b&gt;</code></p>\n"},
+		{"見出しの中のHTML", "# <i>見出し</i>", "<h1>&lt;i&gt;見出し&lt;/i&gt;</h1>\n"},
+		{"アンパサンド", "A & B", "<p>A &amp; B</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.src); got != tt.want {
+				t.Errorf("ToHTML(%q) =\n%s\nwant\n%s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestToHTML は対応している書式がHTMLに変換されることを確認する
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"段落の中の改行", "一行目\r\n二行目\n\n次の段落", "<p>一行目<br>\n二行目</p>\n<p>次の段落</p>\n"},
+		{"見出し", "## 感想 ##", "<h2>感想</h2>\n"},
+		{"強調", "**太字**と*斜体*と~~取り消し~~", "<p><strong>太字</strong>と<em>斜体</em>と<del>取り消し</del></p>\n"},
+		{"単語の途中の_は強調にしない", "snake_case_name と _強調_", "<p>snake_case_name と <em>強調</em></p>\n"},
+		{"閉じていない強調", "**閉じていない", "<p>**閉じていない</p>\n"},
+		{"バックスラッシュ", `\*文字\*`, "<p>*文字*</p>\n"},
+		{"リンク", "[本](/books/1)と<https://example.com>", `<p><a href="/books/1" rel="nofollow noopener noreferrer">本</a>と<a href="https://example.com" rel="nofollow noopener noreferrer">https://example.com</a></p>` + "\n"},
+		{"文章中のURL", "詳しくは https://example.com/a_b. を見る", `<p>詳しくは <a href="https://example.com/a_b" rel="nofollow noopener noreferrer">https://example.com/a_b</a>. を見る</p>` + "\n"},
+		{"括弧を含むURL", "[wiki](https://en.wikipedia.org/wiki/Go_(language))", `<p><a href="https://en.wikipedia.org/wiki/Go_(language)" rel="nofollow noopener noreferrer">wiki</a></p>` + "\n"},
+		{"箇条書き", "- 一つ目\n- 二つ目\n  - 入れ子", "<ul>\n<li>一つ目</li>\n<li>二つ目\n<ul>\n<li>入れ子</li>\n</ul>\n</li>\n</ul>\n"},
+		{"番号付きリストの開始番号", "3. 三\n4. 四", "<ol start=\"3\">\n<li>三</li>\n<li>四</li>\n</ol>\n"},
+		{"引用", "> 引用の\n> 続き", "<blockquote>\n<p>引用の<br>\n続き</p>\n</blockquote>\n"},
+		{"区切り線", "前\n\n---\n\n後", "<p>前</p>\n<hr>\n<p>後</p>\n"},
+		{"閉じていないコードブロック", "~~~\ncode", "<pre><code>code\n</code></pre>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.src); got != tt.want {
+				t.Errorf("ToHTML(%q) =\n%s\nwant\n%s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestReview はレビューの見出しのエスケープと、ネタバレの折りたたみを確認する
+func TestReview(t *testing.T) {
+	tests := []struct {
+		name    string
+		review  *model.Review
+		want    []string
+		wantNot []string
+	}{
+		{"見出しのHTMLと改行", &model.Review{Title: "<b>題</b>\n二行目", Body: "本文"},
+			[]string{"<h2>&lt;b&gt;題&lt;/b&gt; 二行目</h2>", "<p>本文</p>"}, []string{"<details"}},
+		{"ネタバレ", &model.Review{Title: "題", Body: "犯人は**執事**", Spoiler: true},
+			[]string{`<details class="spoiler">`, "<summary>ネタバレを含みます</summary>", "<strong>執事</strong>"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Review(tt.review)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Review に %s が含まれていません\n%s", want, got)
+				}
+			}
+			for _, not := range tt.wantNot {
+				if strings.Contains(got, not) {
+					t.Errorf("Review に %s が含まれています\n%s", not, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+// ArchiveRelated は移行用アーカイブに書籍と一緒に含める関連データ
+// すべてのデータを書き出したインスタンスでのIDのまま持ち、取り込み先の表が空ならそのIDで、空でなければ新しいIDで取り込む
+type ArchiveRelated struct {
+	Locations       []*Location       // 保管場所（親の場所のIDも書き出し元のID）
+	Loans           []*Loan           // 貸し出しの記録
+	WishlistItems   []*WishlistItem   // 欲しい本（購入して作成した書籍のIDも書き出し元のID）
+	PriceRecords    []*PriceRecord    // 欲しい本の価格の履歴
+	Goals           []*ReadingGoal    // 読書目標
+	Highlights      []*Highlight      // ハイライト・引用
+	Reviews         []*Review         // レビュー
+	ReviewRevisions []*ReviewRevision // レビューの編集履歴
+	Budgets         []*Budget         // 予算
+	ExchangeRates   []*ExchangeRate   // 為替レート（全ユーザー共通）
+	AuditLogs       []*AuditLog       // 変更履歴（書籍の変更履歴の対象のIDも書き出し元のID）
+}
+
+// ArchivedRecords はアーカイブから取り込んだ関連データの件数
+type ArchivedRecords struct {
+	Locations       int `json:"locations"`        // 新しく作成した場所（同じ名前の場所が既にあればそれを使い、数えない）
+	Loans           int `json:"loans"`            // 貸し出しの記録
+	WishlistItems   int `json:"wishlist_items"`   // 欲しい本（同じ欲しい本が既にあれば取り込まない）
+	PriceRecords    int `json:"price_records"`    // 欲しい本の価格の履歴
+	Goals           int `json:"goals"`            // 読書目標（同じ目標が既にあれば取り込まない）
+	Highlights      int `json:"highlights"`       // ハイライト・引用
+	Reviews         int `json:"reviews"`          // レビュー
+	ReviewRevisions int `json:"review_revisions"` // レビューの編集履歴
+	Budgets         int `json:"budgets"`          // 予算（同じ本棚・期間の予算が既にあれば取り込まない）
+	ExchangeRates   int `json:"exchange_rates"`   // 為替レート（同じ通貨・日付のレートが既にあれば取り込まない）
+	AuditLogs       int `json:"audit_logs"`       // 変更履歴
+}
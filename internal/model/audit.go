@@ -0,0 +1,108 @@
+package model
+
+import (
+	"bytes"         // 項目の値の比較
+	"encoding/json" // 変更前後のデータ（JSON）を保持するため
+	"sort"          // 項目名の並び替え
+	"time"          // 時間関連の型（time.Time）を使うため
+)
+
+// 変更履歴の対象の種類
+const (
+	EntityBook  = "book"  // 書籍
+	EntityShare = "share" // 共有設定
+)
+
+// 変更履歴の操作の種類
+const (
+	ActionCreate  = "create"  // 作成
+	ActionUpdate  = "update"  // 更新
+	ActionDelete  = "delete"  // 削除（ゴミ箱に移す）
+	ActionRestore = "restore" // ゴミ箱から元に戻す
+	ActionRevert  = "revert"  // 以前の版に戻す
+)
+
+// AuditLog は変更履歴（誰が・いつ・何を・どう変えたか）の1件を表すモデル
+// 変更前後のデータをJSONのまま保存するため、後から項目が増えても同じ形で記録できる
+type AuditLog struct {
+	ID         int             `json:"id" db:"id"`                        // 履歴の一意なID番号
+	ActorID    int             `json:"actor_id" db:"actor_id"`            // 変更した人のユーザーID（0は未ログインまたはコマンド）
+	ActorName  string          `json:"actor_name" db:"actor_name"`        // 変更した人のユーザー名
+	OwnerID    int             `json:"owner_id" db:"owner_id"`            // 変更されたデータの持ち主のユーザーID（0は共有の本棚）
+	EntityType string          `json:"entity_type" db:"entity_type"`      // 対象の種類（book、share）
+	EntityID   int             `json:"entity_id" db:"entity_id"`          // 対象のID
+	Action     string          `json:"action" db:"action"`                // 操作の種類（create、update、delete、restore、revert）
+	Before     json.RawMessage `json:"before,omitempty" db:"before_data"` // 変更前のデータ（作成時はなし）
+	After      json.RawMessage `json:"after,omitempty" db:"after_data"`   // 変更後のデータ（削除時はなし）
+	RequestID  string          `json:"request_id" db:"request_id"`        // 変更したAPIリクエストのID（空文字はコマンドや定期処理）
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`        // 変更日時
+	Changes    []AuditChange   `json:"changes,omitempty" db:"-"`          // 項目ごとの変更（変更前後のデータから計算する）
+}
+
+// AuditChange は変更履歴の1項目の変更（変更前と変更後の値）
+type AuditChange struct {
+	Field  string          `json:"field"`  // 項目名（JSONのキー。例：rating）
+	Before json.RawMessage `json:"before"` // 変更前の値（作成時や項目がなかった場合は null）
+	After  json.RawMessage `json:"after"`  // 変更後の値（削除時や項目がなくなった場合は null）
+}
+
+// auditIgnoredFields は項目ごとの変更に含めない項目
+// IDは entity_id と同じ、更新日時は変更のたびに変わり、貸し出しは書籍の項目ではない（貸し出しの記録で確認できる）ため
+var auditIgnoredFields = map[string]bool{"id": true, "updated_at": true, "loan": true}
+
+// Diff は変更前後のデータを比べ、値が変わった項目を項目名の順に返すメソッド
+// 作成ではすべての項目が null からの変更、削除ではすべての項目が null への変更になる
+func (a *AuditLog) Diff() []AuditChange {
+	before, after := map[string]json.RawMessage{}, map[string]json.RawMessage{}
+	if len(a.Before) > 0 && json.Unmarshal(a.Before, &before) != nil {
+		return nil
+	}
+	if len(a.After) > 0 && json.Unmarshal(a.After, &after) != nil {
+		return nil
+	}
+
+	fields := []string{}
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []AuditChange{}
+	for _, field := range fields {
+		from, to := jsonOrNull(before[field]), jsonOrNull(after[field])
+		if auditIgnoredFields[field] || bytes.Equal(from, to) {
+			continue
+		}
+		changes = append(changes, AuditChange{Field: field, Before: from, After: to})
+	}
+	return changes
+}
+
+// jsonOrNull は値がなければ JSON の null を返す
+func jsonOrNull(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+	return value
+}
+
+// RevertBookRequest は書籍を以前の版に戻すときのリクエスト構造体
+// 戻す版は変更履歴のIDで指定し、その変更の直後の状態（削除の履歴なら削除の直前の状態）に戻す
+type RevertBookRequest struct {
+	AuditID int `json:"audit_id" validate:"required,min=1"` // 戻す版の変更履歴のID
+}
+
+// AuditFilter は変更履歴の絞り込み条件
+type AuditFilter struct {
+	OwnerID    int    // 持ち主のユーザーID（この人のデータの履歴だけを対象にする）
+	EntityType string // 対象の種類（空文字はすべて）
+	EntityID   int    // 対象のID（0はすべて）
+	Action     string // 操作の種類（空文字はすべて）
+	ActorID    int    // 変更した人のユーザーID（0はすべて）
+	RequestID  string // APIリクエストのID（空文字はすべて）
+}
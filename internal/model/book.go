@@ -3,7 +3,8 @@
 package model
 
 import (
-	"time"  // 時間関連の型（time.Time）を使うため
+	"strings" // タグの分割
+	"time"    // 時間関連の型（time.Time）を使うため
 )
 
 // ReadingStatus は読書の状況を表す列挙型（決められた値のみ使える型）
@@ -19,6 +20,58 @@ const (
 	StatusDropped    ReadingStatus = "dropped"     // 中断（途中でやめた）
 )
 
+// IsValid は読書ステータスが決められた値かどうかを判定するメソッド
+func (s ReadingStatus) IsValid() bool {
+	switch s {
+	case StatusNotStarted, StatusReading, StatusCompleted, StatusDropped:
+		return true
+	}
+	return false
+}
+
+// PurchaseChannel は書籍を買った方法を表す列挙型（空文字は未設定）
+type PurchaseChannel string
+
+// 購入方法の定数定義
+const (
+	ChannelInStore    PurchaseChannel = "in_store"   // 書店の店頭
+	ChannelOnline     PurchaseChannel = "online"     // 通販・電子書籍ストア
+	ChannelSecondhand PurchaseChannel = "secondhand" // 古書店・フリマ
+	ChannelGift       PurchaseChannel = "gift"       // もらった本
+	ChannelOther      PurchaseChannel = "other"      // その他
+)
+
+// IsValid は購入方法が決められた値（または未設定）かどうかを判定するメソッド
+func (c PurchaseChannel) IsValid() bool {
+	switch c {
+	case "", ChannelInStore, ChannelOnline, ChannelSecondhand, ChannelGift, ChannelOther:
+		return true
+	}
+	return false
+}
+
+// BookFormat は書籍の形態を表す列挙型（空文字は未設定）
+type BookFormat string
+
+// 書籍の形態の定数定義
+const (
+	FormatPaperback BookFormat = "paperback" // 文庫・新書・ソフトカバー
+	FormatHardcover BookFormat = "hardcover" // 単行本（ハードカバー）
+	FormatEbook     BookFormat = "ebook"     // 電子書籍
+	FormatAudiobook BookFormat = "audiobook" // オーディオブック
+	FormatMagazine  BookFormat = "magazine"  // 雑誌・ムック
+	FormatOther     BookFormat = "other"     // その他
+)
+
+// IsValid は書籍の形態が決められた値（または未設定）かどうかを判定するメソッド
+func (f BookFormat) IsValid() bool {
+	switch f {
+	case "", FormatPaperback, FormatHardcover, FormatEbook, FormatAudiobook, FormatMagazine, FormatOther:
+		return true
+	}
+	return false
+}
+
 // Book は書籍情報を表すモデル（データ構造）
 // struct：複数のデータをまとめて一つの型にする仕組み
 // `json:"xxx"` と `db:"xxx"`：JSON形式とデータベースでの項目名を指定
@@ -30,7 +83,8 @@ type Book struct {
 	Publisher     string        `json:"publisher" db:"publisher"`           // 出版社名
 	PublishedDate *time.Time    `json:"published_date" db:"published_date"` // 出版日（*は値がnullの可能性があることを示す）
 	PurchaseDate  time.Time     `json:"purchase_date" db:"purchase_date"`   // 購入日
-	PurchasePrice int           `json:"purchase_price" db:"purchase_price"` // 購入価格（円）
+	PurchasePrice int           `json:"purchase_price" db:"purchase_price"` // 購入価格（currency の最小単位。円なら1円、ドルなら1セント）
+	Currency      string        `json:"currency" db:"currency"`             // 購入価格の通貨（ISO 4217 の通貨コード、例：JPY・USD）
 	Status        ReadingStatus `json:"status" db:"status"`                 // 読書ステータス
 	StartReadDate *time.Time    `json:"start_read_date" db:"start_read_date"` // 読書開始日（nullable）
 	EndReadDate   *time.Time    `json:"end_read_date" db:"end_read_date"`   // 読書終了日（nullable）
@@ -39,6 +93,25 @@ type Book struct {
 	Tags          string        `json:"tags" db:"tags"`                     // タグ（カンマ区切り文字列）
 	CreatedAt     time.Time     `json:"created_at" db:"created_at"`         // 作成日時
 	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`         // 更新日時
+	OwnerID       int           `json:"owner_id,omitempty" db:"owner_id"`   // 所有者のユーザーID（0はユーザー登録前からある共有の本棚）
+	LocationID    *int          `json:"location_id" db:"location_id"`       // 保管場所のID（nilは場所が未設定）
+	PageCount     *int          `json:"page_count" db:"page_count"`         // ページ数（nilは未登録。読書目標のページ数の集計に使う）
+	Store           string          `json:"store" db:"store"`                       // 購入した店（例：紀伊國屋書店、Amazon）
+	PurchaseChannel PurchaseChannel `json:"purchase_channel" db:"purchase_channel"` // 購入方法（空文字は未設定）
+	Format          BookFormat      `json:"format" db:"format"`                     // 書籍の形態（空文字は未設定）
+	Loan          *Loan         `json:"loan,omitempty" db:"-"`              // 現在の貸し出し（貸し出し中でなければnil）
+	DeletedAt     *time.Time    `json:"deleted_at,omitempty" db:"deleted_at"` // ゴミ箱に移した日時（nilはゴミ箱に入っていない）
+}
+
+// HasTag は書籍に指定したタグが付いているかどうかを判定するメソッド
+// 部分一致ではなく、カンマ区切りの各タグと完全に一致するか（大文字・小文字は区別しない）を確認する
+func (b *Book) HasTag(tag string) bool {
+	for _, t := range strings.Split(b.Tags, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), strings.TrimSpace(tag)) {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateBookRequest は書籍作成時のリクエスト構造体
@@ -51,9 +124,14 @@ type CreateBookRequest struct {
 	Publisher     string     `json:"publisher"`                         // 出版社（任意）
 	PublishedDate *time.Time `json:"published_date"`                   // 出版日（任意、nullの可能性あり）
 	PurchaseDate  time.Time  `json:"purchase_date" validate:"required"` // 購入日（必須）
-	PurchasePrice int        `json:"purchase_price"`                   // 購入価格（任意）
+	PurchasePrice int        `json:"purchase_price"`                   // 購入価格（任意、通貨の最小単位）
+	Currency      string     `json:"currency"`                         // 購入価格の通貨（任意、省略すると基準通貨）
 	Tags          string     `json:"tags"`                              // タグ（任意）
 	Notes         string     `json:"notes"`                             // メモ（任意）
+	PageCount     *int       `json:"page_count"`                        // ページ数（任意）
+	Store           string          `json:"store"`            // 購入した店（任意）
+	PurchaseChannel PurchaseChannel `json:"purchase_channel"` // 購入方法（任意）
+	Format          BookFormat      `json:"format"`           // 書籍の形態（任意）
 }
 
 // UpdateBookRequest は書籍更新時のリクエスト構造体
@@ -66,21 +144,29 @@ type UpdateBookRequest struct {
 	Publisher     *string        `json:"publisher"`      // 出版社（更新する場合のみ）
 	PublishedDate *time.Time     `json:"published_date"` // 出版日（更新する場合のみ）
 	PurchasePrice *int           `json:"purchase_price"` // 購入価格（更新する場合のみ）
+	Currency      *string        `json:"currency"`       // 購入価格の通貨（更新する場合のみ）
 	Status        *ReadingStatus `json:"status"`         // 読書ステータス（更新する場合のみ）
 	StartReadDate *time.Time     `json:"start_read_date"` // 読書開始日（更新する場合のみ）
 	EndReadDate   *time.Time     `json:"end_read_date"`  // 読書終了日（更新する場合のみ）
 	Rating        *int           `json:"rating"`         // 評価（更新する場合のみ）
 	Notes         *string        `json:"notes"`          // メモ（更新する場合のみ）
 	Tags          *string        `json:"tags"`           // タグ（更新する場合のみ）
+	PageCount     *int           `json:"page_count"`     // ページ数（更新する場合のみ）
+	Store           *string          `json:"store"`            // 購入した店（更新する場合のみ）
+	PurchaseChannel *PurchaseChannel `json:"purchase_channel"` // 購入方法（更新する場合のみ）
+	Format          *BookFormat      `json:"format"`           // 書籍の形態（更新する場合のみ）
 }
 
 // BookFilter は書籍検索用のフィルター構造体
 // 書籍一覧を取得する時の検索・絞り込み条件を指定する形式
 type BookFilter struct {
-	Status    *ReadingStatus `json:"status"`    // 読書ステータスで絞り込み
-	Author    *string        `json:"author"`    // 著者名で絞り込み
-	Publisher *string        `json:"publisher"` // 出版社で絞り込み
-	Tag       *string        `json:"tag"`       // タグで絞り込み
-	Rating    *int           `json:"rating"`    // 評価で絞り込み
-	Search    *string        `json:"search"`    // タイトル・著者の部分一致検索
+	Status     *ReadingStatus `json:"status"`      // 読書ステータスで絞り込み
+	Author     *string        `json:"author"`      // 著者名で絞り込み
+	Publisher  *string        `json:"publisher"`   // 出版社で絞り込み
+	Tag        *string        `json:"tag"`         // タグで絞り込み
+	ExactTag   *string        `json:"exact_tag"`   // タグの完全一致で絞り込み（Book.HasTag と同じ。タグで範囲を絞った共有に使う）
+	Rating     *int           `json:"rating"`      // 評価で絞り込み
+	Search     *string        `json:"search"`      // タイトル・著者の部分一致検索
+	OnLoan     *bool          `json:"on_loan"`     // 貸し出し中かどうかで絞り込み
+	LocationID *int           `json:"location_id"` // 保管場所で絞り込み（下の場所にある書籍も含む）
 }
\ No newline at end of file
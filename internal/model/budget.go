@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// BudgetPeriod は予算の期間の種類を表す列挙型
+type BudgetPeriod string
+
+// 予算の期間の種類の定数定義
+const (
+	BudgetMonthly BudgetPeriod = "month" // 毎月の予算
+	BudgetYearly  BudgetPeriod = "year"  // 毎年の予算
+)
+
+// BudgetStatus は予算に対する支出の状況を表す列挙型
+type BudgetStatus string
+
+// 予算に対する支出の状況の定数定義
+const (
+	BudgetNotSet  BudgetStatus = "not_set" // 予算が設定されていない
+	BudgetOK      BudgetStatus = "ok"      // 予算内
+	BudgetWarning BudgetStatus = "warning" // 予算の残りが少ない（警告の割合以上を使った）
+	BudgetOver    BudgetStatus = "over"    // 予算を超えた
+)
+
+// Budget は1か月または1年に書籍に使える金額を表すモデル
+// 期間の種類ごとに1件で、毎月・毎年同じ金額を使う
+type Budget struct {
+	ID        int          `json:"id" db:"id"`                       // 予算の一意なID番号
+	OwnerID   int          `json:"owner_id,omitempty" db:"owner_id"` // 所有者のユーザーID（0は共有の本棚の予算）
+	Period    BudgetPeriod `json:"period" db:"period"`               // 期間の種類
+	Amount    int          `json:"amount" db:"amount"`               // 予算（基準通貨の最小単位）
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`       // 作成日時
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`       // 更新日時
+}
+
+// SetBudgetRequest は予算を設定するときのリクエスト構造体
+type SetBudgetRequest struct {
+	Amount *int `json:"amount" validate:"required,min=0"` // 予算（基準通貨の最小単位、必須）
+}
+
+// BudgetUsage は1つの期間の予算と実際の支出
+type BudgetUsage struct {
+	Period    BudgetPeriod `json:"period"`    // 期間の種類
+	Label     string       `json:"label"`     // 期間（2026-10 または 2026）
+	Budget    *int         `json:"budget"`    // 予算（未設定ならnull）
+	Spent     int          `json:"spent"`     // 期間内に購入した書籍の購入金額の合計（基準通貨の最小単位）
+	Books     int          `json:"books"`     // 期間内に購入した冊数
+	Remaining *int         `json:"remaining"` // 予算の残り（超えていれば負の値、未設定ならnull）
+	Percent   *float64     `json:"percent"`   // 予算に対する支出の割合（％、未設定ならnull）
+	Status    BudgetStatus `json:"status"`    // 予算に対する状況
+}
+
+// BudgetAlert は予算の警告
+type BudgetAlert struct {
+	Period  BudgetPeriod `json:"period"`  // 期間の種類
+	Label   string       `json:"label"`   // 期間
+	Status  BudgetStatus `json:"status"`  // warning または over
+	Message string       `json:"message"` // 表示用のメッセージ
+}
+
+// BudgetReport は予算と実際の支出の報告
+// 月ごとの支出は、その年の各月を毎月の予算と比べる
+type BudgetReport struct {
+	Year             int              `json:"year"`              // 対象の年
+	Month            int              `json:"month"`             // 対象の月
+	Currency         string           `json:"currency"`          // 予算と支出の通貨（基準通貨）
+	UnconvertedBooks int              `json:"unconverted_books"` // 為替レートがなく支出に含められなかった対象の年の書籍の冊数
+	Monthly          *BudgetUsage     `json:"monthly"`           // 対象の月の予算と支出
+	Yearly           *BudgetUsage     `json:"yearly"`            // 対象の年の予算と支出
+	Months           []*BudgetUsage   `json:"months"`            // 対象の年の月ごとの予算と支出（1月〜12月）
+	Alerts           []*BudgetAlert   `json:"alerts"`            // 予算を超えた・超えそうな期間の警告
+	ByStore          []*BreakdownItem `json:"by_store"`          // 対象の年の店ごとの支出（金額の多い順）
+	ByFormat         []*BreakdownItem `json:"by_format"`         // 対象の年の形態ごとの支出（金額の多い順）
+	ByTag            []*BreakdownItem `json:"by_tag"`            // 対象の年のタグごとの支出（金額の多い順）
+}
@@ -0,0 +1,54 @@
+package model
+
+// BulkOperation は複数の書籍にまとめて行う操作の種類を表す型
+type BulkOperation string
+
+// まとめて行う操作の定数定義
+const (
+	BulkSetStatus   BulkOperation = "set_status"   // 読書ステータスを変更する（status）
+	BulkAddTag      BulkOperation = "add_tag"      // タグを追加する（tag）
+	BulkRemoveTag   BulkOperation = "remove_tag"   // タグを外す（tag）
+	BulkSetLocation BulkOperation = "set_location" // 保管場所を変更する（location_id。nullは場所の設定を外す）
+	BulkDelete      BulkOperation = "delete"       // ゴミ箱に移す
+	BulkUpdate      BulkOperation = "update"       // 項目を更新する（fields。書籍の更新と同じ形式）
+)
+
+// 書籍ごとの結果の定数定義
+const (
+	BulkItemOK        = "ok"        // 操作した（取り消した場合は、操作できることを確認した）
+	BulkItemUnchanged = "unchanged" // すでに操作後の状態だったため何もしなかった
+	BulkItemError     = "error"     // 操作できなかった
+)
+
+// BulkRequest は複数の書籍にまとめて操作を行うときのリクエスト構造体
+// 対象は ids（書籍IDの一覧）と filter（書籍一覧と同じ絞り込み条件）のどちらか一方で指定する
+type BulkRequest struct {
+	IDs        []int              `json:"ids"`         // 対象の書籍のID（共有された書籍も役割に応じて指定できる）
+	Filter     *BookFilter        `json:"filter"`      // 対象の書籍の絞り込み条件（自分の本棚の書籍が対象）
+	Operation  BulkOperation      `json:"operation"`   // 操作の種類
+	Status     *ReadingStatus     `json:"status"`      // set_status の読書ステータス
+	Tag        string             `json:"tag"`         // add_tag・remove_tag のタグ
+	LocationID *int               `json:"location_id"` // set_location の保管場所のID
+	Fields     *UpdateBookRequest `json:"fields"`      // update で更新する項目
+	DryRun     bool               `json:"dry_run"`     // trueなら確認だけを行い、変更は取り消す
+}
+
+// BulkItemResult は1冊の書籍に対する操作の結果
+type BulkItemResult struct {
+	ID     int    `json:"id"`              // 書籍のID
+	Status string `json:"status"`          // 結果（ok・unchanged・error）
+	Error  string `json:"error,omitempty"` // 操作できなかった理由
+	Book   *Book  `json:"book,omitempty"`  // 操作後の書籍（削除した場合と操作できなかった場合はなし）
+}
+
+// BulkResult は複数の書籍にまとめて行った操作の結果
+type BulkResult struct {
+	Operation BulkOperation     `json:"operation"` // 操作の種類
+	Applied   bool              `json:"applied"`   // 変更を確定したか（1冊でも操作できなかった場合と dry_run ではfalse）
+	DryRun    bool              `json:"dry_run"`   // 確認だけを行ったか
+	Total     int               `json:"total"`     // 対象の書籍の数
+	Succeeded int               `json:"succeeded"` // 操作できた書籍の数
+	Unchanged int               `json:"unchanged"` // 何もしなかった書籍の数
+	Failed    int               `json:"failed"`    // 操作できなかった書籍の数
+	Results   []*BulkItemResult `json:"results"`   // 書籍ごとの結果（指定した順）
+}
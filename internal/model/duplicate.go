@@ -0,0 +1,52 @@
+package model
+
+// 重複の候補とみなした理由の定数定義
+const (
+	DuplicateISBN         = "isbn"          // ISBNが同じ（10桁と13桁、ハイフンの有無の違いは無視する）
+	DuplicateTitle        = "title"         // タイトルが似ている（全角・半角、カタカナ・ひらがなの違いは無視する）
+	DuplicateAuthor       = "author"        // 著者が似ている
+	DuplicatePurchaseDate = "purchase_date" // 購入日が近い
+)
+
+// DuplicateCandidate は同じ書籍を二重に登録したと思われる書籍の組
+type DuplicateCandidate struct {
+	Book    *Book    `json:"book"`    // 先に登録された書籍（統合先の候補）
+	Other   *Book    `json:"other"`   // 後から登録された書籍
+	Score   float64  `json:"score"`   // 重複らしさ（0〜1。1はISBNが同じ）
+	Reasons []string `json:"reasons"` // 候補とみなした理由
+}
+
+// MergeChoice は統合するときに項目の値をどちらの書籍から取るかを表す型
+type MergeChoice string
+
+// 項目の値の取り方の定数定義
+const (
+	MergeTarget MergeChoice = "target" // 統合先の書籍の値
+	MergeSource MergeChoice = "source" // 統合元（統合後に削除する書籍）の値
+	MergeBoth   MergeChoice = "both"   // 両方の値をつなげる（notes と tags だけ）
+)
+
+// MergeBooksRequest は2冊の書籍を1冊に統合するときのリクエスト構造体
+// fields を省略した項目は、統合先の値が空なら統合元の値を使う（notes と tags は両方をつなげる）
+type MergeBooksRequest struct {
+	TargetID int                    `json:"target_id" validate:"required,min=1"` // 統合先の書籍のID（残る書籍）
+	SourceID int                    `json:"source_id" validate:"required,min=1"` // 統合元の書籍のID（統合後に削除する書籍）
+	Fields   map[string]MergeChoice `json:"fields"`                              // 項目ごとの値の取り方（例：{"title": "source"}）
+}
+
+// MergedRecords は統合元から統合先に移した関連データの件数
+type MergedRecords struct {
+	Highlights        int `json:"highlights"`         // ハイライト
+	SkippedHighlights int `json:"skipped_highlights"` // 両方の書籍に同じものが取り込まれていたため移さなかったハイライト
+	Reviews           int `json:"reviews"`            // レビュー（読書の回は統合先の後に続く番号にする）
+	Loans             int `json:"loans"`              // 貸し出しの記録
+	WishlistItems     int `json:"wishlist_items"`     // 購入元の欲しい本
+}
+
+// MergeResult は書籍の統合の結果
+type MergeResult struct {
+	Book     *Book         `json:"book"`      // 統合後の書籍
+	SourceID int           `json:"source_id"` // 削除した統合元の書籍のID
+	Fields   []string      `json:"fields"`    // 統合元の値で更新した項目
+	Moved    MergedRecords `json:"moved"`     // 移した関連データの件数
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// ExchangeRate は為替レートを表すモデル
+// 「1 Currency が Rate Base にあたる」という意味で、Date 以降（次のレートの日付まで）の購入の換算に使う
+type ExchangeRate struct {
+	ID        int       `json:"id" db:"id"`                 // レートの一意なID番号
+	Currency  string    `json:"currency" db:"currency"`     // 換算元の通貨（例：USD）
+	Base      string    `json:"base" db:"base"`             // 換算先の通貨（基準通貨、例：JPY）
+	Date      time.Time `json:"date" db:"rate_date"`        // レートの日付
+	Rate      float64   `json:"rate" db:"rate"`             // 1 Currency あたりの Base の金額（例：149.5）
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // 登録日時
+}
+
+// SetExchangeRateRequest は為替レートを登録するときのリクエスト構造体
+// 同じ通貨・同じ日付のレートがあれば置き換える
+type SetExchangeRateRequest struct {
+	Currency string     `json:"currency" validate:"required"`  // 換算元の通貨（必須）
+	Date     *time.Time `json:"date" validate:"required"`      // レートの日付（必須）
+	Rate     float64    `json:"rate" validate:"required,gt=0"` // 1 Currency あたりの基準通貨の金額（必須）
+}
+
+// ExchangeRateImport はCSVファイルからの為替レートの取り込み結果
+type ExchangeRateImport struct {
+	Imported int      `json:"imported"` // 登録・更新したレートの件数
+	Errors   []string `json:"errors"`   // 取り込めなかった行とその理由
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// GoalMetric は読書目標で数えるものを表す列挙型
+type GoalMetric string
+
+// 読書目標で数えるものの定数定義
+const (
+	MetricBooks GoalMetric = "books" // 読み終えた冊数
+	MetricPages GoalMetric = "pages" // 読み終えた書籍のページ数の合計（ページ数が未登録の書籍は0ページ）
+)
+
+// GoalPeriod は読書目標の期間の種類を表す列挙型
+type GoalPeriod string
+
+// 読書目標の期間の種類の定数定義
+const (
+	PeriodYear   GoalPeriod = "year"   // 1年間（1月1日〜12月31日）
+	PeriodMonth  GoalPeriod = "month"  // 1か月間
+	PeriodCustom GoalPeriod = "custom" // 開始日と終了日を指定した期間
+)
+
+// GoalPace は読書目標の進み具合（予定と比べて進んでいるか）を表す列挙型
+type GoalPace string
+
+// 読書目標の進み具合の定数定義
+const (
+	PaceNotStarted GoalPace = "not_started" // 期間がまだ始まっていない
+	PaceAhead      GoalPace = "ahead"       // 予定より進んでいる
+	PaceOnTrack    GoalPace = "on_track"    // 予定どおり
+	PaceBehind     GoalPace = "behind"      // 予定より遅れている
+	PaceAchieved   GoalPace = "achieved"    // 目標を達成した
+	PaceMissed     GoalPace = "missed"      // 期間が終わったが達成できなかった
+)
+
+// ReadingGoal は読書目標を表すモデル
+// 達成状況は保存せず、取得のたびに読み終えた書籍（status が completed の書籍の end_read_date）から計算する
+type ReadingGoal struct {
+	ID        int        `json:"id" db:"id"`                       // 目標の一意なID番号
+	OwnerID   int        `json:"owner_id,omitempty" db:"owner_id"` // 所有者のユーザーID（0は共有の本棚の目標）
+	Title     string     `json:"title" db:"title"`                 // 目標の名前（例：2026年のチャレンジ）
+	Metric    GoalMetric `json:"metric" db:"metric"`               // 数えるもの（冊数・ページ数）
+	Target    int        `json:"target" db:"target"`               // 目標の冊数・ページ数
+	Period    GoalPeriod `json:"period" db:"period"`               // 期間の種類
+	StartDate time.Time  `json:"start_date" db:"start_date"`       // 期間の初日
+	EndDate   time.Time  `json:"end_date" db:"end_date"`           // 期間の最終日（この日も含む）
+	Tag       string     `json:"tag" db:"tag"`                     // 数える書籍のタグ（空ならすべての書籍）
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`       // 作成日時
+}
+
+// CreateGoalRequest は読書目標を作成するときのリクエスト構造体
+// 期間は period によって指定する項目が変わる
+//   - year：year（省略すると今年）
+//   - month：year と month（省略すると今月）
+//   - custom：start_date と end_date（必須）
+type CreateGoalRequest struct {
+	Title     string     `json:"title" validate:"max=200"`                           // 目標の名前（省略すると期間から作る）
+	Metric    GoalMetric `json:"metric" validate:"required,oneof=books pages"`       // 数えるもの（必須）
+	Target    int        `json:"target" validate:"required,min=1"`                   // 目標の冊数・ページ数（必須）
+	Period    GoalPeriod `json:"period" validate:"required,oneof=year month custom"` // 期間の種類（必須）
+	Year      int        `json:"year" validate:"omitempty,min=1900,max=9999"`        // 年（year・month のとき）
+	Month     int        `json:"month" validate:"omitempty,min=1,max=12"`            // 月（month のとき）
+	StartDate *time.Time `json:"start_date"`                                         // 期間の初日（custom のとき）
+	EndDate   *time.Time `json:"end_date"`                                           // 期間の最終日（custom のとき）
+	Tag       string     `json:"tag" validate:"max=100"`                             // 数える書籍のタグ（任意）
+}
+
+// GoalBook は読書目標に数えた書籍
+type GoalBook struct {
+	ID          int       `json:"id"`            // 書籍のID
+	Title       string    `json:"title"`         // タイトル
+	PageCount   *int      `json:"page_count"`    // ページ数（nilは未登録）
+	EndReadDate time.Time `json:"end_read_date"` // 読み終えた日
+}
+
+// GoalProgress は読書目標の達成状況
+// expected は「期間のうち昨日までに経過した割合 × 目標」で、予定どおりなら今日の時点で読み終えているはずの量
+type GoalProgress struct {
+	Goal          *ReadingGoal `json:"goal"`           // 読書目標
+	Current       int          `json:"current"`        // これまでに読み終えた冊数・ページ数
+	Percent       float64      `json:"percent"`        // 達成率（％、100を超えることもある）
+	Expected      float64      `json:"expected"`       // 予定どおりなら読み終えているはずの量
+	Difference    float64      `json:"difference"`     // current - expected（正なら予定より進んでいる）
+	Pace          GoalPace     `json:"pace"`           // 進み具合
+	Projected     float64      `json:"projected"`      // 今のペースが続いた場合の期間終了時の見込み
+	DaysElapsed   int          `json:"days_elapsed"`   // 期間のうち経過した日数（今日は含まない）
+	DaysRemaining int          `json:"days_remaining"` // 期間の残りの日数（今日を含む）
+	NeededPerDay  float64      `json:"needed_per_day"` // 達成するために今日から1日あたりに必要な量
+	Books         []*GoalBook  `json:"books"`          // 数えた書籍（読み終えた日の順）
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// HighlightKind はハイライトの種類を表す型
+type HighlightKind string
+
+// ハイライトの種類の定数定義
+const (
+	KindHighlight HighlightKind = "highlight" // 読みながら印を付けた文章
+	KindQuote     HighlightKind = "quote"     // 人に紹介したい・引用したい文章
+)
+
+// IsValid はハイライトの種類が正しいかどうかを判定するメソッド
+func (k HighlightKind) IsValid() bool {
+	switch k {
+	case KindHighlight, KindQuote:
+		return true
+	default:
+		return false
+	}
+}
+
+// Highlight は書籍のハイライト・引用の1件を表すモデル
+// 書籍の notes（自由記述のメモ）とは別に、1つの文章ごとに記録して検索・書き出しできるようにする
+type Highlight struct {
+	ID        int           `json:"id" db:"id"`                           // ハイライトの一意なID番号
+	BookID    int           `json:"book_id" db:"book_id"`                 // 書籍のID
+	BookTitle string        `json:"book_title,omitempty" db:"book_title"` // 書籍のタイトル（検索結果の表示用）
+	Kind      HighlightKind `json:"kind" db:"kind"`                       // 種類（highlight または quote）
+	Text      string        `json:"text" db:"text"`                       // 本文
+	Note      string        `json:"note" db:"note"`                       // 自分のメモ
+	Page      *int          `json:"page" db:"page"`                       // ページ（nilは未登録）
+	Location  string        `json:"location" db:"location"`               // 電子書籍の位置（例：1234-1236）
+	Chapter   string        `json:"chapter" db:"chapter"`                 // 章
+	Tags      string        `json:"tags" db:"tags"`                       // カンマ区切りのタグ
+	SourceKey string        `json:"source_key,omitempty" db:"source_key"` // 電子書籍リーダーから取り込んだ場合の取り込み元でのキー（手入力は空文字）
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`           // 作成日時（取り込んだハイライトは元の日時）
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`           // 更新日時
+}
+
+// CreateHighlightRequest はハイライトを登録するときのリクエスト構造体
+type CreateHighlightRequest struct {
+	Kind      HighlightKind `json:"kind"`                               // 種類（省略すると highlight）
+	Text      string        `json:"text" validate:"required,max=10000"` // 本文（必須）
+	Note      string        `json:"note" validate:"max=5000"`           // メモ（任意）
+	Page      *int          `json:"page" validate:"omitempty,min=1"`    // ページ（任意）
+	Location  string        `json:"location" validate:"max=100"`        // 電子書籍の位置（任意）
+	Chapter   string        `json:"chapter" validate:"max=500"`         // 章（任意）
+	Tags      string        `json:"tags" validate:"max=500"`            // タグ（任意）
+	CreatedAt *time.Time    `json:"created_at"`                         // 作成日時（省略すると現在時刻。取り込みで元の日時を残すため）
+}
+
+// UpdateHighlightRequest はハイライトを更新するときのリクエスト構造体
+// 全ての項目がポインタになっているのは、更新しない項目はnullを送るため
+type UpdateHighlightRequest struct {
+	Kind     *HighlightKind `json:"kind"`                                      // 種類（更新する場合のみ）
+	Text     *string        `json:"text" validate:"omitempty,min=1,max=10000"` // 本文（更新する場合のみ）
+	Note     *string        `json:"note" validate:"omitempty,max=5000"`        // メモ（更新する場合のみ）
+	Page     *int           `json:"page" validate:"omitempty,min=0"`           // ページ（更新する場合のみ、0で未登録に戻す）
+	Location *string        `json:"location" validate:"omitempty,max=100"`     // 電子書籍の位置（更新する場合のみ）
+	Chapter  *string        `json:"chapter" validate:"omitempty,max=500"`      // 章（更新する場合のみ）
+	Tags     *string        `json:"tags" validate:"omitempty,max=500"`         // タグ（更新する場合のみ）
+}
+
+// HighlightFilter はハイライトの検索条件
+type HighlightFilter struct {
+	OwnerID int           // 本棚の持ち主のユーザーID（0は共有の本棚）
+	BookID  int           // 書籍のID（0はすべての書籍）
+	Query   string        // 検索語（空白で区切った語をすべて含むものを探す。空文字は条件なし）
+	Tag     string        // タグ（空文字は条件なし）
+	Kind    HighlightKind // 種類（空文字はすべて）
+}
+
+// HighlightImport は電子書籍リーダーからのハイライトの取り込み結果
+// 書籍と結びつかなかったハイライトは取り込まず、書籍を登録してから取り込み直せるよう Unmatched で知らせる
+type HighlightImport struct {
+	Source     string                      `json:"source"`     // 取り込み元（kindle または kobo）
+	DryRun     bool                        `json:"dry_run"`    // true なら確認だけで保存していない
+	Entries    int                         `json:"entries"`    // ファイルから読み込んだハイライトの件数
+	Imported   int                         `json:"imported"`   // 新しく取り込んだ件数（dry_run では取り込める件数）
+	Duplicates int                         `json:"duplicates"` // 取り込み済みのため飛ばした件数
+	Books      []*HighlightImportBook      `json:"books"`      // 書籍ごとの取り込み件数
+	Unmatched  []*HighlightImportUnmatched `json:"unmatched"`  // 本棚の書籍と結びつかなかったタイトル
+	Skipped    []string                    `json:"skipped"`    // 取り込まなかった項目とその理由
+}
+
+// HighlightImportBook は書籍ごとの取り込み件数
+type HighlightImportBook struct {
+	BookID      int    `json:"book_id"`      // 書籍のID
+	Title       string `json:"title"`        // 書籍のタイトル（本棚での表記）
+	SourceTitle string `json:"source_title"` // リーダーでのタイトル
+	Imported    int    `json:"imported"`     // 新しく取り込んだ件数
+	Duplicates  int    `json:"duplicates"`   // 取り込み済みのため飛ばした件数
+}
+
+// HighlightImportUnmatched は本棚の書籍と結びつかなかったタイトル
+type HighlightImportUnmatched struct {
+	Title      string `json:"title"`      // リーダーでのタイトル
+	Author     string `json:"author"`     // リーダーでの著者
+	Highlights int    `json:"highlights"` // ハイライトの件数
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// Loan は書籍の貸し出し（誰に・いつ貸して・いつまでに返してもらうか）を表すモデル
+// 返却されても削除せず returned_at を記録するため、書籍ごとの貸し出し履歴として残る
+type Loan struct {
+	ID           int        `json:"id" db:"id"`                             // 貸し出しの一意なID番号
+	BookID       int        `json:"book_id" db:"book_id"`                   // 貸し出した書籍のID
+	BookTitle    string     `json:"book_title,omitempty" db:"book_title"`   // 貸し出した書籍のタイトル（一覧表示用）
+	BorrowerName string     `json:"borrower_name" db:"borrower_name"`       // 借りた人の名前
+	BorrowerID   int        `json:"borrower_id,omitempty" db:"borrower_id"` // 借りた人がユーザーの場合のユーザーID
+	LentAt       time.Time  `json:"lent_at" db:"lent_at"`                   // 貸し出した日時
+	DueAt        *time.Time `json:"due_at" db:"due_at"`                     // 返却期限（nilは期限なし）
+	ReturnedAt   *time.Time `json:"returned_at" db:"returned_at"`           // 返却された日時（nilは貸し出し中）
+	Notes        string     `json:"notes" db:"notes"`                       // メモ
+	Overdue      bool       `json:"overdue" db:"-"`                         // 返却期限を過ぎているか（取得時に計算する）
+}
+
+// IsOverdue は貸し出しが now の時点で返却期限を過ぎているかどうかを判定するメソッド
+// 返却済み、または期限のない貸し出しは期限切れにならない
+func (l *Loan) IsOverdue(now time.Time) bool {
+	return l.ReturnedAt == nil && l.DueAt != nil && now.After(*l.DueAt)
+}
+
+// LendRequest は書籍を貸し出すときのリクエスト構造体
+// 借りる人は名前（borrower_name）か、アプリのユーザー名（borrower_username）のどちらかで指定する
+type LendRequest struct {
+	BorrowerName     string     `json:"borrower_name" validate:"required_without=BorrowerUsername,max=100"` // 借りる人の名前
+	BorrowerUsername string     `json:"borrower_username" validate:"max=32"`                                // 借りる人のユーザー名（任意）
+	LentAt           *time.Time `json:"lent_at"`                                                            // 貸し出した日時（省略すると現在時刻）
+	DueAt            *time.Time `json:"due_at"`                                                             // 返却期限（任意）
+	Notes            string     `json:"notes" validate:"max=1000"`                                          // メモ（任意）
+}
+
+// ReturnRequest は書籍の返却を記録するときのリクエスト構造体
+type ReturnRequest struct {
+	ReturnedAt *time.Time `json:"returned_at"` // 返却された日時（省略すると現在時刻）
+}
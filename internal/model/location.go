@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// LocationKind は保管場所の種類を表す列挙型
+type LocationKind string
+
+// 保管場所の種類の定数定義（上から順に大きな場所）
+const (
+	KindBuilding LocationKind = "building" // 建物（自宅、事務所など）
+	KindRoom     LocationKind = "room"     // 部屋
+	KindShelf    LocationKind = "shelf"    // 本棚
+	KindPosition LocationKind = "position" // 本棚の中の位置（段など）
+)
+
+// Level は保管場所の階層の深さを返すメソッド（建物が0、位置が3。不明な種類は-1）
+// 子の場所は親の場所より深い種類でなければならない（例：部屋の中に建物は置けない）
+func (k LocationKind) Level() int {
+	switch k {
+	case KindBuilding:
+		return 0
+	case KindRoom:
+		return 1
+	case KindShelf:
+		return 2
+	case KindPosition:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// Location は書籍の保管場所を表すモデル
+// 建物 > 部屋 > 本棚 > 位置 の階層構造で、書籍は Book.LocationID で場所に置かれる
+type Location struct {
+	ID        int          `json:"id" db:"id"`                       // 場所の一意なID番号
+	OwnerID   int          `json:"owner_id,omitempty" db:"owner_id"` // 所有者のユーザーID（0は共有の本棚の場所）
+	ParentID  *int         `json:"parent_id" db:"parent_id"`         // 親の場所のID（nilは最上位）
+	Kind      LocationKind `json:"kind" db:"kind"`                   // 場所の種類
+	Name      string       `json:"name" db:"name"`                   // 場所の名前（例：書斎、本棚A、3段目）
+	Path      string       `json:"path" db:"-"`                      // 最上位からの道のり（例：自宅 > 書斎 > 本棚A）
+	BookCount int          `json:"book_count" db:"-"`                // この場所に直接置かれている書籍の数（下の場所は含まない）
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`       // 作成日時
+}
+
+// CreateLocationRequest は保管場所を作成するときのリクエスト構造体
+type CreateLocationRequest struct {
+	ParentID *int         `json:"parent_id"`                                                   // 親の場所のID（省略すると最上位）
+	Kind     LocationKind `json:"kind" validate:"required,oneof=building room shelf position"` // 場所の種類（必須）
+	Name     string       `json:"name" validate:"required,max=100"`                            // 場所の名前（必須）
+}
+
+// UpdateLocationRequest は保管場所を更新するときのリクエスト構造体
+// parent_id に 0 を指定すると最上位の場所に移動する
+type UpdateLocationRequest struct {
+	ParentID *int          `json:"parent_id"`                                                    // 親の場所のID（変更する場合のみ）
+	Kind     *LocationKind `json:"kind" validate:"omitempty,oneof=building room shelf position"` // 場所の種類（変更する場合のみ）
+	Name     *string       `json:"name" validate:"omitempty,min=1,max=100"`                      // 場所の名前（変更する場合のみ）
+}
+
+// MoveBooksRequest は書籍をまとめて別の場所に移動するときのリクエスト構造体
+type MoveBooksRequest struct {
+	BookIDs    []int `json:"book_ids" validate:"required,min=1,max=500"` // 移動する書籍のID
+	LocationID *int  `json:"location_id"`                                // 移動先の場所のID（nullは場所の設定を外す）
+}
+
+// InventorySection は棚卸し表の1つの場所の分の書籍一覧
+type InventorySection struct {
+	Location *Location `json:"location"` // 場所
+	Books    []*Book   `json:"books"`    // この場所に直接置かれている書籍（タイトル順）
+}
+
+// InventoryReport は保管場所の棚卸し表（場所とその下のすべての場所にある書籍の一覧）
+type InventoryReport struct {
+	Location    *Location           `json:"location"`     // 棚卸しの対象の場所
+	Sections    []*InventorySection `json:"sections"`     // 場所ごとの書籍一覧（道のり順）
+	TotalBooks  int                 `json:"total_books"`  // 書籍の総数
+	GeneratedAt time.Time           `json:"generated_at"` // 作成日時
+}
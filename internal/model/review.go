@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// ReviewVisibility はレビューの公開範囲を表す型
+type ReviewVisibility string
+
+// レビューの公開範囲の定数定義
+const (
+	VisibilityPrivate ReviewVisibility = "private" // 本棚を変更できる人（持ち主と editor）だけが見られる
+	VisibilityPublic  ReviewVisibility = "public"  // 書籍を閲覧できる人なら誰でも見られる（公開ページでの表示も想定）
+)
+
+// IsValid はレビューの公開範囲が正しいかどうかを判定するメソッド
+func (v ReviewVisibility) IsValid() bool {
+	switch v {
+	case VisibilityPrivate, VisibilityPublic:
+		return true
+	default:
+		return false
+	}
+}
+
+// Review は書籍のレビュー（読書1回分の感想）を表すモデル
+// 買い物のメモなども入る書籍の notes とは分け、本文はMarkdownで書く
+// 見出し・本文・ネタバレの有無を変えるたびに版（Version）が1つ増え、以前の版は ReviewRevision として残る
+type Review struct {
+	ID         int              `json:"id" db:"id"`                   // レビューの一意なID番号
+	BookID     int              `json:"book_id" db:"book_id"`         // 書籍のID
+	ReadNumber int              `json:"read_number" db:"read_number"` // 何回目の読書のレビューか（1冊につき1回の読書に1件）
+	ReadOn     *time.Time       `json:"read_on" db:"read_on"`         // 読み終えた日（nilは未登録）
+	Title      string           `json:"title" db:"title"`             // 見出し
+	Body       string           `json:"body" db:"body"`               // 本文（Markdown）
+	Spoiler    bool             `json:"spoiler" db:"spoiler"`         // ネタバレを含むか（HTMLでは折りたたんで表示する）
+	Visibility ReviewVisibility `json:"visibility" db:"visibility"`   // 公開範囲
+	Version    int              `json:"version" db:"version"`         // 現在の版
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`   // 作成日時
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`   // 更新日時
+}
+
+// ReviewRevision はレビューの版（編集履歴の1件）を表すモデル
+type ReviewRevision struct {
+	ID        int       `json:"id" db:"id"`                 // 版の一意なID番号
+	ReviewID  int       `json:"review_id" db:"review_id"`   // レビューのID
+	Version   int       `json:"version" db:"version"`       // 版の番号（1から順に増える）
+	Title     string    `json:"title" db:"title"`           // この版の見出し
+	Body      string    `json:"body" db:"body"`             // この版の本文（Markdown）
+	Spoiler   bool      `json:"spoiler" db:"spoiler"`       // この版のネタバレの有無
+	EditedBy  int       `json:"edited_by" db:"edited_by"`   // 編集した人のユーザーID（0は未ログインまたは削除されたユーザー）
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // この版を保存した日時
+}
+
+// CreateReviewRequest はレビューを書くときのリクエスト構造体
+type CreateReviewRequest struct {
+	ReadNumber int              `json:"read_number" validate:"omitempty,min=1"` // 何回目の読書か（省略すると次の回）
+	ReadOn     *time.Time       `json:"read_on"`                                // 読み終えた日（任意）
+	Title      string           `json:"title" validate:"max=200"`               // 見出し（任意）
+	Body       string           `json:"body" validate:"required,max=100000"`    // 本文（必須、Markdown）
+	Spoiler    bool             `json:"spoiler"`                                // ネタバレを含むか
+	Visibility ReviewVisibility `json:"visibility"`                             // 公開範囲（省略すると private）
+}
+
+// UpdateReviewRequest はレビューを更新するときのリクエスト構造体
+// 全ての項目がポインタになっているのは、更新しない項目はnullを送るため
+type UpdateReviewRequest struct {
+	ReadNumber *int              `json:"read_number" validate:"omitempty,min=1"`     // 何回目の読書か（更新する場合のみ）
+	ReadOn     *time.Time        `json:"read_on"`                                    // 読み終えた日（更新する場合のみ）
+	Title      *string           `json:"title" validate:"omitempty,max=200"`         // 見出し（更新する場合のみ）
+	Body       *string           `json:"body" validate:"omitempty,min=1,max=100000"` // 本文（更新する場合のみ）
+	Spoiler    *bool             `json:"spoiler"`                                    // ネタバレを含むか（更新する場合のみ）
+	Visibility *ReviewVisibility `json:"visibility"`                                 // 公開範囲（更新する場合のみ）
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// ShareRole は共有相手に与える役割を表す型
+type ShareRole string
+
+// 役割の定数定義
+// 上位の役割は下位の役割を含む（editor ⊃ viewer）
+const (
+	RoleViewer ShareRole = "viewer" // 閲覧のみ
+	RoleEditor ShareRole = "editor" // 閲覧・更新・削除
+)
+
+// roleLevels は役割の強さ（大きいほど強い）
+var roleLevels = map[ShareRole]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+}
+
+// Includes はこの役割が required の役割を含むかどうかを判定するメソッド
+// 例：RoleEditor.Includes(RoleViewer) は true
+func (r ShareRole) Includes(required ShareRole) bool {
+	level, ok := roleLevels[r]
+	return ok && level >= roleLevels[required]
+}
+
+// Share は本棚の共有設定（誰に・どの範囲を・どの役割で見せるか）を表すモデル
+// 書籍はコピーせず、持ち主の本棚の書籍をそのまま共有相手が扱えるようにする
+type Share struct {
+	ID          int       `json:"id" db:"id"`                     // 共有設定の一意なID番号
+	OwnerID     int       `json:"owner_id" db:"owner_id"`         // 共有する人（本棚の持ち主）のユーザーID
+	OwnerName   string    `json:"owner_name" db:"owner_name"`     // 共有する人のユーザー名
+	GranteeID   int       `json:"grantee_id" db:"grantee_id"`     // 共有される人のユーザーID
+	GranteeName string    `json:"grantee_name" db:"grantee_name"` // 共有される人のユーザー名
+	Role        ShareRole `json:"role" db:"role"`                 // 役割
+	Tag         string    `json:"tag" db:"tag"`                   // 共有する範囲のタグ（空文字は本棚全体）
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`     // 共有した日時
+}
+
+// Covers は共有設定が指定した書籍を含むかどうかを判定するメソッド
+func (s *Share) Covers(book *Book) bool {
+	return book.OwnerID == s.OwnerID && (s.Tag == "" || book.HasTag(s.Tag))
+}
+
+// CreateShareRequest は共有設定の作成時のリクエスト構造体
+// 同じ相手・同じ範囲の共有設定が既にある場合は役割を更新する
+type CreateShareRequest struct {
+	Username string    `json:"username" validate:"required"`                 // 共有する相手のユーザー名
+	Role     ShareRole `json:"role" validate:"required,oneof=viewer editor"` // 役割
+	Tag      string    `json:"tag" validate:"max=100"`                       // 共有する範囲のタグ（省略すると本棚全体）
+}
+
+// ShareList は自分が共有した設定と、自分に共有された設定の一覧
+type ShareList struct {
+	Given    []*Share `json:"given"`    // 自分が他の人に共有した設定
+	Received []*Share `json:"received"` // 他の人から自分に共有された設定
+}
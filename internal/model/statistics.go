@@ -0,0 +1,165 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// StatsInterval は時系列の統計の集計の単位を表す列挙型
+type StatsInterval string
+
+// 時系列の統計の集計の単位の定数定義
+const (
+	IntervalDay   StatsInterval = "day"   // 日ごと（2026-01-31）
+	IntervalWeek  StatsInterval = "week"  // 週ごと（月曜日の日付で表す）
+	IntervalMonth StatsInterval = "month" // 月ごと（2026-01）
+	IntervalYear  StatsInterval = "year"  // 年ごと（2026）
+)
+
+// SeriesMetric は時系列の統計で集計する値を表す列挙型
+type SeriesMetric string
+
+// 時系列の統計で集計する値の定数定義
+const (
+	SeriesPurchased SeriesMetric = "purchased" // 購入した冊数（購入日で集計）
+	SeriesCompleted SeriesMetric = "completed" // 読み終えた冊数（読書終了日で集計）
+	SeriesSpent     SeriesMetric = "spent"     // 購入金額の合計（購入日で集計）
+)
+
+// BreakdownDimension は内訳の統計の分け方を表す列挙型
+type BreakdownDimension string
+
+// 内訳の統計の分け方の定数定義
+const (
+	BreakdownTag       BreakdownDimension = "tag"              // タグごと（1冊に複数のタグがあれば、それぞれに数える）
+	BreakdownPublisher BreakdownDimension = "publisher"        // 出版社ごと
+	BreakdownAuthor    BreakdownDimension = "author"           // 著者ごと
+	BreakdownRating    BreakdownDimension = "rating"           // 評価ごと（評価のない書籍は空文字）
+	BreakdownStore     BreakdownDimension = "store"            // 購入した店ごと
+	BreakdownChannel   BreakdownDimension = "purchase_channel" // 購入方法ごと
+	BreakdownFormat    BreakdownDimension = "format"           // 書籍の形態ごと
+)
+
+// BreakdownSort は内訳の統計の並び順を表す列挙型
+type BreakdownSort string
+
+// 内訳の統計の並び順の定数定義
+const (
+	SortByBooks BreakdownSort = "books" // 書籍数の多い順（評価は高い順）
+	SortBySpent BreakdownSort = "spent" // 購入金額の多い順
+)
+
+// BreakdownQuery は内訳の統計の条件
+type BreakdownQuery struct {
+	By    BreakdownDimension // 分け方
+	From  *time.Time         // 購入日の範囲の初日（nilは制限なし）
+	To    *time.Time         // 購入日の範囲の最終日（この日も含む、nilは制限なし）
+	Sort  BreakdownSort      // 並び順
+	Limit int                // 最大件数
+}
+
+// TimeSeriesPoint は時系列の統計の1期間分の値
+type TimeSeriesPoint struct {
+	Period string `json:"period"` // 期間（集計の単位に応じた形式の文字列）
+	Value  int    `json:"value"`  // 冊数または金額（基準通貨の最小単位）
+}
+
+// TimeSeries は時系列の統計
+// データのない期間も値0で含めるため、そのままグラフに使える
+type TimeSeries struct {
+	Interval StatsInterval      `json:"interval"` // 集計の単位
+	Metric   SeriesMetric       `json:"metric"`   // 集計した値
+	Total    int                `json:"total"`    // 全期間の合計
+	Currency string             `json:"currency"` // 金額の通貨（metric が spent のときの基準通貨）
+	Points   []*TimeSeriesPoint `json:"points"`   // 期間ごとの値（古い順）
+}
+
+// BreakdownItem は内訳の統計の1項目
+type BreakdownItem struct {
+	Key           string   `json:"key"`            // タグ・出版社・著者・評価（未設定は空文字）
+	Books         int      `json:"books"`          // 書籍数
+	Completed     int      `json:"completed"`      // 読み終えた書籍数
+	Spent         int      `json:"spent"`          // 購入金額の合計（基準通貨の最小単位）
+	AverageRating *float64 `json:"average_rating"` // 平均評価（評価のある書籍がなければnull）
+}
+
+// BacklogStats は積読（購入したがまだ読み始めていない書籍）の統計
+type BacklogStats struct {
+	Books          int        `json:"books"`            // 積読の冊数
+	Value          int        `json:"value"`            // 積読の購入金額の合計（基準通貨の最小単位）
+	OldestPurchase *time.Time `json:"oldest_purchase"`  // いちばん古い積読の購入日
+	AverageAgeDays *float64   `json:"average_age_days"` // 購入してからの平均日数
+}
+
+// LibrarySummary は本棚の書籍の概要（ステータスごとの冊数・購入金額・平均評価など）
+type LibrarySummary struct {
+	Books          int      // 書籍の冊数
+	NotStarted     int      // 未読の冊数
+	Reading        int      // 読書中の冊数
+	Completed      int      // 読了の冊数
+	Dropped        int      // 中断の冊数
+	Spent          int      // 購入金額の合計（基準通貨の最小単位。換算できない書籍は含まない）
+	Unconverted    int      // 為替レートがなく金額に含めなかった冊数
+	AverageRating  *float64 // 平均評価（評価された書籍がなければnil）
+	PurchasedSince int      // 指定した日以降に購入した冊数
+	CompletedSince int      // 指定した日以降に読み終えた冊数
+}
+
+// ReadingSpan は書籍を読んでいた日が続いた期間（開始日と最終日を含む）
+type ReadingSpan struct {
+	From time.Time `json:"from"` // 期間の初日
+	To   time.Time `json:"to"`   // 期間の最終日
+}
+
+// StreakStats は連続して読書した日数の統計
+// 読書した日は、読書開始日から読書終了日まで（読書中なら今日まで）のいずれかの書籍を読んでいた日
+type StreakStats struct {
+	Current     int          `json:"current"`      // 今日（または昨日）まで続いている連続日数（途切れていれば0）
+	Longest     int          `json:"longest"`      // これまでで最も長い連続日数
+	LongestSpan *ReadingSpan `json:"longest_span"` // 最も長く続いた期間
+	ReadingDays int          `json:"reading_days"` // 読書した日の合計
+}
+
+// ReadingInsights は読書の傾向の統計
+type ReadingInsights struct {
+	AverageDaysToFinish *float64     `json:"average_days_to_finish"` // 読み始めてから読み終えるまでの平均日数
+	FinishedBooks       int          `json:"finished_books"`         // 平均日数の計算に使った書籍数（開始日・終了日のある読了済みの書籍）
+	Backlog             BacklogStats `json:"backlog"`                // 積読
+	Streaks             StreakStats  `json:"streaks"`                // 連続読書日数
+}
+
+// ReviewBook は年間の振り返りに載せる、読み終えた書籍
+type ReviewBook struct {
+	ID            int        `json:"id"`              // 書籍のID
+	Title         string     `json:"title"`           // タイトル
+	Author        string     `json:"author"`          // 著者
+	Rating        *int       `json:"rating"`          // 評価（nilは未評価）
+	PageCount     *int       `json:"page_count"`      // ページ数（nilは未登録）
+	StartReadDate *time.Time `json:"start_read_date"` // 読書開始日（nilは未記録）
+	EndReadDate   time.Time  `json:"end_read_date"`   // 読書終了日
+	Days          *int       `json:"days"`            // 読み終えるまでの日数（開始日がなければnil）
+}
+
+// AuthorCount は著者ごとの読み終えた冊数
+type AuthorCount struct {
+	Author string `json:"author"` // 著者
+	Books  int    `json:"books"`  // 読み終えた冊数
+}
+
+// YearInReview は1年間の読書の振り返り（Year in Books）
+type YearInReview struct {
+	Year             int                `json:"year"`               // 対象の年
+	GeneratedAt      time.Time          `json:"generated_at"`       // 作成日時
+	BooksFinished    int                `json:"books_finished"`     // 読み終えた冊数
+	PagesRead        int                `json:"pages_read"`         // 読み終えた書籍のページ数の合計
+	BooksWithoutPage int                `json:"books_without_page"` // ページ数が未登録のため合計に含めなかった冊数
+	AverageRating    *float64           `json:"average_rating"`     // 読み終えた書籍の平均評価
+	BooksPurchased   int                `json:"books_purchased"`    // 購入した冊数
+	TotalSpent       int                `json:"total_spent"`        // 購入金額の合計（基準通貨の最小単位）
+	Currency         string             `json:"currency"`           // 金額の通貨（基準通貨）
+	FinishedPerMonth []*TimeSeriesPoint `json:"finished_per_month"` // 月ごとの読み終えた冊数（1月〜12月）
+	SpendPerMonth    []*TimeSeriesPoint `json:"spend_per_month"`    // 月ごとの購入金額（1月〜12月）
+	TopRated         []*ReviewBook      `json:"top_rated"`          // 評価の高い書籍
+	TopAuthors       []*AuthorCount     `json:"top_authors"`        // よく読んだ著者
+	LongestRead      *ReviewBook        `json:"longest_read"`       // 読み終えるまで最も長くかかった書籍
+	FastestRead      *ReviewBook        `json:"fastest_read"`       // 最も早く読み終えた書籍
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// Scope は個人用アクセストークンに与える権限を表す型
+type Scope string
+
+// 権限の定数定義
+// 上位の権限は下位の権限を含む（admin ⊃ write ⊃ read）
+const (
+	ScopeRead  Scope = "read"  // 書籍の閲覧
+	ScopeWrite Scope = "write" // 書籍の登録・更新・削除
+	ScopeAdmin Scope = "admin" // トークンの管理と、管理者ならバックアップ・アーカイブなどの管理用API
+)
+
+// scopeLevels は権限の強さ（大きいほど強い）
+var scopeLevels = map[Scope]int{
+	ScopeRead:  1,
+	ScopeWrite: 2,
+	ScopeAdmin: 3,
+}
+
+// IsValid は権限が有効な値かどうかを判定するメソッド
+func (s Scope) IsValid() bool {
+	_, ok := scopeLevels[s]
+	return ok
+}
+
+// Includes はこの権限が required の権限を含むかどうかを判定するメソッド
+// 例：ScopeWrite.Includes(ScopeRead) は true
+func (s Scope) Includes(required Scope) bool {
+	return s.IsValid() && scopeLevels[s] >= scopeLevels[required]
+}
+
+// APIToken は個人用アクセストークン（スクリプトなどからAPIを使うための鍵）を表すモデル
+// トークンそのものは作成時に一度だけ返し、データベースにはSHA-256のハッシュだけを保存する
+type APIToken struct {
+	ID         int        `json:"id" db:"id"`                     // トークンの一意なID番号
+	UserID     int        `json:"user_id" db:"user_id"`           // 持ち主のユーザーID
+	Name       string     `json:"name" db:"name"`                 // 用途が分かる名前（例：「バックアップスクリプト」）
+	TokenHash  string     `json:"-" db:"token_hash"`              // トークンのSHA-256（JSONには出さない）
+	Scopes     []Scope    `json:"scopes" db:"scopes"`             // 与えた権限
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`     // 有効期限（nilは無期限）
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"` // 最後に使われた日時
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`     // 作成日時
+}
+
+// HasScope はトークンが required の権限を持っているかどうかを判定するメソッド
+func (t *APIToken) HasScope(required Scope) bool {
+	for _, s := range t.Scopes {
+		if s.Includes(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired はトークンが now の時点で有効期限切れかどうかを判定するメソッド
+func (t *APIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// CreateTokenRequest はトークン作成時のリクエスト構造体
+type CreateTokenRequest struct {
+	Name          string  `json:"name" validate:"required,max=100"`                             // 用途が分かる名前
+	Scopes        []Scope `json:"scopes" validate:"required,min=1,dive,oneof=read write admin"` // 与える権限
+	ExpiresInDays int     `json:"expires_in_days" validate:"min=0,max=3650"`                    // 有効日数（0は無期限）
+}
+
+// CreatedToken はトークン作成時のレスポンス
+// token は作成時にしか返さないため、クライアントは安全な場所に保存する必要がある
+type CreatedToken struct {
+	Token     string `json:"token"` // トークンそのもの
+	*APIToken        // 作成したトークンの情報
+}
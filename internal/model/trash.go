@@ -0,0 +1,10 @@
+package model
+
+import "time" // 完全に削除される日時
+
+// TrashedBook はゴミ箱に入っている書籍
+// 書籍の項目（deleted_at を含む）に加えて、保存期間が過ぎて完全に削除される日時を返す
+type TrashedBook struct {
+	*Book
+	PurgeAt *time.Time `json:"purge_at"` // 完全に削除される日時（nilは自動では削除しない）
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// User はアプリを使う人（アカウント）を表すモデル
+// 書籍は owner_id でユーザーごとの本棚に分かれる
+type User struct {
+	ID           int       `json:"id" db:"id"`                     // ユーザーの一意なID番号
+	Username     string    `json:"username" db:"username"`         // ログインに使うユーザー名
+	PasswordHash string    `json:"-" db:"password_hash"`           // bcryptでハッシュ化したパスワード（JSONには出さない）
+	DisplayName  string    `json:"display_name" db:"display_name"` // 表示名
+	IsAdmin      bool      `json:"is_admin" db:"is_admin"`         // サーバーの管理者か（最初に登録したユーザー。バックアップ・アーカイブ・為替レートの変更ができる）
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`     // 登録日時
+}
+
+// RegisterRequest はユーザー登録時のリクエスト構造体
+type RegisterRequest struct {
+	Username    string `json:"username" validate:"required,min=3,max=32,alphanum"` // ユーザー名（英数字3〜32文字）
+	Password    string `json:"password" validate:"required,min=8,max=72"`          // パスワード（8〜72文字。bcryptは72バイトまでしか使わない）
+	DisplayName string `json:"display_name" validate:"max=100"`                    // 表示名（任意）
+}
+
+// LoginRequest はログイン時のリクエスト構造体
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"` // ユーザー名
+	Password string `json:"password" validate:"required"` // パスワード
+}
+
+// Session はログイン状態（セッション）を表すモデル
+// トークンそのものは保存せず、SHA-256のハッシュだけをデータベースに保存する
+type Session struct {
+	TokenHash string    `json:"-" db:"token_hash"`          // トークンのSHA-256
+	UserID    int       `json:"user_id" db:"user_id"`       // ログインしているユーザーのID
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"` // 有効期限
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // ログイン日時
+}
@@ -0,0 +1,67 @@
+package model
+
+// VaultNoteState は保管庫（Obsidian などのMarkdownのノートのフォルダ）のノート1件の処理結果を表す型
+type VaultNoteState string
+
+// ノートの処理結果の定数定義
+const (
+	VaultCreated   VaultNoteState = "created"   // ノートを新しく書き出した
+	VaultWritten   VaultNoteState = "written"   // 既存のノートを書籍の内容で書き直した
+	VaultUnchanged VaultNoteState = "unchanged" // ノートと書籍に違いがない
+	VaultApplied   VaultNoteState = "applied"   // ノートで編集したフロントマターを書籍に反映した
+	VaultRefreshed VaultNoteState = "refreshed" // 書き出した後に書籍が更新されていたため、ノートを書き直した
+	VaultPending   VaultNoteState = "pending"   // ノートに書籍へ反映していない編集があるため、書き出さなかった
+	VaultConflict  VaultNoteState = "conflict"  // 書き出した後にノートと書籍の両方が変わっていたため、どちらも変えなかった
+	VaultMissing   VaultNoteState = "missing"   // ノートの書籍が本棚にない（削除された書籍など）
+	VaultFailed    VaultNoteState = "error"     // ノートの読み取りや書籍の更新に失敗した
+)
+
+// VaultPrefer は保管庫の競合をどちらの内容で解決するかを表す型
+type VaultPrefer string
+
+// 競合の解決方法の定数定義
+const (
+	PreferNone VaultPrefer = ""     // 解決しない（競合として報告するだけ）
+	PreferFile VaultPrefer = "file" // ノートの内容で書籍を更新する
+	PreferDB   VaultPrefer = "db"   // 書籍の内容でノートを書き直す
+)
+
+// IsValid は競合の解決方法が決められた値かどうかを判定するメソッド
+func (p VaultPrefer) IsValid() bool {
+	switch p {
+	case PreferNone, PreferFile, PreferDB:
+		return true
+	default:
+		return false
+	}
+}
+
+// VaultOptions は保管庫の書き出し・読み戻しの設定
+type VaultOptions struct {
+	DryRun bool        // 書籍もノートも変更せず、結果だけを返す
+	Prefer VaultPrefer // 競合（書き出しでは未反映の編集があるノートも）の解決方法
+}
+
+// VaultNote は保管庫のノート1件の処理結果
+type VaultNote struct {
+	BookID int            `json:"book_id"`          // 書籍のID
+	Title  string         `json:"title,omitempty"`  // 書籍のタイトル
+	Path   string         `json:"path"`             // ノートのパス（保管庫のフォルダからの相対パス）
+	State  VaultNoteState `json:"state"`            // 処理結果
+	Fields []string       `json:"fields,omitempty"` // ノートと書籍で違う項目（フロントマターの項目名）
+	Error  string         `json:"error,omitempty"`  // 失敗した理由
+}
+
+// VaultResult は保管庫の書き出し・読み戻しの結果
+type VaultResult struct {
+	Dir    string                 `json:"dir"`     // 保管庫のフォルダ
+	DryRun bool                   `json:"dry_run"` // 変更せずに結果だけを確認したか
+	Notes  []*VaultNote           `json:"notes"`   // ノートごとの処理結果
+	Counts map[VaultNoteState]int `json:"counts"`  // 処理結果ごとの件数
+}
+
+// Add はノートの処理結果を追加するメソッド
+func (r *VaultResult) Add(note *VaultNote) {
+	r.Notes = append(r.Notes, note)
+	r.Counts[note.State]++
+}
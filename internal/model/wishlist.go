@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time" // 時間関連の型（time.Time）を使うため
+)
+
+// PriceSourceManual は手入力で記録した価格の取得元の名前
+const PriceSourceManual = "manual"
+
+// WishlistItem は欲しい本リストの1冊を表すモデル
+// 購入日が決まっていない書籍を記録するため、Book とは別のテーブルで管理する
+type WishlistItem struct {
+	ID          int        `json:"id" db:"id"`                       // 欲しい本の一意なID番号
+	OwnerID     int        `json:"owner_id,omitempty" db:"owner_id"` // 所有者のユーザーID（0は共有の本棚）
+	Title       string     `json:"title" db:"title"`                 // タイトル
+	Author      string     `json:"author" db:"author"`               // 著者名
+	ISBN        string     `json:"isbn" db:"isbn"`                   // ISBN番号（価格の取得元での検索にも使う）
+	Publisher   string     `json:"publisher" db:"publisher"`         // 出版社名
+	Priority    int        `json:"priority" db:"priority"`           // 優先度（1が最優先、5が最も低い）
+	TargetPrice *int       `json:"target_price" db:"target_price"`   // この価格以下なら買う（nilは指定なし）
+	WhereSeen   string     `json:"where_seen" db:"where_seen"`       // 見かけた場所（書店名、URLなど）
+	Notes       string     `json:"notes" db:"notes"`                 // メモ
+	Tags        string     `json:"tags" db:"tags"`                   // タグ（購入時に書籍へ引き継ぐ）
+	LatestPrice *int       `json:"latest_price" db:"-"`              // 最後に記録した価格（価格の履歴から計算する）
+	BelowTarget bool       `json:"below_target" db:"-"`              // 最後に記録した価格が目標価格以下か
+	BookID      *int       `json:"book_id" db:"book_id"`             // 購入して作成した書籍のID（nilは未購入）
+	PurchasedAt *time.Time `json:"purchased_at" db:"purchased_at"`   // 購入した日時（nilは未購入）
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`       // 作成日時
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`       // 更新日時
+}
+
+// PriceRecord は欲しい本の価格の履歴の1件を表すモデル
+type PriceRecord struct {
+	ID         int       `json:"id" db:"id"`                   // 価格の記録の一意なID番号
+	ItemID     int       `json:"item_id" db:"item_id"`         // 欲しい本のID
+	Price      int       `json:"price" db:"price"`             // 価格（円）
+	Source     string    `json:"source" db:"source"`           // 取得元（manual は手入力）
+	URL        string    `json:"url" db:"url"`                 // 価格を確認したページのURL
+	RecordedAt time.Time `json:"recorded_at" db:"recorded_at"` // 記録した日時
+}
+
+// CreateWishlistItemRequest は欲しい本を登録するときのリクエスト構造体
+type CreateWishlistItemRequest struct {
+	Title       string `json:"title" validate:"required,max=500"`         // タイトル（必須）
+	Author      string `json:"author" validate:"max=200"`                 // 著者（任意）
+	ISBN        string `json:"isbn" validate:"max=20"`                    // ISBN番号（任意）
+	Publisher   string `json:"publisher" validate:"max=200"`              // 出版社（任意）
+	Priority    int    `json:"priority" validate:"omitempty,min=1,max=5"` // 優先度（省略すると3）
+	TargetPrice *int   `json:"target_price" validate:"omitempty,min=0"`   // 目標価格（任意）
+	WhereSeen   string `json:"where_seen" validate:"max=500"`             // 見かけた場所（任意）
+	Notes       string `json:"notes" validate:"max=2000"`                 // メモ（任意）
+	Tags        string `json:"tags" validate:"max=500"`                   // タグ（任意）
+}
+
+// UpdateWishlistItemRequest は欲しい本を更新するときのリクエスト構造体
+// 全ての項目がポインタになっているのは、更新しない項目はnullを送るため
+type UpdateWishlistItemRequest struct {
+	Title       *string `json:"title" validate:"omitempty,min=1,max=500"`  // タイトル（更新する場合のみ）
+	Author      *string `json:"author" validate:"omitempty,max=200"`       // 著者（更新する場合のみ）
+	ISBN        *string `json:"isbn" validate:"omitempty,max=20"`          // ISBN番号（更新する場合のみ）
+	Publisher   *string `json:"publisher" validate:"omitempty,max=200"`    // 出版社（更新する場合のみ）
+	Priority    *int    `json:"priority" validate:"omitempty,min=1,max=5"` // 優先度（更新する場合のみ）
+	TargetPrice *int    `json:"target_price" validate:"omitempty,min=0"`   // 目標価格（更新する場合のみ）
+	WhereSeen   *string `json:"where_seen" validate:"omitempty,max=500"`   // 見かけた場所（更新する場合のみ）
+	Notes       *string `json:"notes" validate:"omitempty,max=2000"`       // メモ（更新する場合のみ）
+	Tags        *string `json:"tags" validate:"omitempty,max=500"`         // タグ（更新する場合のみ）
+}
+
+// RecordPriceRequest は価格を手入力で記録するときのリクエスト構造体
+type RecordPriceRequest struct {
+	Price      *int       `json:"price" validate:"required,min=0"` // 価格（必須）
+	URL        string     `json:"url" validate:"max=2000"`         // 価格を確認したページのURL（任意）
+	RecordedAt *time.Time `json:"recorded_at"`                     // 記録する日時（省略すると現在時刻）
+}
+
+// PurchaseWishlistItemRequest は欲しい本を購入済みの書籍に変換するときのリクエスト構造体
+// 省略した項目は欲しい本の内容（価格は最後に記録した価格）を引き継ぐ
+type PurchaseWishlistItemRequest struct {
+	PurchaseDate  *time.Time `json:"purchase_date"`                             // 購入日（省略すると現在時刻）
+	PurchasePrice *int       `json:"purchase_price" validate:"omitempty,min=0"` // 購入価格（省略すると最後に記録した価格）
+	Tags          *string    `json:"tags"`                                      // タグ（省略すると欲しい本のタグ）
+	Notes         *string    `json:"notes"`                                     // メモ（省略すると欲しい本のメモ）
+}
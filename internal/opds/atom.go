@@ -0,0 +1,197 @@
+package opds
+
+import (
+	"encoding/xml" // Atom（XML）の出力
+	"fmt"          // 文字列フォーマット
+	"time"         // 日時のフォーマット
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// Atomの名前空間
+const (
+	nsAtom       = "http://www.w3.org/2005/Atom"
+	nsDC         = "http://purl.org/dc/terms/"
+	nsOPDS       = "http://opds-spec.org/2010/catalog"
+	nsOpenSearch = "http://a9.com/-/spec/opensearch/1.1/"
+)
+
+// atomFeed はOPDS 1.2のAtomフィード（<feed>要素）
+type atomFeed struct {
+	XMLName      xml.Name    `xml:"feed"`
+	XMLNS        string      `xml:"xmlns,attr"`
+	XMLNSDC      string      `xml:"xmlns:dc,attr"`
+	XMLNSOPDS    string      `xml:"xmlns:opds,attr"`
+	XMLNSOS      string      `xml:"xmlns:opensearch,attr"`
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	Author       *atomAuthor `xml:"author,omitempty"`
+	TotalResults int         `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int         `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   int         `xml:"opensearch:startIndex,omitempty"`
+	Links        []atomLink  `xml:"link"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+// atomAuthor は<author>要素
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// atomLink は<link>要素
+type atomLink struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+// atomCategory は<category>要素（タグを表す）
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// atomContent は<content>や<summary>要素
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// atomEntry は<entry>要素（ナビゲーション項目または書籍1冊）
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomAuthor   `xml:"author"`
+	Publisher  string         `xml:"dc:publisher,omitempty"`
+	Identifier string         `xml:"dc:identifier,omitempty"`
+	Issued     string         `xml:"dc:issued,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomContent   `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content,omitempty"`
+	Links      []atomLink     `xml:"link"`
+}
+
+// atomTime はAtomで使う日時形式（RFC 3339）に変換する
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Atom はフィードをOPDS 1.2（Atom XML）に変換する関数
+func (f *Feed) Atom() ([]byte, error) {
+	feed := atomFeed{
+		XMLNS:     nsAtom,
+		XMLNSDC:   nsDC,
+		XMLNSOPDS: nsOPDS,
+		XMLNSOS:   nsOpenSearch,
+		ID:        f.ID,
+		Title:     f.Title,
+		Updated:   atomTime(f.Updated),
+		Author:    &atomAuthor{Name: "book-manager"},
+	}
+	for _, l := range f.Links {
+		feed.Links = append(feed.Links, atomLink{Rel: l.Rel, Href: l.Href, Type: l.Type, Title: l.Title})
+	}
+
+	if f.IsNavigation() {
+		// ナビゲーション項目：下の階層のフィードへのリンクを持つエントリ
+		for _, nav := range f.Navigation {
+			linkType := nav.Type
+			if linkType == "" {
+				linkType = TypeAcquisition
+			}
+			entry := atomEntry{
+				ID:      nav.ID,
+				Title:   nav.Title,
+				Updated: atomTime(f.Updated),
+				Links:   []atomLink{{Rel: RelSubsection, Href: nav.Href, Type: linkType}},
+			}
+			if nav.Count > 0 {
+				entry.Content = &atomContent{Type: "text", Text: fmt.Sprintf("%d冊", nav.Count)}
+			}
+			feed.Entries = append(feed.Entries, entry)
+		}
+	} else {
+		// 書籍一覧：OpenSearchの件数情報を付ける
+		feed.TotalResults = f.Total
+		feed.ItemsPerPage = f.PerPage
+		feed.StartIndex = (f.Page-1)*f.PerPage + 1
+		for _, book := range f.Publications {
+			feed.Entries = append(feed.Entries, f.atomBookEntry(book))
+		}
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("OPDSフィードの作成に失敗しました: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// atomBookEntry は書籍1冊をAtomのエントリに変換する
+func (f *Feed) atomBookEntry(book *model.Book) atomEntry {
+	entry := atomEntry{
+		ID:        BookID(book),
+		Title:     book.Title,
+		Updated:   atomTime(book.UpdatedAt),
+		Authors:   []atomAuthor{{Name: book.Author}},
+		Publisher: book.Publisher,
+	}
+	if book.ISBN != "" {
+		entry.Identifier = "urn:isbn:" + book.ISBN
+	}
+	if book.PublishedDate != nil {
+		entry.Issued = book.PublishedDate.Format("2006-01-02")
+	}
+	for _, tag := range Tags(book.Tags) {
+		entry.Categories = append(entry.Categories, atomCategory{Term: tag, Label: tag})
+	}
+	if book.Notes != "" {
+		entry.Summary = &atomContent{Type: "text", Text: book.Notes}
+	}
+	if f.BookHref != nil {
+		entry.Links = append(entry.Links, atomLink{Rel: "alternate", Href: f.BookHref(book), Type: TypeBookJSON})
+	}
+	if cover := CoverURL(book, "L"); cover != "" {
+		entry.Links = append(entry.Links,
+			atomLink{Rel: RelImage, Href: cover, Type: "image/jpeg"},
+			atomLink{Rel: RelThumbnail, Href: CoverURL(book, "S"), Type: "image/jpeg"},
+		)
+	}
+	return entry
+}
+
+// openSearchDescription はOpenSearch記述文書（検索方法をクライアントに伝えるXML）
+type openSearchDescription struct {
+	XMLName       xml.Name        `xml:"OpenSearchDescription"`
+	XMLNS         string          `xml:"xmlns,attr"`
+	ShortName     string          `xml:"ShortName"`
+	Description   string          `xml:"Description"`
+	InputEncoding string          `xml:"InputEncoding"`
+	URLs          []openSearchURL `xml:"Url"`
+}
+
+// openSearchURL は検索URLのテンプレート
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// OpenSearch はOpenSearch記述文書を作成する関数
+// template：検索URL（{searchTerms}が検索語に置き換えられる）
+func OpenSearch(template string) ([]byte, error) {
+	desc := openSearchDescription{
+		XMLNS:         nsOpenSearch,
+		ShortName:     "book-manager",
+		Description:   "タイトル・著者で書籍を検索",
+		InputEncoding: "UTF-8",
+		URLs:          []openSearchURL{{Type: TypeAcquisition, Template: template}},
+	}
+	data, err := xml.MarshalIndent(desc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("OpenSearch記述の作成に失敗しました: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
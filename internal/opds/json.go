@@ -0,0 +1,132 @@
+package opds
+
+import (
+	"encoding/json" // OPDS 2.0（JSON）の出力
+	"fmt"           // 文字列フォーマット
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// jsonLink はOPDS 2.0のリンクオブジェクト
+type jsonLink struct {
+	Rel       string `json:"rel,omitempty"`
+	Href      string `json:"href"`
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+// jsonFeedMetadata はフィードのメタデータ
+type jsonFeedMetadata struct {
+	Title         string `json:"title"`
+	Modified      string `json:"modified"`
+	NumberOfItems int    `json:"numberOfItems,omitempty"`
+	ItemsPerPage  int    `json:"itemsPerPage,omitempty"`
+	CurrentPage   int    `json:"currentPage,omitempty"`
+}
+
+// jsonContributor は著者・出版社を表すオブジェクト
+type jsonContributor struct {
+	Name string `json:"name"`
+}
+
+// jsonPublicationMetadata は書籍1冊分のメタデータ
+type jsonPublicationMetadata struct {
+	Type        string            `json:"@type"`
+	Identifier  string            `json:"identifier"`
+	Title       string            `json:"title"`
+	Author      []jsonContributor `json:"author,omitempty"`
+	Publisher   []jsonContributor `json:"publisher,omitempty"`
+	Published   string            `json:"published,omitempty"`
+	Modified    string            `json:"modified"`
+	Subject     []jsonContributor `json:"subject,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+// jsonPublication は書籍1冊分のオブジェクト
+type jsonPublication struct {
+	Metadata jsonPublicationMetadata `json:"metadata"`
+	Links    []jsonLink              `json:"links"`
+	Images   []jsonLink              `json:"images,omitempty"`
+}
+
+// jsonFeed はOPDS 2.0のフィード
+type jsonFeed struct {
+	Metadata     jsonFeedMetadata  `json:"metadata"`
+	Links        []jsonLink        `json:"links"`
+	Navigation   []jsonLink        `json:"navigation,omitempty"`
+	Publications []jsonPublication `json:"publications,omitempty"`
+}
+
+// JSON はフィードをOPDS 2.0（JSON）に変換する関数
+func (f *Feed) JSON() ([]byte, error) {
+	feed := jsonFeed{
+		Metadata: jsonFeedMetadata{Title: f.Title, Modified: atomTime(f.Updated)},
+		Links:    []jsonLink{},
+	}
+	for _, l := range f.Links {
+		feed.Links = append(feed.Links, jsonLink{Rel: l.Rel, Href: l.Href, Type: l.Type, Title: l.Title, Templated: l.Templated})
+	}
+
+	if f.IsNavigation() {
+		feed.Navigation = []jsonLink{}
+		for _, nav := range f.Navigation {
+			title := nav.Title
+			if nav.Count > 0 {
+				title = fmt.Sprintf("%s（%d冊）", nav.Title, nav.Count)
+			}
+			feed.Navigation = append(feed.Navigation, jsonLink{Rel: RelSubsection, Href: nav.Href, Type: TypeOPDS2, Title: title})
+		}
+	} else {
+		feed.Metadata.NumberOfItems = f.Total
+		feed.Metadata.ItemsPerPage = f.PerPage
+		feed.Metadata.CurrentPage = f.Page
+		feed.Publications = []jsonPublication{}
+		for _, book := range f.Publications {
+			feed.Publications = append(feed.Publications, f.jsonPublication(book))
+		}
+	}
+
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("OPDSフィードの作成に失敗しました: %w", err)
+	}
+	return data, nil
+}
+
+// jsonPublication は書籍1冊をOPDS 2.0のpublicationに変換する
+func (f *Feed) jsonPublication(book *model.Book) jsonPublication {
+	pub := jsonPublication{
+		Metadata: jsonPublicationMetadata{
+			Type:        "http://schema.org/Book",
+			Identifier:  BookID(book),
+			Title:       book.Title,
+			Author:      []jsonContributor{{Name: book.Author}},
+			Modified:    atomTime(book.UpdatedAt),
+			Description: book.Notes,
+		},
+		Links: []jsonLink{},
+	}
+	if book.ISBN != "" {
+		pub.Metadata.Identifier = "urn:isbn:" + book.ISBN
+	}
+	if book.Publisher != "" {
+		pub.Metadata.Publisher = []jsonContributor{{Name: book.Publisher}}
+	}
+	if book.PublishedDate != nil {
+		pub.Metadata.Published = book.PublishedDate.Format("2006-01-02")
+	}
+	for _, tag := range Tags(book.Tags) {
+		pub.Metadata.Subject = append(pub.Metadata.Subject, jsonContributor{Name: tag})
+	}
+	if f.BookHref != nil {
+		pub.Links = append(pub.Links, jsonLink{Rel: RelSelf, Href: f.BookHref(book), Type: TypeBookJSON})
+	}
+	if cover := CoverURL(book, "L"); cover != "" {
+		pub.Images = []jsonLink{
+			{Href: cover, Type: "image/jpeg"},
+			{Href: CoverURL(book, "S"), Type: "image/jpeg", Rel: "thumbnail"},
+		}
+	}
+	return pub
+}
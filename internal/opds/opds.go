@@ -0,0 +1,119 @@
+// opdsパッケージ：電子書籍リーダー向けのOPDSカタログを作成するファイル
+// OPDS（Open Publication Distribution System）：KOReaderなどのアプリが書籍一覧を閲覧するための形式
+// OPDS 1.2（Atom/XML）とOPDS 2.0（JSON）の両方に対応する
+package opds
+
+import (
+	"fmt"     // 文字列フォーマット
+	"strings" // 文字列操作
+	"time"    // 更新日時
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// OPDSで使うリンクの種類（rel属性）とメディアタイプ
+const (
+	RelSelf       = "self"                                 // このフィードそのもの
+	RelStart      = "start"                                // カタログのトップ
+	RelUp         = "up"                                   // 1つ上の階層
+	RelFirst      = "first"                                // 最初のページ
+	RelPrevious   = "previous"                             // 前のページ
+	RelNext       = "next"                                 // 次のページ
+	RelLast       = "last"                                 // 最後のページ
+	RelSearch     = "search"                               // 検索（OpenSearch）
+	RelSubsection = "subsection"                           // 下の階層のフィード
+	RelImage      = "http://opds-spec.org/image"           // 表紙画像
+	RelThumbnail  = "http://opds-spec.org/image/thumbnail" // 表紙サムネイル
+
+	TypeNavigation  = "application/atom+xml;profile=opds-catalog;kind=navigation"  // ナビゲーションフィード
+	TypeAcquisition = "application/atom+xml;profile=opds-catalog;kind=acquisition" // 書籍一覧フィード
+	TypeOpenSearch  = "application/opensearchdescription+xml"                      // OpenSearch記述
+	TypeOPDS2       = "application/opds+json"                                      // OPDS 2.0のフィード
+	TypeBookJSON    = "application/json"                                           // 書籍APIのJSON
+)
+
+// Link はフィード内のリンク（ページ送り、検索など）
+type Link struct {
+	Rel       string
+	Href      string
+	Type      string
+	Title     string
+	Templated bool // OPDS 2.0の検索URLテンプレート（{?query}）の場合にtrue
+}
+
+// NavEntry はナビゲーションフィードの1項目（例：「読書中」「タグ：Go言語」）
+type NavEntry struct {
+	ID    string // 項目の一意な識別子
+	Title string // 表示名
+	Href  string // 遷移先フィードのパス（OPDS 1.2 / 2.0で別々のパスになる）
+	Count int    // 含まれる書籍数（0の場合は表示しない）
+	Type  string // 遷移先のメディアタイプ（空の場合は書籍一覧フィード）
+}
+
+// Feed はOPDSフィード1つ分のデータ
+// Navigationが設定されていればナビゲーションフィード、Publicationsなら書籍一覧フィードになる
+type Feed struct {
+	ID           string
+	Title        string
+	Updated      time.Time
+	Links        []Link
+	Navigation   []NavEntry
+	Publications []*model.Book
+	Total        int                           // 書籍一覧の総件数（ページング用）
+	Page         int                           // 現在のページ番号
+	PerPage      int                           // 1ページあたりの件数
+	BookHref     func(book *model.Book) string // 書籍の詳細（API）へのリンクを作る関数
+}
+
+// IsNavigation はナビゲーションフィードかどうかを返す
+func (f *Feed) IsNavigation() bool {
+	return f.Publications == nil
+}
+
+// BookID は書籍のフィード内での一意な識別子を返す関数
+func BookID(book *model.Book) string {
+	return fmt.Sprintf("urn:book-manager:book:%d", book.ID)
+}
+
+// Tags はカンマ区切りのタグ文字列を個々のタグに分割する関数
+func Tags(tags string) []string {
+	result := []string{}
+	for _, t := range strings.Split(tags, ",") {
+		if tag := strings.TrimSpace(t); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// CoverURL は書籍の表紙画像URLを返す関数
+// 書籍に表紙画像は保存していないため、ISBNがある場合のみOpen Library Covers APIの画像を使う
+// size：S（小）、M（中）、L（大）
+func CoverURL(book *model.Book, size string) string {
+	isbn := strings.NewReplacer("-", "", " ", "").Replace(book.ISBN)
+	if isbn == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://covers.openlibrary.org/b/isbn/%s-%s.jpg", isbn, size)
+}
+
+// PageLinks はページング用のリンク（first、previous、next、last）を作成する関数
+// hrefForPage：ページ番号からURLを作る関数（絞り込み条件を引き継ぐため呼び出し側で用意する）
+func PageLinks(page, perPage, total int, linkType string, hrefForPage func(page int) string) []Link {
+	lastPage := (total + perPage - 1) / perPage
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links := []Link{
+		{Rel: RelFirst, Href: hrefForPage(1), Type: linkType},
+		{Rel: RelLast, Href: hrefForPage(lastPage), Type: linkType},
+	}
+	if page > 1 {
+		links = append(links, Link{Rel: RelPrevious, Href: hrefForPage(page - 1), Type: linkType})
+	}
+	if page < lastPage {
+		links = append(links, Link{Rel: RelNext, Href: hrefForPage(page + 1), Type: linkType})
+	}
+	return links
+}
@@ -0,0 +1,157 @@
+package opds
+
+import (
+	"encoding/json" // OPDS 2.0の出力の解析
+	"encoding/xml"  // OPDS 1.2の出力の解析
+	"fmt"           // ページのURLの作成
+	"strings"       // 出力に含まれる文字列の確認
+	"testing"       // テストの実行と結果の報告
+	"time"          // 更新日時・出版日
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// testBook はテスト用の書籍を作成する
+func testBook() *model.Book {
+	published := time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC)
+	return &model.Book{
+		ID: 7, Title: "Go & <XML>", Author: "山田 太郎", Publisher: "技術出版社", ISBN: "978-4-12-345678-9",
+		Tags: " Go, 技術書 ,,", Notes: "メモ", PublishedDate: &published,
+		UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("JST", 9*60*60)),
+	}
+}
+
+// TestPageLinks はページ番号と総件数から、ページ送りのリンクが作られることを確認する
+func TestPageLinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  int
+		total int
+		want  string // rel=ページ番号 をスペースで区切ったもの
+	}{
+		{"書籍なし", 1, 0, "first=1 last=1"},
+		{"1ページだけ", 1, 10, "first=1 last=1"},
+		{"最初のページ", 1, 25, "first=1 last=3 next=2"},
+		{"途中のページ", 2, 25, "first=1 last=3 previous=1 next=3"},
+		{"最後のページ", 3, 30, "first=1 last=3 previous=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := PageLinks(tt.page, 10, tt.total, TypeAcquisition, func(page int) string { return fmt.Sprint(page) })
+			got := []string{}
+			for _, l := range links {
+				if l.Type != TypeAcquisition {
+					t.Errorf("%s のリンクの種類 = %q, want %q", l.Rel, l.Type, TypeAcquisition)
+				}
+				got = append(got, l.Rel+"="+l.Href)
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("PageLinks(%d, 10, %d) = %v, want %s", tt.page, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTagsAndCoverURL はタグの分割と、ISBNからの表紙画像のURLを確認する
+func TestTagsAndCoverURL(t *testing.T) {
+	tests := []struct {
+		tags      string
+		isbn      string
+		wantTags  string
+		wantCover string
+	}{
+		{"", "", "", ""},
+		{" Go, 技術書 ,,", "978-4-12-345678-9", "Go|技術書", "https://covers.openlibrary.org/b/isbn/9784123456789-S.jpg"},
+		{"小説", "4 12 345678 X", "小説", "https://covers.openlibrary.org/b/isbn/412345678X-S.jpg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tags+tt.isbn, func(t *testing.T) {
+			if got := strings.Join(Tags(tt.tags), "|"); got != tt.wantTags {
+				t.Errorf("Tags(%q) = %q, want %q", tt.tags, got, tt.wantTags)
+			}
+			if got := CoverURL(&model.Book{ISBN: tt.isbn}, "S"); got != tt.wantCover {
+				t.Errorf("CoverURL(%q) = %q, want %q", tt.isbn, got, tt.wantCover)
+			}
+		})
+	}
+}
+
+// TestFeed はナビゲーションフィードと書籍一覧フィードを、OPDS 1.2 と 2.0 のそれぞれで確認する
+func TestFeed(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	navigation := &Feed{
+		ID: "urn:book-manager:root", Title: "本棚", Updated: updated,
+		Links: []Link{{Rel: RelSelf, Href: "/opds", Type: TypeNavigation}},
+		Navigation: []NavEntry{
+			{ID: "reading", Title: "読書中", Href: "/opds/status/reading", Count: 3},
+			{ID: "tags", Title: "タグ", Href: "/opds/tags", Type: TypeNavigation},
+		},
+	}
+	acquisition := &Feed{
+		ID: "urn:book-manager:all", Title: "すべての書籍", Updated: updated,
+		Publications: []*model.Book{testBook()}, Total: 21, Page: 3, PerPage: 10,
+		BookHref: func(book *model.Book) string { return fmt.Sprintf("/api/v1/books/%d", book.ID) },
+	}
+
+	tests := []struct {
+		name   string
+		render func() ([]byte, error)
+		want   []string // 出力に含まれるべき文字列
+	}{
+		{"ナビゲーション（1.2）", navigation.Atom, []string{
+			`<?xml version="1.0" encoding="UTF-8"?>`,
+			`<link rel="subsection" href="/opds/status/reading" type="` + TypeAcquisition + `"></link>`,
+			`<link rel="subsection" href="/opds/tags" type="` + TypeNavigation + `"></link>`,
+			`<content type="text">3冊</content>`,
+			`<updated>2026-10-01T12:00:00Z</updated>`,
+		}},
+		{"書籍一覧（1.2）", acquisition.Atom, []string{
+			`<opensearch:totalResults>21</opensearch:totalResults>`,
+			`<opensearch:startIndex>21</opensearch:startIndex>`,
+			`<title>Go &amp; &lt;XML&gt;</title>`,
+			`<updated>2026-01-01T18:04:05Z</updated>`,
+			`<dc:identifier>urn:isbn:978-4-12-345678-9</dc:identifier>`,
+			`<dc:issued>2023-04-10</dc:issued>`,
+			`<category term="技術書" label="技術書"></category>`,
+			`<link rel="alternate" href="/api/v1/books/7" type="application/json"></link>`,
+			`<link rel="` + RelThumbnail + `" href="https://covers.openlibrary.org/b/isbn/9784123456789-S.jpg" type="image/jpeg"></link>`,
+		}},
+		{"ナビゲーション（2.0）", navigation.JSON, []string{
+			`"title": "読書中（3冊）"`,
+			`"title": "タグ"`,
+			`"type": "application/opds+json"`,
+		}},
+		{"書籍一覧（2.0）", acquisition.JSON, []string{
+			`"numberOfItems": 21`,
+			`"currentPage": 3`,
+			`"identifier": "urn:isbn:978-4-12-345678-9"`,
+			`"published": "2023-04-10"`,
+			`"modified": "2026-01-01T18:04:05Z"`,
+			`"href": "/api/v1/books/7"`,
+			`"rel": "thumbnail"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.render()
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			// 整形式のXML・JSONであることを確認する
+			var v interface{}
+			if strings.HasPrefix(string(data), "<?xml") {
+				err = xml.Unmarshal(data, &struct{}{})
+			} else {
+				err = json.Unmarshal(data, &v)
+			}
+			if err != nil {
+				t.Fatalf("出力を解析できません: %v\n%s", err, data)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("出力に %s が含まれていません\n%s", want, data)
+				}
+			}
+		})
+	}
+}
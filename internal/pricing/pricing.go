@@ -0,0 +1,95 @@
+// pricingパッケージ：欲しい本の現在の価格を外部から取得するためのファイル
+// 価格の取得元（書店のAPI、価格比較サイトなど）は Source インターフェースを実装すれば追加できる
+package pricing
+
+import (
+	"context"       // タイムアウト・キャンセル
+	"encoding/json" // 取得元のレスポンスの解析
+	"errors"        // エラーの定義
+	"fmt"           // エラーメッセージの作成
+	"net/http"      // HTTPクライアント
+	"net/url"       // URLのエスケープ
+	"strings"       // URLのテンプレートの置き換え
+	"time"          // HTTPのタイムアウト
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// ErrNotFound は取得元でその本の価格が見つからなかった場合のエラー
+var ErrNotFound = errors.New("価格が見つかりませんでした")
+
+// Quote は取得元から取得した価格
+type Quote struct {
+	Price int    // 価格（円）
+	URL   string // 価格を確認できるページのURL（なければ空）
+}
+
+// Source は価格の取得元を表すインターフェース
+// 新しい取得元を追加するときは、このインターフェースを実装して cmd/main.go で登録する
+type Source interface {
+	Name() string                                                         // 取得元の名前（価格の履歴の source に記録される）
+	Lookup(ctx context.Context, item *model.WishlistItem) (*Quote, error) // 欲しい本の現在の価格を取得
+}
+
+// HTTPSource はJSONを返すHTTPのエンドポイントから価格を取得する汎用の取得元
+// URLのテンプレートの {isbn}・{title}・{author} を欲しい本の値に置き換えてGETし、
+// {"price": 2980, "url": "https://..."} の形式のレスポンスを読み取る（404は価格なしとして扱う）
+type HTTPSource struct {
+	name        string       // 取得元の名前
+	urlTemplate string       // URLのテンプレート（例：https://example.com/price?isbn={isbn}）
+	client      *http.Client // HTTPクライアント
+}
+
+// NewHTTPSource は新しいHTTPSourceを作成する関数
+func NewHTTPSource(name, urlTemplate string) *HTTPSource {
+	return &HTTPSource{name: name, urlTemplate: urlTemplate, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name は取得元の名前を返す
+func (s *HTTPSource) Name() string {
+	return s.name
+}
+
+// Lookup はエンドポイントから欲しい本の現在の価格を取得する
+func (s *HTTPSource) Lookup(ctx context.Context, item *model.WishlistItem) (*Quote, error) {
+	// strings.NewReplacer：複数の置き換えをまとめて行う（値はURL用にエスケープする）
+	target := strings.NewReplacer(
+		"{isbn}", url.QueryEscape(item.ISBN),
+		"{title}", url.QueryEscape(item.Title),
+		"{author}", url.QueryEscape(item.Author),
+	).Replace(s.urlTemplate)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("価格の取得元のURLが不正です: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("価格の取得に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("価格の取得に失敗しました（HTTP %d）", resp.StatusCode)
+	}
+
+	var body struct {
+		Price *int   `json:"price"`
+		URL   string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("価格の取得元のレスポンスの解析に失敗しました: %w", err)
+	}
+	if body.Price == nil {
+		return nil, ErrNotFound
+	}
+	if *body.Price < 0 {
+		return nil, fmt.Errorf("価格の取得元が不正な価格を返しました: %d", *body.Price)
+	}
+	return &Quote{Price: *body.Price, URL: body.URL}, nil
+}
@@ -0,0 +1,580 @@
+package repository
+
+import (
+	"fmt"     // エラーメッセージの作成
+	"strings" // INSERT文のプレースホルダーの組み立て
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// ArchiveRepository は移行用アーカイブに含める書籍の関連データの読み書きを担当するインターフェース
+// 書籍そのものは BookRepository（Import）で読み書きする
+type ArchiveRepository interface {
+	Related(ownerID int) (*model.ArchiveRelated, error)                                                       // 本棚の書籍の関連データと、本棚の場所・欲しい本・読書目標・予算・変更履歴、為替レートを取得
+	ImportLocations(ownerID int, locations []*model.Location) (map[int]int, int, error)                       // 場所を取り込み、書き出し元のID → 取り込み後のIDと、作成した数を返す
+	ImportRelated(ownerID int, related *model.ArchiveRelated, replaced []int) (*model.ArchivedRecords, error) // 書籍の関連データ・欲しい本・読書目標・予算・為替レート・変更履歴を取り込む
+	Within(books BookRepository) ArchiveRepository                                                            // books の Transaction と同じトランザクションで読み書きするリポジトリを返す
+}
+
+// archiveRepository はArchiveRepositoryインターフェースの実装
+type archiveRepository struct {
+	db *database.DB // データベース接続オブジェクト
+	ex executor     // SQLの実行先（通常は db、Within で作ったリポジトリではトランザクション）
+}
+
+// NewArchiveRepository は新しいArchiveRepositoryを作成する関数
+func NewArchiveRepository(db *database.DB) ArchiveRepository {
+	return &archiveRepository{db: db, ex: db}
+}
+
+// Within は books（BookRepository の Transaction の中のリポジトリ）と同じトランザクションで読み書きするリポジトリを返す
+// アーカイブの場所・書籍・関連データの取り込みをまとめて確定・取り消しするために使う
+// books がデータベースのリポジトリでなければ（エフェメラルモード）、このリポジトリをそのまま返す
+func (r *archiveRepository) Within(books BookRepository) ArchiveRepository {
+	if b, ok := books.(*bookRepository); ok {
+		return &archiveRepository{db: r.db, ex: b.ex}
+	}
+	return r
+}
+
+// archiveOwnerWhere は所有者で絞り込むWHERE句を返す（AllOwnersなら絞り込まない）
+func archiveOwnerWhere(column string, ownerID int) (string, []interface{}) {
+	if ownerID == AllOwners {
+		return "1 = 1", nil
+	}
+	return ownerWhere(column, ownerID)
+}
+
+// Related は本棚の書籍（ゴミ箱の書籍も含む）の関連データと、本棚の場所・欲しい本・読書目標・予算・変更履歴をID順に取得する
+// 為替レートは全ユーザー共通のため、本棚によらずすべて取得する
+// ownerID が AllOwners なら全ユーザーのデータを取得する
+func (r *archiveRepository) Related(ownerID int) (*model.ArchiveRelated, error) {
+	related := &model.ArchiveRelated{
+		Locations: []*model.Location{}, Loans: []*model.Loan{}, WishlistItems: []*model.WishlistItem{},
+		PriceRecords: []*model.PriceRecord{}, Goals: []*model.ReadingGoal{}, Highlights: []*model.Highlight{},
+		Reviews: []*model.Review{}, ReviewRevisions: []*model.ReviewRevision{},
+		Budgets: []*model.Budget{}, ExchangeRates: []*model.ExchangeRate{}, AuditLogs: []*model.AuditLog{},
+	}
+	books, bookArgs := archiveOwnerWhere("b.owner_id", ownerID)
+	locations, locationArgs := archiveOwnerWhere("l.owner_id", ownerID)
+	wishlist, wishlistArgs := archiveOwnerWhere("w.owner_id", ownerID)
+	owned, ownedArgs := archiveOwnerWhere("owner_id", ownerID)
+	audits, auditArgs := archiveOwnerWhere("a.owner_id", ownerID)
+
+	for _, q := range []struct {
+		name  string
+		query string
+		args  []interface{}
+		scan  func(row rowScanner) error
+	}{
+		{"保管場所", locationSelect + " WHERE " + locations + " ORDER BY l.id", locationArgs, func(row rowScanner) error {
+			location, err := scanLocation(row)
+			related.Locations = append(related.Locations, location)
+			return err
+		}},
+		{"貸し出しの記録", loanSelect + " WHERE " + books + " ORDER BY l.id", bookArgs, func(row rowScanner) error {
+			loan, err := scanLoan(row)
+			related.Loans = append(related.Loans, loan)
+			return err
+		}},
+		{"欲しい本", wishlistSelect + " WHERE " + wishlist + " ORDER BY w.id", wishlistArgs, func(row rowScanner) error {
+			item, err := scanWishlistItem(row)
+			related.WishlistItems = append(related.WishlistItems, item)
+			return err
+		}},
+		{"価格の履歴", `SELECT p.id, p.item_id, p.price, p.source, p.url, p.recorded_at
+			FROM price_records p JOIN wishlist_items w ON w.id = p.item_id WHERE ` + wishlist + " ORDER BY p.id", wishlistArgs, func(row rowScanner) error {
+			record := &model.PriceRecord{}
+			related.PriceRecords = append(related.PriceRecords, record)
+			return row.Scan(&record.ID, &record.ItemID, &record.Price, &record.Source, &record.URL, &record.RecordedAt)
+		}},
+		{"読書目標", goalSelect + " WHERE " + owned + " ORDER BY id", ownedArgs, func(row rowScanner) error {
+			goal, err := scanGoal(row)
+			related.Goals = append(related.Goals, goal)
+			return err
+		}},
+		{"ハイライト", highlightSelect + " WHERE " + books + " ORDER BY h.id", bookArgs, func(row rowScanner) error {
+			highlight, err := scanHighlight(row)
+			related.Highlights = append(related.Highlights, highlight)
+			return err
+		}},
+		{"レビュー", `SELECT r.id, r.book_id, r.read_number, r.read_on, r.title, r.body, r.spoiler, r.visibility, r.version, r.created_at, r.updated_at
+			FROM reviews r JOIN books b ON b.id = r.book_id WHERE ` + books + " ORDER BY r.id", bookArgs, func(row rowScanner) error {
+			review, err := scanReview(row)
+			related.Reviews = append(related.Reviews, review)
+			return err
+		}},
+		{"レビューの編集履歴", `SELECT v.id, v.review_id, v.version, v.title, v.body, v.spoiler, v.edited_by, v.created_at
+			FROM review_revisions v JOIN reviews r ON r.id = v.review_id JOIN books b ON b.id = r.book_id WHERE ` + books + " ORDER BY v.id", bookArgs, func(row rowScanner) error {
+			revision, err := scanReviewRevision(row)
+			related.ReviewRevisions = append(related.ReviewRevisions, revision)
+			return err
+		}},
+		{"予算", budgetSelect + " WHERE " + owned + " ORDER BY id", ownedArgs, func(row rowScanner) error {
+			budget, err := scanBudget(row)
+			related.Budgets = append(related.Budgets, budget)
+			return err
+		}},
+		{"為替レート", exchangeRateSelect + " ORDER BY id", nil, func(row rowScanner) error {
+			rate, err := scanExchangeRate(row)
+			related.ExchangeRates = append(related.ExchangeRates, rate)
+			return err
+		}},
+		{"変更履歴", auditSelect + " WHERE " + audits + " ORDER BY a.id", auditArgs, func(row rowScanner) error {
+			entry, err := scanAudit(row)
+			related.AuditLogs = append(related.AuditLogs, entry)
+			return err
+		}},
+	} {
+		if err := r.each(q.query, q.args, q.scan); err != nil {
+			return nil, fmt.Errorf("%sの取得に失敗しました: %w", q.name, err)
+		}
+	}
+	return related, nil
+}
+
+// each はSQLの結果を1行ずつ scan に渡す
+func (r *archiveRepository) each(query string, args []interface{}, scan func(row rowScanner) error) error {
+	return eachRow(r.db, r.ex, query, args, scan)
+}
+
+// eachRow は ex でSQLを実行し、結果を1行ずつ scan に渡す
+func eachRow(db *database.DB, ex executor, query string, args []interface{}, scan func(row rowScanner) error) error {
+	rows, err := ex.Query(db.Rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// importer はアーカイブのデータを1つのトランザクションで取り込むときの、取り込み先の状態
+type importer struct {
+	db      *database.DB    // データベース接続オブジェクト（SQLの方言の判定に使う）
+	tx      executor        // 取り込みのトランザクション
+	ownerID int             // 取り込む本棚（AllOwners なら書き出し元の所有者のまま取り込む）
+	users   map[int]bool    // 取り込み先にあるユーザーのID（AllOwners のときだけ読み込む）
+	keepIDs map[string]bool // アーカイブのIDのまま挿入する表（取り込み先で空だった表）
+}
+
+// newImporter は取り込み先のユーザーと、空の表を調べてから importer を作る
+// 空の表にはアーカイブのIDのまま挿入し（IDが変わらず移行できる）、空でない表では新しいIDを採番する
+func (r *archiveRepository) newImporter(tx executor, ownerID int, tables ...string) (*importer, error) {
+	im := &importer{db: r.db, tx: tx, ownerID: ownerID, users: map[int]bool{}, keepIDs: map[string]bool{}}
+	for _, table := range tables {
+		var n int
+		// 表の名前はプレースホルダーにできないため、呼び出し側で固定の名前だけを渡す
+		if err := tx.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			return nil, fmt.Errorf("取り込み先の %s の件数の取得に失敗しました: %w", table, err)
+		}
+		im.keepIDs[table] = n == 0
+	}
+	if ownerID != AllOwners {
+		return im, nil
+	}
+	err := eachRow(r.db, tx, "SELECT id FROM users", nil, func(row rowScanner) error {
+		var id int
+		if err := row.Scan(&id); err != nil {
+			return err
+		}
+		im.users[id] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("取り込み先のユーザーの取得に失敗しました: %w", err)
+	}
+	return im, nil
+}
+
+// owner は取り込む行の所有者を返す
+// AllOwners で取り込むとき、書き出し元の所有者が取り込み先にいなければ取り込めない（外部キーの制約に違反するため、先にユーザーを作成する）
+func (im *importer) owner(rowOwner int) (int, error) {
+	if im.ownerID != AllOwners {
+		return im.ownerID, nil
+	}
+	if rowOwner > 0 && !im.users[rowOwner] {
+		return 0, fmt.Errorf("ID %d のユーザーが取り込み先にないため取り込めません（先にユーザーを作成してください）", rowOwner)
+	}
+	return rowOwner, nil
+}
+
+// userRef は取り込む行のユーザーへの参照（貸し出し先・レビューの編集者・変更した人）を返す
+// AllOwners で取り込むときは取り込み先にいるユーザーならそのまま、それ以外は書き出し元のユーザーIDは意味を持たないため外す
+func (im *importer) userRef(userID int) interface{} {
+	if im.ownerID == AllOwners && im.users[userID] {
+		return userID
+	}
+	return nil
+}
+
+// insert は表に行を挿入し、取り込み後のIDを返す（keepIDs の表にはアーカイブのID id も指定して挿入する）
+func (im *importer) insert(table string, id int, columns string, args ...interface{}) (int, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	if im.keepIDs[table] {
+		columns, placeholders = "id, "+columns, "?, "+placeholders
+		args = append([]interface{}{id}, args...)
+	}
+	newID, err := im.db.InsertReturningID(im.tx, "INSERT INTO "+table+" ("+columns+") VALUES ("+placeholders+")", args...)
+	return int(newID), err
+}
+
+// syncSequences はIDを指定して挿入した表の、次に採番されるIDを既存の最大IDに合わせる
+func (im *importer) syncSequences() error {
+	for table, keep := range im.keepIDs {
+		if !keep {
+			continue
+		}
+		if err := im.db.SyncSequence(im.tx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportLocations は場所を親から順に取り込み、書き出し元のID → 取り込み後のIDを返す
+// 同じ本棚に同じ親・種類・名前の場所が既にあれば、新しく作らずにその場所を使う（取り込み直しても場所が増えない）
+// 親がアーカイブに含まれていない場所は最上位の場所として取り込む
+// ownerID が AllOwners なら場所の所有者のまま、それ以外ならその本棚の場所として取り込む
+func (r *archiveRepository) ImportLocations(ownerID int, locations []*model.Location) (map[int]int, int, error) {
+	ids := map[int]int{}
+	created := 0
+	err := inTransaction(r.db, r.ex, "保管場所の取り込み", func(tx executor) error {
+		im, err := r.newImporter(tx, ownerID, "locations")
+		if err != nil {
+			return err
+		}
+
+		// locationKey は同じ場所かどうかを判定するキー
+		locationKey := func(owner int, parentID *int, kind model.LocationKind, name string) string {
+			parent := 0
+			if parentID != nil {
+				parent = *parentID
+			}
+			return fmt.Sprintf("%d/%d/%s/%s", owner, parent, kind, name)
+		}
+		existing := map[string]int{}
+		cond, args := archiveOwnerWhere("l.owner_id", ownerID)
+		if err := eachRow(r.db, tx, locationSelect+" WHERE "+cond, args, func(row rowScanner) error {
+			location, err := scanLocation(row)
+			if err != nil {
+				return err
+			}
+			existing[locationKey(location.OwnerID, location.ParentID, location.Kind, location.Name)] = location.ID
+			return nil
+		}); err != nil {
+			return fmt.Errorf("保管場所の取得に失敗しました: %w", err)
+		}
+
+		inArchive := map[int]bool{}
+		for _, location := range locations {
+			inArchive[location.ID] = true
+		}
+		for pending := locations; len(pending) > 0; {
+			next := []*model.Location{}
+			for _, location := range pending {
+				var parentID *int
+				if location.ParentID != nil && inArchive[*location.ParentID] {
+					parent, ok := ids[*location.ParentID]
+					if !ok {
+						next = append(next, location) // 親を取り込んでから取り込む
+						continue
+					}
+					parentID = &parent
+				}
+				owner, err := im.owner(location.OwnerID)
+				if err != nil {
+					return fmt.Errorf("保管場所 %s: %w", location.Name, err)
+				}
+				key := locationKey(owner, parentID, location.Kind, location.Name)
+				if id, ok := existing[key]; ok {
+					ids[location.ID] = id
+					continue
+				}
+				id, err := im.insert("locations", location.ID, "owner_id, parent_id, kind, name, created_at",
+					ownerValue(owner), parentID, location.Kind, location.Name, location.CreatedAt)
+				if err != nil {
+					return fmt.Errorf("保管場所 %s の取り込みに失敗しました: %w", location.Name, err)
+				}
+				existing[key] = id
+				ids[location.ID] = id
+				created++
+			}
+			if len(next) == len(pending) {
+				return fmt.Errorf("保管場所の親子関係が循環しているため取り込めません")
+			}
+			pending = next
+		}
+		return im.syncSequences()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return ids, created, nil
+}
+
+// ImportRelated は書籍の関連データ・欲しい本・読書目標・予算・為替レート・変更履歴を1つのトランザクションで取り込む
+// related の書籍のIDは取り込み後のIDに付け替えてから渡す（場所は ImportLocations で取り込むため使わない）
+// replaced：アーカイブの内容で置き換えた書籍のID（既存の貸し出し・ハイライト・レビュー・変更履歴を削除してから取り込む）
+// 欲しい本・読書目標・予算・為替レートは、同じものが既にあれば取り込まない（取り込み直しても増えない）
+// ownerID が AllOwners なら所有者とユーザーへの参照（貸し出し先・レビューの編集者・変更した人）もそのまま取り込み、
+// それ以外では書き出し元のユーザーIDは意味を持たないため外す
+func (r *archiveRepository) ImportRelated(ownerID int, related *model.ArchiveRelated, replaced []int) (*model.ArchivedRecords, error) {
+	imported := &model.ArchivedRecords{}
+	err := inTransaction(r.db, r.ex, "関連データの取り込み", func(tx executor) error {
+		for _, bookID := range replaced {
+			for _, query := range []string{
+				"DELETE FROM review_revisions WHERE review_id IN (SELECT id FROM reviews WHERE book_id = ?)",
+				"DELETE FROM reviews WHERE book_id = ?",
+				"DELETE FROM highlights WHERE book_id = ?",
+				"DELETE FROM loans WHERE book_id = ?",
+				"DELETE FROM audit_logs WHERE entity_type = '" + model.EntityBook + "' AND entity_id = ?",
+			} {
+				if _, err := tx.Exec(r.db.Rebind(query), bookID); err != nil {
+					return fmt.Errorf("置き換える書籍の関連データの削除に失敗しました: %w", err)
+				}
+			}
+		}
+
+		// 置き換えた書籍の関連データを削除してから、空の表を調べる
+		im, err := r.newImporter(tx, ownerID, "loans", "highlights", "reviews", "review_revisions",
+			"wishlist_items", "price_records", "reading_goals", "budgets", "exchange_rates", "audit_logs")
+		if err != nil {
+			return err
+		}
+		for _, step := range []func(*importer, *model.ArchiveRelated, *model.ArchivedRecords) error{
+			r.importWishlist, r.importGoals, r.importBookRecords, r.importBudgets, r.importExchangeRates, r.importAuditLogs,
+		} {
+			if err := step(im, related, imported); err != nil {
+				return err
+			}
+		}
+		return im.syncSequences()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// importBookRecords は書籍ごとのデータ（貸し出し・ハイライト・レビューとその編集履歴）を取り込む
+func (r *archiveRepository) importBookRecords(im *importer, related *model.ArchiveRelated, imported *model.ArchivedRecords) error {
+	for _, loan := range related.Loans {
+		if _, err := im.insert("loans", loan.ID, "book_id, borrower_name, borrower_id, lent_at, due_at, returned_at, notes, created_at",
+			loan.BookID, loan.BorrowerName, im.userRef(loan.BorrowerID), loan.LentAt, loan.DueAt, loan.ReturnedAt, loan.Notes, loan.LentAt); err != nil {
+			return fmt.Errorf("貸し出しの記録の取り込みに失敗しました: %w", err)
+		}
+		imported.Loans++
+	}
+	for _, h := range related.Highlights {
+		if _, err := im.insert("highlights", h.ID, "book_id, kind, text, note, page, location, chapter, tags, source_key, created_at, updated_at",
+			h.BookID, h.Kind, h.Text, h.Note, h.Page, h.Location, h.Chapter, h.Tags, h.SourceKey, h.CreatedAt, h.UpdatedAt); err != nil {
+			return fmt.Errorf("ハイライトの取り込みに失敗しました: %w", err)
+		}
+		imported.Highlights++
+	}
+
+	reviewIDs := map[int]int{} // 書き出し元のレビューのID → 取り込み後のID
+	for _, review := range related.Reviews {
+		id, err := im.insert("reviews", review.ID, "book_id, read_number, read_on, title, body, spoiler, visibility, version, created_at, updated_at",
+			review.BookID, review.ReadNumber, review.ReadOn, review.Title, review.Body, review.Spoiler, review.Visibility,
+			review.Version, review.CreatedAt, review.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("レビューの取り込みに失敗しました: %w", err)
+		}
+		reviewIDs[review.ID] = id
+		imported.Reviews++
+	}
+	for _, revision := range related.ReviewRevisions {
+		reviewID, ok := reviewIDs[revision.ReviewID]
+		if !ok {
+			continue // レビューを取り込まなかった編集履歴
+		}
+		if _, err := im.insert("review_revisions", revision.ID, "review_id, version, title, body, spoiler, edited_by, created_at",
+			reviewID, revision.Version, revision.Title, revision.Body, revision.Spoiler, im.userRef(revision.EditedBy), revision.CreatedAt); err != nil {
+			return fmt.Errorf("レビューの編集履歴の取り込みに失敗しました: %w", err)
+		}
+		imported.ReviewRevisions++
+	}
+	return nil
+}
+
+// importWishlist は欲しい本と価格の履歴を取り込む（同じ本棚に同じタイトル・著者・ISBNの欲しい本があれば取り込まない）
+func (r *archiveRepository) importWishlist(im *importer, related *model.ArchiveRelated, imported *model.ArchivedRecords) error {
+	itemKey := func(ownerID int, item *model.WishlistItem) string {
+		return fmt.Sprintf("%d/%s/%s/%s", ownerID, item.Title, item.Author, item.ISBN)
+	}
+	existing := map[string]bool{}
+	if err := eachRow(r.db, im.tx, "SELECT id, COALESCE(owner_id, 0), title, author, isbn FROM wishlist_items", nil, func(row rowScanner) error {
+		item := &model.WishlistItem{}
+		if err := row.Scan(&item.ID, &item.OwnerID, &item.Title, &item.Author, &item.ISBN); err != nil {
+			return err
+		}
+		existing[itemKey(item.OwnerID, item)] = true
+		return nil
+	}); err != nil {
+		return fmt.Errorf("欲しい本の取得に失敗しました: %w", err)
+	}
+
+	itemIDs := map[int]int{} // 書き出し元の欲しい本のID → 取り込み後のID
+	for _, item := range related.WishlistItems {
+		owner, err := im.owner(item.OwnerID)
+		if err != nil {
+			return fmt.Errorf("欲しい本 %s: %w", item.Title, err)
+		}
+		key := itemKey(owner, item)
+		if existing[key] {
+			continue
+		}
+		id, err := im.insert("wishlist_items", item.ID,
+			"owner_id, title, author, isbn, publisher, priority, target_price, where_seen, notes, tags, book_id, purchased_at, created_at, updated_at",
+			ownerValue(owner), item.Title, item.Author, item.ISBN, item.Publisher, item.Priority, item.TargetPrice,
+			item.WhereSeen, item.Notes, item.Tags, item.BookID, item.PurchasedAt, item.CreatedAt, item.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("欲しい本の取り込みに失敗しました: %w", err)
+		}
+		existing[key] = true
+		itemIDs[item.ID] = id
+		imported.WishlistItems++
+	}
+	for _, record := range related.PriceRecords {
+		itemID, ok := itemIDs[record.ItemID]
+		if !ok {
+			continue // 取り込まなかった欲しい本の価格
+		}
+		if _, err := im.insert("price_records", record.ID, "item_id, price, source, url, recorded_at",
+			itemID, record.Price, record.Source, record.URL, record.RecordedAt); err != nil {
+			return fmt.Errorf("価格の履歴の取り込みに失敗しました: %w", err)
+		}
+		imported.PriceRecords++
+	}
+	return nil
+}
+
+// importGoals は読書目標を取り込む（同じ本棚に名前・数えるもの・目標・期間・タグが同じ目標があれば取り込まない）
+func (r *archiveRepository) importGoals(im *importer, related *model.ArchiveRelated, imported *model.ArchivedRecords) error {
+	goalKey := func(ownerID int, goal *model.ReadingGoal) string {
+		return fmt.Sprintf("%d/%s/%s/%d/%s/%s/%s/%s", ownerID, goal.Title, goal.Metric, goal.Target, goal.Period,
+			goal.StartDate.Format("2006-01-02"), goal.EndDate.Format("2006-01-02"), goal.Tag)
+	}
+	existing := map[string]bool{}
+	cond, args := archiveOwnerWhere("owner_id", im.ownerID)
+	if err := eachRow(r.db, im.tx, goalSelect+" WHERE "+cond, args, func(row rowScanner) error {
+		goal, err := scanGoal(row)
+		if err != nil {
+			return err
+		}
+		existing[goalKey(goal.OwnerID, goal)] = true
+		return nil
+	}); err != nil {
+		return fmt.Errorf("読書目標の取得に失敗しました: %w", err)
+	}
+
+	for _, goal := range related.Goals {
+		owner, err := im.owner(goal.OwnerID)
+		if err != nil {
+			return fmt.Errorf("読書目標 %s: %w", goal.Title, err)
+		}
+		key := goalKey(owner, goal)
+		if existing[key] {
+			continue
+		}
+		if _, err := im.insert("reading_goals", goal.ID, "owner_id, title, metric, target, period, start_date, end_date, tag, created_at",
+			ownerValue(owner), goal.Title, goal.Metric, goal.Target, goal.Period, goal.StartDate, goal.EndDate, goal.Tag, goal.CreatedAt); err != nil {
+			return fmt.Errorf("読書目標の取り込みに失敗しました: %w", err)
+		}
+		existing[key] = true
+		imported.Goals++
+	}
+	return nil
+}
+
+// importBudgets は予算を取り込む（同じ本棚に同じ期間の予算があれば、取り込み先の予算を残す）
+func (r *archiveRepository) importBudgets(im *importer, related *model.ArchiveRelated, imported *model.ArchivedRecords) error {
+	existing := map[string]bool{}
+	if err := eachRow(r.db, im.tx, budgetSelect, nil, func(row rowScanner) error {
+		budget, err := scanBudget(row)
+		if err != nil {
+			return err
+		}
+		existing[fmt.Sprintf("%d/%s", budget.OwnerID, budget.Period)] = true
+		return nil
+	}); err != nil {
+		return fmt.Errorf("予算の取得に失敗しました: %w", err)
+	}
+
+	for _, budget := range related.Budgets {
+		owner, err := im.owner(budget.OwnerID)
+		if err != nil {
+			return fmt.Errorf("予算: %w", err)
+		}
+		key := fmt.Sprintf("%d/%s", owner, budget.Period)
+		if existing[key] {
+			continue
+		}
+		if _, err := im.insert("budgets", budget.ID, "owner_id, period, amount, created_at, updated_at",
+			ownerValue(owner), budget.Period, budget.Amount, budget.CreatedAt, budget.UpdatedAt); err != nil {
+			return fmt.Errorf("予算の取り込みに失敗しました: %w", err)
+		}
+		existing[key] = true
+		imported.Budgets++
+	}
+	return nil
+}
+
+// importExchangeRates は為替レートを取り込む（同じ通貨・基準通貨・日付のレートがあれば、取り込み先のレートを残す）
+func (r *archiveRepository) importExchangeRates(im *importer, related *model.ArchiveRelated, imported *model.ArchivedRecords) error {
+	rateKey := func(rate *model.ExchangeRate) string {
+		return fmt.Sprintf("%s/%s/%s", rate.Currency, rate.Base, rate.Date.Format("2006-01-02"))
+	}
+	existing := map[string]bool{}
+	if err := eachRow(r.db, im.tx, exchangeRateSelect, nil, func(row rowScanner) error {
+		rate, err := scanExchangeRate(row)
+		if err != nil {
+			return err
+		}
+		existing[rateKey(rate)] = true
+		return nil
+	}); err != nil {
+		return fmt.Errorf("為替レートの取得に失敗しました: %w", err)
+	}
+
+	for _, rate := range related.ExchangeRates {
+		key := rateKey(rate)
+		if existing[key] {
+			continue
+		}
+		if _, err := im.insert("exchange_rates", rate.ID, "currency, base, rate_date, rate, created_at",
+			rate.Currency, rate.Base, rate.Date, rate.Rate, rate.CreatedAt); err != nil {
+			return fmt.Errorf("為替レートの取り込みに失敗しました: %w", err)
+		}
+		existing[key] = true
+		imported.ExchangeRates++
+	}
+	return nil
+}
+
+// importAuditLogs は変更履歴を取り込む
+// 書籍の変更履歴は、取り込んだ書籍（対象のIDを付け替えたもの）の分だけを渡す
+func (r *archiveRepository) importAuditLogs(im *importer, related *model.ArchiveRelated, imported *model.ArchivedRecords) error {
+	for _, entry := range related.AuditLogs {
+		owner := im.ownerID
+		if owner == AllOwners {
+			// 変更履歴の所有者には外部キーの制約がないため、取り込み先にいないユーザーでもそのまま残す
+			owner = entry.OwnerID
+		}
+		if _, err := im.insert("audit_logs", entry.ID,
+			"actor_id, owner_id, entity_type, entity_id, action, before_data, after_data, request_id, created_at",
+			im.userRef(entry.ActorID), ownerValue(owner), entry.EntityType, entry.EntityID, entry.Action,
+			nullableJSON(entry.Before), nullableJSON(entry.After), entry.RequestID, entry.CreatedAt); err != nil {
+			return fmt.Errorf("変更履歴の取り込みに失敗しました: %w", err)
+		}
+		imported.AuditLogs++
+	}
+	return nil
+}
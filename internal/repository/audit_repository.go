@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"database/sql"  // 該当なしのエラー
+	"encoding/json" // 変更前後のデータ（JSON）の変換
+	"fmt"           // エラーメッセージの作成
+	"strings"       // WHERE句の組み立て
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// AuditRepository は変更履歴の永続化を担当するインターフェース
+type AuditRepository interface {
+	Record(entry *model.AuditLog) error                                           // 変更履歴を1件記録
+	GetByID(id int) (*model.AuditLog, error)                                      // IDで変更履歴を1件取得
+	List(filter *model.AuditFilter, limit, offset int) ([]*model.AuditLog, error) // 変更履歴を新しい順に取得
+	Count(filter *model.AuditFilter) (int, error)                                 // 条件に一致する変更履歴の件数
+}
+
+// auditRepository はAuditRepositoryインターフェースの実装
+type auditRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewAuditRepository は新しいAuditRepositoryを作成する関数
+func NewAuditRepository(db *database.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Record は変更履歴を1件記録する
+// ID 0（未ログイン・共有の本棚）は NULL として保存する
+func (r *auditRepository) Record(entry *model.AuditLog) error {
+	_, err := r.db.Exec(r.db.Rebind(`INSERT INTO audit_logs (actor_id, owner_id, entity_type, entity_id, action, before_data, after_data, request_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+		ownerValue(entry.ActorID), ownerValue(entry.OwnerID), entry.EntityType, entry.EntityID, entry.Action,
+		nullableJSON(entry.Before), nullableJSON(entry.After), entry.RequestID,
+	)
+	if err != nil {
+		return fmt.Errorf("変更履歴の記録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// auditSelect は変更履歴を取得するときのSELECT文
+// 変更した人のユーザー名も一緒に取得する（ユーザーが削除されている場合は空文字）
+const auditSelect = `SELECT a.id, COALESCE(a.actor_id, 0), COALESCE(u.username, ''), COALESCE(a.owner_id, 0),
+		a.entity_type, a.entity_id, a.action, a.before_data, a.after_data, a.request_id, a.created_at
+		FROM audit_logs a LEFT JOIN users u ON u.id = a.actor_id`
+
+// scanAudit は変更履歴の1行を読み取る
+func scanAudit(row rowScanner) (*model.AuditLog, error) {
+	entry := &model.AuditLog{}
+	var before, after *string
+	if err := row.Scan(&entry.ID, &entry.ActorID, &entry.ActorName, &entry.OwnerID,
+		&entry.EntityType, &entry.EntityID, &entry.Action, &before, &after, &entry.RequestID, &entry.CreatedAt); err != nil {
+		return nil, err
+	}
+	if before != nil {
+		entry.Before = json.RawMessage(*before)
+	}
+	if after != nil {
+		entry.After = json.RawMessage(*after)
+	}
+	return entry, nil
+}
+
+// GetByID はIDで変更履歴を1件取得する
+func (r *auditRepository) GetByID(id int) (*model.AuditLog, error) {
+	entry, err := scanAudit(r.db.QueryRow(r.db.Rebind(auditSelect+" WHERE a.id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("ID %d の変更履歴が見つかりません", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("変更履歴の取得に失敗しました: %w", err)
+	}
+	return entry, nil
+}
+
+// List は条件に一致する変更履歴を新しい順に取得する
+func (r *auditRepository) List(filter *model.AuditFilter, limit, offset int) ([]*model.AuditLog, error) {
+	where, args := auditConditions(filter)
+	query := auditSelect + where + " ORDER BY a.created_at DESC, a.id DESC"
+	if limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
+
+	rows, err := r.db.Query(r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("変更履歴の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*model.AuditLog{}
+	for rows.Next() {
+		entry, err := scanAudit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("変更履歴の読み取りに失敗しました: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("変更履歴の取得に失敗しました: %w", err)
+	}
+	return entries, nil
+}
+
+// Count は条件に一致する変更履歴の件数を返す
+func (r *auditRepository) Count(filter *model.AuditFilter) (int, error) {
+	where, args := auditConditions(filter)
+	var count int
+	if err := r.db.QueryRow(r.db.Rebind("SELECT COUNT(*) FROM audit_logs a"+where), args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("変更履歴の件数の取得に失敗しました: %w", err)
+	}
+	return count, nil
+}
+
+// auditConditions は絞り込み条件からWHERE句と引数を作る
+func auditConditions(filter *model.AuditFilter) (string, []interface{}) {
+	if filter == nil {
+		return "", nil
+	}
+	conditions := []string{}
+	args := []interface{}{}
+	if filter.OwnerID == 0 {
+		conditions = append(conditions, "a.owner_id IS NULL")
+	} else {
+		conditions = append(conditions, "a.owner_id = ?")
+		args = append(args, filter.OwnerID)
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "a.entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID > 0 {
+		conditions = append(conditions, "a.entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "a.action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.ActorID > 0 {
+		conditions = append(conditions, "a.actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+	if filter.RequestID != "" {
+		conditions = append(conditions, "a.request_id = ?")
+		args = append(args, filter.RequestID)
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullableJSON は空のJSONを NULL に変換する
+func nullableJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
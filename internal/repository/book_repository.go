@@ -9,6 +9,7 @@ import (
 	"strings"                       // 文字列操作（結合、分割など）
 	"time"                          // 時間関連の処理
 
+	"book-manager/internal/currency" // 通貨コードの既定値
 	"book-manager/internal/database" // 自作のデータベース接続機能
 	"book-manager/internal/model"    // 自作のデータ構造定義
 )
@@ -21,22 +22,178 @@ type BookRepository interface {
 	GetByID(id int) (*model.Book, error)                         // IDで書籍を1件取得
 	List(filter *model.BookFilter, limit, offset int) ([]*model.Book, error) // 条件に合う書籍リストを取得
 	Update(id int, book *model.UpdateBookRequest) (*model.Book, error)        // 書籍情報を更新
-	Delete(id int) error                                         // 書籍を削除
+	Replace(id int, book *model.Book) (*model.Book, error)                    // 書籍の項目をすべて指定した値にする（以前の版に戻す用）
+	Delete(id int) error                                         // 書籍をゴミ箱に移す（完全に削除するのは Purge）
 	Count(filter *model.BookFilter) (int, error)                // 条件に合う書籍数をカウント
+	Import(book *model.Book, keepID bool) (*model.Book, error)  // 書籍を全項目そのまま保存（アーカイブの取り込み用）
+	ExistsID(id int) (exists, owned bool, err error)             // IDが使われているか（ゴミ箱・他のユーザーの書籍も含む）と、この本棚の書籍かを確認
+	WithOwner(ownerID int) BookRepository                        // 指定したユーザーの本棚だけを扱うリポジトリを返す
+	ClaimUnowned(ownerID int) (int, error)                       // 所有者のない書籍をすべて指定したユーザーのものにする
+	SetLocation(id int, locationID *int) (*model.Book, error)    // 書籍の保管場所を設定（nilは場所の設定を外す）
+	Transaction(fn func(repo BookRepository) error) error        // fn の中の操作を1つのトランザクションで行う
+	ListDeleted(limit, offset int) ([]*model.Book, error)        // ゴミ箱の書籍をゴミ箱に移した日時の新しい順に取得
+	CountDeleted() (int, error)                                  // ゴミ箱の書籍数をカウント
+	Restore(id int) (*model.Book, error)                         // ゴミ箱の書籍を元に戻す
+	Purge(before time.Time) (int, error)                         // ゴミ箱に移した日時が before より前の書籍を完全に削除
 }
 
+// AllOwners は所有者で絞り込まないことを表す値（WithOwnerに渡す）
+// 管理用のコマンド（アーカイブの書き出しなど）で全ユーザーの書籍をまとめて扱うときに使う
+// ownerID 0 は「所有者なし」（ユーザー登録前からある共有の本棚）を表す
+const AllOwners = -1
+
 // bookRepository はBookRepositoryインターフェースの実装
 // struct：複数のデータをまとめた構造体
 // *database.DB：データベース接続を保持（*はポインタ型）
 type bookRepository struct {
-	db *database.DB // データベース接続オブジェクト
+	db      *database.DB // データベース接続オブジェクト
+	ex      executor     // SQLの実行先（通常は db、Transaction の中ではトランザクション）
+	ownerID int          // 扱う本棚の所有者（AllOwnersなら絞り込まない）
+	trash   bool         // trueならゴミ箱の書籍だけを扱う（ListDeletedなどの内部で使う）
 }
 
 // NewBookRepository は新しいBookRepositoryを作成する関数
 // コンストラクタ関数：新しいインスタンス（実体）を作る関数
 // &：アドレス演算子（メモリ上の場所を示すポインタを作る）
 func NewBookRepository(db *database.DB) BookRepository {
-	return &bookRepository{db: db} // bookRepository構造体のポインタを返す
+	return &bookRepository{db: db, ex: db, ownerID: AllOwners} // bookRepository構造体のポインタを返す
+}
+
+// executor はSQLを実行するオブジェクト（データベース接続とトランザクションのどちらでもよい）
+type executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// inTransaction は ex がトランザクション（Transaction の中）ならその中で、そうでなければ新しいトランザクションで fn を実行する
+// Transaction の中で呼ばれた処理は、外のトランザクションと一緒に確定・取り消しされる
+// name：確定に失敗したときのエラーメッセージに使う処理の名前
+func inTransaction(db *database.DB, ex executor, name string, fn func(tx executor) error) error {
+	if tx, ok := ex.(*sql.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	// Commit前にreturnした場合は変更を取り消す（Commit後のRollbackは何もしない）
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%sの確定に失敗しました: %w", name, err)
+	}
+	return nil
+}
+
+// WithOwner は指定したユーザーの本棚だけを扱うリポジトリを返す
+// 返されたリポジトリの取得・更新・削除はすべてそのユーザーの書籍に限られ、作成した書籍の所有者になる
+func (r *bookRepository) WithOwner(ownerID int) BookRepository {
+	return &bookRepository{db: r.db, ex: r.ex, ownerID: ownerID}
+}
+
+// ownerCondition は所有者で絞り込むWHERE句の条件と値を返す（絞り込まない場合は空文字）
+func (r *bookRepository) ownerCondition() (string, []interface{}) {
+	switch {
+	case r.ownerID == AllOwners:
+		return "", nil
+	case r.ownerID == 0:
+		return "owner_id IS NULL", nil // 所有者なしの共有の本棚
+	default:
+		return "owner_id = ?", []interface{}{r.ownerID}
+	}
+}
+
+// scopeCondition は扱う書籍に絞り込むWHERE句の条件と値を返す
+// 所有者の条件に加え、通常はゴミ箱の書籍を除き、ゴミ箱を扱うリポジトリならゴミ箱の書籍だけにする
+func (r *bookRepository) scopeCondition() (string, []interface{}) {
+	cond := "books.deleted_at IS NULL"
+	if r.trash {
+		cond = "books.deleted_at IS NOT NULL"
+	}
+	if owner, args := r.ownerCondition(); owner != "" {
+		return cond + " AND " + owner, args
+	}
+	return cond, nil
+}
+
+// activeLoanJoin は書籍に現在の貸し出し（未返却の貸し出し）を結合するSQL
+// 貸し出しのカラムはすべて loan_ で始まる別名にして、booksのカラム名（id、notesなど）と重ならないようにする
+const activeLoanJoin = ` LEFT JOIN (
+		SELECT id AS loan_id, book_id AS loan_book_id, borrower_name AS loan_borrower_name,
+		       borrower_id AS loan_borrower_id, lent_at AS loan_lent_at, due_at AS loan_due_at, notes AS loan_notes
+		FROM loans WHERE returned_at IS NULL
+	) active_loan ON active_loan.loan_book_id = books.id`
+
+// activeLoanColumns は現在の貸し出しのカラム一覧（bookLoanのScanと同じ順番）
+const activeLoanColumns = "loan_id, loan_borrower_name, loan_borrower_id, loan_lent_at, loan_due_at, loan_notes"
+
+// onLoanCondition は貸し出し中の書籍に絞り込むWHERE句の条件
+const onLoanCondition = "EXISTS (SELECT 1 FROM loans WHERE loans.book_id = books.id AND loans.returned_at IS NULL)"
+
+// exactTagCondition は指定したタグが付いた書籍に絞り込むWHERE句の条件（Book.HasTag と同じく、カンマ区切りの各タグと完全一致）
+// カンマ区切りのタグを再帰クエリで1行ずつに分けて比べる。先に LIKE で候補を絞り込むため、プレースホルダーは2つ（部分一致・完全一致）
+func exactTagCondition(db *database.DB) string {
+	comma := db.Instr("rest", "','")
+	return "(tags " + db.Like() + ` ? AND EXISTS (WITH RECURSIVE split(k, rest) AS (
+			SELECT CAST('' AS TEXT), COALESCE(books.tags, '') || ','
+			UNION ALL
+			SELECT TRIM(substr(rest, 1, ` + comma + ` - 1)), substr(rest, ` + comma + ` + 1) FROM split WHERE rest <> ''
+		) SELECT 1 FROM split WHERE k <> '' AND LOWER(k) = LOWER(?)))`
+}
+
+// subLocationsQuery は指定した場所と、その下のすべての場所のIDを取得するSQL
+// WITH RECURSIVE：親から子へ階層をたどる再帰クエリ（SQLiteとPostgreSQLで同じ書き方）
+const subLocationsQuery = `WITH RECURSIVE sub_locations(id) AS (
+		SELECT id FROM locations WHERE id = ?
+		UNION ALL
+		SELECT locations.id FROM locations JOIN sub_locations ON locations.parent_id = sub_locations.id
+	) SELECT id FROM sub_locations`
+
+// bookLoan は現在の貸し出しを読み込むための一時的な変数（LEFT JOINのため、すべてNULLの可能性がある）
+type bookLoan struct {
+	id           sql.NullInt64
+	borrowerName sql.NullString
+	borrowerID   sql.NullInt64
+	lentAt       *time.Time
+	dueAt        *time.Time
+	notes        sql.NullString
+}
+
+// apply は貸し出し中であれば書籍に現在の貸し出しを設定する
+func (l *bookLoan) apply(book *model.Book) {
+	if !l.id.Valid || l.lentAt == nil {
+		return
+	}
+	book.Loan = &model.Loan{
+		ID:           int(l.id.Int64),
+		BookID:       book.ID,
+		BorrowerName: l.borrowerName.String,
+		BorrowerID:   int(l.borrowerID.Int64),
+		LentAt:       *l.lentAt,
+		DueAt:        l.dueAt,
+		Notes:        l.notes.String,
+	}
+	book.Loan.Overdue = book.Loan.IsOverdue(time.Now())
+}
+
+// ownerValue は保存するowner_idの値を返す（0以下はNULL）
+func ownerValue(ownerID int) interface{} {
+	if ownerID <= 0 {
+		return nil
+	}
+	return ownerID
+}
+
+// currencyOrDefault は保存する通貨コードを返す（空文字は円。通貨がなかった頃のデータやアーカイブのため）
+func currencyOrDefault(code string) string {
+	if code == "" {
+		return currency.Default
+	}
+	return code
 }
 
 // Create は新しい書籍をデータベースに保存する関数
@@ -48,13 +205,14 @@ func (r *bookRepository) Create(req *model.CreateBookRequest) (*model.Book, erro
 	// INSERT INTO：新しいデータを挿入するSQL命令
 	// ?：プレースホルダー（後で実際の値に置き換えられる）
 	query := `
-		INSERT INTO books (title, author, isbn, publisher, published_date, purchase_date, purchase_price, tags, notes)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO books (title, author, isbn, publisher, published_date, purchase_date, purchase_price, tags, notes, owner_id, page_count, store, purchase_channel, format, currency)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	// r.db.Exec()：SQLを実行する関数
+	// InsertReturningID()：SQLを実行し、自動生成されたID（主キー）を取得する関数
 	// プレースホルダー（?）に実際の値を順番に入れて実行
-	result, err := r.db.Exec(query,
+	// データベースの種類に応じて LastInsertId() か RETURNING id を使い分ける
+	id, err := r.db.InsertReturningID(r.ex, query,
 		req.Title,         // タイトル
 		req.Author,        // 著者
 		req.ISBN,          // ISBN
@@ -64,18 +222,18 @@ func (r *bookRepository) Create(req *model.CreateBookRequest) (*model.Book, erro
 		req.PurchasePrice, // 購入価格
 		req.Tags,          // タグ
 		req.Notes,         // メモ
+		ownerValue(r.ownerID), // 所有者（このリポジトリが扱う本棚のユーザー）
+		req.PageCount,     // ページ数
+		req.Store,           // 購入した店
+		req.PurchaseChannel, // 購入方法
+		req.Format,          // 書籍の形態
+		currencyOrDefault(req.Currency), // 購入価格の通貨
 	)
 	// エラーハンドリング：エラーが発生した場合の処理
 	if err != nil {
 		return nil, fmt.Errorf("書籍の作成に失敗しました: %w", err)
 	}
 
-	// LastInsertId()：挿入されたデータの自動生成ID（主キー）を取得
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("書籍IDの取得に失敗しました: %w", err)
-	}
-
 	// 作成された書籍のデータを取得して返す
 	// int(id)：int64型をint型に変換
 	return r.GetByID(int(id))
@@ -89,15 +247,23 @@ func (r *bookRepository) GetByID(id int) (*model.Book, error) {
 	query := `
 		SELECT id, title, author, isbn, publisher, published_date, purchase_date, 
 		       purchase_price, status, start_read_date, end_read_date, rating, 
-		       notes, tags, created_at, updated_at
-		FROM books 
+		       notes, tags, created_at, updated_at, COALESCE(owner_id, 0), location_id, page_count, store, purchase_channel, format, currency, deleted_at, ` + activeLoanColumns + `
+		FROM books` + activeLoanJoin + `
 		WHERE id = ?
 	`
+	args := []interface{}{id}
+	// 他のユーザーの書籍とゴミ箱の書籍は「見つからない」として扱う
+	if cond, condArgs := r.scopeCondition(); cond != "" {
+		query += " AND " + cond
+		args = append(args, condArgs...)
+	}
 
 	// &model.Book{}：空のBook構造体を作成（&でポインタにする）
 	book := &model.Book{}
+	loan := &bookLoan{} // 現在の貸し出し（貸し出し中でなければすべてNULL）
 	// QueryRow()：1行だけを取得するSQL実行関数
-	row := r.db.QueryRow(query, id)
+	// Rebind()：プレースホルダーをデータベースの種類に合わせた書き方に変換
+	row := r.ex.QueryRow(r.db.Rebind(query), args...)
 
 	// Scan()：取得したデータを構造体の各フィールドに格納
 	// &book.ID：bookのIDフィールドのアドレス（格納先を指定）
@@ -118,6 +284,15 @@ func (r *bookRepository) GetByID(id int) (*model.Book, error) {
 		&book.Tags,          // タグ
 		&book.CreatedAt,     // 作成日時
 		&book.UpdatedAt,     // 更新日時
+		&book.OwnerID,       // 所有者
+		&book.LocationID,    // 保管場所
+		&book.PageCount,     // ページ数
+		&book.Store,           // 購入した店
+		&book.PurchaseChannel, // 購入方法
+		&book.Format,          // 書籍の形態
+		&book.Currency,        // 購入価格の通貨
+		&book.DeletedAt,       // ゴミ箱に移した日時
+		&loan.id, &loan.borrowerName, &loan.borrowerID, &loan.lentAt, &loan.dueAt, &loan.notes, // 現在の貸し出し
 	)
 
 	// エラーハンドリング
@@ -129,6 +304,8 @@ func (r *bookRepository) GetByID(id int) (*model.Book, error) {
 		return nil, fmt.Errorf("書籍の取得に失敗しました: %w", err)
 	}
 
+	loan.apply(book)
+
 	// 正常終了：取得した書籍データとnilエラーを返す
 	return book, nil
 }
@@ -138,12 +315,18 @@ func (r *bookRepository) GetByID(id int) (*model.Book, error) {
 // limit：最大取得件数、offset：何件目から取得するか（ページング用）
 func (r *bookRepository) List(filter *model.BookFilter, limit, offset int) ([]*model.Book, error) {
 	// 基本のSELECT文
-	query := "SELECT id, title, author, isbn, publisher, published_date, purchase_date, purchase_price, status, start_read_date, end_read_date, rating, notes, tags, created_at, updated_at FROM books"
+	query := "SELECT id, title, author, isbn, publisher, published_date, purchase_date, purchase_price, status, start_read_date, end_read_date, rating, notes, tags, created_at, updated_at, COALESCE(owner_id, 0), location_id, page_count, store, purchase_channel, format, currency, deleted_at, " + activeLoanColumns + " FROM books" + activeLoanJoin
 	// args：SQLのプレースホルダーに入れる値のスライス
 	args := []interface{}{}
 	// conditions：WHERE句の条件文のスライス
 	conditions := []string{}
 
+	// 所有者の本棚に限定（ゴミ箱の書籍は除く）
+	if cond, condArgs := r.scopeCondition(); cond != "" {
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
 	// フィルター条件を動的に構築
 	// 動的SQL：条件に応じてSQL文を組み立てる手法
 	if filter != nil {
@@ -166,15 +349,33 @@ func (r *bookRepository) List(filter *model.BookFilter, limit, offset int) ([]*m
 		}
 		if filter.Tag != nil {
 			// LIKE：部分一致検索、%は任意の文字列を表すワイルドカード
-			conditions = append(conditions, "tags LIKE ?")   // タグで部分一致検索
+			conditions = append(conditions, "tags "+r.db.Like()+" ?") // タグで部分一致検索
 			args = append(args, "%"+*filter.Tag+"%")
 		}
+		if filter.ExactTag != nil {
+			tag := strings.TrimSpace(*filter.ExactTag)
+			conditions = append(conditions, exactTagCondition(r.db)) // タグで完全一致検索
+			args = append(args, "%"+tag+"%", tag)
+		}
 		if filter.Search != nil {
 			// OR：複数条件のいずれかに一致
-			conditions = append(conditions, "(title LIKE ? OR author LIKE ?)")
+			conditions = append(conditions, "(title "+r.db.Like()+" ? OR author "+r.db.Like()+" ?)")
 			searchTerm := "%" + *filter.Search + "%"  // 前後にワイルドカードを付加
 			args = append(args, searchTerm, searchTerm) // タイトルと著者の両方に同じ条件
 		}
+		if filter.OnLoan != nil {
+			// EXISTS：貸し出し中（未返却）の貸し出しがあるかどうか
+			if *filter.OnLoan {
+				conditions = append(conditions, onLoanCondition)
+			} else {
+				conditions = append(conditions, "NOT "+onLoanCondition)
+			}
+		}
+		if filter.LocationID != nil {
+			// IN (サブクエリ)：指定した場所とその下の場所のどこかに置かれている書籍
+			conditions = append(conditions, "location_id IN ("+subLocationsQuery+")")
+			args = append(args, *filter.LocationID)
+		}
 	}
 
 	// 条件がある場合はWHERE句を追加
@@ -185,7 +386,12 @@ func (r *bookRepository) List(filter *model.BookFilter, limit, offset int) ([]*m
 	}
 
 	// ORDER BY：結果の並び順を指定（created_at DESC = 作成日時の降順）
-	query += " ORDER BY created_at DESC"
+	// 作成日時は秒単位のため、同じ秒に作成された書籍はIDの降順にして順番を安定させる
+	order := "created_at DESC"
+	if r.trash {
+		order = "deleted_at DESC" // ゴミ箱はゴミ箱に移した日時の新しい順
+	}
+	query += " ORDER BY " + order + ", id DESC"
 
 	// ページング処理（LIMIT：件数制限、OFFSET：開始位置）
 	if limit > 0 {
@@ -199,7 +405,7 @@ func (r *bookRepository) List(filter *model.BookFilter, limit, offset int) ([]*m
 
 	// Query()：複数行を取得するSQL実行関数
 	// args...：スライスを可変長引数として展開
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.ex.Query(r.db.Rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("書籍一覧の取得に失敗しました: %w", err)
 	}
@@ -212,6 +418,7 @@ func (r *bookRepository) List(filter *model.BookFilter, limit, offset int) ([]*m
 	for rows.Next() {
 		// 各行ごとに新しいBook構造体を作成
 		book := &model.Book{}
+		loan := &bookLoan{} // 現在の貸し出し
 		// 1行分のデータを構造体のフィールドに格納
 		err := rows.Scan(
 			&book.ID,            // ID
@@ -230,10 +437,20 @@ func (r *bookRepository) List(filter *model.BookFilter, limit, offset int) ([]*m
 			&book.Tags,          // タグ
 			&book.CreatedAt,     // 作成日時
 			&book.UpdatedAt,     // 更新日時
+			&book.OwnerID,       // 所有者
+			&book.LocationID,    // 保管場所
+			&book.PageCount,     // ページ数
+			&book.Store,           // 購入した店
+			&book.PurchaseChannel, // 購入方法
+			&book.Format,          // 書籍の形態
+			&book.Currency,        // 購入価格の通貨
+			&book.DeletedAt,       // ゴミ箱に移した日時
+			&loan.id, &loan.borrowerName, &loan.borrowerID, &loan.lentAt, &loan.dueAt, &loan.notes, // 現在の貸し出し
 		)
 		if err != nil {
 			return nil, fmt.Errorf("書籍データの読み込みに失敗しました: %w", err)
 		}
+		loan.apply(book)
 		// スライスに書籍データを追加
 		books = append(books, book)
 	}
@@ -318,6 +535,26 @@ func (r *bookRepository) Update(id int, req *model.UpdateBookRequest) (*model.Bo
 		setParts = append(setParts, "tags = ?")            // タグ更新
 		args = append(args, *req.Tags)
 	}
+	if req.PageCount != nil {
+		setParts = append(setParts, "page_count = ?")      // ページ数更新
+		args = append(args, *req.PageCount)
+	}
+	if req.Store != nil {
+		setParts = append(setParts, "store = ?")           // 購入した店更新
+		args = append(args, *req.Store)
+	}
+	if req.PurchaseChannel != nil {
+		setParts = append(setParts, "purchase_channel = ?") // 購入方法更新
+		args = append(args, *req.PurchaseChannel)
+	}
+	if req.Format != nil {
+		setParts = append(setParts, "format = ?")          // 書籍の形態更新
+		args = append(args, *req.Format)
+	}
+	if req.Currency != nil {
+		setParts = append(setParts, "currency = ?")        // 購入価格の通貨更新
+		args = append(args, currencyOrDefault(*req.Currency))
+	}
 
 	// 更新するフィールドがない場合は、現在のデータをそのまま返す
 	if len(setParts) == 0 {
@@ -328,9 +565,13 @@ func (r *bookRepository) Update(id int, req *model.UpdateBookRequest) (*model.Bo
 	// strings.Join()：SET句の各部分をカンマで結合
 	query := "UPDATE books SET " + strings.Join(setParts, ", ") + " WHERE id = ?"
 	args = append(args, id)  // WHERE句のIDをパラメータに追加
+	if cond, condArgs := r.scopeCondition(); cond != "" {
+		query += " AND " + cond // 他のユーザーの書籍とゴミ箱の書籍は更新しない
+		args = append(args, condArgs...)
+	}
 
 	// UPDATE文を実行
-	_, err := r.db.Exec(query, args...)
+	_, err := r.ex.Exec(r.db.Rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("書籍の更新に失敗しました: %w", err)
 	}
@@ -339,11 +580,48 @@ func (r *bookRepository) Update(id int, req *model.UpdateBookRequest) (*model.Bo
 	return r.GetByID(id)
 }
 
-// Delete は書籍をデータベースから削除する関数
+// Replace は書籍の項目を book の値にそろえる関数（以前の版に戻すときに使う）
+// Update と違い、値が nil の項目（評価・読書開始日など）は NULL に戻す
+// ID・所有者・作成日時・保管場所は変えない（保管場所は移動の操作で変える）
+func (r *bookRepository) Replace(id int, book *model.Book) (*model.Book, error) {
+	query := `UPDATE books SET title = ?, author = ?, isbn = ?, publisher = ?, published_date = ?, purchase_date = ?,
+		purchase_price = ?, currency = ?, status = ?, start_read_date = ?, end_read_date = ?, rating = ?, notes = ?, tags = ?,
+		page_count = ?, store = ?, purchase_channel = ?, format = ? WHERE id = ?`
+	args := []interface{}{
+		book.Title, book.Author, book.ISBN, book.Publisher, book.PublishedDate, book.PurchaseDate,
+		book.PurchasePrice, currencyOrDefault(book.Currency), book.Status, book.StartReadDate, book.EndReadDate, book.Rating, book.Notes, book.Tags,
+		book.PageCount, book.Store, book.PurchaseChannel, book.Format, id,
+	}
+	if cond, condArgs := r.scopeCondition(); cond != "" {
+		query += " AND " + cond // 他のユーザーの書籍とゴミ箱の書籍は変えない
+		args = append(args, condArgs...)
+	}
+	result, err := r.ex.Exec(r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("書籍の更新に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("更新結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("ID %d の書籍が見つかりません", id)
+	}
+	return r.GetByID(id)
+}
+
+// Delete は書籍をゴミ箱に移す関数
+// 行は削除せずに deleted_at を記録するだけなので、Restore で元に戻せる（完全に削除するのは Purge）
 func (r *bookRepository) Delete(id int) error {
-	// DELETE文：指定したIDの書籍を削除
-	query := "DELETE FROM books WHERE id = ?"
-	result, err := r.db.Exec(query, id)
+	// UPDATE文：指定したIDの書籍にゴミ箱に移した日時を記録
+	query := "UPDATE books SET deleted_at = ? WHERE id = ?"
+	// UTCで保存する（SQLiteでは日時を文字列で比べるため、Purge の比較とタイムゾーンをそろえる）
+	args := []interface{}{time.Now().UTC(), id}
+	if cond, condArgs := r.scopeCondition(); cond != "" {
+		query += " AND " + cond // 他のユーザーの書籍とゴミ箱の書籍は削除しない
+		args = append(args, condArgs...)
+	}
+	result, err := r.ex.Exec(r.db.Rebind(query), args...)
 	if err != nil {
 		return fmt.Errorf("書籍の削除に失敗しました: %w", err)
 	}
@@ -371,6 +649,12 @@ func (r *bookRepository) Count(filter *model.BookFilter) (int, error) {
 	args := []interface{}{}
 	conditions := []string{}
 
+	// 所有者の本棚に限定（ゴミ箱の書籍は除く）
+	if cond, condArgs := r.scopeCondition(); cond != "" {
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
 	// Listメソッドと同じフィルター条件を適用
 	// カウント対象を絞り込む
 	if filter != nil {
@@ -391,14 +675,30 @@ func (r *bookRepository) Count(filter *model.BookFilter) (int, error) {
 			args = append(args, *filter.Rating)
 		}
 		if filter.Tag != nil {
-			conditions = append(conditions, "tags LIKE ?")     // タグ部分一致
+			conditions = append(conditions, "tags "+r.db.Like()+" ?") // タグ部分一致
 			args = append(args, "%"+*filter.Tag+"%")
 		}
+		if filter.ExactTag != nil {
+			tag := strings.TrimSpace(*filter.ExactTag)
+			conditions = append(conditions, exactTagCondition(r.db)) // タグ完全一致
+			args = append(args, "%"+tag+"%", tag)
+		}
 		if filter.Search != nil {
-			conditions = append(conditions, "(title LIKE ? OR author LIKE ?)") // 全文検索
+			conditions = append(conditions, "(title "+r.db.Like()+" ? OR author "+r.db.Like()+" ?)") // 全文検索
 			searchTerm := "%" + *filter.Search + "%"
 			args = append(args, searchTerm, searchTerm)
 		}
+		if filter.OnLoan != nil {
+			if *filter.OnLoan {
+				conditions = append(conditions, onLoanCondition) // 貸し出し中
+			} else {
+				conditions = append(conditions, "NOT "+onLoanCondition) // 貸し出し中でない
+			}
+		}
+		if filter.LocationID != nil {
+			conditions = append(conditions, "location_id IN ("+subLocationsQuery+")") // 保管場所（下の場所も含む）
+			args = append(args, *filter.LocationID)
+		}
 	}
 
 	// 条件がある場合はWHERE句を追加
@@ -409,11 +709,253 @@ func (r *bookRepository) Count(filter *model.BookFilter) (int, error) {
 	// カウント結果を格納する変数
 	var count int
 	// QueryRow()で1つの値（カウント数）を取得
-	err := r.db.QueryRow(query, args...).Scan(&count)
+	err := r.ex.QueryRow(r.db.Rebind(query), args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("書籍数の取得に失敗しました: %w", err)
 	}
 
 	// カウント数を返す
 	return count, nil
-}
\ No newline at end of file
+}
+
+// Import は書籍を全項目（作成日時・更新日時を含む）そのまま保存する関数
+// アーカイブからの取り込みで、別のインスタンスのデータを失わずに移すために使う
+// keepID：trueなら book.ID をそのまま使う（同じIDの書籍があれば置き換える）、falseなら新しいIDを採番する
+func (r *bookRepository) Import(book *model.Book, keepID bool) (*model.Book, error) {
+	// 所有者：本棚を限定したリポジトリならその本棚に、限定していなければ書籍の所有者のまま取り込む
+	ownerID := r.ownerID
+	if ownerID == AllOwners {
+		ownerID = book.OwnerID
+	}
+
+	// 保管場所とゴミ箱に移した日時もそのまま保存する（保管場所のIDの付け替えは呼び出し側で行う）
+	columns := "title, author, isbn, publisher, published_date, purchase_date, purchase_price, status, start_read_date, end_read_date, rating, notes, tags, created_at, updated_at, owner_id, page_count, store, purchase_channel, format, currency, location_id, deleted_at"
+	args := []interface{}{
+		book.Title, book.Author, book.ISBN, book.Publisher, book.PublishedDate,
+		book.PurchaseDate, book.PurchasePrice, book.Status, book.StartReadDate, book.EndReadDate,
+		book.Rating, book.Notes, book.Tags, book.CreatedAt, book.UpdatedAt, ownerValue(ownerID),
+		book.PageCount, book.Store, book.PurchaseChannel, book.Format, currencyOrDefault(book.Currency),
+		book.LocationID, book.DeletedAt,
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+
+	// トランザクション：置き換え時の「削除→挿入」を1つの処理としてまとめる
+	// Transaction の中（アーカイブの取り込み）ではそのトランザクションを使う
+	var id int64
+	err := inTransaction(r.db, r.ex, "書籍の取り込み", func(tx executor) error {
+		wishlistItems := []int{} // 置き換える書籍を指していた欲しい本
+		if keepID {
+			// 同じIDの行を、ゴミ箱の書籍も含めて所有者まで確かめてから置き換える
+			// 他のユーザーの書籍は置き換えない（主キーの重複エラーに頼らず、ここで断る）
+			var existingOwner int
+			err := tx.QueryRow(r.db.Rebind("SELECT COALESCE(owner_id, 0) FROM books WHERE id = ?"), book.ID).Scan(&existingOwner)
+			switch {
+			case err == sql.ErrNoRows:
+			case err != nil:
+				return fmt.Errorf("既存の書籍の確認に失敗しました: %w", err)
+			case r.ownerID != AllOwners && existingOwner != r.ownerID:
+				return fmt.Errorf("ID %d は他のユーザーの書籍で使われているため、同じIDでは取り込めません", book.ID)
+			default:
+				// UPDATEではなく削除してから挿入する（UPDATEだと更新日時のトリガーが動き、updated_atが変わってしまうため）
+				// 削除すると外部キーで貸し出し・ハイライト・レビューも削除され（アーカイブの内容で取り込み直す）、
+				// 欲しい本からの参照は外れるため、挿入した後に付け直す
+				if wishlistItems, err = r.wishlistItemsOf(tx, book.ID); err != nil {
+					return err
+				}
+				if _, err := tx.Exec(r.db.Rebind("DELETE FROM books WHERE id = ?"), book.ID); err != nil {
+					return fmt.Errorf("既存の書籍の置き換えに失敗しました: %w", err)
+				}
+			}
+			columns = "id, " + columns
+			placeholders = "?, " + placeholders
+			args = append([]interface{}{book.ID}, args...)
+		}
+
+		var err error
+		id, err = r.db.InsertReturningID(tx, "INSERT INTO books ("+columns+") VALUES ("+placeholders+")", args...)
+		if err != nil {
+			return fmt.Errorf("書籍の取り込みに失敗しました: %w", err)
+		}
+		for _, itemID := range wishlistItems {
+			if _, err := tx.Exec(r.db.Rebind("UPDATE wishlist_items SET book_id = ? WHERE id = ?"), id, itemID); err != nil {
+				return fmt.Errorf("欲しい本の参照の付け直しに失敗しました: %w", err)
+			}
+		}
+		if keepID {
+			// IDを指定して挿入した場合は、次に採番されるIDが重複しないように合わせる
+			return r.db.SyncSequence(tx, "books")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// ゴミ箱に入った状態で取り込んだ書籍は、ゴミ箱を扱うリポジトリで取得する
+	return (&bookRepository{db: r.db, ex: r.ex, ownerID: ownerID, trash: book.DeletedAt != nil}).GetByID(int(id))
+}
+
+// wishlistItemsOf は書籍を指している欲しい本のIDを返す
+func (r *bookRepository) wishlistItemsOf(tx executor, bookID int) ([]int, error) {
+	rows, err := tx.Query(r.db.Rebind("SELECT id FROM wishlist_items WHERE book_id = ?"), bookID)
+	if err != nil {
+		return nil, fmt.Errorf("書籍を指している欲しい本の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("欲しい本データの読み取りに失敗しました: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ExistsID はIDの書籍があるか（ゴミ箱の書籍と他のユーザーの書籍も含む）と、それがこのリポジトリの本棚の書籍かを確認する
+// GetByID はゴミ箱の書籍と他のユーザーの書籍を「見つからない」として扱うため、取り込みで同じIDを使えるかの判断にはこちらを使う
+func (r *bookRepository) ExistsID(id int) (exists, owned bool, err error) {
+	var ownerID int
+	err = r.ex.QueryRow(r.db.Rebind("SELECT COALESCE(owner_id, 0) FROM books WHERE id = ?"), id).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("書籍の確認に失敗しました: %w", err)
+	}
+	return true, r.ownerID == AllOwners || ownerID == r.ownerID, nil
+}
+
+// ClaimUnowned は所有者のない書籍（ユーザー登録前からある共有の本棚）をすべて指定したユーザーのものにする
+// 1人で使っていたアプリに最初のユーザーを登録したとき、それまでの書籍を引き継ぐために使う
+func (r *bookRepository) ClaimUnowned(ownerID int) (int, error) {
+	result, err := r.ex.Exec(r.db.Rebind("UPDATE books SET owner_id = ? WHERE owner_id IS NULL"), ownerID)
+	if err != nil {
+		return 0, fmt.Errorf("書籍の所有者の設定に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("更新結果の確認に失敗しました: %w", err)
+	}
+	return int(n), nil
+}
+
+// ListDeleted はゴミ箱の書籍をゴミ箱に移した日時の新しい順に取得する
+func (r *bookRepository) ListDeleted(limit, offset int) ([]*model.Book, error) {
+	return (&bookRepository{db: r.db, ex: r.ex, ownerID: r.ownerID, trash: true}).List(nil, limit, offset)
+}
+
+// CountDeleted はゴミ箱の書籍数を取得する
+func (r *bookRepository) CountDeleted() (int, error) {
+	return (&bookRepository{db: r.db, ex: r.ex, ownerID: r.ownerID, trash: true}).Count(nil)
+}
+
+// Restore はゴミ箱の書籍を元に戻す
+func (r *bookRepository) Restore(id int) (*model.Book, error) {
+	query := "UPDATE books SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL"
+	args := []interface{}{id}
+	if cond, condArgs := r.ownerCondition(); cond != "" {
+		query += " AND " + cond // 他のユーザーの書籍は戻さない
+		args = append(args, condArgs...)
+	}
+	result, err := r.ex.Exec(r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("書籍の復元に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("復元結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("ID %d の書籍はゴミ箱にありません", id)
+	}
+	return r.GetByID(id)
+}
+
+// Purge はゴミ箱に移した日時が before より前の書籍を完全に削除し、削除した冊数を返す
+// ハイライト・レビュー・貸し出しの記録は外部キーの ON DELETE CASCADE でも消えるが、どのデータベースでも同じ結果になるよう
+// 明示的に削除し、欲しい本からの購入元の参照は外す（途中で失敗して関連データだけが消えないよう、1つのトランザクションで行う）
+func (r *bookRepository) Purge(before time.Time) (int, error) {
+	cond := "deleted_at IS NOT NULL AND deleted_at < ?"
+	args := []interface{}{before.UTC()}
+	if owner, ownerArgs := r.ownerCondition(); owner != "" {
+		cond += " AND " + owner
+		args = append(args, ownerArgs...)
+	}
+	purged := "SELECT id FROM books WHERE " + cond // 完全に削除する書籍のID
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM review_revisions WHERE review_id IN (SELECT id FROM reviews WHERE book_id IN (" + purged + "))",
+		"DELETE FROM reviews WHERE book_id IN (" + purged + ")",
+		"DELETE FROM highlights WHERE book_id IN (" + purged + ")",
+		"DELETE FROM loans WHERE book_id IN (" + purged + ")",
+		"UPDATE wishlist_items SET book_id = NULL WHERE book_id IN (" + purged + ")",
+	} {
+		if _, err := tx.Exec(r.db.Rebind(query), args...); err != nil {
+			return 0, fmt.Errorf("ゴミ箱の書籍の関連データの削除に失敗しました: %w", err)
+		}
+	}
+	result, err := tx.Exec(r.db.Rebind("DELETE FROM books WHERE "+cond), args...)
+	if err != nil {
+		return 0, fmt.Errorf("ゴミ箱の書籍の削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ゴミ箱の書籍の削除の確定に失敗しました: %w", err)
+	}
+	return int(n), nil
+}
+
+// SetLocation は書籍の保管場所を設定する（nilは場所の設定を外す）
+// 場所が書籍の持ち主のものかどうかは呼び出し側（ユースケース）で確認する
+func (r *bookRepository) SetLocation(id int, locationID *int) (*model.Book, error) {
+	query := "UPDATE books SET location_id = ? WHERE id = ?"
+	args := []interface{}{locationID, id}
+	if cond, condArgs := r.scopeCondition(); cond != "" {
+		query += " AND " + cond // 他のユーザーの書籍とゴミ箱の書籍は変えない
+		args = append(args, condArgs...)
+	}
+	result, err := r.ex.Exec(r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("書籍の移動に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("更新結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("ID %d の書籍が見つかりません", id)
+	}
+	return r.GetByID(id)
+}
+
+// Transaction は fn の中のリポジトリの操作（取得・作成・更新・削除）を1つのトランザクションで行う
+// fn に渡すリポジトリはこのリポジトリと同じ本棚を扱い、WithOwner で作ったリポジトリも同じトランザクションを使う
+// fn がエラーを返すとすべての変更を取り消す（Import は fn のトランザクションを使い、Purge は独自のトランザクションを使うため fn の中では使わないこと）
+func (r *bookRepository) Transaction(fn func(repo BookRepository) error) error {
+	if _, ok := r.ex.(*sql.Tx); ok {
+		return fmt.Errorf("トランザクションの中で別のトランザクションは開始できません")
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&bookRepository{db: r.db, ex: tx, ownerID: r.ownerID, trash: r.trash}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションの確定に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository_test
+
+import (
+	"path/filepath" // テスト用のデータベースファイルのパス
+	"testing"       // テストの実行と結果の報告
+
+	"book-manager/internal/database"            // データベース接続とマイグレーション
+	"book-manager/internal/repository"          // テスト対象のリポジトリ
+	"book-manager/internal/repository/repotest" // BookRepositoryの適合性テスト
+)
+
+// TestSQLiteBookRepository はSQLiteの実装がBookRepositoryの共通の振る舞いを守っているかを確認する
+// テストごとに一時ディレクトリに新しいデータベースを作る
+func TestSQLiteBookRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.BookRepository {
+		return repository.NewBookRepository(openSQLite(t))
+	})
+}
+
+// openSQLite はマイグレーション済みの空のSQLiteデータベースを開く（テストの終了時に閉じる）
+func openSQLite(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt" // エラーメッセージの作成
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// BudgetRepository は予算の永続化を担当するインターフェース
+// 予算は所有者と期間の種類（毎月・毎年）ごとに1件だけ保存する
+type BudgetRepository interface {
+	Set(ownerID int, period model.BudgetPeriod, amount int) (*model.Budget, error) // 予算を設定（なければ作成、あれば金額を変更）
+	List(ownerID int) ([]*model.Budget, error)                                     // 予算の一覧（毎月・毎年の順）
+	Delete(ownerID int, period model.BudgetPeriod) error                           // 予算を削除
+}
+
+// budgetRepository はBudgetRepositoryインターフェースの実装
+type budgetRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewBudgetRepository は新しいBudgetRepositoryを作成する関数
+func NewBudgetRepository(db *database.DB) BudgetRepository {
+	return &budgetRepository{db: db}
+}
+
+// budgetSelect は予算を取得するときのSELECT文
+const budgetSelect = "SELECT id, COALESCE(owner_id, 0), period, amount, created_at, updated_at FROM budgets"
+
+// Set は予算を設定する
+// 共有の本棚は owner_id が NULL で一意制約を使えないため、トランザクションの中で更新し、なければ挿入する
+func (r *budgetRepository) Set(ownerID int, period model.BudgetPeriod, amount int) (*model.Budget, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	cond, args := ownerWhere("owner_id", ownerID)
+	result, err := tx.Exec(r.db.Rebind("UPDATE budgets SET amount = ?, updated_at = CURRENT_TIMESTAMP WHERE period = ? AND "+cond),
+		append([]interface{}{amount, period}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("予算の更新に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("更新結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		if _, err := r.db.InsertReturningID(tx, "INSERT INTO budgets (owner_id, period, amount) VALUES (?, ?, ?)",
+			ownerValue(ownerID), period, amount); err != nil {
+			return nil, fmt.Errorf("予算の保存に失敗しました: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("予算の保存に失敗しました: %w", err)
+	}
+
+	budget, err := scanBudget(r.db.QueryRow(r.db.Rebind(budgetSelect+" WHERE period = ? AND "+cond), append([]interface{}{period}, args...)...))
+	if err != nil {
+		return nil, fmt.Errorf("予算の取得に失敗しました: %w", err)
+	}
+	return budget, nil
+}
+
+// List は予算の一覧を毎月・毎年の順に取得する
+func (r *budgetRepository) List(ownerID int) ([]*model.Budget, error) {
+	cond, args := ownerWhere("owner_id", ownerID)
+	rows, err := r.db.Query(r.db.Rebind(budgetSelect+" WHERE "+cond+" ORDER BY period"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("予算一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	budgets := []*model.Budget{}
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("予算データの読み取りに失敗しました: %w", err)
+		}
+		budgets = append(budgets, budget)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("予算一覧の取得に失敗しました: %w", err)
+	}
+	return budgets, nil
+}
+
+// Delete は予算を削除する
+func (r *budgetRepository) Delete(ownerID int, period model.BudgetPeriod) error {
+	cond, args := ownerWhere("owner_id", ownerID)
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM budgets WHERE period = ? AND "+cond), append([]interface{}{period}, args...)...)
+	if err != nil {
+		return fmt.Errorf("予算の削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s の予算は設定されていません", period)
+	}
+	return nil
+}
+
+// scanBudget は1行分の予算を読み取る
+func scanBudget(row rowScanner) (*model.Budget, error) {
+	budget := &model.Budget{}
+	if err := row.Scan(&budget.ID, &budget.OwnerID, &budget.Period, &budget.Amount, &budget.CreatedAt, &budget.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"time"         // レートの日付
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// ExchangeRateRepository は為替レートの永続化を担当するインターフェース
+// 為替レートは全ユーザー共通のデータのため、所有者で分けない
+type ExchangeRateRepository interface {
+	Set(rates ...*model.ExchangeRate) error                                  // レートを登録（同じ通貨・基準通貨・日付のレートは置き換える）
+	List(currency, base string) ([]*model.ExchangeRate, error)               // レートの一覧（currency が空ならすべての通貨、日付の新しい順）
+	Find(currency, base string, date time.Time) (*model.ExchangeRate, error) // date 以前で最新のレート（なければ nil）
+	Delete(id int) error                                                     // レートを削除
+}
+
+// exchangeRateRepository はExchangeRateRepositoryインターフェースの実装
+type exchangeRateRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewExchangeRateRepository は新しいExchangeRateRepositoryを作成する関数
+func NewExchangeRateRepository(db *database.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// exchangeRateSelect はレートを取得するときのSELECT文
+const exchangeRateSelect = "SELECT id, currency, base, rate_date, rate, created_at FROM exchange_rates"
+
+// Set はレートをまとめて登録する
+// CSVファイルの取り込みで途中の行が失敗したときに中途半端にならないよう、1つのトランザクションで保存する
+func (r *exchangeRateRepository) Set(rates ...*model.ExchangeRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := r.db.Rebind(`INSERT INTO exchange_rates (currency, base, rate_date, rate) VALUES (?, ?, ?, ?)
+		ON CONFLICT (currency, base, rate_date) DO UPDATE SET rate = excluded.rate`)
+	for _, rate := range rates {
+		if _, err := tx.Exec(query, rate.Currency, rate.Base, rate.Date, rate.Rate); err != nil {
+			return fmt.Errorf("為替レート（%s %s）の保存に失敗しました: %w", rate.Currency, rate.Date.Format("2006-01-02"), err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("為替レートの保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// List はレートを通貨の順、日付の新しい順に取得する
+func (r *exchangeRateRepository) List(currency, base string) ([]*model.ExchangeRate, error) {
+	query, args := exchangeRateSelect+" WHERE base = ?", []interface{}{base}
+	if currency != "" {
+		query += " AND currency = ?"
+		args = append(args, currency)
+	}
+	rows, err := r.db.Query(r.db.Rebind(query+" ORDER BY currency, rate_date DESC"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("為替レート一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []*model.ExchangeRate{}
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("為替レートデータの読み取りに失敗しました: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("為替レート一覧の取得に失敗しました: %w", err)
+	}
+	return rates, nil
+}
+
+// Find は date 以前で最新のレートを取得する（登録されていなければ nil を返す）
+// 統計のSQL（convertedPrice）と同じ latestRate でレートを選ぶため、Goでの換算とSQLでの集計の結果がそろう
+func (r *exchangeRateRepository) Find(currency, base string, date time.Time) (*model.ExchangeRate, error) {
+	rate, err := scanExchangeRate(r.db.QueryRow(r.db.Rebind(exchangeRateSelect+
+		" WHERE id = "+latestRate(r.db, "id", "?", "?", "?")), currency, base, epochDay(date)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("為替レートの取得に失敗しました: %w", err)
+	}
+	return rate, nil
+}
+
+// Delete はレートを削除する
+func (r *exchangeRateRepository) Delete(id int) error {
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM exchange_rates WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("為替レートの削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ID %d の為替レートが見つかりません", id)
+	}
+	return nil
+}
+
+// scanExchangeRate は1行分のレートを読み取る
+func scanExchangeRate(row rowScanner) (*model.ExchangeRate, error) {
+	rate := &model.ExchangeRate{}
+	if err := row.Scan(&rate.ID, &rate.Currency, &rate.Base, &rate.Date, &rate.Rate, &rate.CreatedAt); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// latestRate は通貨 code・基準通貨 base で、日付 day 以前の最新のレートの column を取り出すサブクエリを返す関数
+// code・base・day にはSQLの式（プレースホルダーや列名）を渡す。day はUTCの1970年1月1日からの日数
+// 日付はUTCの日単位で比べる（同じ日のレートは購入時刻によらず使う）
+func latestRate(db *database.DB, column, code, base, day string) string {
+	return "(SELECT er." + column + " FROM exchange_rates er WHERE er.currency = " + code + " AND er.base = " + base +
+		" AND " + db.EpochDay("er.rate_date") + " <= " + day + " ORDER BY er.rate_date DESC LIMIT 1)"
+}
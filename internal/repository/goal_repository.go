@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// GoalRepository は読書目標の永続化を担当するインターフェース
+// 他のユーザーの目標は「見つからない」として扱うため、取得・削除には所有者のIDを渡す
+type GoalRepository interface {
+	Create(goal *model.ReadingGoal) (*model.ReadingGoal, error) // 目標を作成
+	GetByID(ownerID, id int) (*model.ReadingGoal, error)        // IDで目標を取得
+	List(ownerID int) ([]*model.ReadingGoal, error)             // 目標を期間の新しい順に取得
+	Delete(ownerID, id int) error                               // 目標を削除
+}
+
+// goalRepository はGoalRepositoryインターフェースの実装
+type goalRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewGoalRepository は新しいGoalRepositoryを作成する関数
+func NewGoalRepository(db *database.DB) GoalRepository {
+	return &goalRepository{db: db}
+}
+
+// goalSelect は目標を取得するときのSELECT文
+const goalSelect = `SELECT id, COALESCE(owner_id, 0), title, metric, target, period, start_date, end_date, tag, created_at
+	FROM reading_goals`
+
+// Create は目標を作成する
+func (r *goalRepository) Create(goal *model.ReadingGoal) (*model.ReadingGoal, error) {
+	id, err := r.db.InsertReturningID(r.db,
+		`INSERT INTO reading_goals (owner_id, title, metric, target, period, start_date, end_date, tag)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		ownerValue(goal.OwnerID), goal.Title, goal.Metric, goal.Target, goal.Period, goal.StartDate, goal.EndDate, goal.Tag,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("読書目標の保存に失敗しました: %w", err)
+	}
+	return r.GetByID(goal.OwnerID, int(id))
+}
+
+// GetByID はIDで目標を取得する
+func (r *goalRepository) GetByID(ownerID, id int) (*model.ReadingGoal, error) {
+	cond, args := ownerWhere("owner_id", ownerID)
+	goal, err := scanGoal(r.db.QueryRow(r.db.Rebind(goalSelect+" WHERE id = ? AND "+cond), append([]interface{}{id}, args...)...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ID %d の読書目標が見つかりません", id)
+		}
+		return nil, fmt.Errorf("読書目標の取得に失敗しました: %w", err)
+	}
+	return goal, nil
+}
+
+// List は目標を期間の新しい順（同じ開始日なら新しく作成した順）に取得する
+func (r *goalRepository) List(ownerID int) ([]*model.ReadingGoal, error) {
+	cond, args := ownerWhere("owner_id", ownerID)
+	rows, err := r.db.Query(r.db.Rebind(goalSelect+" WHERE "+cond+" ORDER BY start_date DESC, id DESC"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("読書目標一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	goals := []*model.ReadingGoal{}
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("読書目標データの読み取りに失敗しました: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("読書目標一覧の取得に失敗しました: %w", err)
+	}
+	return goals, nil
+}
+
+// Delete は目標を削除する
+func (r *goalRepository) Delete(ownerID, id int) error {
+	cond, args := ownerWhere("owner_id", ownerID)
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM reading_goals WHERE id = ? AND "+cond), append([]interface{}{id}, args...)...)
+	if err != nil {
+		return fmt.Errorf("読書目標の削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ID %d の読書目標が見つかりません", id)
+	}
+	return nil
+}
+
+// scanGoal は1行分の目標を読み取る
+func scanGoal(row rowScanner) (*model.ReadingGoal, error) {
+	goal := &model.ReadingGoal{}
+	err := row.Scan(&goal.ID, &goal.OwnerID, &goal.Title, &goal.Metric, &goal.Target, &goal.Period,
+		&goal.StartDate, &goal.EndDate, &goal.Tag, &goal.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
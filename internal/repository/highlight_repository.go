@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"strings"      // 検索語の分割
+	"time"         // 更新日時
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// HighlightRepository はハイライト・引用の永続化を担当するインターフェース
+type HighlightRepository interface {
+	Create(highlight *model.Highlight) (*model.Highlight, error)                              // ハイライトを登録
+	GetByID(id int) (*model.Highlight, error)                                                 // IDでハイライトを1件取得
+	ListByBook(bookID int) ([]*model.Highlight, error)                                        // 書籍のハイライトをページ順に取得
+	Update(highlight *model.Highlight) (*model.Highlight, error)                              // ハイライトを更新
+	Delete(id int) error                                                                      // ハイライトを削除
+	Search(filter *model.HighlightFilter, offset, limit int) ([]*model.Highlight, int, error) // 本棚のハイライトを検索（新しい順、総件数付き）
+	SourceKeys(bookID int) (map[string]bool, error)                                           // 書籍の取り込み済みハイライトの取り込み元でのキー
+}
+
+// highlightRepository はHighlightRepositoryインターフェースの実装
+type highlightRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewHighlightRepository は新しいHighlightRepositoryを作成する関数
+func NewHighlightRepository(db *database.DB) HighlightRepository {
+	return &highlightRepository{db: db}
+}
+
+// highlightSelect はハイライトを取得するときのSELECT文（書籍のタイトルも一緒に取得する）
+const highlightSelect = `SELECT h.id, h.book_id, b.title, h.kind, h.text, h.note, h.page, h.location, h.chapter, h.tags, h.source_key, h.created_at, h.updated_at
+	FROM highlights h
+	JOIN books b ON b.id = h.book_id`
+
+// highlightOrder は書籍の中でのハイライトの並び順（ページ順。ページのないものは最後に登録順で並べる）
+const highlightOrder = " ORDER BY CASE WHEN h.page IS NULL THEN 1 ELSE 0 END, h.page, h.created_at, h.id"
+
+// Create はハイライトを登録する
+func (r *highlightRepository) Create(highlight *model.Highlight) (*model.Highlight, error) {
+	id, err := r.db.InsertReturningID(r.db,
+		`INSERT INTO highlights (book_id, kind, text, note, page, location, chapter, tags, source_key, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		highlight.BookID, highlight.Kind, highlight.Text, highlight.Note, highlight.Page, highlight.Location,
+		highlight.Chapter, highlight.Tags, highlight.SourceKey, highlight.CreatedAt, time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ハイライトの保存に失敗しました: %w", err)
+	}
+	return r.GetByID(int(id))
+}
+
+// GetByID はIDでハイライトを1件取得する
+func (r *highlightRepository) GetByID(id int) (*model.Highlight, error) {
+	highlight, err := scanHighlight(r.db.QueryRow(r.db.Rebind(highlightSelect+" WHERE h.id = ?"), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ID %d のハイライトが見つかりません", id)
+		}
+		return nil, fmt.Errorf("ハイライトの取得に失敗しました: %w", err)
+	}
+	return highlight, nil
+}
+
+// ListByBook は書籍のハイライトをページ順に取得する
+func (r *highlightRepository) ListByBook(bookID int) ([]*model.Highlight, error) {
+	return r.query(highlightSelect+" WHERE h.book_id = ?"+highlightOrder, bookID)
+}
+
+// Update はハイライトの内容を更新する（書籍と作成日時は変えない）
+func (r *highlightRepository) Update(highlight *model.Highlight) (*model.Highlight, error) {
+	result, err := r.db.Exec(r.db.Rebind(`UPDATE highlights SET kind = ?, text = ?, note = ?, page = ?, location = ?,
+		chapter = ?, tags = ?, updated_at = ? WHERE id = ?`),
+		highlight.Kind, highlight.Text, highlight.Note, highlight.Page, highlight.Location,
+		highlight.Chapter, highlight.Tags, time.Now(), highlight.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ハイライトの更新に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("更新結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("ID %d のハイライトが見つかりません", highlight.ID)
+	}
+	return r.GetByID(highlight.ID)
+}
+
+// Delete はハイライトを削除する
+func (r *highlightRepository) Delete(id int) error {
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM highlights WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("ハイライトの削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ID %d のハイライトが見つかりません", id)
+	}
+	return nil
+}
+
+// Search は本棚のハイライトを検索する
+// 検索語は空白で区切り、すべての語が本文・メモ・章・タグ・書籍のタイトルのどれかに含まれるものを探す（大文字と小文字は区別しない）
+// 結果は新しい順に並べ、offset から limit 件と、条件に一致する総件数を返す
+func (r *highlightRepository) Search(filter *model.HighlightFilter, offset, limit int) ([]*model.Highlight, int, error) {
+	cond, args := ownerWhere("b.owner_id", filter.OwnerID)
+	cond += " AND b.deleted_at IS NULL" // ゴミ箱の書籍のハイライトは検索しない
+	if filter.BookID > 0 {
+		cond += " AND h.book_id = ?"
+		args = append(args, filter.BookID)
+	}
+	if filter.Kind != "" {
+		cond += " AND h.kind = ?"
+		args = append(args, filter.Kind)
+	}
+	if filter.Tag != "" {
+		// タグは保存時にカンマ区切り（前後の空白なし）にそろえるため、前後にカンマを付けて完全一致で探す
+		cond += " AND ',' || h.tags || ',' " + r.db.Like() + " ?" + database.LikeEscape
+		args = append(args, "%,"+database.EscapeLike(filter.Tag)+",%")
+	}
+	// 検索語の % と _ はワイルドカードではなく、そのままの文字として探す
+	like := r.db.Like()
+	match := like + " ?" + database.LikeEscape
+	for _, term := range strings.Fields(filter.Query) {
+		cond += " AND (h.text " + match + " OR h.note " + match + " OR h.chapter " + match + " OR h.tags " + match + " OR b.title " + match + ")"
+		pattern := "%" + database.EscapeLike(term) + "%"
+		args = append(args, pattern, pattern, pattern, pattern, pattern)
+	}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM highlights h JOIN books b ON b.id = h.book_id WHERE " + cond
+	if err := r.db.QueryRow(r.db.Rebind(countQuery), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("ハイライトの件数の取得に失敗しました: %w", err)
+	}
+	highlights, err := r.query(highlightSelect+" WHERE "+cond+" ORDER BY h.created_at DESC, h.id DESC LIMIT ? OFFSET ?",
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return highlights, total, nil
+}
+
+// SourceKeys は書籍の取り込み済みハイライトの取り込み元でのキーを取得する（手入力のハイライトは含まない）
+func (r *highlightRepository) SourceKeys(bookID int) (map[string]bool, error) {
+	rows, err := r.db.Query(r.db.Rebind("SELECT source_key FROM highlights WHERE book_id = ? AND source_key <> ''"), bookID)
+	if err != nil {
+		return nil, fmt.Errorf("取り込み済みハイライトの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	keys := map[string]bool{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("取り込み済みハイライトの読み取りに失敗しました: %w", err)
+		}
+		keys[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("取り込み済みハイライトの取得に失敗しました: %w", err)
+	}
+	return keys, nil
+}
+
+// query は条件に一致するハイライトを取得する
+func (r *highlightRepository) query(query string, args ...interface{}) ([]*model.Highlight, error) {
+	rows, err := r.db.Query(r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("ハイライトの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	highlights := []*model.Highlight{}
+	for rows.Next() {
+		highlight, err := scanHighlight(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ハイライトデータの読み取りに失敗しました: %w", err)
+		}
+		highlights = append(highlights, highlight)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ハイライトの取得に失敗しました: %w", err)
+	}
+	return highlights, nil
+}
+
+// scanHighlight は1行分のハイライトを読み取る
+func scanHighlight(row rowScanner) (*model.Highlight, error) {
+	highlight := &model.Highlight{}
+	err := row.Scan(&highlight.ID, &highlight.BookID, &highlight.BookTitle, &highlight.Kind, &highlight.Text, &highlight.Note,
+		&highlight.Page, &highlight.Location, &highlight.Chapter, &highlight.Tags, &highlight.SourceKey, &highlight.CreatedAt, &highlight.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return highlight, nil
+}
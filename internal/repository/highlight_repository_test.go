@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"testing" // テストの実行と結果の報告
+	"time"    // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // テスト対象のリポジトリ
+)
+
+// TestHighlightRepositorySearch は検索語とタグの % と _ と \ が、ワイルドカードではなくそのままの文字として探されることを確認する
+func TestHighlightRepositorySearch(t *testing.T) {
+	t.Run("SQLite", func(t *testing.T) { testHighlightSearch(t, openSQLite(t)) })
+	t.Run("Postgres", func(t *testing.T) { testHighlightSearch(t, openPostgres(t)) })
+}
+
+func testHighlightSearch(t *testing.T, db *database.DB) {
+	book, err := repository.NewBookRepository(db).Create(&model.CreateBookRequest{Title: "検索の本", Author: "著者", PurchaseDate: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	highlights := repository.NewHighlightRepository(db)
+	for _, h := range []*model.Highlight{
+		{Text: "達成率は100%だった", Tags: "数字"},
+		{Text: "達成率は1000だった", Tags: "数字"},
+		{Text: "snake_case で書く", Tags: "a_b"},
+		{Text: "snakeXcase で書く", Tags: "axb"},
+		{Text: `C:\path に保存する`, Tags: "パス"},
+	} {
+		h.BookID, h.Kind = book.ID, model.KindHighlight
+		if _, err := highlights.Create(h); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter model.HighlightFilter
+		want   int
+	}{
+		{"%はそのままの文字", model.HighlightFilter{Query: "100%"}, 1},
+		{"%だけでもすべてには一致しない", model.HighlightFilter{Query: "%"}, 1},
+		{"_はそのままの文字", model.HighlightFilter{Query: "snake_case"}, 1},
+		{`\はそのままの文字`, model.HighlightFilter{Query: `C:\path`}, 1},
+		{"大文字・小文字は区別しない", model.HighlightFilter{Query: "SNAKE"}, 2},
+		{"タグの_はそのままの文字", model.HighlightFilter{Tag: "a_b"}, 1},
+		{"ワイルドカードのない検索語", model.HighlightFilter{Query: "達成率"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, total, err := highlights.Search(&tt.filter, 0, 10)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if total != tt.want {
+				t.Errorf("Search(%+v) の件数 = %d, want %d", tt.filter, total, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"time"         // 返却日時・返却期限
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// LoanRepository は書籍の貸し出しの永続化を担当するインターフェース
+type LoanRepository interface {
+	Create(loan *model.Loan) (*model.Loan, error)                        // 貸し出しを記録
+	GetActive(bookID int) (*model.Loan, error)                           // 書籍の現在の貸し出し（未返却）を取得
+	MarkReturned(id int, returnedAt time.Time) (*model.Loan, error)      // 返却を記録
+	ListByBook(bookID int) ([]*model.Loan, error)                        // 書籍の貸し出し履歴を新しい順に取得
+	ListActive(ownerID int, dueBefore *time.Time) ([]*model.Loan, error) // 本棚の貸し出し中の一覧（dueBeforeを指定すると期限切れのみ）
+}
+
+// loanRepository はLoanRepositoryインターフェースの実装
+type loanRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewLoanRepository は新しいLoanRepositoryを作成する関数
+func NewLoanRepository(db *database.DB) LoanRepository {
+	return &loanRepository{db: db}
+}
+
+// loanSelect は貸し出しを取得するときのSELECT文（書籍のタイトルも一緒に取得する）
+const loanSelect = `SELECT l.id, l.book_id, b.title, l.borrower_name, COALESCE(l.borrower_id, 0), l.lent_at, l.due_at, l.returned_at, l.notes
+	FROM loans l
+	JOIN books b ON b.id = l.book_id`
+
+// Create は貸し出しを記録する
+// 同じ書籍の未返却の貸し出しは1件だけ（部分ユニークインデックスで保証）のため、貸し出し中の書籍ではエラーになる
+func (r *loanRepository) Create(loan *model.Loan) (*model.Loan, error) {
+	id, err := r.db.InsertReturningID(r.db,
+		"INSERT INTO loans (book_id, borrower_name, borrower_id, lent_at, due_at, notes) VALUES (?, ?, ?, ?, ?, ?)",
+		loan.BookID, loan.BorrowerName, ownerValue(loan.BorrowerID), loan.LentAt, loan.DueAt, loan.Notes,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("貸し出しの保存に失敗しました: %w", err)
+	}
+	return r.get(" WHERE l.id = ?", id)
+}
+
+// GetActive は書籍の現在の貸し出しを取得する
+func (r *loanRepository) GetActive(bookID int) (*model.Loan, error) {
+	return r.get(" WHERE l.book_id = ? AND l.returned_at IS NULL", bookID)
+}
+
+// MarkReturned は貸し出しの返却日時を記録する
+func (r *loanRepository) MarkReturned(id int, returnedAt time.Time) (*model.Loan, error) {
+	result, err := r.db.Exec(r.db.Rebind("UPDATE loans SET returned_at = ? WHERE id = ? AND returned_at IS NULL"), returnedAt, id)
+	if err != nil {
+		return nil, fmt.Errorf("返却の記録に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("更新結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("ID %d の貸し出し中の記録が見つかりません", id)
+	}
+	return r.get(" WHERE l.id = ?", id)
+}
+
+// ListByBook は書籍の貸し出し履歴を新しい順に取得する
+func (r *loanRepository) ListByBook(bookID int) ([]*model.Loan, error) {
+	return r.query(" WHERE l.book_id = ? ORDER BY l.lent_at DESC, l.id DESC", bookID)
+}
+
+// ListActive は本棚の貸し出し中の一覧を返却期限の近い順に取得する
+// dueBefore を指定すると、その日時より前に返却期限を過ぎた貸し出し（期限切れ）だけを返す
+func (r *loanRepository) ListActive(ownerID int, dueBefore *time.Time) ([]*model.Loan, error) {
+	condition := " WHERE l.returned_at IS NULL AND b.deleted_at IS NULL" // ゴミ箱の書籍は除く
+	args := []interface{}{}
+	if ownerID > 0 {
+		condition += " AND b.owner_id = ?"
+		args = append(args, ownerID)
+	} else {
+		condition += " AND b.owner_id IS NULL" // 所有者なしの共有の本棚
+	}
+	if dueBefore != nil {
+		condition += " AND l.due_at IS NOT NULL AND l.due_at < ?"
+		args = append(args, *dueBefore)
+	}
+	// 期限のない貸し出しは最後に並べる（NULLの並び順はデータベースによって異なるため明示する）
+	return r.query(condition+" ORDER BY CASE WHEN l.due_at IS NULL THEN 1 ELSE 0 END, l.due_at, l.id", args...)
+}
+
+// get は条件に一致する貸し出しを1件取得する
+func (r *loanRepository) get(condition string, args ...interface{}) (*model.Loan, error) {
+	loan, err := scanLoan(r.db.QueryRow(r.db.Rebind(loanSelect+condition), args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("貸し出しの記録が見つかりません")
+		}
+		return nil, fmt.Errorf("貸し出しの取得に失敗しました: %w", err)
+	}
+	return loan, nil
+}
+
+// query は条件に一致する貸し出しを取得する
+func (r *loanRepository) query(condition string, args ...interface{}) ([]*model.Loan, error) {
+	rows, err := r.db.Query(r.db.Rebind(loanSelect+condition), args...)
+	if err != nil {
+		return nil, fmt.Errorf("貸し出しの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	loans := []*model.Loan{}
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("貸し出しデータの読み取りに失敗しました: %w", err)
+		}
+		loans = append(loans, loan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("貸し出しの取得に失敗しました: %w", err)
+	}
+	return loans, nil
+}
+
+// scanLoan は1行分の貸し出しを読み取り、期限切れかどうかを計算する
+func scanLoan(row rowScanner) (*model.Loan, error) {
+	loan := &model.Loan{}
+	err := row.Scan(&loan.ID, &loan.BookID, &loan.BookTitle, &loan.BorrowerName, &loan.BorrowerID,
+		&loan.LentAt, &loan.DueAt, &loan.ReturnedAt, &loan.Notes)
+	if err != nil {
+		return nil, err
+	}
+	loan.Overdue = loan.IsOverdue(time.Now())
+	return loan, nil
+}
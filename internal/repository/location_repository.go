@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"strings"      // IN句のプレースホルダーの組み立て
+	"time"         // 書籍の更新日時
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// LocationRepository は書籍の保管場所の永続化を担当するインターフェース
+type LocationRepository interface {
+	Create(location *model.Location) (*model.Location, error) // 場所を作成
+	GetByID(id int) (*model.Location, error)                  // IDで場所を取得
+	ListByOwner(ownerID int) ([]*model.Location, error)       // 本棚の場所をすべて取得（直接置かれている書籍の数付き）
+	Update(location *model.Location) (*model.Location, error) // 場所の親・種類・名前を更新
+	Delete(id int) error                                      // 場所を削除
+	CountAllBooks(id int) (int, error)                        // 場所に直接置かれている書籍の数（ゴミ箱の書籍も含む）
+	MoveBooks(bookIDs []int, locationID *int) (int, error)    // 書籍をまとめて別の場所に移動（nilは場所の設定を外す）
+}
+
+// locationRepository はLocationRepositoryインターフェースの実装
+type locationRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewLocationRepository は新しいLocationRepositoryを作成する関数
+func NewLocationRepository(db *database.DB) LocationRepository {
+	return &locationRepository{db: db}
+}
+
+// locationSelect は場所を取得するときのSELECT文（直接置かれている書籍の数も一緒に取得する）
+const locationSelect = `SELECT l.id, COALESCE(l.owner_id, 0), l.parent_id, l.kind, l.name, l.created_at,
+		(SELECT COUNT(*) FROM books WHERE books.location_id = l.id AND books.deleted_at IS NULL)
+	FROM locations l`
+
+// Create は場所を作成する
+func (r *locationRepository) Create(location *model.Location) (*model.Location, error) {
+	id, err := r.db.InsertReturningID(r.db,
+		"INSERT INTO locations (owner_id, parent_id, kind, name) VALUES (?, ?, ?, ?)",
+		ownerValue(location.OwnerID), location.ParentID, location.Kind, location.Name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("場所の保存に失敗しました: %w", err)
+	}
+	return r.GetByID(int(id))
+}
+
+// GetByID はIDで場所を取得する
+func (r *locationRepository) GetByID(id int) (*model.Location, error) {
+	location, err := scanLocation(r.db.QueryRow(r.db.Rebind(locationSelect+" WHERE l.id = ?"), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ID %d の場所が見つかりません", id)
+		}
+		return nil, fmt.Errorf("場所の取得に失敗しました: %w", err)
+	}
+	return location, nil
+}
+
+// ListByOwner は本棚の場所をすべて取得する
+// 階層の組み立てや道のりの計算はユースケース層で行う
+func (r *locationRepository) ListByOwner(ownerID int) ([]*model.Location, error) {
+	query := locationSelect + " WHERE l.owner_id IS NULL ORDER BY l.id"
+	args := []interface{}{}
+	if ownerID > 0 {
+		query = locationSelect + " WHERE l.owner_id = ? ORDER BY l.id"
+		args = append(args, ownerID)
+	}
+
+	rows, err := r.db.Query(r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("場所一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	locations := []*model.Location{}
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("場所データの読み取りに失敗しました: %w", err)
+		}
+		locations = append(locations, location)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("場所一覧の取得に失敗しました: %w", err)
+	}
+	return locations, nil
+}
+
+// Update は場所の親・種類・名前を更新する
+func (r *locationRepository) Update(location *model.Location) (*model.Location, error) {
+	_, err := r.db.Exec(r.db.Rebind("UPDATE locations SET parent_id = ?, kind = ?, name = ? WHERE id = ?"),
+		location.ParentID, location.Kind, location.Name, location.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("場所の更新に失敗しました: %w", err)
+	}
+	return r.GetByID(location.ID)
+}
+
+// Delete は場所を削除する
+// 下の場所や置かれている書籍の確認はユースケース層で行う
+func (r *locationRepository) Delete(id int) error {
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM locations WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("場所の削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ID %d の場所が見つかりません", id)
+	}
+	return nil
+}
+
+// CountAllBooks は場所に直接置かれている書籍の数を、ゴミ箱の書籍も含めて数える
+// 一覧の書籍の数（BookCount）はゴミ箱の書籍を含めないため、場所を削除してよいかの確認にはこちらを使う
+func (r *locationRepository) CountAllBooks(id int) (int, error) {
+	var count int
+	if err := r.db.QueryRow(r.db.Rebind("SELECT COUNT(*) FROM books WHERE location_id = ?"), id).Scan(&count); err != nil {
+		return 0, fmt.Errorf("場所の書籍の数の取得に失敗しました: %w", err)
+	}
+	return count, nil
+}
+
+// MoveBooks は書籍をまとめて別の場所に移動し、移動した冊数を返す
+// 1つのUPDATE文で更新するため、途中で失敗しても一部の書籍だけが移動することはない
+func (r *locationRepository) MoveBooks(bookIDs []int, locationID *int) (int, error) {
+	if len(bookIDs) == 0 {
+		return 0, nil
+	}
+	placeholders := make([]string, len(bookIDs))
+	args := []interface{}{locationID, time.Now()}
+	for i, id := range bookIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	result, err := r.db.Exec(r.db.Rebind("UPDATE books SET location_id = ?, updated_at = ? WHERE id IN ("+strings.Join(placeholders, ", ")+")"), args...)
+	if err != nil {
+		return 0, fmt.Errorf("書籍の移動に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("更新結果の確認に失敗しました: %w", err)
+	}
+	return int(n), nil
+}
+
+// scanLocation は1行分の場所を読み取る
+func scanLocation(row rowScanner) (*model.Location, error) {
+	location := &model.Location{}
+	err := row.Scan(&location.ID, &location.OwnerID, &location.ParentID, &location.Kind, &location.Name, &location.CreatedAt, &location.BookCount)
+	if err != nil {
+		return nil, err
+	}
+	return location, nil
+}
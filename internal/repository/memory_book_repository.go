@@ -0,0 +1,528 @@
+package repository
+
+import (
+	"fmt"     // エラーメッセージの作成
+	"sort"    // 並び替え
+	"strings" // 文字列の部分一致
+	"sync"    // 同時アクセスからの保護
+	"time"    // 作成日時・更新日時
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// memoryBookRepository はBookRepositoryインターフェースのメモリ上の実装
+// データベースを使わないため、cgo（SQLiteドライバー）なしで動き、テストやデモに使える
+// プログラムを終了するとデータは消える
+type memoryBookRepository struct {
+	store   *memoryBookStore // 書籍の保存場所（WithOwnerで作ったリポジトリ同士で共有する）
+	ownerID int              // 扱う本棚の所有者（AllOwnersなら絞り込まない）
+}
+
+// memoryBookStore はメモリ上の書籍の保存場所（データベースのbooksテーブルに当たる）
+type memoryBookStore struct {
+	mu     sync.RWMutex        // 複数のリクエストから同時にアクセスされても壊れないようにするロック
+	books  map[int]*model.Book // ID → 書籍
+	nextID int                 // 次に採番するID（SQLiteのAUTOINCREMENTと同じく、削除したIDは再利用しない）
+}
+
+// NewMemoryBookRepository はメモリ上に書籍を保存するBookRepositoryを作成する関数
+func NewMemoryBookRepository() BookRepository {
+	store := &memoryBookStore{books: map[int]*model.Book{}, nextID: 1}
+	return &memoryBookRepository{store: store, ownerID: AllOwners}
+}
+
+// WithOwner は指定したユーザーの本棚だけを扱うリポジトリを返す
+func (r *memoryBookRepository) WithOwner(ownerID int) BookRepository {
+	return &memoryBookRepository{store: r.store, ownerID: ownerID}
+}
+
+// owns はこのリポジトリが扱う本棚の書籍かを判定する（SQLite実装の ownerCondition に当たる）
+func (r *memoryBookRepository) owns(book *model.Book) bool {
+	return r.ownerID == AllOwners || book.OwnerID == r.ownerID
+}
+
+// get は扱う本棚の書籍をIDで取得する（ゴミ箱の書籍は除く。ロックを取得済みの状態で呼ぶ）
+func (r *memoryBookRepository) get(id int) (*model.Book, bool) {
+	book, ok := r.store.books[id]
+	if !ok || !r.owns(book) || book.DeletedAt != nil {
+		return nil, false
+	}
+	return book, true
+}
+
+// now はデータベースの CURRENT_TIMESTAMP と同じ精度（秒単位・UTC）の現在時刻を返す
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// Create は新しい書籍を保存する
+func (r *memoryBookRepository) Create(req *model.CreateBookRequest) (*model.Book, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t := now()
+	book := &model.Book{
+		ID:            r.store.nextID,
+		Title:         req.Title,
+		Author:        req.Author,
+		ISBN:          req.ISBN,
+		Publisher:     req.Publisher,
+		PublishedDate: copyTime(req.PublishedDate),
+		PurchaseDate:  req.PurchaseDate,
+		PurchasePrice: req.PurchasePrice,
+		Status:        model.StatusNotStarted, // テーブルの既定値と同じ
+		Notes:         req.Notes,
+		Tags:          req.Tags,
+		PageCount:     copyInt(req.PageCount),
+		Store:           req.Store,
+		PurchaseChannel: req.PurchaseChannel,
+		Format:          req.Format,
+		Currency:        currencyOrDefault(req.Currency),
+		CreatedAt:     t,
+		UpdatedAt:     t,
+	}
+	if r.ownerID > 0 {
+		book.OwnerID = r.ownerID
+	}
+	r.store.books[book.ID] = book
+	r.store.nextID++
+	return copyBook(book), nil
+}
+
+// GetByID は指定されたIDの書籍を1件取得する
+func (r *memoryBookRepository) GetByID(id int) (*model.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	book, ok := r.get(id)
+	if !ok {
+		return nil, fmt.Errorf("ID %d の書籍が見つかりません", id)
+	}
+	return copyBook(book), nil
+}
+
+// List はフィルター条件に一致する書籍を作成日時の新しい順に取得する
+// limitが0以下なら件数制限なし、offsetはlimitを指定した場合だけ使う（SQLite実装と同じ）
+func (r *memoryBookRepository) List(filter *model.BookFilter, limit, offset int) ([]*model.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	books := r.filter(filter)
+	// ORDER BY created_at DESC, id DESC と同じ並び
+	sort.Slice(books, func(i, j int) bool {
+		if !books[i].CreatedAt.Equal(books[j].CreatedAt) {
+			return books[i].CreatedAt.After(books[j].CreatedAt)
+		}
+		return books[i].ID > books[j].ID
+	})
+
+	if limit > 0 {
+		if offset > len(books) {
+			offset = len(books)
+		}
+		if offset < 0 {
+			offset = 0
+		}
+		books = books[offset:]
+		if limit < len(books) {
+			books = books[:limit]
+		}
+	}
+
+	result := make([]*model.Book, 0, len(books))
+	for _, book := range books {
+		result = append(result, copyBook(book))
+	}
+	return result, nil
+}
+
+// Update は指定された項目だけを更新する
+// ステータスに応じた読書開始日・終了日の自動設定もSQLite実装と同じように行う
+func (r *memoryBookRepository) Update(id int, req *model.UpdateBookRequest) (*model.Book, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.get(id)
+	if !ok {
+		return nil, fmt.Errorf("ID %d の書籍が見つかりません", id)
+	}
+	book := copyBook(stored) // 途中で失敗しても保存済みのデータを変えないようにコピーを更新する
+	changed := false
+
+	if req.Title != nil {
+		book.Title, changed = *req.Title, true
+	}
+	if req.Author != nil {
+		book.Author, changed = *req.Author, true
+	}
+	if req.ISBN != nil {
+		book.ISBN, changed = *req.ISBN, true
+	}
+	if req.Publisher != nil {
+		book.Publisher, changed = *req.Publisher, true
+	}
+	if req.PublishedDate != nil {
+		book.PublishedDate, changed = copyTime(req.PublishedDate), true
+	}
+	if req.PurchasePrice != nil {
+		book.PurchasePrice, changed = *req.PurchasePrice, true
+	}
+	if req.Status != nil {
+		book.Status, changed = *req.Status, true
+
+		t := time.Now()
+		if *req.Status == model.StatusReading && req.StartReadDate == nil {
+			book.StartReadDate = &t
+		}
+		if (*req.Status == model.StatusCompleted || *req.Status == model.StatusDropped) && req.EndReadDate == nil {
+			book.EndReadDate = &t
+		}
+	}
+	if req.StartReadDate != nil {
+		book.StartReadDate, changed = copyTime(req.StartReadDate), true
+	}
+	if req.EndReadDate != nil {
+		book.EndReadDate, changed = copyTime(req.EndReadDate), true
+	}
+	if req.Rating != nil {
+		rating := *req.Rating
+		book.Rating, changed = &rating, true
+	}
+	if req.Notes != nil {
+		book.Notes, changed = *req.Notes, true
+	}
+	if req.Tags != nil {
+		book.Tags, changed = *req.Tags, true
+	}
+	if req.PageCount != nil {
+		book.PageCount, changed = copyInt(req.PageCount), true
+	}
+	if req.Store != nil {
+		book.Store, changed = *req.Store, true
+	}
+	if req.PurchaseChannel != nil {
+		book.PurchaseChannel, changed = *req.PurchaseChannel, true
+	}
+	if req.Format != nil {
+		book.Format, changed = *req.Format, true
+	}
+	if req.Currency != nil {
+		book.Currency, changed = currencyOrDefault(*req.Currency), true
+	}
+
+	// 更新する項目がない場合は何も変えない（SQLite実装と同じく更新日時も変わらない）
+	if !changed {
+		return copyBook(stored), nil
+	}
+
+	// テーブルのCHECK制約と同じ検証
+	if err := validateBook(book); err != nil {
+		return nil, fmt.Errorf("書籍の更新に失敗しました: %w", err)
+	}
+
+	book.UpdatedAt = now() // 更新日時のトリガーと同じ
+	r.store.books[id] = book
+	return copyBook(book), nil
+}
+
+// Replace は書籍の項目を book の値にそろえる（ID・所有者・作成日時・保管場所は変えない）
+func (r *memoryBookRepository) Replace(id int, book *model.Book) (*model.Book, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.get(id)
+	if !ok {
+		return nil, fmt.Errorf("ID %d の書籍が見つかりません", id)
+	}
+	replaced := copyBook(book)
+	replaced.ID, replaced.OwnerID, replaced.CreatedAt = current.ID, current.OwnerID, current.CreatedAt
+	replaced.LocationID, replaced.DeletedAt, replaced.Loan = current.LocationID, nil, nil
+	replaced.Currency = currencyOrDefault(replaced.Currency)
+	replaced.UpdatedAt = now()
+	if err := validateBook(replaced); err != nil {
+		return nil, fmt.Errorf("書籍の更新に失敗しました: %w", err)
+	}
+	r.store.books[id] = replaced
+	return copyBook(replaced), nil
+}
+
+// Delete は書籍をゴミ箱に移す
+// SQLite実装（UPDATE文と更新日時のトリガー）と同じく、更新日時も現在時刻になる
+func (r *memoryBookRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	book, ok := r.get(id)
+	if !ok {
+		return fmt.Errorf("ID %d の書籍が見つかりません", id)
+	}
+	t := now()
+	book.DeletedAt = &t
+	book.UpdatedAt = t
+	return nil
+}
+
+// Count はフィルター条件に一致する書籍数を返す
+func (r *memoryBookRepository) Count(filter *model.BookFilter) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return len(r.filter(filter)), nil
+}
+
+// Import は書籍を全項目（作成日時・更新日時を含む）そのまま保存する
+// keepID：trueなら book.ID をそのまま使う（同じIDの書籍があれば置き換える）、falseなら新しいIDを採番する
+func (r *memoryBookRepository) Import(book *model.Book, keepID bool) (*model.Book, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	imported := copyBook(book)
+	imported.Currency = currencyOrDefault(imported.Currency)
+	if err := validateBook(imported); err != nil {
+		return nil, fmt.Errorf("書籍の取り込みに失敗しました: %w", err)
+	}
+	// 本棚を限定したリポジトリならその本棚に、限定していなければ書籍の所有者のまま取り込む
+	if r.ownerID != AllOwners {
+		imported.OwnerID = r.ownerID
+	}
+	if !keepID {
+		imported.ID = r.store.nextID
+	}
+	if imported.ID <= 0 {
+		return nil, fmt.Errorf("書籍の取り込みに失敗しました: IDが不正です（%d）", imported.ID)
+	}
+	// 他のユーザーの書籍は置き換えない（ゴミ箱の書籍も含めて所有者を確かめる。SQLite実装と同じ）
+	if existing, ok := r.store.books[imported.ID]; ok && !r.owns(existing) {
+		return nil, fmt.Errorf("書籍の取り込みに失敗しました: ID %d は他のユーザーの書籍で使われています", imported.ID)
+	}
+
+	r.store.books[imported.ID] = imported
+	// 採番をこれまでの最大IDより後ろに進める
+	if imported.ID >= r.store.nextID {
+		r.store.nextID = imported.ID + 1
+	}
+	return copyBook(imported), nil
+}
+
+// ExistsID はIDの書籍があるか（ゴミ箱の書籍と他のユーザーの書籍も含む）と、それがこの本棚の書籍かを確認する
+func (r *memoryBookRepository) ExistsID(id int) (exists, owned bool, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	book, ok := r.store.books[id]
+	if !ok {
+		return false, false, nil
+	}
+	return true, r.owns(book), nil
+}
+
+// ClaimUnowned は所有者のない書籍をすべて指定したユーザーのものにする
+// SQLite実装（UPDATE文と更新日時のトリガー）と同じく、更新日時も現在時刻になる
+func (r *memoryBookRepository) ClaimUnowned(ownerID int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := 0
+	t := now()
+	for _, book := range r.store.books {
+		if book.OwnerID == 0 {
+			book.OwnerID = ownerID
+			book.UpdatedAt = t
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ListDeleted はゴミ箱の書籍をゴミ箱に移した日時の新しい順に取得する
+func (r *memoryBookRepository) ListDeleted(limit, offset int) ([]*model.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	books := r.deleted()
+	// ORDER BY deleted_at DESC, id DESC と同じ並び
+	sort.Slice(books, func(i, j int) bool {
+		if !books[i].DeletedAt.Equal(*books[j].DeletedAt) {
+			return books[i].DeletedAt.After(*books[j].DeletedAt)
+		}
+		return books[i].ID > books[j].ID
+	})
+	if limit > 0 {
+		offset = min(max(offset, 0), len(books))
+		books = books[offset:min(offset+limit, len(books))]
+	}
+
+	result := make([]*model.Book, 0, len(books))
+	for _, book := range books {
+		result = append(result, copyBook(book))
+	}
+	return result, nil
+}
+
+// CountDeleted はゴミ箱の書籍数を返す
+func (r *memoryBookRepository) CountDeleted() (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return len(r.deleted()), nil
+}
+
+// Restore はゴミ箱の書籍を元に戻す
+func (r *memoryBookRepository) Restore(id int) (*model.Book, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	book, ok := r.store.books[id]
+	if !ok || !r.owns(book) || book.DeletedAt == nil {
+		return nil, fmt.Errorf("ID %d の書籍はゴミ箱にありません", id)
+	}
+	book.DeletedAt = nil
+	book.UpdatedAt = now()
+	return copyBook(book), nil
+}
+
+// Purge はゴミ箱に移した日時が before より前の書籍を完全に削除し、削除した冊数を返す
+func (r *memoryBookRepository) Purge(before time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := 0
+	for _, book := range r.deleted() {
+		if book.DeletedAt.Before(before) {
+			delete(r.store.books, book.ID)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// deleted は扱う本棚のゴミ箱の書籍を返す（ロックを取得済みの状態で呼ぶ）
+func (r *memoryBookRepository) deleted() []*model.Book {
+	books := []*model.Book{}
+	for _, book := range r.store.books {
+		if r.owns(book) && book.DeletedAt != nil {
+			books = append(books, book)
+		}
+	}
+	return books
+}
+
+// SetLocation は書籍の保管場所を設定する
+// 保管場所はデータベースにだけ保存するため、メモリ上の書籍には設定できない
+func (r *memoryBookRepository) SetLocation(id int, locationID *int) (*model.Book, error) {
+	return nil, fmt.Errorf("メモリ上の本棚では保管場所を設定できません")
+}
+
+// Transaction は fn の中の操作を1つのまとまりとして行う
+// 書籍の写しに対して fn を実行し、成功したときだけ写しを保存場所に反映する（失敗したら写しを捨てる）
+// fn の実行中は保存場所のロックを持ち続けるため、他のリクエストの変更が失われることはない
+// （写しは別のロックを持つので、fn の中で渡されたリポジトリを使う限りデッドロックしない）
+func (r *memoryBookRepository) Transaction(fn func(repo BookRepository) error) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	snapshot := &memoryBookStore{books: make(map[int]*model.Book, len(r.store.books)), nextID: r.store.nextID}
+	for id, book := range r.store.books {
+		snapshot.books[id] = copyBook(book)
+	}
+	if err := fn(&memoryBookRepository{store: snapshot, ownerID: r.ownerID}); err != nil {
+		return err
+	}
+	r.store.books, r.store.nextID = snapshot.books, snapshot.nextID
+	return nil
+}
+
+// filter はフィルター条件に一致する書籍を返す（ロックを取得済みの状態で呼ぶ）
+// 条件の意味はSQLite実装のWHERE句と同じ（タグと検索語は英字の大文字・小文字を区別しない部分一致）
+func (r *memoryBookRepository) filter(filter *model.BookFilter) []*model.Book {
+	books := []*model.Book{}
+	for _, book := range r.store.books {
+		if !r.owns(book) || book.DeletedAt != nil {
+			continue
+		}
+		if filter != nil {
+			if filter.Status != nil && book.Status != *filter.Status {
+				continue
+			}
+			if filter.Author != nil && book.Author != *filter.Author {
+				continue
+			}
+			if filter.Publisher != nil && book.Publisher != *filter.Publisher {
+				continue
+			}
+			if filter.Rating != nil && (book.Rating == nil || *book.Rating != *filter.Rating) {
+				continue
+			}
+			if filter.Tag != nil && !containsFold(book.Tags, *filter.Tag) {
+				continue
+			}
+			if filter.ExactTag != nil && !book.HasTag(*filter.ExactTag) {
+				continue
+			}
+			if filter.Search != nil && !containsFold(book.Title, *filter.Search) && !containsFold(book.Author, *filter.Search) {
+				continue
+			}
+			// 貸し出しはデータベースにだけ保存するため、メモリ上の書籍は常に「貸し出し中でない」
+			if filter.OnLoan != nil && *filter.OnLoan {
+				continue
+			}
+			// 保管場所も同様に、メモリ上の書籍はどの場所にも置かれていない
+			if filter.LocationID != nil {
+				continue
+			}
+		}
+		books = append(books, book)
+	}
+	return books
+}
+
+// validateBook はテーブルのCHECK制約と同じ検証を行う
+func validateBook(book *model.Book) error {
+	switch book.Status {
+	case model.StatusNotStarted, model.StatusReading, model.StatusCompleted, model.StatusDropped:
+	default:
+		return fmt.Errorf("読書ステータスが不正です: %s", book.Status)
+	}
+	if book.Rating != nil && (*book.Rating < 1 || *book.Rating > 5) {
+		return fmt.Errorf("評価は1から5の範囲で指定してください: %d", *book.Rating)
+	}
+	if book.PageCount != nil && *book.PageCount < 1 {
+		return fmt.Errorf("ページ数は1以上で指定してください: %d", *book.PageCount)
+	}
+	return nil
+}
+
+// containsFold は英字の大文字・小文字を区別せずに部分一致を判定する（SQLiteのLIKEと同じ）
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// copyBook は書籍のコピーを作る
+// 呼び出し側が戻り値を書き換えても、保存しているデータが変わらないようにするため
+func copyBook(book *model.Book) *model.Book {
+	c := *book
+	c.PublishedDate = copyTime(book.PublishedDate)
+	c.StartReadDate = copyTime(book.StartReadDate)
+	c.EndReadDate = copyTime(book.EndReadDate)
+	c.Rating = copyInt(book.Rating)
+	c.PageCount = copyInt(book.PageCount)
+	c.LocationID = copyInt(book.LocationID)
+	c.DeletedAt = copyTime(book.DeletedAt)
+	return &c
+}
+
+// copyInt は整数のポインタのコピーを作る
+func copyInt(n *int) *int {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	return &c
+}
+
+// copyTime は日時のポインタのコピーを作る
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
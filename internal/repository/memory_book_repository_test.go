@@ -0,0 +1,62 @@
+package repository_test
+
+import (
+	"sync"    // 同時に実行する操作の待ち合わせ
+	"testing" // テストの実行と結果の報告
+
+	"book-manager/internal/model"               // 自作のデータ構造定義
+	"book-manager/internal/repository"          // テスト対象のリポジトリ
+	"book-manager/internal/repository/repotest" // BookRepositoryの適合性テスト
+)
+
+// TestMemoryBookRepository はメモリ上の実装がBookRepositoryの共通の振る舞いを守っているかを確認する
+func TestMemoryBookRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.BookRepository {
+		return repository.NewMemoryBookRepository()
+	})
+}
+
+// TestMemoryBookRepositoryTransactionConcurrent はトランザクションの実行中に
+// 他の操作で作成した書籍が、トランザクションの確定で失われないことを確認する
+func TestMemoryBookRepositoryTransactionConcurrent(t *testing.T) {
+	repo := repository.NewMemoryBookRepository()
+	book, err := repo.Create(&model.CreateBookRequest{Title: "t", Author: "a"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	const creates = 20
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+	err = repo.Transaction(func(tx repository.BookRepository) error {
+		wg.Add(creates)
+		for i := 0; i < creates; i++ {
+			go func() {
+				defer wg.Done()
+				<-started
+				if _, err := repo.Create(&model.CreateBookRequest{Title: "並行", Author: "a"}); err != nil {
+					t.Errorf("Create: %v", err)
+				}
+			}()
+		}
+		close(started)
+		title := "確定"
+		_, err := tx.Update(book.ID, &model.UpdateBookRequest{Title: &title})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+	wg.Wait()
+
+	total, err := repo.Count(nil)
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if total != creates+1 {
+		t.Errorf("Count = %d, want %d（トランザクション中に作成した書籍が失われています）", total, creates+1)
+	}
+	if got, _ := repo.GetByID(book.ID); got == nil || got.Title != "確定" {
+		t.Errorf("トランザクションの変更が確定していません: %+v", got)
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"fmt" // エラーメッセージの作成
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// MergeRepository は書籍の統合で関連データを移す処理を担当するインターフェース
+type MergeRepository interface {
+	MoveRelated(sourceID, targetID int) (*model.MergedRecords, error) // 統合元の書籍の関連データを統合先に移す
+	Within(books BookRepository) MergeRepository                      // books の Transaction と同じトランザクションで書き込むリポジトリを返す
+}
+
+// mergeRepository はMergeRepositoryインターフェースの実装
+type mergeRepository struct {
+	db *database.DB // データベース接続オブジェクト
+	ex executor     // SQLの実行先（通常は db、Within で作ったリポジトリではトランザクション）
+}
+
+// NewMergeRepository は新しいMergeRepositoryを作成する関数
+func NewMergeRepository(db *database.DB) MergeRepository {
+	return &mergeRepository{db: db, ex: db}
+}
+
+// Within は books（BookRepository の Transaction の中のリポジトリ）と同じトランザクションで書き込むリポジトリを返す
+// 統合先の更新・関連データの移動・統合元の削除をまとめて確定・取り消しするために使う
+func (r *mergeRepository) Within(books BookRepository) MergeRepository {
+	if b, ok := books.(*bookRepository); ok {
+		return &mergeRepository{db: r.db, ex: b.ex}
+	}
+	return r
+}
+
+// MoveRelated は統合元の書籍のハイライト・レビュー・貸し出し・欲しい本を統合先の書籍に移す
+// 途中で失敗して片方だけに残らないよう、1つのトランザクションで移す（Within で作ったリポジトリなら、そのトランザクションで移す）
+// - 両方に同じ取り込み元のハイライト（source_key が同じ）がある場合、統合元のものは削除する
+// - レビューは1冊につき1回の読書に1件のため、統合元のレビューの読書の回は統合先の最後の回の後に続ける
+func (r *mergeRepository) MoveRelated(sourceID, targetID int) (*model.MergedRecords, error) {
+	moved := &model.MergedRecords{}
+	err := inTransaction(r.db, r.ex, "関連データの移動", func(tx executor) error {
+		var err error
+		if moved.SkippedHighlights, err = r.exec(tx, `DELETE FROM highlights WHERE book_id = ? AND source_key <> ''
+			AND source_key IN (SELECT source_key FROM highlights WHERE book_id = ?)`, sourceID, targetID); err != nil {
+			return fmt.Errorf("ハイライトの移動に失敗しました: %w", err)
+		}
+		if moved.Highlights, err = r.exec(tx, "UPDATE highlights SET book_id = ? WHERE book_id = ?", targetID, sourceID); err != nil {
+			return fmt.Errorf("ハイライトの移動に失敗しました: %w", err)
+		}
+
+		var lastReadNumber int
+		if err := tx.QueryRow(r.db.Rebind("SELECT COALESCE(MAX(read_number), 0) FROM reviews WHERE book_id = ?"), targetID).Scan(&lastReadNumber); err != nil {
+			return fmt.Errorf("レビューの移動に失敗しました: %w", err)
+		}
+		if moved.Reviews, err = r.exec(tx, "UPDATE reviews SET book_id = ?, read_number = read_number + ? WHERE book_id = ?",
+			targetID, lastReadNumber, sourceID); err != nil {
+			return fmt.Errorf("レビューの移動に失敗しました: %w", err)
+		}
+
+		// 未返却の貸し出しは1冊に1件まで（両方が貸し出し中ならユースケースで統合を断る）
+		if moved.Loans, err = r.exec(tx, "UPDATE loans SET book_id = ? WHERE book_id = ?", targetID, sourceID); err != nil {
+			return fmt.Errorf("貸し出しの記録の移動に失敗しました: %w", err)
+		}
+		if moved.WishlistItems, err = r.exec(tx, "UPDATE wishlist_items SET book_id = ? WHERE book_id = ?", targetID, sourceID); err != nil {
+			return fmt.Errorf("欲しい本の移動に失敗しました: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// exec はトランザクションの中でSQLを実行し、変更した行数を返す
+func (r *mergeRepository) exec(tx executor, query string, args ...interface{}) (int, error) {
+	result, err := tx.Exec(r.db.Rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
@@ -0,0 +1,71 @@
+//go:build postgres
+
+package repository_test
+
+import (
+	"fmt"           // エラーメッセージと接続先URLの作成
+	"io"            // PostgreSQLのログの破棄
+	"net"           // 空いているポートの確認
+	"os"            // 環境変数 TEST_DATABASE_URL の設定と一時ディレクトリ
+	"path/filepath" // PostgreSQLの実行用ディレクトリのパス
+	"testing"       // テストの実行と結果の報告
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres" // テスト用に起動するPostgreSQL
+)
+
+// TestMain は TEST_DATABASE_URL が設定されていなければ、組み込みのPostgreSQLを起動してからテストを実行する
+// -tags postgres を付けたとき（make test-postgres）だけ使い、PostgreSQLのテストをスキップせずに実行する
+// 初回はPostgreSQLのバイナリをダウンロードする（2回目からはキャッシュを使う）
+func TestMain(m *testing.M) {
+	os.Exit(runWithPostgres(m))
+}
+
+// runWithPostgres は必要ならPostgreSQLを起動してテストを実行し、終了コードを返す
+// os.Exit の前に PostgreSQL の停止と一時ディレクトリの削除を済ませるため、TestMain から分けている
+func runWithPostgres(m *testing.M) int {
+	if os.Getenv(postgresEnv) != "" {
+		return m.Run()
+	}
+
+	port, err := freePort()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "PostgreSQLのポートの確保に失敗しました: %v\n", err)
+		return 1
+	}
+	dir, err := os.MkdirTemp("", "book-manager-postgres-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "PostgreSQLの一時ディレクトリの作成に失敗しました: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	config := embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V16).
+		Port(uint32(port)).
+		RuntimePath(filepath.Join(dir, "runtime")).
+		DataPath(filepath.Join(dir, "data")).
+		Logger(io.Discard)
+	postgres := embeddedpostgres.NewDatabase(config)
+	if err := postgres.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "組み込みのPostgreSQLの起動に失敗しました（%s を設定すれば既存のPostgreSQLを使えます）: %v\n", postgresEnv, err)
+		return 1
+	}
+	defer func() {
+		if err := postgres.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "組み込みのPostgreSQLの停止に失敗しました: %v\n", err)
+		}
+	}()
+
+	os.Setenv(postgresEnv, config.GetConnectionURL()+"?sslmode=disable")
+	return m.Run()
+}
+
+// freePort はOSに空いているTCPポートを選ばせて返す
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,660 @@
+// repotestパッケージ：BookRepositoryの実装が共通の振る舞いを守っているかを確認するテスト集
+// SQLite・PostgreSQL・メモリなど、すべての実装に同じテストを流して結果を揃える（適合性テスト）
+//
+// 使い方（各実装のテストファイルから呼び出す）：
+//
+//	func TestMemoryBookRepository(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) repository.BookRepository {
+//			return repository.NewMemoryBookRepository()
+//		})
+//	}
+//
+// newRepoはテストごとに呼ばれ、書籍が1件もない新しいリポジトリを返すこと
+package repotest
+
+import (
+	"errors"  // トランザクションを取り消すエラー
+	"testing" // テストの実行と結果の報告
+	"time"    // 日時のテストデータ
+
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // テスト対象のインターフェース
+)
+
+// Factory は空のリポジトリを作る関数
+type Factory func(t *testing.T) repository.BookRepository
+
+// Run はすべての適合性テストをサブテストとして実行する
+func Run(t *testing.T, newRepo Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo repository.BookRepository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"GetNotFound", testGetNotFound},
+		{"ListFilters", testListFilters},
+		{"ListOrder", testListOrder},
+		{"ListPagination", testListPagination},
+		{"Update", testUpdate},
+		{"UpdateStatusDates", testUpdateStatusDates},
+		{"UpdateInvalid", testUpdateInvalid},
+		{"Replace", testReplace},
+		{"Delete", testDelete},
+		{"Trash", testTrash},
+		{"Transaction", testTransaction},
+		{"IDsAreNotReused", testIDsAreNotReused},
+		{"Import", testImport},
+		{"OwnerScope", testOwnerScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t))
+		})
+	}
+}
+
+// テストデータの日時（PostgreSQLの精度に合わせて秒単位・UTCにする）
+var (
+	purchased = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	published = time.Date(2008, 8, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// create はテスト用の書籍を作成する（失敗したらテストを中止する）
+func create(t *testing.T, repo repository.BookRepository, req model.CreateBookRequest) *model.Book {
+	t.Helper()
+	if req.PurchaseDate.IsZero() {
+		req.PurchaseDate = purchased
+	}
+	book, err := repo.Create(&req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return book
+}
+
+// ids は書籍のIDを順番に並べたものを返す
+func ids(books []*model.Book) []int {
+	result := make([]int, 0, len(books))
+	for _, b := range books {
+		result = append(result, b.ID)
+	}
+	return result
+}
+
+// sameIDs は2つのID列が同じかを判定する
+func sameIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+func statusPtr(s model.ReadingStatus) *model.ReadingStatus {
+	return &s
+}
+
+func testCreateAndGet(t *testing.T, repo repository.BookRepository) {
+	created := create(t, repo, model.CreateBookRequest{
+		Title: "Clean Code", Author: "Robert C. Martin", ISBN: "9780132350884",
+		Publisher: "Prentice Hall", PublishedDate: &published, PurchasePrice: 4200,
+		Tags: "programming,classic", Notes: "名著",
+	})
+
+	if created.ID <= 0 {
+		t.Fatalf("IDが採番されていません: %d", created.ID)
+	}
+	if created.Status != model.StatusNotStarted {
+		t.Errorf("初期ステータス = %q, want %q", created.Status, model.StatusNotStarted)
+	}
+	if created.CreatedAt.IsZero() || created.UpdatedAt.IsZero() {
+		t.Errorf("作成日時・更新日時が設定されていません")
+	}
+
+	got, err := repo.GetByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Title != "Clean Code" || got.Author != "Robert C. Martin" || got.ISBN != "9780132350884" ||
+		got.Publisher != "Prentice Hall" || got.PurchasePrice != 4200 || got.Tags != "programming,classic" || got.Notes != "名著" {
+		t.Errorf("保存内容が一致しません: %+v", got)
+	}
+	if got.PublishedDate == nil || !got.PublishedDate.Equal(published) {
+		t.Errorf("出版日 = %v, want %v", got.PublishedDate, published)
+	}
+	if !got.PurchaseDate.Equal(purchased) {
+		t.Errorf("購入日 = %v, want %v", got.PurchaseDate, purchased)
+	}
+	if got.Rating != nil || got.StartReadDate != nil || got.EndReadDate != nil {
+		t.Errorf("未設定の項目がnilではありません: %+v", got)
+	}
+}
+
+func testGetNotFound(t *testing.T, repo repository.BookRepository) {
+	if _, err := repo.GetByID(9999); err == nil {
+		t.Error("存在しないIDでエラーになりません")
+	}
+}
+
+func testListFilters(t *testing.T, repo repository.BookRepository) {
+	a := create(t, repo, model.CreateBookRequest{Title: "Go言語入門", Author: "山田太郎", Publisher: "技術評論社", Tags: "Go,入門"})
+	b := create(t, repo, model.CreateBookRequest{Title: "Effective Go", Author: "Alice", Publisher: "O'Reilly", Tags: "go,advanced"})
+	c := create(t, repo, model.CreateBookRequest{Title: "料理の本", Author: "佐藤花子", Publisher: "技術評論社", Tags: "cooking"})
+
+	if _, err := repo.Update(b.ID, &model.UpdateBookRequest{Status: statusPtr(model.StatusReading), Rating: intPtr(4)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := repo.Update(c.ID, &model.UpdateBookRequest{Rating: intPtr(4)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter *model.BookFilter
+		want   []int
+	}{
+		{"フィルターなし", nil, []int{c.ID, b.ID, a.ID}},
+		{"空のフィルター", &model.BookFilter{}, []int{c.ID, b.ID, a.ID}},
+		{"ステータス", &model.BookFilter{Status: statusPtr(model.StatusReading)}, []int{b.ID}},
+		{"著者（完全一致）", &model.BookFilter{Author: strPtr("山田太郎")}, []int{a.ID}},
+		{"著者（部分一致はしない）", &model.BookFilter{Author: strPtr("山田")}, []int{}},
+		{"出版社", &model.BookFilter{Publisher: strPtr("技術評論社")}, []int{c.ID, a.ID}},
+		{"評価", &model.BookFilter{Rating: intPtr(4)}, []int{c.ID, b.ID}},
+		{"タグ（大文字・小文字を区別しない）", &model.BookFilter{Tag: strPtr("GO")}, []int{b.ID, a.ID}},
+		{"タグ（完全一致）", &model.BookFilter{ExactTag: strPtr(" GO ")}, []int{b.ID, a.ID}},
+		{"タグ（完全一致は部分一致しない）", &model.BookFilter{ExactTag: strPtr("adv")}, []int{}},
+		{"検索（タイトル）", &model.BookFilter{Search: strPtr("effective")}, []int{b.ID}},
+		{"検索（著者）", &model.BookFilter{Search: strPtr("花子")}, []int{c.ID}},
+		{"複数条件（AND）", &model.BookFilter{Publisher: strPtr("技術評論社"), Rating: intPtr(4)}, []int{c.ID}},
+	}
+	for _, tt := range tests {
+		books, err := repo.List(tt.filter, 0, 0)
+		if err != nil {
+			t.Fatalf("%s: List: %v", tt.name, err)
+		}
+		if got := ids(books); !sameIDs(got, tt.want) {
+			t.Errorf("%s: List = %v, want %v", tt.name, got, tt.want)
+		}
+		count, err := repo.Count(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: Count: %v", tt.name, err)
+		}
+		if count != len(tt.want) {
+			t.Errorf("%s: Count = %d, want %d", tt.name, count, len(tt.want))
+		}
+	}
+}
+
+func testListOrder(t *testing.T, repo repository.BookRepository) {
+	// 作成日時を指定して取り込み、作成日時の新しい順（同じならIDの大きい順）に並ぶことを確認する
+	older := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, b := range []struct {
+		id        int
+		createdAt time.Time
+	}{{1, newer}, {2, older}, {3, newer}, {4, older}} {
+		book := &model.Book{ID: b.id, Title: "t", Author: "a", PurchaseDate: purchased,
+			Status: model.StatusNotStarted, CreatedAt: b.createdAt, UpdatedAt: b.createdAt}
+		if _, err := repo.Import(book, true); err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+	}
+
+	books, err := repo.List(nil, 0, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got, want := ids(books), []int{3, 1, 4, 2}; !sameIDs(got, want) {
+		t.Errorf("List = %v, want %v", got, want)
+	}
+}
+
+func testListPagination(t *testing.T, repo repository.BookRepository) {
+	all := []int{}
+	for i := 0; i < 5; i++ {
+		all = append([]int{create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"}).ID}, all...)
+	}
+
+	tests := []struct {
+		limit, offset int
+		want          []int
+	}{
+		{0, 0, all},      // limit 0：件数制限なし
+		{2, 0, all[0:2]}, // 先頭から2件
+		{2, 2, all[2:4]}, // 3件目から2件
+		{2, 4, all[4:5]}, // 最後のページは残りだけ
+		{2, 10, []int{}}, // 範囲外は0件
+		{10, 0, all},     // 件数より大きいlimit
+		{0, 3, all},      // limitがなければoffsetは使わない
+		{-1, 0, all},     // 負のlimitは件数制限なし
+	}
+	for _, tt := range tests {
+		books, err := repo.List(nil, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatalf("List(limit=%d, offset=%d): %v", tt.limit, tt.offset, err)
+		}
+		if got := ids(books); !sameIDs(got, tt.want) {
+			t.Errorf("List(limit=%d, offset=%d) = %v, want %v", tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func testUpdate(t *testing.T, repo repository.BookRepository) {
+	book := create(t, repo, model.CreateBookRequest{Title: "旧タイトル", Author: "著者", Notes: "メモ"})
+
+	updated, err := repo.Update(book.ID, &model.UpdateBookRequest{Title: strPtr("新タイトル"), Rating: intPtr(5)})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Title != "新タイトル" || updated.Rating == nil || *updated.Rating != 5 {
+		t.Errorf("指定した項目が更新されていません: %+v", updated)
+	}
+	if updated.Author != "著者" || updated.Notes != "メモ" {
+		t.Errorf("指定していない項目が変わっています: %+v", updated)
+	}
+	if !updated.CreatedAt.Equal(book.CreatedAt) {
+		t.Errorf("作成日時が変わっています: %v → %v", book.CreatedAt, updated.CreatedAt)
+	}
+
+	// 更新する項目がなければ現在のデータをそのまま返す
+	same, err := repo.Update(book.ID, &model.UpdateBookRequest{})
+	if err != nil {
+		t.Fatalf("Update（項目なし）: %v", err)
+	}
+	if same.Title != "新タイトル" {
+		t.Errorf("項目なしの更新で内容が変わっています: %+v", same)
+	}
+
+	if _, err := repo.Update(9999, &model.UpdateBookRequest{Title: strPtr("x")}); err == nil {
+		t.Error("存在しないIDの更新でエラーになりません")
+	}
+}
+
+func testUpdateStatusDates(t *testing.T, repo repository.BookRepository) {
+	book := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+
+	reading, err := repo.Update(book.ID, &model.UpdateBookRequest{Status: statusPtr(model.StatusReading)})
+	if err != nil {
+		t.Fatalf("Update（読書中）: %v", err)
+	}
+	if reading.StartReadDate == nil {
+		t.Error("読書中にしても読書開始日が設定されません")
+	}
+	if reading.EndReadDate != nil {
+		t.Error("読書中にしただけで読書終了日が設定されています")
+	}
+
+	completed, err := repo.Update(book.ID, &model.UpdateBookRequest{Status: statusPtr(model.StatusCompleted)})
+	if err != nil {
+		t.Fatalf("Update（読了）: %v", err)
+	}
+	if completed.EndReadDate == nil {
+		t.Error("読了にしても読書終了日が設定されません")
+	}
+
+	// 日付を明示した場合はその値を使う
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	other := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+	explicit, err := repo.Update(other.ID, &model.UpdateBookRequest{Status: statusPtr(model.StatusReading), StartReadDate: &start})
+	if err != nil {
+		t.Fatalf("Update（開始日指定）: %v", err)
+	}
+	if explicit.StartReadDate == nil || !explicit.StartReadDate.Equal(start) {
+		t.Errorf("読書開始日 = %v, want %v", explicit.StartReadDate, start)
+	}
+}
+
+func testUpdateInvalid(t *testing.T, repo repository.BookRepository) {
+	book := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+
+	if _, err := repo.Update(book.ID, &model.UpdateBookRequest{Rating: intPtr(6)}); err == nil {
+		t.Error("範囲外の評価でエラーになりません")
+	}
+	if _, err := repo.Update(book.ID, &model.UpdateBookRequest{Status: statusPtr("unknown")}); err == nil {
+		t.Error("不正なステータスでエラーになりません")
+	}
+
+	got, err := repo.GetByID(book.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Rating != nil || got.Status != model.StatusNotStarted {
+		t.Errorf("失敗した更新の内容が保存されています: %+v", got)
+	}
+}
+
+func testReplace(t *testing.T, repo repository.BookRepository) {
+	original := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a", PurchaseDate: purchased})
+	if _, err := repo.Update(original.ID, &model.UpdateBookRequest{
+		Title: strPtr("変更後"), Rating: intPtr(4), Status: statusPtr(model.StatusReading),
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// 評価・読書開始日が nil の版に戻すと、値も消える
+	got, err := repo.Replace(original.ID, original)
+	if err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if got.Title != "t" || got.Rating != nil || got.StartReadDate != nil || got.Status != model.StatusNotStarted {
+		t.Errorf("Replace = %+v, want 作成時の値", got)
+	}
+	if !got.CreatedAt.Equal(original.CreatedAt) || got.OwnerID != original.OwnerID {
+		t.Errorf("作成日時・所有者が変わっています: %+v", got)
+	}
+
+	invalid := *original
+	invalid.Rating = intPtr(9)
+	if _, err := repo.Replace(original.ID, &invalid); err == nil {
+		t.Error("評価が範囲外でもエラーになりません")
+	}
+	if _, err := repo.WithOwner(99).Replace(original.ID, original); err == nil {
+		t.Error("他のユーザーの書籍を置き換えできてしまいます")
+	}
+}
+
+func testDelete(t *testing.T, repo repository.BookRepository) {
+	book := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+
+	if err := repo.Delete(book.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(book.ID); err == nil {
+		t.Error("削除した書籍を取得できてしまいます")
+	}
+	if err := repo.Delete(book.ID); err == nil {
+		t.Error("削除済みの書籍の削除でエラーになりません")
+	}
+	if count, _ := repo.Count(nil); count != 0 {
+		t.Errorf("Count = %d, want 0", count)
+	}
+}
+
+func testTrash(t *testing.T, repo repository.BookRepository) {
+	alice := repo.WithOwner(1)
+	kept := create(t, alice, model.CreateBookRequest{Title: "残す本", Author: "a"})
+	trashed := create(t, alice, model.CreateBookRequest{Title: "捨てる本", Author: "a"})
+	bobBook := create(t, repo.WithOwner(2), model.CreateBookRequest{Title: "Bobの本", Author: "b"})
+
+	if err := alice.Delete(trashed.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := alice.Update(trashed.ID, &model.UpdateBookRequest{Title: strPtr("x")}); err == nil {
+		t.Error("ゴミ箱の書籍を更新できてしまいます")
+	}
+	books, err := alice.ListDeleted(0, 0)
+	if err != nil {
+		t.Fatalf("ListDeleted: %v", err)
+	}
+	if got := ids(books); !sameIDs(got, []int{trashed.ID}) {
+		t.Errorf("ListDeleted = %v, want [%d]", got, trashed.ID)
+	} else if books[0].DeletedAt == nil {
+		t.Error("ゴミ箱の書籍に deleted_at がありません")
+	}
+	if count, _ := alice.CountDeleted(); count != 1 {
+		t.Errorf("CountDeleted = %d, want 1", count)
+	}
+
+	// 他のユーザーのゴミ箱の書籍は戻せない・ゴミ箱にない書籍は戻せない
+	if _, err := repo.WithOwner(2).Restore(trashed.ID); err == nil {
+		t.Error("他のユーザーのゴミ箱の書籍を戻せてしまいます")
+	}
+	if _, err := alice.Restore(kept.ID); err == nil {
+		t.Error("ゴミ箱にない書籍の復元でエラーになりません")
+	}
+	restored, err := alice.Restore(trashed.ID)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.DeletedAt != nil || restored.Title != "捨てる本" {
+		t.Errorf("復元した書籍 = %+v", restored)
+	}
+	if count, _ := alice.Count(nil); count != 2 {
+		t.Errorf("復元後の Count = %d, want 2", count)
+	}
+
+	// 完全な削除は基準の日時より前にゴミ箱に移した書籍だけ
+	if err := alice.Delete(trashed.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.WithOwner(2).Delete(bobBook.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if n, err := alice.Purge(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("Purge（1時間前まで） = %d, %v, want 0", n, err)
+	}
+	if n, err := alice.Purge(time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("Purge = %d, %v, want 1", n, err)
+	}
+	if _, err := alice.Restore(trashed.ID); err == nil {
+		t.Error("完全に削除した書籍を戻せてしまいます")
+	}
+	if count, _ := repo.WithOwner(2).CountDeleted(); count != 1 {
+		t.Errorf("他のユーザーのゴミ箱の書籍まで削除されています（CountDeleted = %d）", count)
+	}
+}
+
+func testTransaction(t *testing.T, repo repository.BookRepository) {
+	alice := repo.WithOwner(1)
+	book := create(t, alice, model.CreateBookRequest{Title: "t", Author: "a"})
+
+	// エラーを返すと、トランザクションの中の変更はすべて取り消される
+	errRollback := errors.New("取り消し")
+	err := alice.Transaction(func(tx repository.BookRepository) error {
+		if _, err := tx.Update(book.ID, &model.UpdateBookRequest{Title: strPtr("取り消される")}); err != nil {
+			return err
+		}
+		if got, err := tx.GetByID(book.ID); err != nil || got.Title != "取り消される" {
+			t.Errorf("トランザクションの中で変更が見えません: %+v, %v", got, err)
+		}
+		if err := tx.Delete(book.ID); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if err != errRollback {
+		t.Fatalf("Transaction = %v, want %v", err, errRollback)
+	}
+	if got, err := alice.GetByID(book.ID); err != nil || got.Title != "t" {
+		t.Errorf("取り消したはずの変更が残っています: %+v, %v", got, err)
+	}
+
+	// 成功すると変更が確定する（同じ本棚だけを扱う）
+	err = alice.Transaction(func(tx repository.BookRepository) error {
+		if _, err := tx.Update(book.ID, &model.UpdateBookRequest{Title: strPtr("確定")}); err != nil {
+			return err
+		}
+		if _, err := tx.WithOwner(2).GetByID(book.ID); err == nil {
+			t.Error("トランザクションの中で他のユーザーの本棚から書籍が見えます")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+	if got, _ := alice.GetByID(book.ID); got == nil || got.Title != "確定" {
+		t.Errorf("確定した変更がありません: %+v", got)
+	}
+}
+
+func testIDsAreNotReused(t *testing.T, repo repository.BookRepository) {
+	first := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+	second := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+	if err := repo.Delete(second.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	third := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+	if third.ID <= second.ID || third.ID <= first.ID {
+		t.Errorf("削除したIDが再利用されています: %d（削除したID: %d）", third.ID, second.ID)
+	}
+}
+
+func testImport(t *testing.T, repo repository.BookRepository) {
+	createdAt := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	started := time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC)
+	book := &model.Book{
+		ID: 42, Title: "取り込み", Author: "著者", ISBN: "123", Publisher: "出版社",
+		PublishedDate: &published, PurchaseDate: purchased, PurchasePrice: 1000,
+		Status: model.StatusReading, StartReadDate: &started, Rating: intPtr(3),
+		Notes: "メモ", Tags: "a,b", CreatedAt: createdAt, UpdatedAt: updatedAt,
+	}
+
+	// IDを保ったまま取り込むと、作成日時・更新日時も含めてそのまま保存される
+	imported, err := repo.Import(book, true)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if imported.ID != 42 {
+		t.Errorf("ID = %d, want 42", imported.ID)
+	}
+	if !imported.CreatedAt.Equal(createdAt) || !imported.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("日時が保たれていません: created_at=%v updated_at=%v", imported.CreatedAt, imported.UpdatedAt)
+	}
+	if imported.StartReadDate == nil || !imported.StartReadDate.Equal(started) || imported.Rating == nil || *imported.Rating != 3 {
+		t.Errorf("読書情報が保たれていません: %+v", imported)
+	}
+
+	// 同じIDで取り込むと置き換わる
+	book.Title = "置き換え後"
+	if _, err := repo.Import(book, true); err != nil {
+		t.Fatalf("Import（置き換え）: %v", err)
+	}
+	if count, _ := repo.Count(nil); count != 1 {
+		t.Errorf("置き換えで件数が増えています: %d", count)
+	}
+	if got, _ := repo.GetByID(42); got == nil || got.Title != "置き換え後" {
+		t.Errorf("置き換えられていません: %+v", got)
+	}
+
+	// 新しいIDで取り込むと、既存の最大IDより後ろが採番される
+	duplicated, err := repo.Import(book, false)
+	if err != nil {
+		t.Fatalf("Import（新しいID）: %v", err)
+	}
+	if duplicated.ID <= 42 {
+		t.Errorf("新しいID = %d, want > 42", duplicated.ID)
+	}
+
+	// 取り込み後の通常の作成でもIDが重複しない
+	created := create(t, repo, model.CreateBookRequest{Title: "t", Author: "a"})
+	if created.ID <= duplicated.ID {
+		t.Errorf("作成したID = %d, want > %d", created.ID, duplicated.ID)
+	}
+
+	// 保管場所とゴミ箱に移した日時もそのまま保存される
+	deletedAt := time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC)
+	trashed := *book
+	trashed.ID, trashed.LocationID, trashed.DeletedAt = 50, intPtr(7), &deletedAt
+	imported, err = repo.Import(&trashed, true)
+	if err != nil {
+		t.Fatalf("Import（ゴミ箱の書籍）: %v", err)
+	}
+	if imported.LocationID == nil || *imported.LocationID != 7 || imported.DeletedAt == nil || !imported.DeletedAt.Equal(deletedAt) {
+		t.Errorf("保管場所・ゴミ箱に移した日時が保たれていません: location_id=%v deleted_at=%v", imported.LocationID, imported.DeletedAt)
+	}
+	if _, err := repo.GetByID(50); err == nil {
+		t.Error("ゴミ箱に入った状態で取り込んだ書籍が、ゴミ箱の外に見えています")
+	}
+
+	// ゴミ箱の書籍も「使われているID」として扱う
+	for _, tt := range []struct {
+		id                  int
+		wantExists, wantOwn bool
+	}{{42, true, true}, {50, true, true}, {9999, false, false}} {
+		exists, owned, err := repo.ExistsID(tt.id)
+		if err != nil {
+			t.Fatalf("ExistsID(%d): %v", tt.id, err)
+		}
+		if exists != tt.wantExists || owned != tt.wantOwn {
+			t.Errorf("ExistsID(%d) = %v, %v, want %v, %v", tt.id, exists, owned, tt.wantExists, tt.wantOwn)
+		}
+	}
+}
+
+func testOwnerScope(t *testing.T, repo repository.BookRepository) {
+	alice := repo.WithOwner(1)
+	bob := repo.WithOwner(2)
+	shared := repo.WithOwner(0)
+
+	aliceBook := create(t, alice, model.CreateBookRequest{Title: "Aliceの本", Author: "a"})
+	bobBook := create(t, bob, model.CreateBookRequest{Title: "Bobの本", Author: "b"})
+	sharedBook := create(t, shared, model.CreateBookRequest{Title: "共有の本", Author: "c"})
+
+	if aliceBook.OwnerID != 1 || bobBook.OwnerID != 2 || sharedBook.OwnerID != 0 {
+		t.Errorf("所有者 = %d, %d, %d, want 1, 2, 0", aliceBook.OwnerID, bobBook.OwnerID, sharedBook.OwnerID)
+	}
+
+	// 自分の本棚の書籍だけが見える
+	for _, tt := range []struct {
+		name string
+		repo repository.BookRepository
+		want []int
+	}{
+		{"alice", alice, []int{aliceBook.ID}},
+		{"bob", bob, []int{bobBook.ID}},
+		{"共有", shared, []int{sharedBook.ID}},
+		{"全体", repo.WithOwner(repository.AllOwners), []int{sharedBook.ID, bobBook.ID, aliceBook.ID}},
+	} {
+		books, err := tt.repo.List(nil, 0, 0)
+		if err != nil {
+			t.Fatalf("%s: List: %v", tt.name, err)
+		}
+		if got := ids(books); !sameIDs(got, tt.want) {
+			t.Errorf("%s: List = %v, want %v", tt.name, got, tt.want)
+		}
+		if count, _ := tt.repo.Count(nil); count != len(tt.want) {
+			t.Errorf("%s: Count = %d, want %d", tt.name, count, len(tt.want))
+		}
+	}
+
+	// 他のユーザーの書籍は取得・更新・削除できない
+	if _, err := alice.GetByID(bobBook.ID); err == nil {
+		t.Error("他のユーザーの書籍を取得できてしまいます")
+	}
+	if _, err := alice.Update(bobBook.ID, &model.UpdateBookRequest{Title: strPtr("x")}); err == nil {
+		if got, _ := bob.GetByID(bobBook.ID); got != nil && got.Title == "x" {
+			t.Error("他のユーザーの書籍を更新できてしまいます")
+		}
+	}
+	if err := alice.Delete(bobBook.ID); err == nil {
+		t.Error("他のユーザーの書籍を削除できてしまいます")
+	}
+	if _, err := bob.GetByID(bobBook.ID); err != nil {
+		t.Errorf("他のユーザーの操作で書籍が消えています: %v", err)
+	}
+
+	// 他のユーザーの書籍のIDは、使われているが自分の本棚のものではない（ゴミ箱に移しても同じ）
+	if err := bob.Delete(bobBook.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, owned, err := alice.ExistsID(bobBook.ID); err != nil || !exists || owned {
+		t.Errorf("ExistsID（他のユーザーのゴミ箱の書籍） = %v, %v, %v, want true, false, nil", exists, owned, err)
+	}
+
+	// 他のユーザーの書籍と同じIDでは取り込めない（ゴミ箱の書籍も置き換えない）
+	other := *bobBook
+	other.Title = "乗っ取り"
+	if _, err := alice.Import(&other, true); err == nil {
+		t.Error("他のユーザーの書籍を取り込みで置き換えできてしまいます")
+	}
+	if got, _ := bob.ListDeleted(0, 0); len(got) != 1 || got[0].Title != "Bobの本" {
+		t.Errorf("他のユーザーの書籍が変わっています: %+v", got)
+	}
+
+	// 本棚を限定したリポジトリで取り込むと、その本棚の書籍になる
+	imported, err := alice.Import(&other, false)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if imported.OwnerID != 1 {
+		t.Errorf("取り込んだ書籍の所有者 = %d, want 1", imported.OwnerID)
+	}
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"time"         // 更新日時
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// ReviewRepository はレビューとその編集履歴の永続化を担当するインターフェース
+type ReviewRepository interface {
+	Create(review *model.Review, editorID int) (*model.Review, error) // レビューを登録（最初の版も記録）
+	GetByID(id int) (*model.Review, error)                            // IDでレビューを1件取得
+	ListByBook(bookID int, publicOnly bool) ([]*model.Review, error)  // 書籍のレビューを読書の回の順に取得
+	Update(review *model.Review, editorID int) (*model.Review, error) // レビューを更新（内容が変わったら新しい版を記録）
+	Delete(id int) error                                              // レビューを編集履歴ごと削除
+	Revisions(reviewID int) ([]*model.ReviewRevision, error)          // レビューの版の一覧（新しい順）
+	Revision(reviewID, version int) (*model.ReviewRevision, error)    // レビューの版を1件取得
+	NextReadNumber(bookID int) (int, error)                           // 書籍の次の読書の回
+	ExistsReadNumber(bookID, readNumber, excludeID int) (bool, error) // 同じ回のレビューがあるか（excludeID のレビューは除く）
+}
+
+// reviewRepository はReviewRepositoryインターフェースの実装
+type reviewRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewReviewRepository は新しいReviewRepositoryを作成する関数
+func NewReviewRepository(db *database.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// reviewColumns はレビューを取得するときのカラム
+const reviewColumns = "id, book_id, read_number, read_on, title, body, spoiler, visibility, version, created_at, updated_at"
+
+// Create はレビューを登録し、その内容を1版目として編集履歴に記録する
+func (r *reviewRepository) Create(review *model.Review, editorID int) (*model.Review, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	id, err := r.db.InsertReturningID(tx,
+		`INSERT INTO reviews (book_id, read_number, read_on, title, body, spoiler, visibility, version, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)`,
+		review.BookID, review.ReadNumber, review.ReadOn, review.Title, review.Body, review.Spoiler, review.Visibility, now, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("レビューの保存に失敗しました: %w", err)
+	}
+	if err := r.addRevision(tx, int(id), 1, review, editorID, now); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("レビューの保存に失敗しました: %w", err)
+	}
+	return r.GetByID(int(id))
+}
+
+// GetByID はIDでレビューを1件取得する
+func (r *reviewRepository) GetByID(id int) (*model.Review, error) {
+	review, err := scanReview(r.db.QueryRow(r.db.Rebind("SELECT "+reviewColumns+" FROM reviews WHERE id = ?"), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ID %d のレビューが見つかりません", id)
+		}
+		return nil, fmt.Errorf("レビューの取得に失敗しました: %w", err)
+	}
+	return review, nil
+}
+
+// ListByBook は書籍のレビューを読書の回の順に取得する（publicOnly なら公開のレビューだけ）
+func (r *reviewRepository) ListByBook(bookID int, publicOnly bool) ([]*model.Review, error) {
+	query := "SELECT " + reviewColumns + " FROM reviews WHERE book_id = ?"
+	args := []interface{}{bookID}
+	if publicOnly {
+		query += " AND visibility = ?"
+		args = append(args, model.VisibilityPublic)
+	}
+	rows, err := r.db.Query(r.db.Rebind(query+" ORDER BY read_number"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("レビューの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []*model.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("レビューデータの読み取りに失敗しました: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("レビューの取得に失敗しました: %w", err)
+	}
+	return reviews, nil
+}
+
+// Update はレビューを更新する
+// 見出し・本文・ネタバレの有無のどれかが今の版と違えば、版を1つ増やして編集履歴に記録する
+// 読書の回・読み終えた日・公開範囲だけの変更では版は増やさない（内容の履歴ではないため）
+func (r *reviewRepository) Update(review *model.Review, editorID int) (*model.Review, error) {
+	current, err := r.GetByID(review.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	version := current.Version
+	if review.Title != current.Title || review.Body != current.Body || review.Spoiler != current.Spoiler {
+		version++
+		if err := r.addRevision(tx, review.ID, version, review, editorID, now); err != nil {
+			return nil, err
+		}
+	}
+	_, err = tx.Exec(r.db.Rebind(`UPDATE reviews SET read_number = ?, read_on = ?, title = ?, body = ?, spoiler = ?,
+		visibility = ?, version = ?, updated_at = ? WHERE id = ?`),
+		review.ReadNumber, review.ReadOn, review.Title, review.Body, review.Spoiler, review.Visibility, version, now, review.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("レビューの更新に失敗しました: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("レビューの更新に失敗しました: %w", err)
+	}
+	return r.GetByID(review.ID)
+}
+
+// addRevision はレビューの版を編集履歴に記録する
+func (r *reviewRepository) addRevision(tx *sql.Tx, reviewID, version int, review *model.Review, editorID int, now time.Time) error {
+	_, err := tx.Exec(r.db.Rebind(`INSERT INTO review_revisions (review_id, version, title, body, spoiler, edited_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		reviewID, version, review.Title, review.Body, review.Spoiler, ownerValue(editorID), now,
+	)
+	if err != nil {
+		return fmt.Errorf("レビューの編集履歴の保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Delete はレビューを編集履歴ごと削除する
+// 編集履歴は外部キーの ON DELETE CASCADE でも消えるが、bookRepository.Purge と同じく明示的に削除する
+// （途中で失敗して編集履歴だけが消えないよう、1つのトランザクションで行う）
+func (r *reviewRepository) Delete(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(r.db.Rebind("DELETE FROM review_revisions WHERE review_id = ?"), id); err != nil {
+		return fmt.Errorf("レビューの編集履歴の削除に失敗しました: %w", err)
+	}
+	result, err := tx.Exec(r.db.Rebind("DELETE FROM reviews WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("レビューの削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ID %d のレビューが見つかりません", id)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("レビューの削除の確定に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Revisions はレビューの版の一覧を新しい順に取得する
+func (r *reviewRepository) Revisions(reviewID int) ([]*model.ReviewRevision, error) {
+	rows, err := r.db.Query(r.db.Rebind(`SELECT id, review_id, version, title, body, spoiler, edited_by, created_at
+		FROM review_revisions WHERE review_id = ? ORDER BY version DESC`), reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("レビューの編集履歴の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*model.ReviewRevision{}
+	for rows.Next() {
+		revision, err := scanReviewRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("レビューの編集履歴の読み取りに失敗しました: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("レビューの編集履歴の取得に失敗しました: %w", err)
+	}
+	return revisions, nil
+}
+
+// Revision はレビューの版を1件取得する
+func (r *reviewRepository) Revision(reviewID, version int) (*model.ReviewRevision, error) {
+	revision, err := scanReviewRevision(r.db.QueryRow(r.db.Rebind(`SELECT id, review_id, version, title, body, spoiler, edited_by, created_at
+		FROM review_revisions WHERE review_id = ? AND version = ?`), reviewID, version))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("レビューの %d 版が見つかりません", version)
+		}
+		return nil, fmt.Errorf("レビューの編集履歴の取得に失敗しました: %w", err)
+	}
+	return revision, nil
+}
+
+// NextReadNumber は書籍の次の読書の回（レビューのある回の最大値 + 1）を返す
+func (r *reviewRepository) NextReadNumber(bookID int) (int, error) {
+	var last int
+	err := r.db.QueryRow(r.db.Rebind("SELECT COALESCE(MAX(read_number), 0) FROM reviews WHERE book_id = ?"), bookID).Scan(&last)
+	if err != nil {
+		return 0, fmt.Errorf("レビューの取得に失敗しました: %w", err)
+	}
+	return last + 1, nil
+}
+
+// ExistsReadNumber は書籍に同じ読書の回のレビューがあるかを確認する（excludeID のレビューは除く）
+func (r *reviewRepository) ExistsReadNumber(bookID, readNumber, excludeID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow(r.db.Rebind("SELECT COUNT(*) FROM reviews WHERE book_id = ? AND read_number = ? AND id <> ?"),
+		bookID, readNumber, excludeID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("レビューの取得に失敗しました: %w", err)
+	}
+	return count > 0, nil
+}
+
+// scanReview は1行分のレビューを読み取る
+func scanReview(row rowScanner) (*model.Review, error) {
+	review := &model.Review{}
+	err := row.Scan(&review.ID, &review.BookID, &review.ReadNumber, &review.ReadOn, &review.Title, &review.Body,
+		&review.Spoiler, &review.Visibility, &review.Version, &review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// scanReviewRevision は1行分のレビューの版を読み取る
+func scanReviewRevision(row rowScanner) (*model.ReviewRevision, error) {
+	revision := &model.ReviewRevision{}
+	var editedBy sql.NullInt64
+	err := row.Scan(&revision.ID, &revision.ReviewID, &revision.Version, &revision.Title, &revision.Body,
+		&revision.Spoiler, &editedBy, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	revision.EditedBy = int(editedBy.Int64)
+	return revision, nil
+}
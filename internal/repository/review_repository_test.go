@@ -0,0 +1,55 @@
+package repository_test
+
+import (
+	"testing" // テストの実行と結果の報告
+	"time"    // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // テスト対象のリポジトリ
+)
+
+// TestReviewRepositoryDelete はレビューを削除すると、外部キーの制約に頼らず編集履歴も削除されることを確認する
+func TestReviewRepositoryDelete(t *testing.T) {
+	t.Run("SQLite", func(t *testing.T) { testReviewDelete(t, openSQLite(t), true) })
+	t.Run("SQLite（外部キーなし）", func(t *testing.T) {
+		db := openSQLite(t)
+		// PRAGMA は接続ごとの設定のため、接続を1つにしてから外部キーを無効にする
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec("PRAGMA foreign_keys = OFF"); err != nil {
+			t.Fatalf("PRAGMA: %v", err)
+		}
+		testReviewDelete(t, db, false)
+	})
+	t.Run("Postgres", func(t *testing.T) { testReviewDelete(t, openPostgres(t), true) })
+}
+
+func testReviewDelete(t *testing.T, db *database.DB, foreignKeys bool) {
+	book, err := repository.NewBookRepository(db).Create(&model.CreateBookRequest{Title: "レビューの本", Author: "著者", PurchaseDate: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	reviews := repository.NewReviewRepository(db)
+	review, err := reviews.Create(&model.Review{BookID: book.ID, ReadNumber: 1, Title: "初版", Body: "本文", Visibility: model.VisibilityPrivate}, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	review.Body = "書き直した本文"
+	if _, err := reviews.Update(review, 0); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if revisions, err := reviews.Revisions(review.ID); err != nil || len(revisions) != 2 {
+		t.Fatalf("Revisions = %d件（%v）, want 2件", len(revisions), err)
+	}
+
+	if err := reviews.Delete(review.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	var n int
+	if err := db.QueryRow(db.Rebind("SELECT COUNT(*) FROM review_revisions WHERE review_id = ?"), review.ID).Scan(&n); err != nil || n != 0 {
+		t.Errorf("削除したレビューの編集履歴 = %d件（%v, 外部キー %v）, want 0件", n, err, foreignKeys)
+	}
+	if err := reviews.Delete(review.ID); err == nil {
+		t.Error("削除済みのレビューをもう一度削除できました")
+	}
+}
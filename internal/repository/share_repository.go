@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"fmt" // エラーメッセージの作成
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// ShareRepository は本棚の共有設定の永続化を担当するインターフェース
+type ShareRepository interface {
+	Save(share *model.Share) (*model.Share, error)             // 共有設定を保存（同じ相手・同じ範囲なら役割を更新）
+	GetByID(id int) (*model.Share, error)                      // IDで共有設定を取得
+	ListByOwner(ownerID int) ([]*model.Share, error)           // 自分が共有した設定の一覧
+	ListByGrantee(granteeID int) ([]*model.Share, error)       // 自分に共有された設定の一覧
+	ListGrants(ownerID, granteeID int) ([]*model.Share, error) // 持ち主から共有相手への設定の一覧（権限の確認用）
+	Delete(ownerID, id int) error                              // 共有設定を削除（持ち主だけが削除できる）
+}
+
+// shareRepository はShareRepositoryインターフェースの実装
+type shareRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewShareRepository は新しいShareRepositoryを作成する関数
+func NewShareRepository(db *database.DB) ShareRepository {
+	return &shareRepository{db: db}
+}
+
+// shareSelect は共有設定を取得するときのSELECT文（ユーザー名も一緒に取得する）
+const shareSelect = `SELECT s.id, s.owner_id, o.username, s.grantee_id, g.username, s.role, s.tag, s.created_at
+	FROM shares s
+	JOIN users o ON o.id = s.owner_id
+	JOIN users g ON g.id = s.grantee_id`
+
+// Save は共有設定を保存する
+// ON CONFLICT ... DO UPDATE：同じ相手・同じ範囲の設定が既にあれば、新しく作らずに役割だけを更新する（SQLiteとPostgreSQLで同じ書き方）
+func (r *shareRepository) Save(share *model.Share) (*model.Share, error) {
+	_, err := r.db.Exec(r.db.Rebind(`INSERT INTO shares (owner_id, grantee_id, role, tag) VALUES (?, ?, ?, ?)
+		ON CONFLICT (owner_id, grantee_id, tag) DO UPDATE SET role = excluded.role`),
+		share.OwnerID, share.GranteeID, share.Role, share.Tag,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("共有設定の保存に失敗しました: %w", err)
+	}
+	shares, err := r.query(" WHERE s.owner_id = ? AND s.grantee_id = ? AND s.tag = ?", share.OwnerID, share.GranteeID, share.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("保存した共有設定が見つかりません")
+	}
+	return shares[0], nil
+}
+
+// GetByID はIDで共有設定を取得する
+func (r *shareRepository) GetByID(id int) (*model.Share, error) {
+	shares, err := r.query(" WHERE s.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("ID %d の共有設定が見つかりません", id)
+	}
+	return shares[0], nil
+}
+
+// ListByOwner は自分が共有した設定を取得する
+func (r *shareRepository) ListByOwner(ownerID int) ([]*model.Share, error) {
+	return r.query(" WHERE s.owner_id = ? ORDER BY s.created_at DESC, s.id DESC", ownerID)
+}
+
+// ListByGrantee は自分に共有された設定を取得する
+func (r *shareRepository) ListByGrantee(granteeID int) ([]*model.Share, error) {
+	return r.query(" WHERE s.grantee_id = ? ORDER BY s.created_at DESC, s.id DESC", granteeID)
+}
+
+// ListGrants は持ち主から共有相手への設定を取得する
+func (r *shareRepository) ListGrants(ownerID, granteeID int) ([]*model.Share, error) {
+	return r.query(" WHERE s.owner_id = ? AND s.grantee_id = ?", ownerID, granteeID)
+}
+
+// Delete は共有設定を削除する
+// 他のユーザーの設定は削除できない（見つからないものとして扱う）
+func (r *shareRepository) Delete(ownerID, id int) error {
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM shares WHERE id = ? AND owner_id = ?"), id, ownerID)
+	if err != nil {
+		return fmt.Errorf("共有設定の削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ID %d の共有設定が見つかりません", id)
+	}
+	return nil
+}
+
+// query は条件に一致する共有設定を取得する
+func (r *shareRepository) query(condition string, args ...interface{}) ([]*model.Share, error) {
+	rows, err := r.db.Query(r.db.Rebind(shareSelect+condition), args...)
+	if err != nil {
+		return nil, fmt.Errorf("共有設定の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []*model.Share{}
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("共有設定の読み取りに失敗しました: %w", err)
+		}
+		shares = append(shares, share)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("共有設定の取得に失敗しました: %w", err)
+	}
+	return shares, nil
+}
+
+// scanShare は1行分のデータを共有設定に読み込む
+func scanShare(row rowScanner) (*model.Share, error) {
+	share := &model.Share{}
+	err := row.Scan(&share.ID, &share.OwnerID, &share.OwnerName, &share.GranteeID, &share.GranteeName, &share.Role, &share.Tag, &share.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
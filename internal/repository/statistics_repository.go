@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"math"         // 通貨の桁の計算
+	"sort"         // 通貨コードの並べ替え（SQLの式を毎回同じにするため）
+	"strconv"      // 数値をSQLの式に埋め込む
+	"time"         // 日数と日付の変換
+
+	"book-manager/internal/currency" // 通貨の最小単位
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// StatisticsRepository は書籍の統計をデータベースで集計するインターフェース
+// すべての書籍をメモリに読み込まずに、GROUP BY などのSQLの集計で計算する
+// ownerID は本棚の所有者（0は owner_id が NULL の共有の本棚）
+type StatisticsRepository interface {
+	TimeSeries(ownerID int, interval model.StatsInterval, metric model.SeriesMetric, from, to string) ([]*model.TimeSeriesPoint, error) // 期間ごとの集計（from・to は期間の文字列、空なら制限なし）
+	Breakdown(ownerID int, q *model.BreakdownQuery) ([]*model.BreakdownItem, error)                                                     // タグ・出版社・著者・評価・店・購入方法・形態ごとの集計
+	FinishTime(ownerID int) (*float64, int, error)                                                                                      // 読み終えるまでの平均日数と、計算に使った冊数
+	Backlog(ownerID int, today time.Time) (*model.BacklogStats, error)                                                                  // 積読の集計
+	ReadingSpans(ownerID int, today time.Time) ([]*model.ReadingSpan, error)                                                            // 読書した日が続いた期間（古い順）
+	BaseCurrency() string                                                                                                               // 金額を集計する基準通貨
+	Unconverted(ownerID int, from, to *time.Time) (int, error)                                                                          // 為替レートがなく金額に含められない書籍の冊数
+	FinishedInYear(ownerID, year int) ([]*model.ReviewBook, error)                                                                      // 指定した年に読み終えた書籍（読書終了日の順）
+	Summary(ownerID int, since time.Time) (*model.LibrarySummary, error)                                                                // 冊数・購入金額・平均評価と、since 以降に購入・読了した冊数
+}
+
+// statisticsRepository はStatisticsRepositoryインターフェースの実装
+type statisticsRepository struct {
+	db    *database.DB // データベース接続オブジェクト
+	base  string       // 金額を集計する基準通貨
+	price string       // 購入価格を基準通貨に換算するSQLの式
+}
+
+// NewStatisticsRepository は新しいStatisticsRepositoryを作成する関数
+// baseCurrency は currency.Normalize で確認済みの通貨コード（SQLに埋め込むため、英大文字3文字に限る）
+func NewStatisticsRepository(db *database.DB, baseCurrency string) StatisticsRepository {
+	return &statisticsRepository{db: db, base: baseCurrency, price: convertedPrice(db, baseCurrency)}
+}
+
+// convertedPrice は books の購入価格を基準通貨の最小単位に換算するSQLの式を返す関数
+// 購入日以前で最新の為替レートを使い、レートが登録されていない書籍は NULL（SUM では数えない）になる
+// 通貨ごとの最小単位の違い（円は1円、ドルは1セント）は CASE 式で桁をそろえる
+func convertedPrice(db *database.DB, base string) string {
+	codes := []string{}
+	for code := range currency.Exponents() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	scale := "CASE books.currency"
+	for _, code := range codes {
+		scale += " WHEN '" + code + "' THEN " + strconv.FormatFloat(currency.Scale(code, base), 'f', -1, 64)
+	}
+	// 一覧にない通貨は最小単位が2桁
+	scale += " ELSE " + strconv.FormatFloat(math.Pow10(currency.Exponent(base)-2), 'f', -1, 64) + " END"
+
+	// レートは ExchangeRateRepository.Find（PriceConverter.Rate）と同じ latestRate で選ぶ
+	rate := latestRate(db, "rate", "books.currency", "'"+base+"'", db.EpochDay("books.purchase_date"))
+	return "(CASE WHEN books.currency = '" + base + "' THEN books.purchase_price ELSE CAST(ROUND(books.purchase_price * (" +
+		scale + ") * " + rate + ") AS INTEGER) END)"
+}
+
+// bookWhere は集計する書籍に絞り込むWHERE句を返す（本棚の所有者で絞り込み、ゴミ箱の書籍は除く）
+// ownerID が AllOwners ならすべての本棚の書籍を集計する
+func bookWhere(ownerID int) (string, []interface{}) {
+	if ownerID == AllOwners {
+		return "deleted_at IS NULL", nil
+	}
+	cond, args := ownerWhere("owner_id", ownerID)
+	return cond + " AND deleted_at IS NULL", args
+}
+
+// BaseCurrency は金額を集計する基準通貨を返す
+func (r *statisticsRepository) BaseCurrency() string {
+	return r.base
+}
+
+// Unconverted は購入日の範囲（nilは制限なし）で、為替レートがなく金額の集計に含められない書籍の冊数を返す
+func (r *statisticsRepository) Unconverted(ownerID int, from, to *time.Time) (int, error) {
+	cond, args := bookWhere(ownerID)
+	if from != nil {
+		cond += " AND " + r.db.EpochDay("purchase_date") + " >= ?"
+		args = append(args, epochDay(*from))
+	}
+	if to != nil {
+		cond += " AND " + r.db.EpochDay("purchase_date") + " <= ?"
+		args = append(args, epochDay(*to))
+	}
+	var count int
+	if err := r.db.QueryRow(r.db.Rebind("SELECT COUNT(*) FROM books WHERE "+cond+" AND purchase_price > 0 AND "+r.price+" IS NULL"), args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("換算できない書籍の集計に失敗しました: %w", err)
+	}
+	return count, nil
+}
+
+// epochDay は日時を1970年1月1日からの日数（UTC）に変換する（SQLの database.EpochDay と同じ計算）
+func epochDay(t time.Time) int {
+	return int(t.Unix() / 86400)
+}
+
+// fromEpochDay は1970年1月1日からの日数を日付（UTCの0時）に変換する
+func fromEpochDay(day int) time.Time {
+	return time.Unix(int64(day)*86400, 0).UTC()
+}
+
+// TimeSeries は購入した冊数・読み終えた冊数・購入金額を期間ごとに集計する
+// データのない期間は含まれない（補完はユースケース層で行う）
+func (r *statisticsRepository) TimeSeries(ownerID int, interval model.StatsInterval, metric model.SeriesMetric, from, to string) ([]*model.TimeSeriesPoint, error) {
+	column, value := "purchase_date", "COUNT(*)"
+	cond, args := bookWhere(ownerID)
+	switch metric {
+	case model.SeriesPurchased:
+	case model.SeriesCompleted:
+		column = "end_read_date"
+		cond += " AND status = 'completed'"
+	case model.SeriesSpent:
+		value = "COALESCE(SUM(" + r.price + "), 0)"
+	default:
+		return nil, fmt.Errorf("対応していない集計の値です: %s", metric)
+	}
+
+	label, err := r.db.DateLabel(column, string(interval))
+	if err != nil {
+		return nil, err
+	}
+	cond += " AND " + column + " IS NOT NULL"
+	// 期間の文字列は大小を比較できる形式のため、文字列のまま範囲を絞り込める
+	if from != "" {
+		cond += " AND " + label + " >= ?"
+		args = append(args, from)
+	}
+	if to != "" {
+		cond += " AND " + label + " <= ?"
+		args = append(args, to)
+	}
+
+	query := "SELECT " + label + ", " + value + " FROM books WHERE " + cond + " GROUP BY " + label + " ORDER BY " + label
+	rows, err := r.db.Query(r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("時系列の統計の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	points := []*model.TimeSeriesPoint{}
+	for rows.Next() {
+		point := &model.TimeSeriesPoint{}
+		if err := rows.Scan(&point.Period, &point.Value); err != nil {
+			return nil, fmt.Errorf("時系列の統計の読み取りに失敗しました: %w", err)
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("時系列の統計の取得に失敗しました: %w", err)
+	}
+	return points, nil
+}
+
+// Breakdown はタグ・出版社・著者・評価・店・購入方法・形態ごとに、書籍数・読了数・購入金額・平均評価を集計する
+// 評価は評価の高い順、それ以外は書籍数の多い順に並べ、上位 q.Limit 件を返す（q.Sort が spent なら購入金額の多い順）
+func (r *statisticsRepository) Breakdown(ownerID int, q *model.BreakdownQuery) ([]*model.BreakdownItem, error) {
+	cond, args := bookWhere(ownerID)
+	// 購入日で範囲を絞り込む（日付の文字列は大小を比較できる）
+	if q.From != nil || q.To != nil {
+		day, err := r.db.DateLabel("purchase_date", string(model.IntervalDay))
+		if err != nil {
+			return nil, err
+		}
+		if q.From != nil {
+			cond += " AND " + day + " >= ?"
+			args = append(args, q.From.UTC().Format("2006-01-02"))
+		}
+		if q.To != nil {
+			cond += " AND " + day + " <= ?"
+			args = append(args, q.To.UTC().Format("2006-01-02"))
+		}
+	}
+
+	// source：集計の元になる行（k が分けるキー）、group：GROUP BY の式
+	var with, source, group string
+	order := "COUNT(*) DESC, MIN(k)"
+	switch q.By {
+	case model.BreakdownPublisher, model.BreakdownAuthor, model.BreakdownStore, model.BreakdownChannel, model.BreakdownFormat:
+		source = "SELECT TRIM(COALESCE(" + string(q.By) + ", '')) AS k, status, " + r.price + " AS purchase_price, rating FROM books WHERE " + cond
+		group = "k"
+	case model.BreakdownRating:
+		source = "SELECT COALESCE(CAST(rating AS TEXT), '') AS k, status, " + r.price + " AS purchase_price, rating FROM books WHERE " + cond
+		group = "k"
+		order = "MIN(k) DESC"
+	case model.BreakdownTag:
+		// カンマ区切りのタグを再帰クエリで1行ずつに分ける（rest は未処理の残りの文字列）
+		// タグは大文字・小文字を区別せずにまとめる（Book.HasTag と同じ）
+		comma := r.db.Instr("rest", "','")
+		with = `WITH RECURSIVE split(k, status, purchase_price, rating, rest) AS (
+				SELECT CAST('' AS TEXT), status, ` + r.price + `, rating, COALESCE(tags, '') || ',' FROM books WHERE ` + cond + `
+				UNION ALL
+				SELECT TRIM(substr(rest, 1, ` + comma + ` - 1)), status, purchase_price, rating, substr(rest, ` + comma + ` + 1)
+				FROM split WHERE rest <> ''
+			) `
+		source = "SELECT k, status, purchase_price, rating FROM split WHERE k <> ''"
+		group = "LOWER(k)"
+	default:
+		return nil, fmt.Errorf("対応していない内訳です: %s", q.By)
+	}
+	if q.Sort == model.SortBySpent {
+		order = "COALESCE(SUM(purchase_price), 0) DESC, " + order
+	}
+
+	query := with + `SELECT MIN(k), COUNT(*), SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END),
+			COALESCE(SUM(purchase_price), 0), AVG(CAST(rating AS FLOAT))
+		FROM (` + source + `) breakdown GROUP BY ` + group + ` ORDER BY ` + order + ` LIMIT ?`
+	rows, err := r.db.Query(r.db.Rebind(query), append(args, q.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("内訳の統計の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*model.BreakdownItem{}
+	for rows.Next() {
+		item := &model.BreakdownItem{}
+		var rating sql.NullFloat64
+		if err := rows.Scan(&item.Key, &item.Books, &item.Completed, &item.Spent, &rating); err != nil {
+			return nil, fmt.Errorf("内訳の統計の読み取りに失敗しました: %w", err)
+		}
+		if rating.Valid {
+			item.AverageRating = &rating.Float64
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("内訳の統計の取得に失敗しました: %w", err)
+	}
+	return items, nil
+}
+
+// FinishTime は読み始めてから読み終えるまでの平均日数を集計する
+// 開始日・終了日のある読了済みの書籍だけを対象にする（同じ日に読み終えた場合は0日）
+func (r *statisticsRepository) FinishTime(ownerID int) (*float64, int, error) {
+	cond, args := bookWhere(ownerID)
+	days := r.db.EpochDay("end_read_date") + " - " + r.db.EpochDay("start_read_date")
+	query := "SELECT COUNT(*), AVG(CAST(" + days + " AS FLOAT)) FROM books WHERE " + cond +
+		" AND status = 'completed' AND start_read_date IS NOT NULL AND end_read_date IS NOT NULL AND " + days + " >= 0"
+
+	var count int
+	var average sql.NullFloat64
+	if err := r.db.QueryRow(r.db.Rebind(query), args...).Scan(&count, &average); err != nil {
+		return nil, 0, fmt.Errorf("読了までの日数の集計に失敗しました: %w", err)
+	}
+	if !average.Valid {
+		return nil, count, nil
+	}
+	return &average.Float64, count, nil
+}
+
+// Backlog は積読（読書ステータスが未読の書籍）の冊数・金額・古さを集計する
+func (r *statisticsRepository) Backlog(ownerID int, today time.Time) (*model.BacklogStats, error) {
+	cond, args := bookWhere(ownerID)
+	purchased := r.db.EpochDay("purchase_date")
+	query := "SELECT COUNT(*), COALESCE(SUM(" + r.price + "), 0), MIN(" + purchased + "), AVG(CAST(? - " + purchased + " AS FLOAT)) FROM books WHERE " +
+		cond + " AND status = 'not_started'"
+
+	stats := &model.BacklogStats{}
+	var oldest sql.NullInt64
+	var age sql.NullFloat64
+	if err := r.db.QueryRow(r.db.Rebind(query), append([]interface{}{epochDay(today)}, args...)...).Scan(&stats.Books, &stats.Value, &oldest, &age); err != nil {
+		return nil, fmt.Errorf("積読の集計に失敗しました: %w", err)
+	}
+	if oldest.Valid {
+		t := fromEpochDay(int(oldest.Int64))
+		stats.OldestPurchase = &t
+	}
+	if age.Valid {
+		stats.AverageAgeDays = &age.Float64
+	}
+	return stats, nil
+}
+
+// ReadingSpans は書籍を読んでいた日が続いた期間を古い順に返す
+// 各書籍の「読書開始日〜読書終了日（読書中なら今日）」を、重なるか隣り合う期間どうしでまとめる
+// ウィンドウ関数で「それまでの期間の最終日の最大値」と比べ、1日以上離れていれば新しい期間の始まりとする
+func (r *statisticsRepository) ReadingSpans(ownerID int, today time.Time) ([]*model.ReadingSpan, error) {
+	cond, args := bookWhere(ownerID)
+	query := `WITH spans AS (
+			SELECT ` + r.db.EpochDay("start_read_date") + ` AS s, COALESCE(` + r.db.EpochDay("end_read_date") + `, ?) AS e
+			FROM books WHERE ` + cond + ` AND start_read_date IS NOT NULL AND (end_read_date IS NOT NULL OR status = 'reading')
+		), ordered AS (
+			SELECT s, e, MAX(e) OVER (ORDER BY s, e ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) AS prev_end
+			FROM spans WHERE e >= s
+		), islands AS (
+			SELECT s, e, SUM(CASE WHEN prev_end IS NULL OR s > prev_end + 1 THEN 1 ELSE 0 END)
+				OVER (ORDER BY s, e ROWS UNBOUNDED PRECEDING) AS grp
+			FROM ordered
+		)
+		SELECT MIN(s), MAX(e) FROM islands GROUP BY grp ORDER BY MIN(s)`
+
+	rows, err := r.db.Query(r.db.Rebind(query), append([]interface{}{epochDay(today)}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("読書した期間の集計に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	spans := []*model.ReadingSpan{}
+	for rows.Next() {
+		var from, to int
+		if err := rows.Scan(&from, &to); err != nil {
+			return nil, fmt.Errorf("読書した期間の読み取りに失敗しました: %w", err)
+		}
+		spans = append(spans, &model.ReadingSpan{From: fromEpochDay(from), To: fromEpochDay(to)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("読書した期間の集計に失敗しました: %w", err)
+	}
+	return spans, nil
+}
+
+// FinishedInYear は指定した年（UTC）に読み終えた書籍を読書終了日の順に返す
+// 年間の振り返りの並べ替え（評価の高い順など）はユースケース層で行う
+func (r *statisticsRepository) FinishedInYear(ownerID, year int) ([]*model.ReviewBook, error) {
+	cond, args := bookWhere(ownerID)
+	yearLabel, err := r.db.DateLabel("end_read_date", string(model.IntervalYear))
+	if err != nil {
+		return nil, err
+	}
+	start, end := r.db.EpochDay("start_read_date"), r.db.EpochDay("end_read_date")
+	query := "SELECT id, title, author, rating, page_count, " + start + ", " + end + " FROM books WHERE " + cond +
+		" AND status = 'completed' AND end_read_date IS NOT NULL AND " + yearLabel + " = ? ORDER BY " + end + ", id"
+
+	rows, err := r.db.Query(r.db.Rebind(query), append(args, fmt.Sprintf("%04d", year))...)
+	if err != nil {
+		return nil, fmt.Errorf("読み終えた書籍の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	books := []*model.ReviewBook{}
+	for rows.Next() {
+		book := &model.ReviewBook{}
+		var startDay sql.NullInt64
+		var endDay int
+		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Rating, &book.PageCount, &startDay, &endDay); err != nil {
+			return nil, fmt.Errorf("読み終えた書籍の読み取りに失敗しました: %w", err)
+		}
+		book.EndReadDate = fromEpochDay(endDay)
+		if startDay.Valid && int(startDay.Int64) <= endDay {
+			started := fromEpochDay(int(startDay.Int64))
+			days := endDay - int(startDay.Int64)
+			book.StartReadDate, book.Days = &started, &days
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("読み終えた書籍の取得に失敗しました: %w", err)
+	}
+	return books, nil
+}
+
+// Summary は書籍の冊数・購入金額・平均評価などを1回の集計で返す（書籍の統計情報の概要）
+// since 以降に購入・読了した冊数は、ほかの統計と同じくUTCの日単位で数える
+func (r *statisticsRepository) Summary(ownerID int, since time.Time) (*model.LibrarySummary, error) {
+	cond, args := bookWhere(ownerID)
+	count := func(expr string) string { return "COALESCE(SUM(CASE WHEN " + expr + " THEN 1 ELSE 0 END), 0)" }
+	query := "SELECT COUNT(*), " +
+		count("status = 'not_started'") + ", " +
+		count("status = 'reading'") + ", " +
+		count("status = 'completed'") + ", " +
+		count("status = 'dropped'") + ", " +
+		"COALESCE(SUM(" + r.price + "), 0), " +
+		count("purchase_price > 0 AND "+r.price+" IS NULL") + ", " +
+		"AVG(CAST(rating AS FLOAT)), " +
+		count(r.db.EpochDay("purchase_date")+" >= ?") + ", " +
+		count("status = 'completed' AND end_read_date IS NOT NULL AND "+r.db.EpochDay("end_read_date")+" >= ?") +
+		" FROM books WHERE " + cond
+
+	day := epochDay(since)
+	summary := &model.LibrarySummary{}
+	var rating sql.NullFloat64
+	if err := r.db.QueryRow(r.db.Rebind(query), append([]interface{}{day, day}, args...)...).Scan(
+		&summary.Books, &summary.NotStarted, &summary.Reading, &summary.Completed, &summary.Dropped,
+		&summary.Spent, &summary.Unconverted, &rating, &summary.PurchasedSince, &summary.CompletedSince); err != nil {
+		return nil, fmt.Errorf("書籍の概要の集計に失敗しました: %w", err)
+	}
+	if rating.Valid {
+		summary.AverageRating = &rating.Float64
+	}
+	return summary, nil
+}
@@ -0,0 +1,215 @@
+package repository_test
+
+import (
+	"reflect" // 集計結果の比較
+	"strconv" // エラーメッセージの数値の変換
+	"strings" // タグの大文字・小文字を区別しない比較
+	"testing" // テストの実行と結果の報告
+	"time"    // 日時のテストデータ
+
+	"book-manager/internal/currency"   // 換算結果の期待値
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // テスト対象のリポジトリ
+)
+
+// TestStatisticsRepository は統計のSQL（EpochDay・DateLabel などデータベースごとに違う式）が
+// SQLiteとPostgreSQLで同じ結果になることを確認する
+func TestStatisticsRepository(t *testing.T) {
+	t.Run("SQLite", func(t *testing.T) { testStatistics(t, openSQLite(t)) })
+	t.Run("Postgres", func(t *testing.T) { testStatistics(t, openPostgres(t)) })
+}
+
+// day はUTCの0時の日時を返す
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// testStatistics は共有の本棚（所有者なし）に書籍を登録して、各集計の結果を確認する
+func testStatistics(t *testing.T, db *database.DB) {
+	books := repository.NewBookRepository(db).WithOwner(0)
+	add := func(req model.CreateBookRequest, update *model.UpdateBookRequest) {
+		t.Helper()
+		book, err := books.Create(&req)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if update != nil {
+			if _, err := books.Update(book.ID, update); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+		}
+	}
+	status := func(s model.ReadingStatus) *model.ReadingStatus { return &s }
+	date := func(t time.Time) *time.Time { return &t }
+	rating := func(r int) *int { return &r }
+
+	// 2024-02-01〜02-11 に読んだ本（10日）
+	add(model.CreateBookRequest{Title: "A", Author: "a", PurchaseDate: day(2024, 1, 15), PurchasePrice: 1000, Tags: "SF, 小説"},
+		&model.UpdateBookRequest{Status: status(model.StatusCompleted), StartReadDate: date(day(2024, 2, 1)), EndReadDate: date(day(2024, 2, 11)), Rating: rating(5)})
+	// 積読
+	add(model.CreateBookRequest{Title: "B", Author: "b", PurchaseDate: day(2024, 1, 20), PurchasePrice: 500, Tags: "sf"}, nil)
+	// 2024-02-10 から読書中（A の期間とつながる）
+	add(model.CreateBookRequest{Title: "C", Author: "c", PurchaseDate: day(2024, 3, 5), PurchasePrice: 800},
+		&model.UpdateBookRequest{Status: status(model.StatusReading), StartReadDate: date(day(2024, 2, 10))})
+	// 2023-12-01〜12-03 に読んだ本（2日）
+	add(model.CreateBookRequest{Title: "D", Author: "d", PurchaseDate: day(2023, 11, 20), PurchasePrice: 300},
+		&model.UpdateBookRequest{Status: status(model.StatusCompleted), StartReadDate: date(day(2023, 12, 1)), EndReadDate: date(day(2023, 12, 3)), Rating: rating(3)})
+
+	stats := repository.NewStatisticsRepository(db, "JPY")
+	today := day(2024, 3, 10)
+
+	seriesTests := []struct {
+		name     string
+		interval model.StatsInterval
+		metric   model.SeriesMetric
+		from, to string
+		want     []*model.TimeSeriesPoint
+	}{
+		{"月ごとの購入冊数", model.IntervalMonth, model.SeriesPurchased, "", "",
+			[]*model.TimeSeriesPoint{{Period: "2023-11", Value: 1}, {Period: "2024-01", Value: 2}, {Period: "2024-03", Value: 1}}},
+		{"週ごと（月曜日の日付）", model.IntervalWeek, model.SeriesPurchased, "2024-01-01", "2024-12-31",
+			[]*model.TimeSeriesPoint{{Period: "2024-01-15", Value: 2}, {Period: "2024-03-04", Value: 1}}},
+		{"日ごと", model.IntervalDay, model.SeriesPurchased, "2024-01-16", "2024-03-05",
+			[]*model.TimeSeriesPoint{{Period: "2024-01-20", Value: 1}, {Period: "2024-03-05", Value: 1}}},
+		{"月ごとの購入金額", model.IntervalMonth, model.SeriesSpent, "2024-01", "",
+			[]*model.TimeSeriesPoint{{Period: "2024-01", Value: 1500}, {Period: "2024-03", Value: 800}}},
+		{"年ごとの読了冊数", model.IntervalYear, model.SeriesCompleted, "", "",
+			[]*model.TimeSeriesPoint{{Period: "2023", Value: 1}, {Period: "2024", Value: 1}}},
+	}
+	for _, tt := range seriesTests {
+		got, err := stats.TimeSeries(0, tt.interval, tt.metric, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("%s: TimeSeries: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: TimeSeries = %s, want %s", tt.name, points(got), points(tt.want))
+		}
+	}
+
+	tags, err := stats.Breakdown(0, &model.BreakdownQuery{By: model.BreakdownTag, Sort: model.SortByBooks, Limit: 10})
+	if err != nil {
+		t.Fatalf("Breakdown: %v", err)
+	}
+	if len(tags) != 2 || !strings.EqualFold(tags[0].Key, "sf") || tags[0].Books != 2 || tags[0].Spent != 1500 || tags[1].Key != "小説" {
+		t.Errorf("タグごとの内訳が違います: %+v", tags)
+	}
+
+	average, count, err := stats.FinishTime(0)
+	if err != nil {
+		t.Fatalf("FinishTime: %v", err)
+	}
+	if count != 2 || average == nil || *average != 6 {
+		t.Errorf("FinishTime = %v, %d, want 6, 2", average, count)
+	}
+
+	backlog, err := stats.Backlog(0, today)
+	if err != nil {
+		t.Fatalf("Backlog: %v", err)
+	}
+	if backlog.Books != 1 || backlog.Value != 500 || backlog.OldestPurchase == nil || !backlog.OldestPurchase.Equal(day(2024, 1, 20)) ||
+		backlog.AverageAgeDays == nil || *backlog.AverageAgeDays != 50 {
+		t.Errorf("積読の集計が違います: %+v", backlog)
+	}
+
+	spans, err := stats.ReadingSpans(0, today)
+	if err != nil {
+		t.Fatalf("ReadingSpans: %v", err)
+	}
+	wantSpans := []*model.ReadingSpan{{From: day(2023, 12, 1), To: day(2023, 12, 3)}, {From: day(2024, 2, 1), To: today}}
+	if !reflect.DeepEqual(spans, wantSpans) {
+		t.Errorf("ReadingSpans = %+v, want %+v", spans, wantSpans)
+	}
+
+	summary, err := stats.Summary(0, day(2024, 2, 1))
+	if err != nil {
+		t.Fatalf("Summary: %v", err)
+	}
+	wantSummary := model.LibrarySummary{Books: 4, NotStarted: 1, Reading: 1, Completed: 2, Spent: 2600, PurchasedSince: 1, CompletedSince: 1}
+	gotRating := summary.AverageRating
+	summary.AverageRating = nil
+	if *summary != wantSummary || gotRating == nil || *gotRating != 4 {
+		t.Errorf("Summary = %+v（平均評価 %v）, want %+v（平均評価 4）", *summary, gotRating, wantSummary)
+	}
+
+	finished, err := stats.FinishedInYear(0, 2024)
+	if err != nil {
+		t.Fatalf("FinishedInYear: %v", err)
+	}
+	if len(finished) != 1 || finished[0].Title != "A" || finished[0].Days == nil || *finished[0].Days != 10 {
+		t.Errorf("2024年に読み終えた書籍が違います: %+v", finished)
+	}
+}
+
+// points は時系列の統計をエラーメッセージ用の文字列にする
+func points(series []*model.TimeSeriesPoint) string {
+	parts := []string{}
+	for _, p := range series {
+		parts = append(parts, p.Period+"="+strconv.Itoa(p.Value))
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// TestStatisticsConvertedPrice は購入金額の集計で、外貨の価格を購入日以前で最新の為替レートで換算し、
+// 結果が currency.Convert と同じになることを確認する
+func TestStatisticsConvertedPrice(t *testing.T) {
+	t.Run("SQLite", func(t *testing.T) { testConvertedPrice(t, openSQLite(t)) })
+	t.Run("Postgres", func(t *testing.T) { testConvertedPrice(t, openPostgres(t)) })
+}
+
+func testConvertedPrice(t *testing.T, db *database.DB) {
+	if err := repository.NewExchangeRateRepository(db).Set(
+		&model.ExchangeRate{Currency: "USD", Base: "JPY", Date: day(2024, 1, 1), Rate: 140},
+		&model.ExchangeRate{Currency: "USD", Base: "JPY", Date: day(2024, 3, 1), Rate: 150},
+		&model.ExchangeRate{Currency: "KWD", Base: "JPY", Date: day(2024, 1, 1), Rate: 487.25},
+		&model.ExchangeRate{Currency: "CHF", Base: "JPY", Date: day(2024, 1, 1), Rate: 170.4},
+	); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		price    int
+		currency string
+		date     time.Time
+		rate     float64 // 換算に使われるべきレート（0なら換算できない）
+	}{
+		{"基準通貨はそのまま", 1200, "JPY", day(2024, 2, 10), 1},
+		{"購入日以前で最新のレート", 1234, "USD", day(2024, 2, 10), 140},
+		{"購入日当日のレート", 1000, "USD", day(2024, 3, 1), 150},
+		{"購入日当日の遅い時刻", 1000, "USD", day(2024, 3, 1).Add(23 * time.Hour), 150},
+		{"最小単位が3桁の通貨", 1500, "KWD", day(2024, 2, 10), 487.25},
+		{"一覧にない通貨は2桁", 500, "CHF", day(2024, 2, 10), 170.4},
+		{"購入日より前のレートがない", 999, "USD", day(2023, 12, 31), 0},
+		{"レートのない通貨", 999, "EUR", day(2024, 2, 10), 0},
+	}
+	for i, tt := range tests {
+		ownerID := i + 1 // 書籍ごとに別の本棚にして1冊ずつ集計する
+		t.Run(tt.name, func(t *testing.T) {
+			books := repository.NewBookRepository(db).WithOwner(ownerID)
+			if _, err := books.Create(&model.CreateBookRequest{Title: tt.name, Author: "a", PurchaseDate: tt.date, PurchasePrice: tt.price, Currency: tt.currency}); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			stats := repository.NewStatisticsRepository(db, "JPY")
+			got, err := stats.TimeSeries(ownerID, model.IntervalYear, model.SeriesSpent, "", "")
+			if err != nil {
+				t.Fatalf("TimeSeries: %v", err)
+			}
+			unconverted, err := stats.Unconverted(ownerID, nil, nil)
+			if err != nil {
+				t.Fatalf("Unconverted: %v", err)
+			}
+
+			if tt.rate == 0 {
+				if unconverted != 1 || len(got) != 1 || got[0].Value != 0 {
+					t.Errorf("換算できない書籍：TimeSeries = %s, Unconverted = %d, want 金額0・1冊", points(got), unconverted)
+				}
+				return
+			}
+			want := currency.Convert(tt.price, tt.currency, "JPY", tt.rate)
+			if unconverted != 0 || len(got) != 1 || got[0].Value != want {
+				t.Errorf("TimeSeries = %s, Unconverted = %d, want %d・0冊", points(got), unconverted, want)
+			}
+		})
+	}
+}
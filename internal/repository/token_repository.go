@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"strings"      // 権限のカンマ区切りの変換
+	"time"         // 最終使用日時
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// TokenRepository は個人用アクセストークンの永続化を担当するインターフェース
+type TokenRepository interface {
+	Create(token *model.APIToken) (*model.APIToken, error) // トークンを保存
+	ListByUser(userID int) ([]*model.APIToken, error)      // ユーザーのトークン一覧を取得
+	GetByHash(tokenHash string) (*model.APIToken, error)   // トークンのハッシュでトークンを取得
+	Delete(userID, id int) error                           // ユーザーのトークンを削除（取り消し）
+	UpdateLastUsed(id int, usedAt time.Time) error         // 最終使用日時を記録
+}
+
+// tokenRepository はTokenRepositoryインターフェースの実装
+type tokenRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewTokenRepository は新しいTokenRepositoryを作成する関数
+func NewTokenRepository(db *database.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// tokenColumns はトークンを取得するときのカラム一覧（scanTokenと同じ順番）
+const tokenColumns = "id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at"
+
+// Create はトークンを保存する
+func (r *tokenRepository) Create(token *model.APIToken) (*model.APIToken, error) {
+	id, err := r.db.InsertReturningID(r.db,
+		"INSERT INTO api_tokens (user_id, name, token_hash, scopes, expires_at) VALUES (?, ?, ?, ?, ?)",
+		token.UserID, token.Name, token.TokenHash, joinScopes(token.Scopes), token.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("トークンの保存に失敗しました: %w", err)
+	}
+	return r.get("id = ?", id)
+}
+
+// ListByUser はユーザーのトークンを新しい順に取得する
+func (r *tokenRepository) ListByUser(userID int) ([]*model.APIToken, error) {
+	rows, err := r.db.Query(r.db.Rebind("SELECT "+tokenColumns+" FROM api_tokens WHERE user_id = ? ORDER BY created_at DESC, id DESC"), userID)
+	if err != nil {
+		return nil, fmt.Errorf("トークン一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*model.APIToken{}
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("トークンデータの読み取りに失敗しました: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("トークン一覧の取得に失敗しました: %w", err)
+	}
+	return tokens, nil
+}
+
+// GetByHash はトークンのハッシュでトークンを取得する
+func (r *tokenRepository) GetByHash(tokenHash string) (*model.APIToken, error) {
+	return r.get("token_hash = ?", tokenHash)
+}
+
+// get は条件に一致するトークンを1件取得する
+func (r *tokenRepository) get(condition string, arg interface{}) (*model.APIToken, error) {
+	row := r.db.QueryRow(r.db.Rebind("SELECT "+tokenColumns+" FROM api_tokens WHERE "+condition), arg)
+	token, err := scanToken(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("トークンが見つかりません")
+		}
+		return nil, fmt.Errorf("トークンの取得に失敗しました: %w", err)
+	}
+	return token, nil
+}
+
+// Delete はユーザーのトークンを削除する
+// 他のユーザーのトークンは削除できない（見つからないものとして扱う）
+func (r *tokenRepository) Delete(userID, id int) error {
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM api_tokens WHERE id = ? AND user_id = ?"), id, userID)
+	if err != nil {
+		return fmt.Errorf("トークンの削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ID %d のトークンが見つかりません", id)
+	}
+	return nil
+}
+
+// UpdateLastUsed はトークンの最終使用日時を記録する
+func (r *tokenRepository) UpdateLastUsed(id int, usedAt time.Time) error {
+	if _, err := r.db.Exec(r.db.Rebind("UPDATE api_tokens SET last_used_at = ? WHERE id = ?"), usedAt, id); err != nil {
+		return fmt.Errorf("トークンの最終使用日時の記録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// rowScanner は *sql.Row と *sql.Rows の共通のインターフェース
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanToken は1行分のデータをトークンに読み込む
+func scanToken(row rowScanner) (*model.APIToken, error) {
+	token := &model.APIToken{}
+	var scopes string
+	if err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenHash, &scopes, &token.ExpiresAt, &token.LastUsedAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+	token.Scopes = splitScopes(scopes)
+	return token, nil
+}
+
+// joinScopes は権限の一覧をカンマ区切りの文字列に変換する（例：read,write）
+func joinScopes(scopes []model.Scope) string {
+	s := make([]string, len(scopes))
+	for i, scope := range scopes {
+		s[i] = string(scope)
+	}
+	return strings.Join(s, ",")
+}
+
+// splitScopes はカンマ区切りの文字列を権限の一覧に変換する
+func splitScopes(s string) []model.Scope {
+	scopes := []model.Scope{}
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			scopes = append(scopes, model.Scope(part))
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"time"         // 有効期限の判定
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// UserRepository はユーザーとログインセッションの永続化を担当するインターフェース
+type UserRepository interface {
+	Register(user *model.User, check func(count int) error) (*model.User, error) // 登録済みのユーザー数を確認してユーザーを登録
+	GetByID(id int) (*model.User, error)                                         // IDでユーザーを取得
+	GetByUsername(username string) (*model.User, error)                          // ユーザー名でユーザーを取得
+	Count() (int, error)                                                         // 登録済みのユーザー数
+	CreateSession(session *model.Session) error                                  // セッションを保存
+	GetSession(tokenHash string) (*model.Session, error)                         // トークンのハッシュでセッションを取得
+	DeleteSession(tokenHash string) error                                        // セッションを削除（ログアウト）
+	DeleteExpiredSessions(now time.Time) (int, error)                            // 有効期限切れのセッションを削除
+}
+
+// userRepository はUserRepositoryインターフェースの実装
+type userRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewUserRepository は新しいUserRepositoryを作成する関数
+func NewUserRepository(db *database.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Register は登録済みのユーザー数を check で確認してから、ユーザーを登録する
+// 数える・登録する・（最初のユーザーなら）所有者のない書籍を引き継ぐまでを1つのトランザクションで行い、
+// 同時に登録されても2人が同じ数を見ないようにする（最初のユーザーが2人になって、どちらも管理者になるのを防ぐ）
+// check がエラーを返すと、何も登録せずにそのエラーを返す
+func (r *userRepository) Register(user *model.User, check func(count int) error) (*model.User, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 数える前に書き込みのロックを取り、ほかの登録はこのトランザクションが終わるまで待たせる
+	// PostgreSQL：テーブルのロック（読み取りは妨げない）、SQLite：何も変えない更新でデータベースの書き込みロックを取る
+	lock := "UPDATE users SET id = id WHERE 1 = 0"
+	if r.db.Dialect == database.Postgres {
+		lock = "LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE"
+	}
+	if _, err := tx.Exec(lock); err != nil {
+		return nil, fmt.Errorf("ユーザーの登録の準備に失敗しました: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		return nil, fmt.Errorf("ユーザー数の取得に失敗しました: %w", err)
+	}
+	if err := check(count); err != nil {
+		return nil, err
+	}
+
+	id, err := r.db.InsertReturningID(tx,
+		"INSERT INTO users (username, password_hash, display_name, is_admin) VALUES (?, ?, ?, ?)",
+		user.Username, user.PasswordHash, user.DisplayName, user.IsAdmin,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ユーザーの登録に失敗しました: %w", err)
+	}
+	if count == 0 {
+		// 最初のユーザーは、ユーザー登録前から使っていた書籍（所有者なし）をすべて引き継ぐ
+		if _, err := (&bookRepository{db: r.db, ex: tx}).ClaimUnowned(int(id)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("ユーザーの登録の確定に失敗しました: %w", err)
+	}
+	return r.GetByID(int(id))
+}
+
+// GetByID はIDでユーザーを取得する
+func (r *userRepository) GetByID(id int) (*model.User, error) {
+	return r.get("id = ?", id)
+}
+
+// GetByUsername はユーザー名でユーザーを取得する
+func (r *userRepository) GetByUsername(username string) (*model.User, error) {
+	return r.get("username = ?", username)
+}
+
+// get は条件に一致するユーザーを1件取得する
+func (r *userRepository) get(condition string, arg interface{}) (*model.User, error) {
+	query := "SELECT id, username, password_hash, display_name, is_admin, created_at FROM users WHERE " + condition
+	user := &model.User{}
+	err := r.db.QueryRow(r.db.Rebind(query), arg).Scan(
+		&user.ID, &user.Username, &user.PasswordHash, &user.DisplayName, &user.IsAdmin, &user.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ユーザーが見つかりません")
+		}
+		return nil, fmt.Errorf("ユーザーの取得に失敗しました: %w", err)
+	}
+	return user, nil
+}
+
+// Count は登録済みのユーザー数を返す
+func (r *userRepository) Count() (int, error) {
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		return 0, fmt.Errorf("ユーザー数の取得に失敗しました: %w", err)
+	}
+	return count, nil
+}
+
+// CreateSession はセッションを保存する
+func (r *userRepository) CreateSession(session *model.Session) error {
+	_, err := r.db.Exec(r.db.Rebind("INSERT INTO sessions (token_hash, user_id, expires_at) VALUES (?, ?, ?)"),
+		session.TokenHash, session.UserID, session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("セッションの保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// GetSession はトークンのハッシュでセッションを取得する
+func (r *userRepository) GetSession(tokenHash string) (*model.Session, error) {
+	session := &model.Session{}
+	err := r.db.QueryRow(r.db.Rebind("SELECT token_hash, user_id, expires_at, created_at FROM sessions WHERE token_hash = ?"), tokenHash).Scan(
+		&session.TokenHash, &session.UserID, &session.ExpiresAt, &session.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("セッションが見つかりません")
+		}
+		return nil, fmt.Errorf("セッションの取得に失敗しました: %w", err)
+	}
+	return session, nil
+}
+
+// DeleteSession はセッションを削除する
+func (r *userRepository) DeleteSession(tokenHash string) error {
+	if _, err := r.db.Exec(r.db.Rebind("DELETE FROM sessions WHERE token_hash = ?"), tokenHash); err != nil {
+		return fmt.Errorf("セッションの削除に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions は有効期限切れのセッションを削除し、削除した件数を返す
+func (r *userRepository) DeleteExpiredSessions(now time.Time) (int, error) {
+	result, err := r.db.Exec(r.db.Rebind("DELETE FROM sessions WHERE expires_at <= ?"), now)
+	if err != nil {
+		return 0, fmt.Errorf("期限切れセッションの削除に失敗しました: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("削除結果の確認に失敗しました: %w", err)
+	}
+	return int(n), nil
+}
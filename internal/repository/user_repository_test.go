@@ -0,0 +1,105 @@
+package repository_test
+
+import (
+	"errors"  // check が返したエラーの確認
+	"fmt"     // ユーザー名の作成
+	"sync"    // 同時に登録するゴルーチンの待ち合わせ
+	"testing" // テストの実行と結果の報告
+	"time"    // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // テスト対象のリポジトリ
+)
+
+// TestUserRepositoryRegister は登録済みのユーザー数の確認と登録、最初のユーザーへの書籍の引き継ぎを確認する
+func TestUserRepositoryRegister(t *testing.T) {
+	t.Run("SQLite", func(t *testing.T) { testUserRegister(t, openSQLite(t)) })
+	t.Run("Postgres", func(t *testing.T) { testUserRegister(t, openPostgres(t)) })
+}
+
+// TestUserRepositoryRegisterConcurrent は同時に登録しても、最初のユーザー（count が0）になるのが1人だけであることを確認する
+func TestUserRepositoryRegisterConcurrent(t *testing.T) {
+	t.Run("SQLite", func(t *testing.T) { testUserRegisterConcurrent(t, openSQLite(t)) })
+	t.Run("Postgres", func(t *testing.T) { testUserRegisterConcurrent(t, openPostgres(t)) })
+}
+
+func testUserRegister(t *testing.T, db *database.DB) {
+	books := repository.NewBookRepository(db)
+	users := repository.NewUserRepository(db)
+	if _, err := books.Create(&model.CreateBookRequest{Title: "登録前の本", Author: "著者", PurchaseDate: time.Now().UTC()}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// check がエラーを返したら何も登録しない
+	errStop := errors.New("登録しない")
+	if _, err := users.Register(&model.User{Username: "stop", PasswordHash: "x"}, func(int) error { return errStop }); !errors.Is(err, errStop) {
+		t.Fatalf("Register = %v, want %v", err, errStop)
+	}
+	if n, _ := users.Count(); n != 0 {
+		t.Fatalf("取り消した登録が残っています: %d人", n)
+	}
+
+	counts := []int{}
+	for _, name := range []string{"alice", "bob"} {
+		user := &model.User{Username: name, PasswordHash: "x"}
+		if _, err := users.Register(user, func(count int) error {
+			counts = append(counts, count)
+			user.IsAdmin = count == 0
+			return nil
+		}); err != nil {
+			t.Fatalf("Register(%s): %v", name, err)
+		}
+	}
+	if fmt.Sprint(counts) != "[0 1]" {
+		t.Errorf("check に渡した数 = %v, want [0 1]", counts)
+	}
+
+	alice, err := users.GetByUsername("alice")
+	if err != nil {
+		t.Fatalf("GetByUsername: %v", err)
+	}
+	if !alice.IsAdmin {
+		t.Errorf("最初のユーザーが管理者になっていません")
+	}
+	if n, _ := books.WithOwner(alice.ID).Count(nil); n != 1 {
+		t.Errorf("最初のユーザーの書籍 = %d冊, want 1（所有者のない書籍を引き継ぐ）", n)
+	}
+	if n, _ := books.WithOwner(0).Count(nil); n != 0 {
+		t.Errorf("所有者のない書籍が残っています: %d冊", n)
+	}
+}
+
+func testUserRegisterConcurrent(t *testing.T, db *database.DB) {
+	users := repository.NewUserRepository(db)
+	const n = 10
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		firsts int
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// SQLiteでは書き込みのロックを取れなかった登録はエラーになる（2人目の「最初のユーザー」にはならない）
+			users.Register(&model.User{Username: fmt.Sprintf("user%d", i), PasswordHash: "x"}, func(count int) error {
+				if count == 0 {
+					mu.Lock()
+					firsts++
+					mu.Unlock()
+				}
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	registered, err := users.Count()
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if registered == 0 || firsts != 1 {
+		t.Errorf("最初のユーザーとして登録した数 = %d（登録できたのは%d人）, want 1", firsts, registered)
+	}
+}
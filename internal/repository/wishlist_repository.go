@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql" // データベース操作の基本機能
+	"fmt"          // エラーメッセージの作成
+	"time"         // 更新日時・購入日時
+
+	"book-manager/internal/database" // 自作のデータベース接続機能
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// WishlistRepository は欲しい本リストと価格の履歴の永続化を担当するインターフェース
+// 他のユーザーの欲しい本は「見つからない」として扱うため、取得・更新・削除には所有者のIDを渡す
+type WishlistRepository interface {
+	Create(item *model.WishlistItem) (*model.WishlistItem, error)           // 欲しい本を登録
+	GetByID(ownerID, id int) (*model.WishlistItem, error)                   // IDで欲しい本を取得
+	List(ownerID int, includePurchased bool) ([]*model.WishlistItem, error) // 欲しい本を優先度順に取得
+	Update(item *model.WishlistItem) (*model.WishlistItem, error)           // 欲しい本を更新
+	Delete(ownerID, id int) error                                           // 欲しい本を削除（価格の履歴も削除）
+	MarkPurchased(id, bookID int, purchasedAt time.Time) error              // 購入済みにして、作成した書籍を記録
+	AddPrice(record *model.PriceRecord) (*model.PriceRecord, error)         // 価格を記録
+	ListPrices(itemID int) ([]*model.PriceRecord, error)                    // 価格の履歴を新しい順に取得
+}
+
+// wishlistRepository はWishlistRepositoryインターフェースの実装
+type wishlistRepository struct {
+	db *database.DB // データベース接続オブジェクト
+}
+
+// NewWishlistRepository は新しいWishlistRepositoryを作成する関数
+func NewWishlistRepository(db *database.DB) WishlistRepository {
+	return &wishlistRepository{db: db}
+}
+
+// wishlistSelect は欲しい本を取得するときのSELECT文（最後に記録した価格も一緒に取得する）
+const wishlistSelect = `SELECT w.id, COALESCE(w.owner_id, 0), w.title, w.author, w.isbn, w.publisher, w.priority, w.target_price,
+		w.where_seen, w.notes, w.tags, w.book_id, w.purchased_at, w.created_at, w.updated_at,
+		(SELECT p.price FROM price_records p WHERE p.item_id = w.id ORDER BY p.recorded_at DESC, p.id DESC LIMIT 1)
+	FROM wishlist_items w`
+
+// ownerWhere は所有者で絞り込むWHERE句を返す（0は owner_id が NULL の共有の本棚）
+func ownerWhere(column string, ownerID int) (string, []interface{}) {
+	if ownerID > 0 {
+		return column + " = ?", []interface{}{ownerID}
+	}
+	return column + " IS NULL", nil
+}
+
+// Create は欲しい本を登録する
+func (r *wishlistRepository) Create(item *model.WishlistItem) (*model.WishlistItem, error) {
+	id, err := r.db.InsertReturningID(r.db,
+		`INSERT INTO wishlist_items (owner_id, title, author, isbn, publisher, priority, target_price, where_seen, notes, tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		ownerValue(item.OwnerID), item.Title, item.Author, item.ISBN, item.Publisher, item.Priority, item.TargetPrice,
+		item.WhereSeen, item.Notes, item.Tags,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("欲しい本の保存に失敗しました: %w", err)
+	}
+	return r.GetByID(item.OwnerID, int(id))
+}
+
+// GetByID はIDで欲しい本を取得する
+func (r *wishlistRepository) GetByID(ownerID, id int) (*model.WishlistItem, error) {
+	cond, args := ownerWhere("w.owner_id", ownerID)
+	row := r.db.QueryRow(r.db.Rebind(wishlistSelect+" WHERE w.id = ? AND "+cond), append([]interface{}{id}, args...)...)
+	item, err := scanWishlistItem(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ID %d の欲しい本が見つかりません", id)
+		}
+		return nil, fmt.Errorf("欲しい本の取得に失敗しました: %w", err)
+	}
+	return item, nil
+}
+
+// List は欲しい本を優先度順（同じ優先度なら新しい順）に取得する
+// includePurchased が false の場合は、まだ購入していない本だけを返す
+func (r *wishlistRepository) List(ownerID int, includePurchased bool) ([]*model.WishlistItem, error) {
+	cond, args := ownerWhere("w.owner_id", ownerID)
+	if !includePurchased {
+		cond += " AND w.purchased_at IS NULL"
+	}
+	rows, err := r.db.Query(r.db.Rebind(wishlistSelect+" WHERE "+cond+" ORDER BY w.priority, w.created_at DESC, w.id DESC"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("欲しい本リストの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*model.WishlistItem{}
+	for rows.Next() {
+		item, err := scanWishlistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("欲しい本データの読み取りに失敗しました: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("欲しい本リストの取得に失敗しました: %w", err)
+	}
+	return items, nil
+}
+
+// Update は欲しい本を更新する（購入の状態は MarkPurchased で更新する）
+func (r *wishlistRepository) Update(item *model.WishlistItem) (*model.WishlistItem, error) {
+	_, err := r.db.Exec(r.db.Rebind(`UPDATE wishlist_items SET title = ?, author = ?, isbn = ?, publisher = ?, priority = ?,
+		target_price = ?, where_seen = ?, notes = ?, tags = ?, updated_at = ? WHERE id = ?`),
+		item.Title, item.Author, item.ISBN, item.Publisher, item.Priority,
+		item.TargetPrice, item.WhereSeen, item.Notes, item.Tags, time.Now(), item.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("欲しい本の更新に失敗しました: %w", err)
+	}
+	return r.GetByID(item.OwnerID, item.ID)
+}
+
+// Delete は欲しい本とその価格の履歴を削除する
+// 価格の履歴は外部キーの ON DELETE CASCADE でも消えるが、どのデータベースでも同じ結果になるよう明示的に削除する
+func (r *wishlistRepository) Delete(ownerID, id int) error {
+	if _, err := r.GetByID(ownerID, id); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(r.db.Rebind("DELETE FROM price_records WHERE item_id = ?"), id); err != nil {
+		return fmt.Errorf("価格の履歴の削除に失敗しました: %w", err)
+	}
+	if _, err := tx.Exec(r.db.Rebind("DELETE FROM wishlist_items WHERE id = ?"), id); err != nil {
+		return fmt.Errorf("欲しい本の削除に失敗しました: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// MarkPurchased は欲しい本を購入済みにして、作成した書籍を記録する
+func (r *wishlistRepository) MarkPurchased(id, bookID int, purchasedAt time.Time) error {
+	_, err := r.db.Exec(r.db.Rebind("UPDATE wishlist_items SET book_id = ?, purchased_at = ?, updated_at = ? WHERE id = ?"),
+		bookID, purchasedAt, time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("購入の記録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// AddPrice は価格を記録する
+func (r *wishlistRepository) AddPrice(record *model.PriceRecord) (*model.PriceRecord, error) {
+	id, err := r.db.InsertReturningID(r.db,
+		"INSERT INTO price_records (item_id, price, source, url, recorded_at) VALUES (?, ?, ?, ?, ?)",
+		record.ItemID, record.Price, record.Source, record.URL, record.RecordedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("価格の記録に失敗しました: %w", err)
+	}
+	saved := *record
+	saved.ID = int(id)
+	return &saved, nil
+}
+
+// ListPrices は価格の履歴を新しい順に取得する
+func (r *wishlistRepository) ListPrices(itemID int) ([]*model.PriceRecord, error) {
+	rows, err := r.db.Query(r.db.Rebind(
+		"SELECT id, item_id, price, source, url, recorded_at FROM price_records WHERE item_id = ? ORDER BY recorded_at DESC, id DESC"), itemID)
+	if err != nil {
+		return nil, fmt.Errorf("価格の履歴の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*model.PriceRecord{}
+	for rows.Next() {
+		record := &model.PriceRecord{}
+		if err := rows.Scan(&record.ID, &record.ItemID, &record.Price, &record.Source, &record.URL, &record.RecordedAt); err != nil {
+			return nil, fmt.Errorf("価格データの読み取りに失敗しました: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("価格の履歴の取得に失敗しました: %w", err)
+	}
+	return records, nil
+}
+
+// scanWishlistItem は1行分の欲しい本を読み取り、目標価格以下かどうかを計算する
+func scanWishlistItem(row rowScanner) (*model.WishlistItem, error) {
+	item := &model.WishlistItem{}
+	err := row.Scan(&item.ID, &item.OwnerID, &item.Title, &item.Author, &item.ISBN, &item.Publisher, &item.Priority, &item.TargetPrice,
+		&item.WhereSeen, &item.Notes, &item.Tags, &item.BookID, &item.PurchasedAt, &item.CreatedAt, &item.UpdatedAt,
+		&item.LatestPrice)
+	if err != nil {
+		return nil, err
+	}
+	item.BelowTarget = item.LatestPrice != nil && item.TargetPrice != nil && *item.LatestPrice <= *item.TargetPrice
+	return item, nil
+}
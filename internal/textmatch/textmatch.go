@@ -0,0 +1,193 @@
+// textmatchパッケージ：書籍のタイトル・著者・ISBNを表記の揺れを無視して比べるファイル
+// 電子書籍リーダーのハイライトと書籍の結びつけや、重複した書籍の検出で使う
+package textmatch
+
+import (
+	"sort"    // 著者名の語の並べ替え
+	"strings" // 文字列操作
+	"unicode" // 文字の種類の判定
+)
+
+// BaseTitle は副題と括弧書きを除いたタイトルを返す関数
+// 例：「リーダブルコード ―より良いコードを書くための…（O'Reilly Japan）」→「リーダブルコード」
+func BaseTitle(title string) string {
+	for _, sep := range []string{":", "：", " - ", " ― ", "―", " — ", "〜", "~"} {
+		if i := strings.Index(title, sep); i > 0 {
+			title = title[:i]
+		}
+	}
+	for _, pair := range [][2]string{{"(", ")"}, {"（", "）"}, {"[", "]"}, {"【", "】"}, {"〔", "〕"}} {
+		for {
+			start := strings.Index(title, pair[0])
+			end := strings.Index(title, pair[1])
+			if start < 0 || end < start {
+				break
+			}
+			title = title[:start] + title[end+len(pair[1]):]
+		}
+	}
+	return title
+}
+
+// Normalize は比べるために表記をそろえる関数
+// 全角の英数字を半角に、半角カタカナを全角に、カタカナをひらがなにし、小文字にして、空白と記号を除く
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range foldKana(s) {
+		r = fold(r)
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// NormalizeAuthor は著者名の表記をそろえる関数
+// 「Orwell, George」と「George Orwell」を同じにするため、語に分けて並べ替えてからつなげる
+// 複数の著者（「;」「&」「、」区切り）は最初の著者だけを使う
+func NormalizeAuthor(s string) string {
+	for _, sep := range []string{";", "&", "、", " and "} {
+		if i := strings.Index(s, sep); i > 0 {
+			s = s[:i]
+		}
+	}
+	words := strings.FieldsFunc(foldKana(s), func(r rune) bool {
+		r = fold(r)
+		return unicode.IsSpace(r) || r == ',' || r == '.' || r == '・'
+	})
+	for i, word := range words {
+		words[i] = Normalize(word)
+	}
+	sort.Strings(words)
+	return strings.Join(words, "")
+}
+
+// NormalizeISBN はISBNをハイフンなしの13桁にそろえる関数
+// 10桁のISBNは978を付けた13桁に直す（チェックディジットは計算し直す）。ISBNとして読めなければ空文字
+func NormalizeISBN(s string) string {
+	digits := make([]byte, 0, 13)
+	for _, r := range s {
+		r = fold(r)
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, byte(r))
+		case (r == 'X' || r == 'x') && len(digits) == 9:
+			digits = append(digits, 'X') // 10桁のISBNのチェックディジットだけは X がある
+		case r == '-' || r == ' ':
+		default:
+			return ""
+		}
+	}
+	switch len(digits) {
+	case 13:
+		return string(digits)
+	case 10:
+		isbn := append([]byte("978"), digits[:9]...)
+		sum := 0
+		for i, d := range isbn {
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += int(d-'0') * weight
+		}
+		return string(append(isbn, byte('0'+(10-sum%10)%10)))
+	}
+	return ""
+}
+
+// Contains はどちらかがもう片方を含むかを判定する関数（4文字未満の短い文字列は偶然の一致が多いため対象外）
+func Contains(a, b string) bool {
+	shorter, longer := a, b
+	if len([]rune(a)) > len([]rune(b)) {
+		shorter, longer = b, a
+	}
+	return len([]rune(shorter)) >= 4 && strings.Contains(longer, shorter)
+}
+
+// Similarity はレーベンシュタイン距離（1文字の追加・削除・置き換えの回数）から、2つの文字列の似ている度合い（0〜1）を計算する関数
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// fold は全角の英数字・記号と全角の空白を半角にする関数
+func fold(r rune) rune {
+	switch {
+	case r >= '！' && r <= '～':
+		return r - 0xFEE0
+	case r == '　':
+		return ' '
+	}
+	return r
+}
+
+// halfWidthKana は半角カタカナ（U+FF66〜U+FF9D）に対応する全角カタカナ
+var halfWidthKana = []rune("ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン")
+
+// foldKana はカタカナの表記の揺れをそろえる関数
+// 半角カタカナは全角にし（濁点・半濁点は前の文字と合わせる）、カタカナはひらがなにする
+// 例：「ﾘｰﾀﾞﾌﾞﾙ」「リーダブル」→「りーだぶる」
+func foldKana(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r >= 'ｦ' && r <= 'ﾝ' {
+			r = halfWidthKana[r-'ｦ']
+			if i+1 < len(runes) {
+				switch next := runes[i+1]; {
+				case next == 'ﾞ' && voiced(r):
+					r, i = r+1, i+1
+				case next == 'ﾞ' && r == 'ウ':
+					r, i = 'ヴ', i+1
+				case next == 'ﾟ' && r >= 'ハ' && r <= 'ホ' && (r-'ハ')%3 == 0:
+					r, i = r+2, i+1
+				}
+			}
+		}
+		switch {
+		case r == 'ﾞ' || r == 'ﾟ':
+			continue // 前の文字と合わせられなかった濁点・半濁点
+		case r == 'ヴ':
+			r = 'ゔ'
+		case r >= 'ァ' && r <= 'ヶ':
+			r -= 'ァ' - 'ぁ'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// voiced は濁点を付けられるカタカナ（カ行・サ行・タ行・ハ行）かどうかを判定する関数
+// 全角カタカナでは濁点付きの文字が直後の文字コードにある（ハ行は ハ バ パ の3つおき）
+func voiced(r rune) bool {
+	switch {
+	case r >= 'カ' && r <= 'ヂ':
+		return (r-'カ')%2 == 0
+	case r == 'ツ' || r == 'テ' || r == 'ト':
+		return true
+	case r >= 'ハ' && r <= 'ホ':
+		return (r-'ハ')%3 == 0
+	}
+	return false
+}
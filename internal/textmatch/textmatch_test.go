@@ -0,0 +1,142 @@
+package textmatch
+
+import (
+	"math"    // 一致度の比較
+	"testing" // テストの実行と結果の報告
+)
+
+// TestBaseTitle は副題と括弧書きが除かれることを確認する
+func TestBaseTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"リーダブルコード ―より良いコードを書くためのシンプルで実践的なテクニック", "リーダブルコード "}, // 残った空白は Normalize で除く
+		{"Clean Code: A Handbook of Agile Software Craftsmanship", "Clean Code"},
+		{"吾輩は猫である（新潮文庫）", "吾輩は猫である"},
+		{"プログラミング言語Go (ADDISON-WESLEY PROFESSIONAL COMPUTING SERIES)", "プログラミング言語Go "},
+		{"【新装版】沈黙の春", "沈黙の春"},
+		{"ノルウェイの森 上", "ノルウェイの森 上"},
+		{":先頭の区切りは除かない", ":先頭の区切りは除かない"},
+		{"閉じていない（括弧", "閉じていない（括弧"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := BaseTitle(tt.title); got != tt.want {
+				t.Errorf("BaseTitle(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNormalize は全角・半角、カタカナ・ひらがな、大文字・小文字、空白と記号の違いがそろえられることを確認する
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"Ｇｏ言語", "go言語"},
+		{"ﾘｰﾀﾞﾌﾞﾙｺｰﾄﾞ", "リーダブルコード"},
+		{"リーダブルコード", "りーだぶるこーど"},
+		{"ﾊﾟｰﾌｪｸﾄ", "パーフェクト"},
+		{"ｳﾞｧｲｵﾘﾝ", "ヴァイオリン"},
+		{"Clean　Code!", "cleancode"},
+		{"C++ & Go", "cgo"},
+		{"１２３", "123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a, func(t *testing.T) {
+			if a, b := Normalize(tt.a), Normalize(tt.b); a != b {
+				t.Errorf("Normalize(%q) = %q, Normalize(%q) = %q, want 同じ", tt.a, a, tt.b, b)
+			}
+		})
+	}
+}
+
+// TestNormalizeAuthor は姓名の順と区切り、複数の著者の違いがそろえられることを確認する
+func TestNormalizeAuthor(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"Orwell, George", "George Orwell"},
+		{"Robert C. Martin", "Martin, Robert C"},
+		{"夏目 漱石", "夏目漱石"},
+		{"ロバート・C・マーチン", "マーチン ロバート C"},
+		{"Kent Beck; Martin Fowler", "Beck, Kent"},
+		{"山田 太郎、佐藤 花子", "太郎 山田"},
+		{"Kent Beck and Cynthia Andres", "Kent Beck"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a, func(t *testing.T) {
+			if a, b := NormalizeAuthor(tt.a), NormalizeAuthor(tt.b); a != b {
+				t.Errorf("NormalizeAuthor(%q) = %q, NormalizeAuthor(%q) = %q, want 同じ", tt.a, a, tt.b, b)
+			}
+		})
+	}
+}
+
+// TestNormalizeISBN はISBNがハイフンなしの13桁にそろえられ、ISBNとして読めなければ空文字になることを確認する
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		isbn string
+		want string
+	}{
+		{"978-4-87311-565-8", "9784873115658"},
+		{"4873115655", "9784873115658"}, // 10桁は978を付けてチェックディジットを計算し直す
+		{"4-87311-565-5", "9784873115658"},
+		{"０３０６４０６１５２", "9780306406157"}, // 全角の数字
+		{"080442957X", "9780804429573"}, // 10桁のチェックディジットの X
+		{"X804429570", ""},              // X は10桁目にしか置けない
+		{"ISBN 4873115655", ""},         // 数字以外の文字
+		{"12345", ""},                   // 桁数が違う
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.isbn, func(t *testing.T) {
+			if got := NormalizeISBN(tt.isbn); got != tt.want {
+				t.Errorf("NormalizeISBN(%q) = %q, want %q", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestContains は短い文字列の偶然の一致を除いて、片方がもう片方を含むかを判定することを確認する
+func TestContains(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"cleancode", "cleancodeahandbook", true},
+		{"cleancodeahandbook", "cleancode", true},
+		{"りーだぶるこーど", "りーだぶる", true},
+		{"go", "goげんご", false}, // 4文字未満
+		{"abcd", "abce", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Contains(tt.a, tt.b); got != tt.want {
+				t.Errorf("Contains(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSimilarity はレーベンシュタイン距離から似ている度合いを計算することを確認する
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"同じ文字列", "同じ文字列", 1},
+		{"あいうえ", "あいうお", 0.75}, // 文字数はバイトではなく文字で数える
+		{"abc", "", 0},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
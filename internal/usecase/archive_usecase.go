@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"io" // 読み書きの抽象化
+
+	"book-manager/internal/archive"    // アーカイブの書き出し・取り込み
+	"book-manager/internal/repository" // 自作のデータアクセス層
+)
+
+// ArchiveUsecase はインスタンス間の移行用アーカイブを扱うビジネスロジックのインターフェース
+type ArchiveUsecase interface {
+	Export(w io.Writer) (*archive.Manifest, error)                                  // 全データをアーカイブとして書き出す
+	Import(r io.ReaderAt, size int64, policy string) (*archive.ImportResult, error) // アーカイブからデータを取り込む
+	ForUser(userID int) ArchiveUsecase                                              // 指定したユーザーの本棚だけを扱うユースケースを返す
+}
+
+// archiveUsecase はArchiveUsecaseインターフェースの実装
+type archiveUsecase struct {
+	bookRepo    repository.BookRepository    // 書籍データの読み書き
+	relatedRepo repository.ArchiveRepository // 書籍の関連データ・場所・欲しい本・読書目標の読み書き（nilなら書籍だけを扱う）
+	ownerID     int                          // 扱う本棚（repository.AllOwners ならすべての本棚）
+}
+
+// NewArchiveUsecase は新しいArchiveUsecaseを作成する関数
+// relatedRepo が nil（エフェメラルモード）なら書籍だけを書き出し・取り込みする
+func NewArchiveUsecase(bookRepo repository.BookRepository, relatedRepo repository.ArchiveRepository) ArchiveUsecase {
+	return &archiveUsecase{bookRepo: bookRepo, relatedRepo: relatedRepo, ownerID: repository.AllOwners}
+}
+
+// ForUser は指定したユーザーの本棚だけを書き出し・取り込みするユースケースを返す
+func (u *archiveUsecase) ForUser(userID int) ArchiveUsecase {
+	return &archiveUsecase{bookRepo: u.bookRepo.WithOwner(userID), relatedRepo: u.relatedRepo, ownerID: userID}
+}
+
+// store は書き出し・取り込みの対象を返す
+func (u *archiveUsecase) store() archive.Store {
+	return archive.Store{Books: u.bookRepo, Related: u.relatedRepo, OwnerID: u.ownerID}
+}
+
+// Export は全データをアーカイブ（ZIP）として書き出す
+func (u *archiveUsecase) Export(w io.Writer) (*archive.Manifest, error) {
+	return archive.Export(u.store(), w)
+}
+
+// Import はアーカイブからデータを取り込む
+// policy：同じIDの書籍が既にある場合の扱い（skip、overwrite、duplicate）
+func (u *archiveUsecase) Import(r io.ReaderAt, size int64, policy string) (*archive.ImportResult, error) {
+	conflictPolicy, err := archive.ParsePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	return archive.Import(u.store(), r, size, conflictPolicy)
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"fmt" // エラーメッセージの作成
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// AuditUsecase は変更履歴のビジネスロジックを定義するインターフェース
+type AuditUsecase interface {
+	List(filter *model.AuditFilter, page, limit int) ([]*model.AuditLog, int, error)                    // 変更履歴を新しい順に取得
+	ListForBook(userID, bookID int, page, limit int) ([]*model.AuditLog, int, error)                    // 1冊の書籍の変更履歴を新しい順に取得
+	Revert(userID int, requestID string, bookID int, req *model.RevertBookRequest) (*model.Book, error) // 書籍を変更履歴の版に戻す
+}
+
+// auditUsecase はAuditUsecaseインターフェースの実装
+type auditUsecase struct {
+	auditRepo   repository.AuditRepository // 変更履歴の保存先
+	bookUsecase BookUsecase                // 書籍の権限の確認と版の復元
+	validator   *validator.Validate        // 入力データ検証用のバリデータ
+}
+
+// NewAuditUsecase は新しいAuditUsecaseを作成する関数
+func NewAuditUsecase(auditRepo repository.AuditRepository, bookUsecase BookUsecase) AuditUsecase {
+	return &auditUsecase{auditRepo: auditRepo, bookUsecase: bookUsecase, validator: validator.New()}
+}
+
+// List は変更履歴を新しい順に取得する（ページネーション対応）
+// filter.OwnerID の人のデータの履歴だけが対象になる（共有相手による変更も含む）
+// 各履歴には、変更前後のデータから計算した項目ごとの変更を付ける
+func (u *auditUsecase) List(filter *model.AuditFilter, page, limit int) ([]*model.AuditLog, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	entries, err := u.auditRepo.List(filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := u.auditRepo.Count(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, entry := range entries {
+		entry.Changes = entry.Diff()
+	}
+	return entries, total, nil
+}
+
+// ListForBook は1冊の書籍の変更履歴を新しい順に取得する
+// 閲覧できる書籍（自分の書籍と共有された書籍）の履歴は、共有相手による変更も含めて持ち主の履歴から探す
+// ゴミ箱の書籍など閲覧できない書籍は、自分の本棚の履歴にあればそれを返す
+func (u *auditUsecase) ListForBook(userID, bookID int, page, limit int) ([]*model.AuditLog, int, error) {
+	filter := &model.AuditFilter{OwnerID: userID, EntityType: model.EntityBook, EntityID: bookID}
+	book, authErr := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleViewer)
+	if authErr == nil {
+		filter.OwnerID = book.OwnerID
+	}
+
+	entries, total, err := u.List(filter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if authErr != nil && total == 0 {
+		return nil, 0, authErr
+	}
+	return entries, total, nil
+}
+
+// Revert は書籍を変更履歴の版に戻す
+func (u *auditUsecase) Revert(userID int, requestID string, bookID int, req *model.RevertBookRequest) (*model.Book, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	return u.bookUsecase.ForUser(userID).WithRequestID(requestID).RevertBook(bookID, req.AuditID)
+}
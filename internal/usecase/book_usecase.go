@@ -4,9 +4,14 @@ package usecase
 
 // import：他のパッケージ（機能）を使うための宣言
 import (
+	"encoding/json"                             // 変更履歴に保存する書籍データのJSON変換
+	"errors"                                    // エラーの定義
 	"fmt"                                       // 文字列フォーマット（エラーメッセージ作成など）
+	"log"                                       // 変更履歴の記録失敗のログ出力
+	"strings"                                   // 通貨コードの空文字の判定
 	"time"                                      // 時間関連の処理
 
+	"book-manager/internal/currency"             // 通貨コードの確認と換算
 	"book-manager/internal/model"                // 自作のデータ構造定義
 	"book-manager/internal/repository"           // 自作のデータアクセス層
 	"github.com/go-playground/validator/v10"   // 入力データのバリデーション（検証）ライブラリ
@@ -19,10 +24,18 @@ type BookUsecase interface {
 	GetBook(id int) (*model.Book, error)                                     // IDで書籍を1件取得
 	ListBooks(filter *model.BookFilter, page, limit int) ([]*model.Book, int, error) // 書籍一覧をページング付きで取得
 	UpdateBook(id int, req *model.UpdateBookRequest) (*model.Book, error)    // 書籍情報を更新
-	DeleteBook(id int) error                                                 // 書籍を削除
+	DeleteBook(id int) error                                                 // 書籍をゴミ箱に移す
+	RestoreBook(id int) (*model.Book, error)                                 // ゴミ箱の書籍を元に戻す
+	RevertBook(id, auditID int) (*model.Book, error)                         // 書籍を変更履歴の版に戻す
 	StartReading(id int) (*model.Book, error)                                // 読書を開始（ステータス変更）
 	FinishReading(id int, rating *int) (*model.Book, error)                  // 読書を完了（評価付き）
 	GetStatistics() (*BookStatistics, error)                                // 統計情報（合計金額、平均評価など）を取得
+	ForUser(userID int) BookUsecase                                          // 指定したユーザーの本棚だけを扱うユースケースを返す
+	WithRequestID(requestID string) BookUsecase                              // 変更履歴にAPIリクエストのIDを記録するユースケースを返す
+	Authorize(id int, required model.ShareRole) (*model.Book, error)         // 書籍に対して役割が必要な操作をしてよいかを確認
+	MoveBook(id int, locationID *int) (*model.Book, error)                   // 書籍の保管場所を変更（nilは場所の設定を外す）
+	Transaction(fn func(tx BookUsecase) error) error                         // fn の中の書籍の操作を1つのトランザクションで行う
+	repository() repository.BookRepository                                   // 書籍のリポジトリ（Transaction の中では同じトランザクションを使う）
 }
 
 // BookStatistics は書籍の統計情報を表す構造体
@@ -33,28 +46,201 @@ type BookStatistics struct {
 	ReadingBooks       int      `json:"reading_books"`        // 読書中の書籍数
 	CompletedBooks     int      `json:"completed_books"`      // 読了済みの書籍数
 	DroppedBooks       int      `json:"dropped_books"`        // 中断した書籍数
-	TotalSpent         int      `json:"total_spent"`          // 総支出金額（円）
+	TotalSpent         int      `json:"total_spent"`          // 総支出金額（基準通貨の最小単位）
+	Currency           string   `json:"currency"`             // 金額の通貨（基準通貨）
+	UnconvertedBooks   int      `json:"unconverted_books"`    // 為替レートがなく総支出金額に含めなかった書籍数
 	AverageRating      *float64 `json:"average_rating"`       // 平均評価（nullの可能性あり）
 	BooksThisMonth     int      `json:"books_this_month"`     // 今月購入した書籍数
 	CompletedThisMonth int      `json:"completed_this_month"` // 今月読了した書籍数
 }
 
+// ErrPermissionDenied は共有された書籍に対して、役割で許可されていない操作をした場合のエラー
+// 例：閲覧者（viewer）として共有された書籍を更新しようとした
+var ErrPermissionDenied = errors.New("この書籍を変更する権限がありません")
+
 // bookUsecase はBookUsecaseインターフェースの実装
 // リポジトリとバリデータを保持して、ビジネスロジックを実行
 type bookUsecase struct {
-	bookRepo  repository.BookRepository // データアクセス用のリポジトリ
-	validator *validator.Validate       // 入力データ検証用のバリデータ
+	bookRepo  repository.BookRepository       // データアクセス用のリポジトリ（ForUser後はそのユーザーの本棚に限定）
+	rootRepo  repository.BookRepository       // 本棚に限定する前のリポジトリ（共有された書籍の取得に使う）
+	shareRepo repository.ShareRepository      // 本棚の共有設定（nilなら共有機能なし）
+	auditRepo repository.AuditRepository      // 変更履歴の記録先（nilなら記録しない）
+	prices    PriceConverter                  // 購入価格の基準通貨への換算（nilなら円だけを集計する）
+	statsRepo repository.StatisticsRepository // 統計情報のデータベースでの集計（nilなら書籍を読み込んで集計する）
+	userID    int                             // 操作しているユーザーのID（0は未ログイン）
+	requestID string                          // 操作しているAPIリクエストのID（変更履歴に記録する。空文字はコマンドなど）
+	scoped    bool                            // ForUser でユーザーの本棚に限定しているか
+	pending   *[]*model.AuditLog              // Transaction の中で記録を待っている変更履歴（nilならすぐに記録する）
+	validator *validator.Validate             // 入力データ検証用のバリデータ
 }
 
 // NewBookUsecase は新しいBookUsecaseを作成する関数
 // コンストラクタ関数：依存関係を注入してインスタンスを作成
-func NewBookUsecase(bookRepo repository.BookRepository) BookUsecase {
+// shareRepo と auditRepo は nil でもよい（エフェメラルモードなど、共有・変更履歴を使わない場合）
+// prices も nil でもよい（円以外の価格は統計の金額に含めない）
+// statsRepo も nil でもよい（エフェメラルモードなど。統計情報は書籍を読み込んでGoで集計する）
+func NewBookUsecase(bookRepo repository.BookRepository, shareRepo repository.ShareRepository, auditRepo repository.AuditRepository, prices PriceConverter, statsRepo repository.StatisticsRepository) BookUsecase {
 	return &bookUsecase{
 		bookRepo:  bookRepo,        // リポジトリを設定
+		rootRepo:  bookRepo,        // 共有された書籍の取得用
+		shareRepo: shareRepo,       // 共有設定
+		auditRepo: auditRepo,       // 変更履歴
+		prices:    prices,          // 通貨の換算
+		statsRepo: statsRepo,       // 統計情報の集計
 		validator: validator.New(), // バリデータの新しいインスタンスを作成
 	}
 }
 
+// ForUser は指定したユーザーの本棚だけを扱うユースケースを返す関数
+// 書籍の一覧・作成・統計はそのユーザーの書籍が対象になる（userID 0 は共有の本棚）
+// 書籍の取得・更新・削除は、他のユーザーから共有された書籍も役割に応じて扱える
+func (u *bookUsecase) ForUser(userID int) BookUsecase {
+	return &bookUsecase{
+		bookRepo:  u.rootRepo.WithOwner(userID), // ユーザーの本棚に限定したリポジトリ
+		rootRepo:  u.rootRepo,
+		shareRepo: u.shareRepo,
+		auditRepo: u.auditRepo,
+		prices:    u.prices,
+		statsRepo: u.statsRepo,
+		userID:    userID,
+		requestID: u.requestID,
+		scoped:    true,
+		pending:   u.pending,   // トランザクションの中なら、変更履歴も一緒に確定する
+		validator: u.validator, // バリデータは共有する
+	}
+}
+
+// WithRequestID は変更履歴に requestID を記録するユースケースを返す関数
+// 1つのAPIリクエストでどの変更が行われたかを、変更履歴から探せるようにする
+func (u *bookUsecase) WithRequestID(requestID string) BookUsecase {
+	c := *u
+	c.requestID = requestID
+	return &c
+}
+
+// access は書籍を操作してよいかを確認し、操作に使うリポジトリと現在の書籍を返す関数
+// ビジネスルール：
+//   - 自分の本棚の書籍はすべての操作ができる
+//   - 他のユーザーの書籍は、共有設定の範囲内で、役割（viewer：閲覧、editor：更新・削除）に応じた操作だけができる
+//   - 共有されていない書籍は「見つからない」として扱う（存在を知られないようにするため）
+func (u *bookUsecase) access(id int, required model.ShareRole) (repository.BookRepository, *model.Book, error) {
+	if id <= 0 {
+		return nil, nil, fmt.Errorf("無効な書籍IDです: %d", id)
+	}
+
+	book, err := u.bookRepo.GetByID(id)
+	if err == nil {
+		return u.bookRepo, book, nil
+	}
+	if u.shareRepo == nil || u.userID <= 0 {
+		return nil, nil, err
+	}
+
+	// 他のユーザーの書籍かどうかを確認し、その持ち主から自分への共有設定を探す
+	shared, getErr := u.rootRepo.GetByID(id)
+	if getErr != nil || shared.OwnerID <= 0 {
+		return nil, nil, err
+	}
+	grants, grantErr := u.shareRepo.ListGrants(shared.OwnerID, u.userID)
+	if grantErr != nil {
+		return nil, nil, grantErr
+	}
+	covered := false
+	for _, grant := range grants {
+		if !grant.Covers(shared) {
+			continue
+		}
+		if grant.Role.Includes(required) {
+			return u.rootRepo.WithOwner(shared.OwnerID), shared, nil
+		}
+		covered = true
+	}
+	if covered {
+		// 閲覧はできるが、役割が足りない
+		return nil, nil, ErrPermissionDenied
+	}
+	return nil, nil, err
+}
+
+// Transaction は fn の中の書籍の操作を1つのトランザクションで行う関数
+// fn に渡すユースケースは同じユーザー・同じリクエストIDで、検証と権限の確認も通常の操作と同じ
+// fn がエラーを返すとすべての変更を取り消す。変更履歴は確定したあとにまとめて記録する
+// （トランザクションの途中で別の接続から書き込むと、SQLiteではロックの待ちで失敗するため）
+func (u *bookUsecase) Transaction(fn func(tx BookUsecase) error) error {
+	if u.pending != nil {
+		return fmt.Errorf("トランザクションの中で別のトランザクションは開始できません")
+	}
+	pending := []*model.AuditLog{}
+	err := u.rootRepo.Transaction(func(root repository.BookRepository) error {
+		tx := *u
+		tx.rootRepo, tx.bookRepo, tx.pending = root, root, &pending
+		tx.statsRepo = nil // 統計情報はトランザクションの中の書籍から集計する（集計用のリポジトリはトランザクションの外の接続を使うため）
+		if u.scoped {
+			tx.bookRepo = root.WithOwner(u.userID)
+		}
+		return fn(&tx)
+	})
+	if err != nil {
+		return err
+	}
+	for _, entry := range pending {
+		u.save(entry)
+	}
+	return nil
+}
+
+// repository は本棚に限定する前の書籍のリポジトリを返す関数
+// Transaction の中で、書籍の関連データも同じトランザクションで書き込むリポジトリを作るために使う（MergeRepository.Within など）
+func (u *bookUsecase) repository() repository.BookRepository {
+	return u.rootRepo
+}
+
+// Authorize は書籍に対して required の役割が必要な操作をしてよいかを確認し、書籍を返す関数
+// 貸し出しなど、書籍そのものは変更しないが書籍の権限に従う機能から使う
+func (u *bookUsecase) Authorize(id int, required model.ShareRole) (*model.Book, error) {
+	_, book, err := u.access(id, required)
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// record は書籍の変更履歴を記録する関数
+// 変更履歴の記録に失敗しても、書籍の操作自体は成功しているため、ログに出力するだけにする
+func (u *bookUsecase) record(action string, before, after *model.Book) {
+	if u.auditRepo == nil {
+		return
+	}
+	entry := &model.AuditLog{ActorID: u.userID, EntityType: model.EntityBook, Action: action, RequestID: u.requestID}
+	var err error
+	if before != nil {
+		entry.EntityID, entry.OwnerID = before.ID, before.OwnerID
+		if entry.Before, err = json.Marshal(before); err != nil {
+			log.Printf("変更履歴のJSON変換に失敗しました: %v", err)
+			return
+		}
+	}
+	if after != nil {
+		entry.EntityID, entry.OwnerID = after.ID, after.OwnerID
+		if entry.After, err = json.Marshal(after); err != nil {
+			log.Printf("変更履歴のJSON変換に失敗しました: %v", err)
+			return
+		}
+	}
+	if u.pending != nil {
+		*u.pending = append(*u.pending, entry)
+		return
+	}
+	u.save(entry)
+}
+
+// save は変更履歴を保存する関数（失敗してもログに出力するだけにする）
+func (u *bookUsecase) save(entry *model.AuditLog) {
+	if err := u.auditRepo.Record(entry); err != nil {
+		log.Printf("%v", err)
+	}
+}
+
 // CreateBook は新しい書籍を作成する関数
 // ビジネスルール：入力データの検証、購入日のチェックなど
 func (u *bookUsecase) CreateBook(req *model.CreateBookRequest) (*model.Book, error) {
@@ -64,26 +250,32 @@ func (u *bookUsecase) CreateBook(req *model.CreateBookRequest) (*model.Book, err
 		return nil, fmt.Errorf("入力データが無効です: %w", err)
 	}
 
-	// ビジネスルール：購入日が未来でないことを確認
-	// time.Now().After()：指定した時刻より後かどうかを判定
-	if req.PurchaseDate.After(time.Now()) {
-		return nil, fmt.Errorf("購入日は現在以前の日付を指定してください")
+	// ビジネスルール：購入日・ページ数・通貨・購入方法・形態の確認（更新・以前の版に戻すときと共通）
+	if err := u.checkBook(bookRules{
+		Title: &req.Title, Author: &req.Author, PurchaseDate: &req.PurchaseDate, PageCount: req.PageCount,
+		Currency: &req.Currency, PurchaseChannel: &req.PurchaseChannel, Format: &req.Format,
+	}); err != nil {
+		return nil, err
 	}
 
 	// 検証が成功したらリポジトリに作成を依頼
-	return u.bookRepo.Create(req)
+	book, err := u.bookRepo.Create(req)
+	if err != nil {
+		return nil, err
+	}
+	u.record(model.ActionCreate, nil, book)
+	return book, nil
 }
 
 // GetBook は指定されたIDの書籍を取得する関数
 // ビジネスルール：IDの有効性をチェック（正の整数のみ有効）
 func (u *bookUsecase) GetBook(id int) (*model.Book, error) {
-	// IDの有効性チェック：0以下はNG（データベースのIDは通常1から始まる）
-	if id <= 0 {
-		return nil, fmt.Errorf("無効な書籍IDです: %d", id)
+	// IDの有効性チェック（0以下はNG）と閲覧できる書籍かの確認
+	_, book, err := u.access(id, model.RoleViewer)
+	if err != nil {
+		return nil, err
 	}
-
-	// 検証が成功したらリポジトリに取得を依頼
-	return u.bookRepo.GetByID(id)
+	return book, nil
 }
 
 // ListBooks は書籍一覧を取得する関数（ページネーション対応）
@@ -121,55 +313,217 @@ func (u *bookUsecase) ListBooks(filter *model.BookFilter, page, limit int) ([]*m
 // UpdateBook は書籍情報を更新する関数
 // ビジネスルール：IDの有効性、存在確認、評価の範囲チェック
 func (u *bookUsecase) UpdateBook(id int, req *model.UpdateBookRequest) (*model.Book, error) {
-	// IDの有効性チェック
-	if id <= 0 {
-		return nil, fmt.Errorf("無効な書籍IDです: %d", id)
-	}
-
-	// 既存の書籍が存在するか確認（存在しないと更新できない）
-	_, err := u.bookRepo.GetByID(id)
+	// IDの有効性チェックと、既存の書籍が存在し更新できるかの確認
+	repo, before, err := u.access(id, model.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
 
-	// ビジネスルール：評価が1-5の範囲内かチェック
-	// nilチェックが必要（評価が設定されていない場合もある）
-	if req.Rating != nil && (*req.Rating < 1 || *req.Rating > 5) {
-		return nil, fmt.Errorf("評価は1-5の範囲で入力してください: %d", *req.Rating)
+	// ビジネスルール：作成・以前の版に戻すときと共通の確認（指定した項目だけ）
+	// 読書の開始日・終了日は、指定しなかった方を今の値として前後を確認する
+	startReadDate, endReadDate := before.StartReadDate, before.EndReadDate
+	if req.StartReadDate != nil {
+		startReadDate = req.StartReadDate
+	}
+	if req.EndReadDate != nil {
+		endReadDate = req.EndReadDate
+	}
+	if err := u.checkBook(bookRules{
+		Title: req.Title, Author: req.Author, Status: req.Status, Rating: req.Rating, PageCount: req.PageCount,
+		Currency: req.Currency, PurchaseChannel: req.PurchaseChannel, Format: req.Format,
+		StartReadDate: startReadDate, EndReadDate: endReadDate,
+	}); err != nil {
+		return nil, err
 	}
 
 	// 検証が成功したらリポジトリに更新を依頼
-	return u.bookRepo.Update(id, req)
+	return u.update(repo, before, req)
+}
+
+// baseCurrency は統計の金額の通貨（基準通貨）を返す関数
+func (u *bookUsecase) baseCurrency() string {
+	if u.prices == nil {
+		return currency.Default
+	}
+	return u.prices.BaseCurrency()
+}
+
+// normalizeCurrency は通貨コードを大文字の3文字にそろえる関数（空文字は基準通貨）
+func (u *bookUsecase) normalizeCurrency(code string) (string, error) {
+	if strings.TrimSpace(code) == "" {
+		return u.baseCurrency(), nil
+	}
+	return currency.Normalize(code)
+}
+
+// bookRules は作成・更新・以前の版に戻すときに共通で確認する書籍の項目
+// nil の項目は指定されていない（変更しない）ものとして確認しない
+type bookRules struct {
+	Title, Author              *string                // タイトル・著者（空にはできない）
+	PurchaseDate               *time.Time             // 購入日（未来の日付にはできない）
+	Status                     *model.ReadingStatus   // 読書ステータス
+	Rating, PageCount          *int                   // 評価（1-5）とページ数（1以上）
+	Currency                   *string                // 通貨（確認して大文字の3文字にそろえる。空文字は基準通貨）
+	PurchaseChannel            *model.PurchaseChannel // 購入方法
+	Format                     *model.BookFormat      // 書籍の形態
+	StartReadDate, EndReadDate *time.Time             // 読書の開始日・終了日（両方あるときだけ前後を確認する）
+}
+
+// checkBook は書籍の項目がビジネスルールに合っているかを確認する関数
+// 作成・更新・以前の版に戻すときのすべてで同じ規則を使うため、ここにまとめる
+func (u *bookUsecase) checkBook(r bookRules) error {
+	if r.Title != nil && strings.TrimSpace(*r.Title) == "" {
+		return fmt.Errorf("タイトルを入力してください")
+	}
+	if r.Author != nil && strings.TrimSpace(*r.Author) == "" {
+		return fmt.Errorf("著者を入力してください")
+	}
+	// time.Now().After()：指定した時刻より後かどうかを判定
+	if r.PurchaseDate != nil && r.PurchaseDate.After(time.Now()) {
+		return fmt.Errorf("購入日は現在以前の日付を指定してください")
+	}
+	if r.Status != nil && !r.Status.IsValid() {
+		return fmt.Errorf("読書ステータスが無効です: %s", *r.Status)
+	}
+	if r.Rating != nil && (*r.Rating < 1 || *r.Rating > 5) {
+		return fmt.Errorf("評価は1-5の範囲で入力してください: %d", *r.Rating)
+	}
+	if r.PageCount != nil && *r.PageCount < 1 {
+		return fmt.Errorf("ページ数は1以上で入力してください: %d", *r.PageCount)
+	}
+	// 通貨はISO 4217の通貨コード
+	if r.Currency != nil {
+		code, err := u.normalizeCurrency(*r.Currency)
+		if err != nil {
+			return err
+		}
+		*r.Currency = code
+	}
+	if r.PurchaseChannel != nil && !r.PurchaseChannel.IsValid() {
+		return fmt.Errorf("購入方法が無効です: %s", *r.PurchaseChannel)
+	}
+	if r.Format != nil && !r.Format.IsValid() {
+		return fmt.Errorf("書籍の形態が無効です: %s", *r.Format)
+	}
+	if r.StartReadDate != nil && r.EndReadDate != nil && r.EndReadDate.Before(*r.StartReadDate) {
+		return fmt.Errorf("読書終了日は読書開始日以降の日付を指定してください")
+	}
+	return nil
+}
+
+// update は書籍を更新し、変更履歴を記録する関数
+func (u *bookUsecase) update(repo repository.BookRepository, before *model.Book, req *model.UpdateBookRequest) (*model.Book, error) {
+	after, err := repo.Update(before.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	u.record(model.ActionUpdate, before, after)
+	return after, nil
 }
 
 // DeleteBook は書籍を削除する関数
 // ビジネスルール：IDの有効性、存在確認を前もって削除実行
 func (u *bookUsecase) DeleteBook(id int) error {
-	// IDの有効性チェック
-	if id <= 0 {
-		return fmt.Errorf("無効な書籍IDです: %d", id)
-	}
-
-	// 既存の書籍が存在するか確認（存在しないものは削除できない）
-	_, err := u.bookRepo.GetByID(id)
+	// IDの有効性チェックと、既存の書籍が存在し削除できるかの確認
+	repo, before, err := u.access(id, model.RoleEditor)
 	if err != nil {
 		return err
 	}
 
 	// 検証が成功したらリポジトリに削除を依頼
-	return u.bookRepo.Delete(id)
+	if err := repo.Delete(id); err != nil {
+		return err
+	}
+	u.record(model.ActionDelete, before, nil)
+	return nil
 }
 
-// StartReading は読書を開始する関数
-// ビジネスルール：未読または中断状態の書籍のみ読書開始可能
-func (u *bookUsecase) StartReading(id int) (*model.Book, error) {
-	// IDの有効性チェック
+// MoveBook は書籍の保管場所を変更する関数（nilは場所の設定を外す）
+// ビジネスルール：更新と同じく editor 以上の役割が必要
+// 場所が書籍の持ち主のものかどうかは、場所を扱う呼び出し側で確認する
+func (u *bookUsecase) MoveBook(id int, locationID *int) (*model.Book, error) {
+	repo, before, err := u.access(id, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	after, err := repo.SetLocation(id, locationID)
+	if err != nil {
+		return nil, err
+	}
+	u.record(model.ActionUpdate, before, after)
+	return after, nil
+}
+
+// RestoreBook はゴミ箱の書籍を元に戻す関数
+// ビジネスルール：戻せるのは自分の本棚のゴミ箱の書籍だけ（共有相手が削除した書籍も持ち主が戻す）
+func (u *bookUsecase) RestoreBook(id int) (*model.Book, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("無効な書籍IDです: %d", id)
 	}
+	book, err := u.bookRepo.Restore(id)
+	if err != nil {
+		return nil, err
+	}
+	u.record(model.ActionRestore, nil, book)
+	return book, nil
+}
 
-	// 現在の書籍情報を取得して、ステータスを確認
-	book, err := u.bookRepo.GetByID(id)
+// RevertBook は書籍を変更履歴の版（auditID の変更の直後の状態）に戻す関数
+// 削除の履歴を指定した場合は、削除の直前の状態に戻す
+// ビジネスルール：
+//   - 更新と同じく editor 以上の役割が必要で、戻した内容も更新と同じ検証を行う
+//   - 保管場所と貸し出しは戻さない（それぞれの操作の履歴で管理する）
+func (u *bookUsecase) RevertBook(id, auditID int) (*model.Book, error) {
+	repo, before, err := u.access(id, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if u.auditRepo == nil {
+		return nil, fmt.Errorf("変更履歴を記録していないため、以前の版に戻せません")
+	}
+	entry, err := u.auditRepo.GetByID(auditID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.EntityType != model.EntityBook || entry.EntityID != id || entry.OwnerID != before.OwnerID {
+		return nil, fmt.Errorf("ID %d の変更履歴はこの書籍の履歴ではありません", auditID)
+	}
+	snapshot := entry.After
+	if len(snapshot) == 0 {
+		snapshot = entry.Before
+	}
+	revision := &model.Book{}
+	if err := json.Unmarshal(snapshot, revision); err != nil {
+		return nil, fmt.Errorf("変更履歴の読み取りに失敗しました: %w", err)
+	}
+
+	// ビジネスルール：作成・更新と同じ検証（古い版が今の規則に合わないこともあるため）
+	// 戻すときはすべての項目を置き換えるため、すべての項目を確認する
+	if revision.PurchaseDate.IsZero() {
+		return nil, fmt.Errorf("変更履歴の版に購入日がないため、この版には戻せません")
+	}
+	if err := u.checkBook(bookRules{
+		Title: &revision.Title, Author: &revision.Author, PurchaseDate: &revision.PurchaseDate, Status: &revision.Status,
+		Rating: revision.Rating, PageCount: revision.PageCount, Currency: &revision.Currency,
+		PurchaseChannel: &revision.PurchaseChannel, Format: &revision.Format,
+		StartReadDate: revision.StartReadDate, EndReadDate: revision.EndReadDate,
+	}); err != nil {
+		return nil, err
+	}
+
+	after, err := repo.Replace(id, revision)
+	if err != nil {
+		return nil, err
+	}
+	u.record(model.ActionRevert, before, after)
+	return after, nil
+}
+
+// StartReading は読書を開始する関数
+// ビジネスルール：未読または中断状態の書籍のみ読書開始可能
+func (u *bookUsecase) StartReading(id int) (*model.Book, error) {
+	// IDの有効性チェックと、現在の書籍情報を取得して更新できるかを確認
+	repo, book, err := u.access(id, model.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -191,19 +545,14 @@ func (u *bookUsecase) StartReading(id int) (*model.Book, error) {
 	}
 
 	// リポジトリに更新を依頼
-	return u.bookRepo.Update(id, updateReq)
+	return u.update(repo, book, updateReq)
 }
 
 // FinishReading は読書を完了する関数
 // ビジネスルール：読書中の書籍のみ完了可能、評価は任意で、1-5の範囲
 func (u *bookUsecase) FinishReading(id int, rating *int) (*model.Book, error) {
-	// IDの有効性チェック
-	if id <= 0 {
-		return nil, fmt.Errorf("無効な書籍IDです: %d", id)
-	}
-
-	// 現在の書籍情報を取得して、ステータスを確認
-	book, err := u.bookRepo.GetByID(id)
+	// IDの有効性チェックと、現在の書籍情報を取得して更新できるかを確認
+	repo, book, err := u.access(id, model.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -228,12 +577,75 @@ func (u *bookUsecase) FinishReading(id int, rating *int) (*model.Book, error) {
 	}
 
 	// リポジトリに更新を依頼
-	return u.bookRepo.Update(id, updateReq)
+	return u.update(repo, book, updateReq)
+}
+
+// toBase は書籍の購入価格を基準通貨に換算する関数（換算できなければ false）
+// rates は「通貨 日付」ごとの為替レートのキャッシュ（レートがなければ -1）
+func (u *bookUsecase) toBase(book *model.Book, rates map[string]float64) (int, bool, error) {
+	base := u.baseCurrency()
+	code := book.Currency
+	if code == "" || code == base || book.PurchasePrice == 0 {
+		return book.PurchasePrice, true, nil
+	}
+	if u.prices == nil {
+		return 0, false, nil
+	}
+	key := code + " " + book.PurchaseDate.UTC().Format("2006-01-02")
+	rate, ok := rates[key]
+	if !ok {
+		found := false
+		var err error
+		rate, found, err = u.prices.Rate(code, book.PurchaseDate)
+		if err != nil {
+			return 0, false, err
+		}
+		if !found {
+			rate = -1 // レートがないことも覚えておく
+		}
+		rates[key] = rate
+	}
+	if rate < 0 {
+		return 0, false, nil
+	}
+	return currency.Convert(book.PurchasePrice, code, base, rate), true, nil
+}
+
+// summaryStatistics はデータベースの集計（StatisticsRepository.Summary）で統計情報を作る関数
+// 今月の購入数・読了数は、ほかの統計と同じくUTCで月を区切る
+func (u *bookUsecase) summaryStatistics() (*BookStatistics, error) {
+	ownerID := repository.AllOwners
+	if u.scoped {
+		ownerID = u.userID
+	}
+	now := time.Now().UTC()
+	summary, err := u.statsRepo.Summary(ownerID, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, fmt.Errorf("統計情報の取得に失敗しました: %w", err)
+	}
+	return &BookStatistics{
+		TotalBooks:         summary.Books,
+		NotStartedBooks:    summary.NotStarted,
+		ReadingBooks:       summary.Reading,
+		CompletedBooks:     summary.Completed,
+		DroppedBooks:       summary.Dropped,
+		TotalSpent:         summary.Spent,
+		Currency:           u.statsRepo.BaseCurrency(),
+		UnconvertedBooks:   summary.Unconverted,
+		AverageRating:      summary.AverageRating,
+		BooksThisMonth:     summary.PurchasedSince,
+		CompletedThisMonth: summary.CompletedSince,
+	}, nil
 }
 
 // GetStatistics は書籍の統計情報を取得する関数
-// 複雑な集計処理：全書籍データを取得して様々な統計値を計算
+// データベースがあれば StatisticsRepository の集計で計算し、書籍を読み込まない
+// エフェメラルモード（statsRepo が nil）のときだけ、全書籍データを取得して様々な統計値を計算
 func (u *bookUsecase) GetStatistics() (*BookStatistics, error) {
+	if u.statsRepo != nil {
+		return u.summaryStatistics()
+	}
+
 	// 空の統計情報構造体を作成（これから各フィールドに値を設定していく）
 	stats := &BookStatistics{}
 
@@ -280,6 +692,7 @@ func (u *bookUsecase) GetStatistics() (*BookStatistics, error) {
 	ratingCount := 0        // 評価された書籍の数（平均計算用）
 	booksThisMonth := 0     // 今月購入した書籍数
 	completedThisMonth := 0 // 今月完了した書籍数
+	rates := map[string]float64{} // 換算に使った為替レート（通貨と日付ごと。同じレートを何度も取得しないため）
 	
 	// 時間計算：今月の開始日時を計算
 	now := time.Now()  // 現在日時を取得
@@ -292,7 +705,14 @@ func (u *bookUsecase) GetStatistics() (*BookStatistics, error) {
 	for _, book := range allBooks {
 		// 合計支出額の累計
 		// +=：現在の値に加算して代入
-		totalSpent += book.PurchasePrice
+		// 購入日の為替レートで基準通貨に換算する（レートがなければ合計に含めず、数だけ数える）
+		if price, ok, err := u.toBase(book, rates); err != nil {
+			return nil, err
+		} else if ok {
+			totalSpent += price
+		} else {
+			stats.UnconvertedBooks++
+		}
 
 		// 評価の平均値計算のための合計値とカウント
 		// nil チェック：評価が設定されている書籍のみ対象
@@ -318,6 +738,7 @@ func (u *bookUsecase) GetStatistics() (*BookStatistics, error) {
 
 	// 計算結果を統計情報構造体に設定
 	stats.TotalSpent = totalSpent                   // 総支出額
+	stats.Currency = u.baseCurrency()               // 総支出額の通貨
 	stats.BooksThisMonth = booksThisMonth           // 今月購入数
 	stats.CompletedThisMonth = completedThisMonth   // 今月完了数
 
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"encoding/json" // 変更履歴の版のJSON
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 購入日・読書の開始日と終了日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・変更履歴のリポジトリ
+)
+
+// TestRevertBookValidation は作成・更新の規則に合わない版には戻せず、書籍が変わらないことを確認する
+func TestRevertBookValidation(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		edit    func(b *model.Book) // 変更履歴の版の内容
+		wantErr bool
+	}{
+		{"正しい版", func(b *model.Book) { b.Title = "以前のタイトル" }, false},
+		{"タイトルが空", func(b *model.Book) { b.Title = "" }, true},
+		{"著者が空白だけ", func(b *model.Book) { b.Author = "  " }, true},
+		{"無効な読書ステータス", func(b *model.Book) { b.Status = "paused" }, true},
+		{"未来の購入日", func(b *model.Book) { b.PurchaseDate = time.Now().AddDate(0, 0, 7) }, true},
+		{"購入日がない", func(b *model.Book) { b.PurchaseDate = time.Time{} }, true},
+		{"読書開始日より前の終了日", func(b *model.Book) {
+			end := start.AddDate(0, 0, -1)
+			b.StartReadDate, b.EndReadDate = &start, &end
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+			if err != nil {
+				t.Fatalf("NewDB: %v", err)
+			}
+			defer db.Close()
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			audit := repository.NewAuditRepository(db)
+			books := NewBookUsecase(repository.NewBookRepository(db), nil, audit, nil, nil)
+
+			book, err := books.CreateBook(&model.CreateBookRequest{Title: "今のタイトル", Author: "著者", PurchaseDate: time.Now().UTC()})
+			if err != nil {
+				t.Fatalf("CreateBook: %v", err)
+			}
+			revision := *book
+			tt.edit(&revision)
+			after, err := json.Marshal(&revision)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if err := audit.Record(&model.AuditLog{EntityType: model.EntityBook, EntityID: book.ID, Action: model.ActionUpdate, After: after}); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+			entries, err := audit.List(&model.AuditFilter{EntityType: model.EntityBook, EntityID: book.ID}, 1, 0)
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("List = %d件（%v）", len(entries), err)
+			}
+
+			_, err = books.RevertBook(book.ID, entries[0].ID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RevertBook のエラー = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := books.GetBook(book.ID)
+			if err != nil {
+				t.Fatalf("GetBook: %v", err)
+			}
+			wantTitle := "今のタイトル"
+			if !tt.wantErr {
+				wantTitle = revision.Title
+			}
+			if got.Title != wantTitle || (tt.wantErr && got.Status != book.Status) {
+				t.Errorf("戻した後の書籍 = %q（%s）, want %q", got.Title, got.Status, wantTitle)
+			}
+		})
+	}
+}
+
+// TestGetStatistics はデータベースの集計（StatisticsRepository）で作る統計情報が、
+// 書籍を読み込んでGoで集計する統計情報（エフェメラルモードと同じ計算）と一致することを確認する
+func TestGetStatistics(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	rateRepo := repository.NewExchangeRateRepository(db)
+	if err := rateRepo.Set(&model.ExchangeRate{Currency: "USD", Base: "JPY", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Rate: 150}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	bookRepo := repository.NewBookRepository(db)
+	prices := NewExchangeRateUsecase(rateRepo, "JPY")
+	aggregated := NewBookUsecase(bookRepo, nil, nil, prices, repository.NewStatisticsRepository(db, "JPY")).ForUser(0)
+	loaded := NewBookUsecase(bookRepo, nil, nil, prices, nil).ForUser(0)
+
+	now := time.Now().UTC()
+	rating := 4
+	completed := model.StatusCompleted
+	requests := []*model.CreateBookRequest{
+		{Title: "円の本", Author: "著者", PurchaseDate: now, PurchasePrice: 1200},
+		{Title: "ドルの本", Author: "著者", PurchaseDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), PurchasePrice: 1234, Currency: "USD"},
+		{Title: "レートのない本", Author: "著者", PurchaseDate: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), PurchasePrice: 999, Currency: "USD"},
+	}
+	for _, req := range requests {
+		if _, err := aggregated.CreateBook(req); err != nil {
+			t.Fatalf("CreateBook: %v", err)
+		}
+	}
+	if _, err := aggregated.UpdateBook(1, &model.UpdateBookRequest{Status: &completed, StartReadDate: &now, EndReadDate: &now, Rating: &rating}); err != nil {
+		t.Fatalf("UpdateBook: %v", err)
+	}
+	// 別の本棚の書籍は集計に含めない
+	if _, err := NewBookUsecase(bookRepo, nil, nil, prices, nil).ForUser(2).CreateBook(&model.CreateBookRequest{Title: "他人の本", Author: "著者", PurchaseDate: now, PurchasePrice: 500}); err != nil {
+		t.Fatalf("CreateBook: %v", err)
+	}
+
+	got, err := aggregated.GetStatistics()
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	want, err := loaded.GetStatistics()
+	if err != nil {
+		t.Fatalf("GetStatistics（書籍を読み込む集計）: %v", err)
+	}
+	if got.TotalBooks != 3 || got.TotalSpent != 1200+1851 || got.UnconvertedBooks != 1 || got.CompletedBooks != 1 {
+		t.Errorf("GetStatistics = %+v, want 3冊・3051円・換算できない1冊・読了1冊", *got)
+	}
+	if got.AverageRating == nil || want.AverageRating == nil || *got.AverageRating != *want.AverageRating {
+		t.Errorf("平均評価 = %v, want %v", got.AverageRating, want.AverageRating)
+	}
+	got.AverageRating, want.AverageRating = nil, nil
+	if *got != *want {
+		t.Errorf("データベースの集計 = %+v, 書籍を読み込む集計 = %+v", *got, *want)
+	}
+}
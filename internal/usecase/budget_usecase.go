@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"fmt"  // エラーメッセージの作成
+	"time" // 対象の年月の計算
+
+	"book-manager/internal/currency"         // 金額の表示
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// budgetWarningPercent は予算の警告を出す割合（％）
+// 予算のこの割合以上を使うと warning、100％を超えると over になる
+const budgetWarningPercent = 80
+
+// budgetBreakdownLimit は予算の報告に載せる店・形態・タグごとの支出の件数
+const budgetBreakdownLimit = 10
+
+// BudgetUsecase は予算のビジネスロジックを定義するインターフェース
+type BudgetUsecase interface {
+	Set(userID int, period model.BudgetPeriod, req *model.SetBudgetRequest) (*model.Budget, error) // 予算を設定
+	Delete(userID int, period model.BudgetPeriod) error                                            // 予算を削除
+	Report(userID, year, month int) (*model.BudgetReport, error)                                   // 予算と実際の支出の報告
+}
+
+// budgetUsecase はBudgetUsecaseインターフェースの実装
+type budgetUsecase struct {
+	budgetRepo repository.BudgetRepository     // 予算の保存先
+	statsRepo  repository.StatisticsRepository // 支出の集計（SQLで集計する）
+	validator  *validator.Validate             // 入力データ検証用のバリデータ
+}
+
+// NewBudgetUsecase は新しいBudgetUsecaseを作成する関数
+func NewBudgetUsecase(budgetRepo repository.BudgetRepository, statsRepo repository.StatisticsRepository) BudgetUsecase {
+	return &budgetUsecase{budgetRepo: budgetRepo, statsRepo: statsRepo, validator: validator.New()}
+}
+
+// validBudgetPeriod は予算の期間の種類が正しいかを確認する関数
+func validBudgetPeriod(period model.BudgetPeriod) error {
+	switch period {
+	case model.BudgetMonthly, model.BudgetYearly:
+		return nil
+	default:
+		return fmt.Errorf("予算の期間は month・year のいずれかを指定してください: %s", period)
+	}
+}
+
+// Set は毎月または毎年の予算を設定する（設定済みなら金額を変更する）
+func (u *budgetUsecase) Set(userID int, period model.BudgetPeriod, req *model.SetBudgetRequest) (*model.Budget, error) {
+	if err := validBudgetPeriod(period); err != nil {
+		return nil, err
+	}
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	return u.budgetRepo.Set(userID, period, *req.Amount)
+}
+
+// Delete は予算を削除する
+func (u *budgetUsecase) Delete(userID int, period model.BudgetPeriod) error {
+	if err := validBudgetPeriod(period); err != nil {
+		return err
+	}
+	return u.budgetRepo.Delete(userID, period)
+}
+
+// Report は予算と実際の支出を比べた報告を返す
+// year・month を省略（0）すると今年・今月を対象にする
+// 支出は購入日（UTC）で期間に分け、月ごとの購入金額・冊数と店・形態・タグごとの内訳をSQLで集計する
+func (u *budgetUsecase) Report(userID, year, month int) (*model.BudgetReport, error) {
+	now := time.Now().UTC()
+	if year == 0 {
+		year = now.Year()
+	}
+	if month == 0 {
+		month = int(now.Month())
+	}
+	if year < 1900 || year > 9999 {
+		return nil, fmt.Errorf("%w: year は1900〜9999で指定してください（%d）", ErrInvalidStatsQuery, year)
+	}
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("%w: month は1〜12で指定してください（%d）", ErrInvalidStatsQuery, month)
+	}
+
+	budgets, err := u.budgetRepo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	amounts := map[model.BudgetPeriod]*int{}
+	for _, budget := range budgets {
+		amount := budget.Amount
+		amounts[budget.Period] = &amount
+	}
+
+	// 月ごとの購入金額と冊数（データのない月は0）
+	yearLabel := fmt.Sprintf("%04d", year)
+	spent, err := u.monthlyValues(userID, model.SeriesSpent, yearLabel)
+	if err != nil {
+		return nil, err
+	}
+	books, err := u.monthlyValues(userID, model.SeriesPurchased, yearLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	code := u.statsRepo.BaseCurrency()
+	report := &model.BudgetReport{Year: year, Month: month, Currency: code, Months: []*model.BudgetUsage{}, Alerts: []*model.BudgetAlert{}}
+	yearly := &model.BudgetUsage{Period: model.BudgetYearly, Label: yearLabel}
+	for m := 1; m <= 12; m++ {
+		label := fmt.Sprintf("%s-%02d", yearLabel, m)
+		usage := &model.BudgetUsage{Period: model.BudgetMonthly, Label: label, Spent: spent[label], Books: books[label]}
+		applyBudget(usage, amounts[model.BudgetMonthly])
+		report.Months = append(report.Months, usage)
+		yearly.Spent += usage.Spent
+		yearly.Books += usage.Books
+	}
+	applyBudget(yearly, amounts[model.BudgetYearly])
+	report.Monthly, report.Yearly = report.Months[month-1], yearly
+
+	// 対象の月と年の警告（対象の年のほかの月の超過は months で確認できる）
+	for _, usage := range []*model.BudgetUsage{report.Monthly, report.Yearly} {
+		if alert := budgetAlert(usage, code); alert != nil {
+			report.Alerts = append(report.Alerts, alert)
+		}
+	}
+
+	// 対象の年の店・形態・タグごとの支出（金額の多い順）
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if report.UnconvertedBooks, err = u.statsRepo.Unconverted(userID, &from, &to); err != nil {
+		return nil, err
+	}
+	for _, b := range []struct {
+		by   model.BreakdownDimension
+		dest *[]*model.BreakdownItem
+	}{
+		{model.BreakdownStore, &report.ByStore},
+		{model.BreakdownFormat, &report.ByFormat},
+		{model.BreakdownTag, &report.ByTag},
+	} {
+		items, err := u.statsRepo.Breakdown(userID, &model.BreakdownQuery{By: b.by, From: &from, To: &to, Sort: model.SortBySpent, Limit: budgetBreakdownLimit})
+		if err != nil {
+			return nil, err
+		}
+		*b.dest = items
+	}
+	return report, nil
+}
+
+// monthlyValues は指定した年の月ごとの集計を「2026-01」形式の期間をキーにしたマップで返す
+func (u *budgetUsecase) monthlyValues(userID int, metric model.SeriesMetric, yearLabel string) (map[string]int, error) {
+	points, err := u.statsRepo.TimeSeries(userID, model.IntervalMonth, metric, yearLabel+"-01", yearLabel+"-12")
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]int{}
+	for _, point := range points {
+		values[point.Period] = point.Value
+	}
+	return values, nil
+}
+
+// applyBudget は予算の金額から残り・割合・状況を計算する関数
+func applyBudget(usage *model.BudgetUsage, amount *int) {
+	usage.Status = model.BudgetNotSet
+	if amount == nil {
+		return
+	}
+	remaining := *amount - usage.Spent
+	usage.Budget, usage.Remaining = amount, &remaining
+	switch {
+	case *amount == 0:
+		// 予算0は「買わない」設定のため、少しでも使えば超過
+		if usage.Spent > 0 {
+			usage.Status = model.BudgetOver
+		} else {
+			usage.Status = model.BudgetOK
+		}
+		return
+	case usage.Spent > *amount:
+		usage.Status = model.BudgetOver
+	case usage.Spent*100 >= *amount*budgetWarningPercent:
+		usage.Status = model.BudgetWarning
+	default:
+		usage.Status = model.BudgetOK
+	}
+	percent := round2(float64(usage.Spent) / float64(*amount) * 100)
+	usage.Percent = &percent
+}
+
+// budgetAlert は予算を超えた・超えそうな期間の警告を作る関数（予算内なら nil）
+// code は金額の表示に使う通貨（基準通貨）
+func budgetAlert(usage *model.BudgetUsage, code string) *model.BudgetAlert {
+	name := usage.Label + "年"
+	if t, err := time.Parse("2006-01", usage.Label); err == nil && usage.Period == model.BudgetMonthly {
+		name = fmt.Sprintf("%d年%d月", t.Year(), t.Month())
+	}
+	switch usage.Status {
+	case model.BudgetOver:
+		return &model.BudgetAlert{Period: usage.Period, Label: usage.Label, Status: usage.Status,
+			Message: fmt.Sprintf("%sの予算を%s超えています（予算 %s・支出 %s）", name,
+				currency.Format(-*usage.Remaining, code), currency.Format(*usage.Budget, code), currency.Format(usage.Spent, code))}
+	case model.BudgetWarning:
+		return &model.BudgetAlert{Period: usage.Period, Label: usage.Label, Status: usage.Status,
+			Message: fmt.Sprintf("%sの予算の残りは%sです（予算の%.0f％を使いました）", name, currency.Format(*usage.Remaining, code), *usage.Percent)}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・予算・統計のリポジトリ
+)
+
+// TestApplyBudget は予算に対する支出の割合から、予算内・警告・超過の状況と警告を決めることを確認する
+func TestApplyBudget(t *testing.T) {
+	amount := func(n int) *int { return &n }
+	tests := []struct {
+		name      string
+		budget    *int
+		spent     int
+		want      model.BudgetStatus
+		wantAlert bool
+	}{
+		{"予算が設定されていない", nil, 5000, model.BudgetNotSet, false},
+		{"予算内", amount(10000), 7999, model.BudgetOK, false},
+		{"警告の割合ちょうど", amount(10000), 8000, model.BudgetWarning, true},
+		{"予算ちょうど", amount(10000), 10000, model.BudgetWarning, true},
+		{"予算を超えた", amount(10000), 10001, model.BudgetOver, true},
+		{"予算0で使っていない", amount(0), 0, model.BudgetOK, false},
+		{"予算0で少しでも使った", amount(0), 1, model.BudgetOver, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := &model.BudgetUsage{Period: model.BudgetMonthly, Label: "2026-03", Spent: tt.spent}
+			applyBudget(usage, tt.budget)
+			if usage.Status != tt.want {
+				t.Errorf("Status = %s, want %s", usage.Status, tt.want)
+			}
+			if tt.budget != nil && (usage.Remaining == nil || *usage.Remaining != *tt.budget-tt.spent) {
+				t.Errorf("Remaining = %v, want %d", usage.Remaining, *tt.budget-tt.spent)
+			}
+			alert := budgetAlert(usage, "JPY")
+			if (alert != nil) != tt.wantAlert {
+				t.Fatalf("budgetAlert = %+v, want 警告あり %v", alert, tt.wantAlert)
+			}
+			if alert != nil && (alert.Status != tt.want || alert.Label != "2026-03" || alert.Message == "") {
+				t.Errorf("budgetAlert = %+v", alert)
+			}
+		})
+	}
+}
+
+// TestBudgetReport は月ごとの購入金額を毎月・毎年の予算と比べ、対象の月と年だけ警告を出すことを確認する
+func TestBudgetReport(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	books := repository.NewBookRepository(db).WithOwner(0)
+	for _, b := range []struct {
+		date  time.Time
+		price int
+	}{
+		{time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), 1000},
+		{time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), 1800},
+		{time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC), 1000},
+		{time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), 9000}, // 前の年は数えない
+	} {
+		if _, err := books.Create(&model.CreateBookRequest{Title: "本", Author: "著者", PurchaseDate: b.date, PurchasePrice: b.price}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	budgets := NewBudgetUsecase(repository.NewBudgetRepository(db), repository.NewStatisticsRepository(db, "JPY"))
+	for period, n := range map[model.BudgetPeriod]int{model.BudgetMonthly: 3000, model.BudgetYearly: 10000} {
+		if _, err := budgets.Set(0, period, &model.SetBudgetRequest{Amount: &n}); err != nil {
+			t.Fatalf("Set(%s): %v", period, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		month       int
+		wantSpent   int
+		wantMonthly model.BudgetStatus
+		wantAlerts  int
+	}{
+		{"予算の残りが少ない月", 3, 2800, model.BudgetWarning, 1},
+		{"予算内の月", 1, 1000, model.BudgetOK, 0},
+		{"購入していない月", 2, 0, model.BudgetOK, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := budgets.Report(0, 2026, tt.month)
+			if err != nil {
+				t.Fatalf("Report: %v", err)
+			}
+			if report.Monthly.Spent != tt.wantSpent || report.Monthly.Status != tt.wantMonthly {
+				t.Errorf("今月 = %d（%s）, want %d（%s）", report.Monthly.Spent, report.Monthly.Status, tt.wantSpent, tt.wantMonthly)
+			}
+			if report.Yearly.Spent != 3800 || report.Yearly.Status != model.BudgetOK {
+				t.Errorf("今年 = %d（%s）, want 3800（ok）", report.Yearly.Spent, report.Yearly.Status)
+			}
+			if len(report.Alerts) != tt.wantAlerts {
+				t.Errorf("警告 = %d件, want %d件", len(report.Alerts), tt.wantAlerts)
+			}
+		})
+	}
+	if _, err := budgets.Set(0, "week", &model.SetBudgetRequest{Amount: new(int)}); err == nil {
+		t.Error("対応していない期間の予算を設定できました")
+	}
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"errors"  // 変更を取り消すためのエラー
+	"fmt"     // エラーメッセージの作成
+	"strings" // タグの追加と削除
+
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 自作のデータアクセス層
+)
+
+// MaxBulkBooks は1回のまとめての操作で扱える書籍の最大数
+// 1つのトランザクションが長くなりすぎないように制限する
+const MaxBulkBooks = 500
+
+// errBulkRollback はまとめての操作を取り消すために Transaction に返すエラー（利用者には返さない）
+var errBulkRollback = errors.New("まとめての操作を取り消します")
+
+// BulkUsecase は複数の書籍にまとめて操作を行うビジネスロジックを定義するインターフェース
+type BulkUsecase interface {
+	Apply(userID int, requestID string, req *model.BulkRequest) (*model.BulkResult, error) // 書籍にまとめて操作を行い、書籍ごとの結果を返す
+}
+
+// bulkUsecase はBulkUsecaseインターフェースの実装
+type bulkUsecase struct {
+	bookRepo     repository.BookRepository     // 絞り込み条件に一致する書籍の取得（本棚に限定する前のリポジトリ）
+	locationRepo repository.LocationRepository // set_location の移動先の確認
+	bookUsecase  BookUsecase                   // 書籍の操作（1冊ずつの操作と同じ検証・権限の確認・変更履歴の記録を行う）
+}
+
+// NewBulkUsecase は新しいBulkUsecaseを作成する関数
+func NewBulkUsecase(bookRepo repository.BookRepository, locationRepo repository.LocationRepository, bookUsecase BookUsecase) BulkUsecase {
+	return &bulkUsecase{bookRepo: bookRepo, locationRepo: locationRepo, bookUsecase: bookUsecase}
+}
+
+// Apply は書籍にまとめて操作を行い、書籍ごとの結果を返す
+// ビジネスルール：
+//   - 1冊ずつの操作（更新・削除・移動）と同じ検証と権限の確認を行い、変更履歴も1冊ずつ記録する
+//   - すべての書籍の操作を1つのトランザクションで行い、1冊でも操作できなければどの書籍も変更しない
+//   - 操作できなかった場合も、ほかの書籍の確認を続けて、書籍ごとの結果を返す
+//   - dry_run なら確認だけを行い、変更は取り消す
+func (u *bulkUsecase) Apply(userID int, requestID string, req *model.BulkRequest) (*model.BulkResult, error) {
+	if err := u.validate(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	ids, err := u.targets(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	var location *model.Location
+	if req.Operation == model.BulkSetLocation && req.LocationID != nil {
+		if location, err = u.locationRepo.GetByID(*req.LocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &model.BulkResult{Operation: req.Operation, DryRun: req.DryRun, Total: len(ids), Results: []*model.BulkItemResult{}}
+	err = u.bookUsecase.ForUser(userID).WithRequestID(requestID).Transaction(func(tx BookUsecase) error {
+		for _, id := range ids {
+			item := u.apply(tx, req, id, location)
+			switch item.Status {
+			case model.BulkItemOK:
+				result.Succeeded++
+			case model.BulkItemUnchanged:
+				result.Unchanged++
+			default:
+				result.Failed++
+			}
+			result.Results = append(result.Results, item)
+		}
+		if result.Failed > 0 || req.DryRun {
+			return errBulkRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkRollback) {
+		return nil, fmt.Errorf("まとめての操作に失敗しました: %w", err)
+	}
+	result.Applied = err == nil
+	return result, nil
+}
+
+// validate は対象の指定と、操作の種類ごとに必要な値を確認する
+func (u *bulkUsecase) validate(req *model.BulkRequest) error {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return fmt.Errorf("ids と filter のどちらか一方で対象の書籍を指定してください")
+	}
+	if len(req.IDs) > MaxBulkBooks {
+		return fmt.Errorf("一度に操作できる書籍は%d冊までです: %d冊", MaxBulkBooks, len(req.IDs))
+	}
+
+	switch req.Operation {
+	case model.BulkSetStatus:
+		if req.Status == nil || !req.Status.IsValid() {
+			return fmt.Errorf("set_status には有効な status を指定してください")
+		}
+	case model.BulkAddTag, model.BulkRemoveTag:
+		req.Tag = strings.TrimSpace(req.Tag)
+		if req.Tag == "" || strings.Contains(req.Tag, ",") {
+			return fmt.Errorf("%s にはカンマを含まない tag を指定してください", req.Operation)
+		}
+	case model.BulkSetLocation, model.BulkDelete:
+	case model.BulkUpdate:
+		if req.Fields == nil || *req.Fields == (model.UpdateBookRequest{}) {
+			return fmt.Errorf("update には更新する項目（fields）を指定してください")
+		}
+	default:
+		return fmt.Errorf("操作の種類が無効です: %q", req.Operation)
+	}
+	return nil
+}
+
+// targets は操作する書籍のIDを返す（同じIDを何度指定しても1回だけ操作する）
+// filter で指定した場合は、自分の本棚の書籍のうち条件に一致するものが対象になる
+func (u *bulkUsecase) targets(userID int, req *model.BulkRequest) ([]int, error) {
+	ids := []int{}
+	if req.Filter != nil {
+		books, err := u.bookRepo.WithOwner(userID).List(req.Filter, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(books) > MaxBulkBooks {
+			return nil, fmt.Errorf("条件に一致する書籍が多すぎます（%d冊。一度に操作できるのは%d冊まで）", len(books), MaxBulkBooks)
+		}
+		for _, book := range books {
+			ids = append(ids, book.ID)
+		}
+		return ids, nil
+	}
+
+	seen := map[int]bool{}
+	for _, id := range req.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// apply は1冊の書籍に操作を行い、その結果を返す
+func (u *bulkUsecase) apply(tx BookUsecase, req *model.BulkRequest, id int, location *model.Location) *model.BulkItemResult {
+	item := &model.BulkItemResult{ID: id, Status: model.BulkItemOK}
+	book, err := u.applyBook(tx, req, id, location)
+	switch {
+	case err != nil:
+		item.Status, item.Error = model.BulkItemError, err.Error()
+	case book == nil && req.Operation != model.BulkDelete:
+		item.Status = model.BulkItemUnchanged
+	default:
+		item.Book = book
+	}
+	return item
+}
+
+// applyBook は1冊の書籍に操作を行い、操作後の書籍を返す（すでに操作後の状態なら nil を返す）
+func (u *bulkUsecase) applyBook(tx BookUsecase, req *model.BulkRequest, id int, location *model.Location) (*model.Book, error) {
+	book, err := tx.GetBook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Operation {
+	case model.BulkSetStatus:
+		if book.Status == *req.Status {
+			return nil, nil
+		}
+		return tx.UpdateBook(id, &model.UpdateBookRequest{Status: req.Status})
+	case model.BulkAddTag, model.BulkRemoveTag:
+		tags, changed := editTags(book, req.Tag, req.Operation == model.BulkAddTag)
+		if !changed {
+			return nil, nil
+		}
+		return tx.UpdateBook(id, &model.UpdateBookRequest{Tags: &tags})
+	case model.BulkSetLocation:
+		if location != nil && location.OwnerID != book.OwnerID {
+			// 他のユーザーの場所は存在を知られないように「見つからない」として扱う
+			return nil, fmt.Errorf("ID %d の場所が見つかりません", location.ID)
+		}
+		if sameLocation(book.LocationID, req.LocationID) {
+			return nil, nil
+		}
+		return tx.MoveBook(id, req.LocationID)
+	case model.BulkDelete:
+		return nil, tx.DeleteBook(id)
+	default:
+		fields := *req.Fields // 通貨コードの正規化などで書き換えられるため、書籍ごとに写しを使う
+		return tx.UpdateBook(id, &fields)
+	}
+}
+
+// editTags は書籍のタグに tag を追加する（add が false なら外す）
+// 大文字・小文字は区別せずに比べ、すでに操作後の状態なら changed に false を返す
+func editTags(book *model.Book, tag string, add bool) (tags string, changed bool) {
+	if book.HasTag(tag) == add {
+		return book.Tags, false
+	}
+	if add {
+		return normalizeTags(book.Tags + "," + tag), true
+	}
+	kept := []string{}
+	for _, t := range strings.Split(normalizeTags(book.Tags), ",") {
+		if t != "" && !strings.EqualFold(t, tag) {
+			kept = append(kept, t)
+		}
+	}
+	return strings.Join(kept, ","), true
+}
+
+// sameLocation は2つの保管場所のIDが同じかどうかを判定する（nilは場所の設定なし）
+func sameLocation(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
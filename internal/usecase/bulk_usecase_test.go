@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"fmt"           // 書籍の状態の文字列化
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・場所・変更履歴のリポジトリ
+)
+
+// bulkFixture はまとめての操作のテストに使うデータベースと書籍
+type bulkFixture struct {
+	bulk       BulkUsecase
+	books      repository.BookRepository
+	audit      repository.AuditRepository
+	alice, bob int // ユーザーのID
+	a, b       int // alice の書籍のID
+	bobBook    int // bob の書籍のID（alice には共有していない）
+	aliceShelf int // alice の場所のID
+	bobShelf   int // bob の場所のID
+}
+
+// newBulkFixture は alice と bob の本棚に書籍と場所を作成する
+func newBulkFixture(t *testing.T) *bulkFixture {
+	t.Helper()
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	f := &bulkFixture{books: repository.NewBookRepository(db), audit: repository.NewAuditRepository(db)}
+	locationRepo := repository.NewLocationRepository(db)
+	bookUsecase := NewBookUsecase(f.books, repository.NewShareRepository(db), f.audit,
+		NewExchangeRateUsecase(repository.NewExchangeRateRepository(db), "JPY"), nil)
+	f.bulk = NewBulkUsecase(f.books, locationRepo, bookUsecase)
+
+	users := repository.NewUserRepository(db)
+	for _, user := range []struct {
+		name  string
+		id    *int
+		books []*int
+		shelf *int
+	}{
+		{"alice", &f.alice, []*int{&f.a, &f.b}, &f.aliceShelf},
+		{"bob", &f.bob, []*int{&f.bobBook}, &f.bobShelf},
+	} {
+		registered, err := users.Register(&model.User{Username: user.name, PasswordHash: "x"}, func(int) error { return nil })
+		if err != nil {
+			t.Fatalf("Register(%s): %v", user.name, err)
+		}
+		*user.id = registered.ID
+		for i, id := range user.books {
+			book, err := bookUsecase.ForUser(registered.ID).CreateBook(&model.CreateBookRequest{
+				Title: fmt.Sprintf("%sの本%d", user.name, i+1), Author: "著者", PurchaseDate: time.Now().UTC(), Tags: "小説"})
+			if err != nil {
+				t.Fatalf("CreateBook: %v", err)
+			}
+			*id = book.ID
+		}
+		location, err := locationRepo.Create(&model.Location{OwnerID: registered.ID, Kind: model.KindShelf, Name: user.name + "の本棚"})
+		if err != nil {
+			t.Fatalf("Create location: %v", err)
+		}
+		*user.shelf = location.ID
+	}
+	return f
+}
+
+// snapshot は alice の書籍の状態（ステータス・タグ・場所・ゴミ箱かどうか）を文字列にする
+func (f *bulkFixture) snapshot(t *testing.T) string {
+	t.Helper()
+	s := ""
+	for _, id := range []int{f.a, f.b} {
+		book, err := f.books.WithOwner(f.alice).GetByID(id)
+		if err != nil {
+			s += fmt.Sprintf("[%d: %v]", id, err) // ゴミ箱の書籍は取得できない
+			continue
+		}
+		s += fmt.Sprintf("[%d: %s %s %v]", id, book.Status, book.Tags, book.LocationID)
+	}
+	return s
+}
+
+// TestBulkApplyAtomic は1冊でも操作できなければ、操作できた書籍も含めてどの書籍も変更されず、変更履歴も残らないことを確認する
+func TestBulkApplyAtomic(t *testing.T) {
+	completed := model.StatusCompleted
+	tests := []struct {
+		name       string
+		req        func(f *bulkFixture) *model.BulkRequest
+		wantFailed int
+	}{
+		{"存在しない書籍が含まれる", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, 9999, f.b}, Operation: model.BulkAddTag, Tag: "積読"}
+		}, 1},
+		{"他のユーザーの書籍が含まれる", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, f.b, f.bobBook}, Operation: model.BulkSetStatus, Status: &completed}
+		}, 1},
+		{"削除の途中で失敗する", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, f.bobBook, f.b}, Operation: model.BulkDelete}
+		}, 1},
+		{"他のユーザーの場所に移動する", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, f.b}, Operation: model.BulkSetLocation, LocationID: &f.bobShelf}
+		}, 2},
+		{"タグを外す途中で失敗する", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, 9999}, Operation: model.BulkRemoveTag, Tag: "小説"}
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBulkFixture(t)
+			before := f.snapshot(t)
+
+			result, err := f.bulk.Apply(f.alice, "bulk-test", tt.req(f))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if result.Applied || result.Failed != tt.wantFailed {
+				t.Errorf("Apply = applied %v, failed %d, want false, %d", result.Applied, result.Failed, tt.wantFailed)
+			}
+			if after := f.snapshot(t); after != before {
+				t.Errorf("失敗したのに書籍が変更されました\nbefore %s\nafter  %s", before, after)
+			}
+			if n, err := f.audit.Count(&model.AuditFilter{OwnerID: f.alice, RequestID: "bulk-test"}); err != nil || n != 0 {
+				t.Errorf("取り消した操作の変更履歴 = %d件（%v）, want 0件", n, err)
+			}
+		})
+	}
+}
+
+// TestBulkApply はすべての書籍を操作できれば変更が確定し、dry_run なら確認だけで変更されないことを確認する
+func TestBulkApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         func(f *bulkFixture) *model.BulkRequest
+		wantApplied bool
+		wantChanged bool
+		wantAudit   int
+	}{
+		{"タグを追加", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, f.b, f.a}, Operation: model.BulkAddTag, Tag: "積読"}
+		}, true, true, 2},
+		{"場所を移動", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, f.b}, Operation: model.BulkSetLocation, LocationID: &f.aliceShelf}
+		}, true, true, 2},
+		{"すでに操作後の状態", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, f.b}, Operation: model.BulkAddTag, Tag: "小説"}
+		}, true, false, 0},
+		{"dry_run", func(f *bulkFixture) *model.BulkRequest {
+			return &model.BulkRequest{IDs: []int{f.a, f.b}, Operation: model.BulkDelete, DryRun: true}
+		}, false, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBulkFixture(t)
+			before := f.snapshot(t)
+
+			result, err := f.bulk.Apply(f.alice, "bulk-test", tt.req(f))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if result.Applied != tt.wantApplied || result.Failed != 0 || result.Total != 2 {
+				t.Errorf("Apply = applied %v, failed %d, total %d, want %v, 0, 2", result.Applied, result.Failed, result.Total, tt.wantApplied)
+			}
+			if changed := f.snapshot(t) != before; changed != tt.wantChanged {
+				t.Errorf("書籍が変更されたか = %v, want %v（%s → %s）", changed, tt.wantChanged, before, f.snapshot(t))
+			}
+			if n, err := f.audit.Count(&model.AuditFilter{OwnerID: f.alice, RequestID: "bulk-test"}); err != nil || n != tt.wantAudit {
+				t.Errorf("変更履歴 = %d件（%v）, want %d件", n, err, tt.wantAudit)
+			}
+		})
+	}
+}
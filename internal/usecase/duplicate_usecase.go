@@ -0,0 +1,436 @@
+package usecase
+
+import (
+	"fmt"     // エラーメッセージの作成
+	"sort"    // 候補の並び替え
+	"strings" // メモ・タグの連結
+	"time"    // 購入日の差
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"book-manager/internal/textmatch"        // 表記の揺れを無視した比較
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// 重複の検出の定数
+const (
+	DefaultDuplicateScore = 0.85               // 重複の候補とみなす重複らしさの既定値
+	duplicateTitleScore   = 0.8                // タイトルの一致度がこれ未満なら、ほかが似ていても候補にしない
+	purchaseDateWindow    = 3 * 24 * time.Hour // 購入日が近いとみなす差（二重登録はほぼ同じ日付になるため）
+	purchaseDateBonus     = 0.1                // 購入日が近いときに重複らしさに加える値
+)
+
+// DuplicateUsecase は重複した書籍の検出と統合のビジネスロジックを定義するインターフェース
+type DuplicateUsecase interface {
+	Find(userID int, minScore float64) ([]*model.DuplicateCandidate, error)     // 重複の候補の組を重複らしさの高い順に返す
+	Merge(userID int, req *model.MergeBooksRequest) (*model.MergeResult, error) // 2冊の書籍を1冊に統合する
+}
+
+// duplicateUsecase はDuplicateUsecaseインターフェースの実装
+type duplicateUsecase struct {
+	bookRepo    repository.BookRepository  // 本棚の書籍の取得
+	mergeRepo   repository.MergeRepository // 関連データの移動
+	bookUsecase BookUsecase                // 書籍の権限の確認・更新・削除（変更履歴の記録のため、ユースケースを通す）
+	validator   *validator.Validate        // 入力データ検証用のバリデータ
+}
+
+// NewDuplicateUsecase は新しいDuplicateUsecaseを作成する関数
+func NewDuplicateUsecase(bookRepo repository.BookRepository, mergeRepo repository.MergeRepository, bookUsecase BookUsecase) DuplicateUsecase {
+	return &duplicateUsecase{bookRepo: bookRepo, mergeRepo: mergeRepo, bookUsecase: bookUsecase, validator: validator.New()}
+}
+
+// dupBook は表記をそろえた書籍の比べる項目
+type dupBook struct {
+	book        *model.Book
+	isbn        string // ハイフンなしの13桁のISBN（読めなければ空文字）
+	title, base string // 表記をそろえたタイトルと、副題・括弧書きを除いたタイトル
+	author      string // 表記をそろえた著者名
+	length      int    // base の文字数（比べる組を絞るのに使う）
+}
+
+// Find は本棚から重複の候補の組を探し、重複らしさの高い順に返す
+// - ISBNが同じなら重複らしさ 1
+// - ISBNが両方にあって違う場合は別の版（文庫と単行本など）として候補にしない
+// - それ以外はタイトル（7割）と著者（3割）の一致度で判断し、購入日が近ければ重複らしさを上げる
+func (u *duplicateUsecase) Find(userID int, minScore float64) ([]*model.DuplicateCandidate, error) {
+	if minScore <= 0 || minScore > 1 {
+		return nil, fmt.Errorf("重複らしさの下限は0より大きく1以下で指定してください: %g", minScore)
+	}
+	books, err := u.bookRepo.WithOwner(userID).List(&model.BookFilter{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dupBook, len(books))
+	for i, book := range books {
+		base := textmatch.Normalize(textmatch.BaseTitle(book.Title))
+		items[i] = &dupBook{
+			book:   book,
+			isbn:   textmatch.NormalizeISBN(book.ISBN),
+			title:  textmatch.Normalize(book.Title),
+			base:   base,
+			author: textmatch.NormalizeAuthor(book.Author),
+			length: len([]rune(base)),
+		}
+	}
+
+	candidates := []*model.DuplicateCandidate{}
+	seen := map[[2]int]bool{}
+	add := func(a, b *dupBook) {
+		if a.book.ID > b.book.ID {
+			a, b = b, a
+		}
+		key := [2]int{a.book.ID, b.book.ID}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if candidate := compare(a, b); candidate != nil && candidate.Score >= minScore {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	// ISBNが同じ組
+	byISBN := map[string][]*dupBook{}
+	for _, item := range items {
+		if item.isbn != "" {
+			byISBN[item.isbn] = append(byISBN[item.isbn], item)
+		}
+	}
+	for _, group := range byISBN {
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				add(group[i], group[j])
+			}
+		}
+	}
+
+	// タイトルが似ている組
+	// 一致度が duplicateTitleScore 以上になるには文字数の差が小さい必要があるため、
+	// 文字数の順に並べて、文字数が近い書籍どうしだけを比べる（全部の組を比べるより速い）
+	sort.Slice(items, func(i, j int) bool { return items[i].length < items[j].length })
+	for i, a := range items {
+		for _, b := range items[i+1:] {
+			if float64(a.length) < float64(b.length)*duplicateTitleScore {
+				break
+			}
+			add(a, b)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Book.ID < candidates[j].Book.ID
+	})
+	return candidates, nil
+}
+
+// compare は2冊の書籍の重複らしさを計算する（候補にならなければ nil）
+func compare(a, b *dupBook) *model.DuplicateCandidate {
+	candidate := &model.DuplicateCandidate{Book: a.book, Other: b.book, Reasons: []string{}}
+	closeDates := absDuration(a.book.PurchaseDate.Sub(b.book.PurchaseDate)) <= purchaseDateWindow
+
+	if a.isbn != "" && a.isbn == b.isbn {
+		candidate.Score = 1
+		candidate.Reasons = append(candidate.Reasons, model.DuplicateISBN)
+		if closeDates {
+			candidate.Reasons = append(candidate.Reasons, model.DuplicatePurchaseDate)
+		}
+		return candidate
+	}
+	if a.isbn != "" && b.isbn != "" {
+		return nil // 違うISBN：別の版
+	}
+
+	if a.title == "" || b.title == "" {
+		return nil
+	}
+	titleScore := max(textmatch.Similarity(a.title, b.title), textmatch.Similarity(a.base, b.base))
+	if textmatch.Contains(a.title, b.title) || textmatch.Contains(a.base, b.base) {
+		titleScore = max(titleScore, 0.9)
+	}
+	if titleScore < duplicateTitleScore {
+		return nil
+	}
+	candidate.Score = titleScore
+	candidate.Reasons = append(candidate.Reasons, model.DuplicateTitle)
+
+	if a.author != "" && b.author != "" {
+		authorScore := textmatch.Similarity(a.author, b.author)
+		if textmatch.Contains(a.author, b.author) {
+			authorScore = 1
+		}
+		candidate.Score = titleScore*0.7 + authorScore*0.3
+		if authorScore >= duplicateTitleScore {
+			candidate.Reasons = append(candidate.Reasons, model.DuplicateAuthor)
+		}
+	}
+	if closeDates {
+		candidate.Score = min(1, candidate.Score+purchaseDateBonus)
+		candidate.Reasons = append(candidate.Reasons, model.DuplicatePurchaseDate)
+	}
+	candidate.Score = float64(int(candidate.Score*1000+0.5)) / 1000 // 小数第3位まで
+	return candidate
+}
+
+// absDuration は期間の絶対値を返す関数
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// Merge は統合元の書籍を統合先の書籍にまとめる
+// 1. 統合先の項目を、項目ごとに選んだ値で更新する
+// 2. 統合元のハイライト・レビュー・貸し出し・欲しい本を統合先に移す
+// 3. 統合元の書籍を削除する
+// ビジネスルール：
+// - 両方の書籍を更新できる人だけが統合できる。持ち主の違う書籍は統合できない
+// - 両方が貸し出し中の場合は、どちらかを返却してからでないと統合できない
+func (u *duplicateUsecase) Merge(userID int, req *model.MergeBooksRequest) (*model.MergeResult, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	if req.TargetID == req.SourceID {
+		return nil, fmt.Errorf("同じ書籍どうしは統合できません")
+	}
+	// 統合先の更新・関連データの移動・統合元の削除を1つのトランザクションで行い、途中で失敗したら何も変えない
+	var result *model.MergeResult
+	err := u.bookUsecase.ForUser(userID).Transaction(func(books BookUsecase) error {
+		target, err := books.Authorize(req.TargetID, model.RoleEditor)
+		if err != nil {
+			return err
+		}
+		source, err := books.Authorize(req.SourceID, model.RoleEditor)
+		if err != nil {
+			return err
+		}
+		if target.OwnerID != source.OwnerID {
+			return fmt.Errorf("持ち主の違う書籍は統合できません")
+		}
+		if target.Loan != nil && source.Loan != nil {
+			return fmt.Errorf("両方の書籍が貸し出し中のため統合できません（どちらかを返却してください）")
+		}
+
+		update, fields, err := mergeFields(target, source, req.Fields)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			if _, err := books.UpdateBook(target.ID, update); err != nil {
+				return err
+			}
+		}
+		moved, err := u.mergeRepo.Within(books.repository()).MoveRelated(source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+		if err := books.DeleteBook(source.ID); err != nil {
+			return err
+		}
+
+		// 移した貸し出しも含めて取得し直す
+		merged, err := books.GetBook(target.ID)
+		if err != nil {
+			return err
+		}
+		result = &model.MergeResult{Book: merged, SourceID: source.ID, Fields: fields, Moved: *moved}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeField は統合できる項目1つ分の扱い
+type mergeField struct {
+	name  string                                            // 項目名（JSONの項目名）
+	empty func(b *model.Book) bool                          // 値が空かどうか（空なら統合元の値を使う）
+	same  func(a, b *model.Book) bool                       // 2冊の値が同じかどうか
+	set   func(req *model.UpdateBookRequest, b *model.Book) // 書籍 b の値を更新リクエストに設定する
+}
+
+// mergeableFields は統合できる項目の一覧（notes と tags は mergeFields で別に扱う）
+// purchase_date は書籍の更新で変えられないため、統合先の値のままにする
+var mergeableFields = []mergeField{
+	stringField("title", func(b *model.Book) string { return b.Title }, func(r *model.UpdateBookRequest, v string) { r.Title = &v }),
+	stringField("author", func(b *model.Book) string { return b.Author }, func(r *model.UpdateBookRequest, v string) { r.Author = &v }),
+	stringField("isbn", func(b *model.Book) string { return b.ISBN }, func(r *model.UpdateBookRequest, v string) { r.ISBN = &v }),
+	stringField("publisher", func(b *model.Book) string { return b.Publisher }, func(r *model.UpdateBookRequest, v string) { r.Publisher = &v }),
+	dateField("published_date", func(b *model.Book) *time.Time { return b.PublishedDate }, func(r *model.UpdateBookRequest, v *time.Time) { r.PublishedDate = v }),
+	{
+		// 購入価格は通貨と組で扱う（金額だけを取ると、違う通貨の金額になってしまうため）
+		name:  "purchase_price",
+		empty: func(b *model.Book) bool { return b.PurchasePrice == 0 },
+		same:  func(a, b *model.Book) bool { return a.PurchasePrice == b.PurchasePrice && a.Currency == b.Currency },
+		set: func(r *model.UpdateBookRequest, b *model.Book) {
+			price, code := b.PurchasePrice, b.Currency
+			r.PurchasePrice, r.Currency = &price, &code
+		},
+	},
+	{
+		name:  "status",
+		empty: func(b *model.Book) bool { return b.Status == model.StatusNotStarted },
+		same:  func(a, b *model.Book) bool { return a.Status == b.Status },
+		set:   func(r *model.UpdateBookRequest, b *model.Book) { status := b.Status; r.Status = &status },
+	},
+	dateField("start_read_date", func(b *model.Book) *time.Time { return b.StartReadDate }, func(r *model.UpdateBookRequest, v *time.Time) { r.StartReadDate = v }),
+	dateField("end_read_date", func(b *model.Book) *time.Time { return b.EndReadDate }, func(r *model.UpdateBookRequest, v *time.Time) { r.EndReadDate = v }),
+	intField("rating", func(b *model.Book) *int { return b.Rating }, func(r *model.UpdateBookRequest, v *int) { r.Rating = v }),
+	intField("page_count", func(b *model.Book) *int { return b.PageCount }, func(r *model.UpdateBookRequest, v *int) { r.PageCount = v }),
+	stringField("store", func(b *model.Book) string { return b.Store }, func(r *model.UpdateBookRequest, v string) { r.Store = &v }),
+	stringField("purchase_channel", func(b *model.Book) string { return string(b.PurchaseChannel) }, func(r *model.UpdateBookRequest, v string) {
+		channel := model.PurchaseChannel(v)
+		r.PurchaseChannel = &channel
+	}),
+	stringField("format", func(b *model.Book) string { return string(b.Format) }, func(r *model.UpdateBookRequest, v string) {
+		format := model.BookFormat(v)
+		r.Format = &format
+	}),
+}
+
+// mergeFields は統合先の書籍を更新するリクエストと、統合元の値を使う項目の一覧を作る
+// choices で指定のない項目は、統合先の値が空なら統合元の値を使う
+func mergeFields(target, source *model.Book, choices map[string]model.MergeChoice) (*model.UpdateBookRequest, []string, error) {
+	known := map[string]bool{"notes": true, "tags": true}
+	for _, field := range mergeableFields {
+		known[field.name] = true
+	}
+	for name, choice := range choices {
+		if !known[name] {
+			return nil, nil, fmt.Errorf("統合できない項目です: %s", name)
+		}
+		switch choice {
+		case model.MergeTarget, model.MergeSource:
+		case model.MergeBoth:
+			if name != "notes" && name != "tags" {
+				return nil, nil, fmt.Errorf("%s は both を指定できません（notes と tags だけ）", name)
+			}
+		default:
+			return nil, nil, fmt.Errorf("%s の値の取り方が無効です: %s（使用可能: target, source, both）", name, choice)
+		}
+	}
+
+	req := &model.UpdateBookRequest{}
+	changed := []string{}
+	for _, field := range mergeableFields {
+		if field.same(target, source) {
+			continue
+		}
+		useSource := choices[field.name] == model.MergeSource
+		if choices[field.name] == "" {
+			useSource = field.empty(target) && !field.empty(source)
+		}
+		if useSource {
+			field.set(req, source)
+			changed = append(changed, field.name)
+		}
+	}
+
+	// メモとタグは、指定がなければ両方をつなげる
+	notes := mergeNotes(target.Notes, source.Notes, choices["notes"])
+	if notes != target.Notes {
+		req.Notes = &notes
+		changed = append(changed, "notes")
+	}
+	tags := mergeTags(target.Tags, source.Tags, choices["tags"])
+	if tags != target.Tags {
+		req.Tags = &tags
+		changed = append(changed, "tags")
+	}
+	return req, changed, nil
+}
+
+// stringField は文字列の項目の扱いを作る
+func stringField(name string, get func(*model.Book) string, set func(*model.UpdateBookRequest, string)) mergeField {
+	return mergeField{
+		name:  name,
+		empty: func(b *model.Book) bool { return strings.TrimSpace(get(b)) == "" },
+		same:  func(a, b *model.Book) bool { return get(a) == get(b) },
+		set:   func(r *model.UpdateBookRequest, b *model.Book) { set(r, get(b)) },
+	}
+}
+
+// dateField は日付の項目の扱いを作る（UpdateBookRequest では日付を空に戻せないため、空の値は選ばない）
+func dateField(name string, get func(*model.Book) *time.Time, set func(*model.UpdateBookRequest, *time.Time)) mergeField {
+	return mergeField{
+		name:  name,
+		empty: func(b *model.Book) bool { return get(b) == nil },
+		same: func(a, b *model.Book) bool {
+			x, y := get(a), get(b)
+			return (x == nil && y == nil) || (x != nil && y != nil && x.Equal(*y))
+		},
+		set: func(r *model.UpdateBookRequest, b *model.Book) {
+			if v := get(b); v != nil {
+				set(r, v)
+			}
+		},
+	}
+}
+
+// intField は数値の項目（評価・ページ数）の扱いを作る（空の値は選ばない）
+func intField(name string, get func(*model.Book) *int, set func(*model.UpdateBookRequest, *int)) mergeField {
+	return mergeField{
+		name:  name,
+		empty: func(b *model.Book) bool { return get(b) == nil },
+		same: func(a, b *model.Book) bool {
+			x, y := get(a), get(b)
+			return (x == nil && y == nil) || (x != nil && y != nil && *x == *y)
+		},
+		set: func(r *model.UpdateBookRequest, b *model.Book) {
+			if v := get(b); v != nil {
+				value := *v
+				set(r, &value)
+			}
+		},
+	}
+}
+
+// mergeNotes はメモを統合する（both は、片方がもう片方を含むなら長い方、そうでなければ空行を挟んでつなげる）
+func mergeNotes(target, source string, choice model.MergeChoice) string {
+	switch choice {
+	case model.MergeTarget:
+		return target
+	case model.MergeSource:
+		return source
+	}
+	t, s := strings.TrimSpace(target), strings.TrimSpace(source)
+	switch {
+	case s == "" || strings.Contains(t, s):
+		return target
+	case t == "" || strings.Contains(s, t):
+		return source
+	}
+	return t + "\n\n" + s
+}
+
+// mergeTags はタグを統合する（both は、統合先のタグの後に統合先にないタグを続ける。大文字・小文字は区別しない）
+func mergeTags(target, source string, choice model.MergeChoice) string {
+	switch choice {
+	case model.MergeTarget:
+		return target
+	case model.MergeSource:
+		return source
+	}
+	merged := &model.Book{Tags: target}
+	tags := strings.Split(normalizeTags(target), ",")
+	if tags[0] == "" {
+		tags = nil
+	}
+	added := false
+	for _, tag := range strings.Split(normalizeTags(source), ",") {
+		if tag != "" && !merged.HasTag(tag) {
+			tags = append(tags, tag)
+			merged.Tags += "," + tag
+			added = true
+		}
+	}
+	if !added {
+		return target
+	}
+	return strings.Join(tags, ",")
+}
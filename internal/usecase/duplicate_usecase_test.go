@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"errors"        // 関連データの移動の失敗
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・ハイライト・統合のリポジトリ
+)
+
+// failingMerge は関連データの移動に失敗する MergeRepository（統合が途中で失敗したときの確認用）
+type failingMerge struct{}
+
+func (failingMerge) MoveRelated(sourceID, targetID int) (*model.MergedRecords, error) {
+	return nil, errors.New("関連データの移動に失敗しました")
+}
+
+func (m failingMerge) Within(books repository.BookRepository) repository.MergeRepository { return m }
+
+// TestMergeTransaction は統合先の更新・関連データの移動・統合元の削除がまとめて確定し、
+// 途中で失敗したときは統合先の更新も取り消されることを確認する
+func TestMergeTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		merge   func(db *database.DB) repository.MergeRepository
+		wantErr bool
+	}{
+		{"成功", func(db *database.DB) repository.MergeRepository { return repository.NewMergeRepository(db) }, false},
+		{"関連データの移動に失敗", func(*database.DB) repository.MergeRepository { return failingMerge{} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+			if err != nil {
+				t.Fatalf("NewDB: %v", err)
+			}
+			defer db.Close()
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			bookRepo := repository.NewBookRepository(db)
+			books := NewBookUsecase(bookRepo, nil, nil, nil, nil)
+			duplicates := NewDuplicateUsecase(bookRepo, tt.merge(db), books)
+
+			target, err := books.CreateBook(&model.CreateBookRequest{Title: "統合先", Author: "著者", PurchaseDate: time.Now().UTC()})
+			if err != nil {
+				t.Fatalf("CreateBook: %v", err)
+			}
+			source, err := books.CreateBook(&model.CreateBookRequest{Title: "統合元", Author: "著者", PurchaseDate: time.Now().UTC()})
+			if err != nil {
+				t.Fatalf("CreateBook: %v", err)
+			}
+			if _, err := repository.NewHighlightRepository(db).Create(&model.Highlight{BookID: source.ID, Kind: model.KindHighlight, Text: "引用"}); err != nil {
+				t.Fatalf("Create highlight: %v", err)
+			}
+
+			_, err = duplicates.Merge(0, &model.MergeBooksRequest{TargetID: target.ID, SourceID: source.ID,
+				Fields: map[string]model.MergeChoice{"title": model.MergeSource}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Merge のエラー = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			wantTitle, wantSourceExists := "統合元", false
+			if tt.wantErr {
+				wantTitle, wantSourceExists = "統合先", true
+			}
+			got, err := books.GetBook(target.ID)
+			if err != nil {
+				t.Fatalf("GetBook: %v", err)
+			}
+			if got.Title != wantTitle {
+				t.Errorf("統合先のタイトル = %q, want %q", got.Title, wantTitle)
+			}
+			if _, err := books.GetBook(source.ID); (err == nil) != wantSourceExists {
+				t.Errorf("統合元の書籍の取得 = %v, want 残っている %v", err, wantSourceExists)
+			}
+		})
+	}
+}
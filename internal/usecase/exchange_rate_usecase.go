@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"encoding/csv" // CSVファイルの読み込み
+	"fmt"          // エラーメッセージの作成
+	"io"           // CSVファイルの読み込み元
+	"strconv"      // レートの数値の変換
+	"strings"      // 見出し行の判定
+	"time"         // レートの日付
+
+	"book-manager/internal/currency"         // 通貨コードの確認と換算
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// PriceConverter は購入価格を基準通貨に換算するインターフェース
+// エフェメラルモードの統計（GetStatistics）のように、書籍を読み込んでGoで集計するときに使う
+type PriceConverter interface {
+	BaseCurrency() string                                              // 基準通貨
+	ToBase(amount int, code string, date time.Time) (int, bool, error) // 基準通貨の最小単位に換算（レートがなければ false）
+	Rate(code string, date time.Time) (float64, bool, error)           // date 時点の code の1単位が基準通貨のいくつか（レートがなければ false）
+}
+
+// ExchangeRateUsecase は為替レートのビジネスロジックを定義するインターフェース
+type ExchangeRateUsecase interface {
+	PriceConverter
+	Set(req *model.SetExchangeRateRequest) (*model.ExchangeRate, error) // レートを登録（同じ通貨・日付なら置き換え）
+	List(code string) ([]*model.ExchangeRate, error)                    // 基準通貨へのレートの一覧（code が空ならすべての通貨）
+	Delete(id int) error                                                // レートを削除
+	ImportCSV(r io.Reader) (*model.ExchangeRateImport, error)           // CSVファイル（date,currency,rate）からレートを取り込む
+}
+
+// exchangeRateUsecase はExchangeRateUsecaseインターフェースの実装
+type exchangeRateUsecase struct {
+	rateRepo  repository.ExchangeRateRepository // レートの保存先（nilならレートを使わない）
+	base      string                            // 基準通貨
+	validator *validator.Validate               // 入力データ検証用のバリデータ
+}
+
+// NewExchangeRateUsecase は新しいExchangeRateUsecaseを作成する関数
+// rateRepo は nil でもよい（エフェメラルモードでは基準通貨の価格だけを集計する）
+func NewExchangeRateUsecase(rateRepo repository.ExchangeRateRepository, baseCurrency string) ExchangeRateUsecase {
+	return &exchangeRateUsecase{rateRepo: rateRepo, base: baseCurrency, validator: validator.New()}
+}
+
+// BaseCurrency は基準通貨を返す
+func (u *exchangeRateUsecase) BaseCurrency() string {
+	return u.base
+}
+
+// ToBase は code の最小単位の金額を、date 以前で最新のレートで基準通貨の最小単位に換算する
+func (u *exchangeRateUsecase) ToBase(amount int, code string, date time.Time) (int, bool, error) {
+	if code == "" || code == u.base {
+		return amount, true, nil
+	}
+	rate, found, err := u.Rate(code, date)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	return currency.Convert(amount, code, u.base, rate), true, nil
+}
+
+// Rate は購入日以前で最新の為替レートを返す（基準通貨・通貨の指定なしは 1）
+// 統計のSQL（repository の convertedPrice）も同じ規則でレートを選ぶ
+func (u *exchangeRateUsecase) Rate(code string, date time.Time) (float64, bool, error) {
+	if code == "" || code == u.base {
+		return 1, true, nil
+	}
+	if u.rateRepo == nil {
+		return 0, false, nil
+	}
+	rate, err := u.rateRepo.Find(code, u.base, rateDate(date))
+	if err != nil || rate == nil {
+		return 0, false, err
+	}
+	return rate.Rate, true, nil
+}
+
+// rateDate はレートの日付をUTCの0時にそろえる関数（統計のSQLと同じくUTCで日付を区切る）
+func rateDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// newRate は通貨コードとレートを確認して、保存するレートを作る
+func (u *exchangeRateUsecase) newRate(code string, date time.Time, rate float64) (*model.ExchangeRate, error) {
+	code, err := currency.Normalize(code)
+	if err != nil {
+		return nil, err
+	}
+	if code == u.base {
+		return nil, fmt.Errorf("基準通貨（%s）のレートは登録できません", u.base)
+	}
+	if rate <= 0 {
+		return nil, fmt.Errorf("レートは0より大きい値を指定してください: %v", rate)
+	}
+	return &model.ExchangeRate{Currency: code, Base: u.base, Date: rateDate(date), Rate: rate}, nil
+}
+
+// Set はレートを登録する
+func (u *exchangeRateUsecase) Set(req *model.SetExchangeRateRequest) (*model.ExchangeRate, error) {
+	if u.rateRepo == nil {
+		return nil, fmt.Errorf("為替レートはデータベースを使う場合のみ登録できます")
+	}
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	rate, err := u.newRate(req.Currency, *req.Date, req.Rate)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.rateRepo.Set(rate); err != nil {
+		return nil, err
+	}
+	return u.rateRepo.Find(rate.Currency, rate.Base, rate.Date)
+}
+
+// List は基準通貨へのレートの一覧を返す
+func (u *exchangeRateUsecase) List(code string) ([]*model.ExchangeRate, error) {
+	if u.rateRepo == nil {
+		return []*model.ExchangeRate{}, nil
+	}
+	if code != "" {
+		normalized, err := currency.Normalize(code)
+		if err != nil {
+			return nil, err
+		}
+		code = normalized
+	}
+	return u.rateRepo.List(code, u.base)
+}
+
+// Delete はレートを削除する
+func (u *exchangeRateUsecase) Delete(id int) error {
+	if u.rateRepo == nil {
+		return fmt.Errorf("ID %d の為替レートが見つかりません", id)
+	}
+	return u.rateRepo.Delete(id)
+}
+
+// ImportCSV はCSVファイルからレートを取り込む
+// 1行に「日付（2026-01-31）,通貨コード,レート」を書く。1行目が見出し（date,currency,rate）なら読み飛ばす
+// 正しくない行は取り込まずに errors に理由を残し、正しい行だけをまとめて保存する
+func (u *exchangeRateUsecase) ImportCSV(r io.Reader) (*model.ExchangeRateImport, error) {
+	if u.rateRepo == nil {
+		return nil, fmt.Errorf("為替レートはデータベースを使う場合のみ登録できます")
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // 列の数の確認は自分で行う（行ごとにエラーを残すため）
+	reader.TrimLeadingSpace = true
+
+	result := &model.ExchangeRateImport{Errors: []string{}}
+	rates := []*model.ExchangeRate{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSVファイルの読み込みに失敗しました: %w", err)
+		}
+		if line == 1 && strings.EqualFold(strings.TrimPrefix(record[0], "\ufeff"), "date") {
+			continue // 見出し行
+		}
+		if len(record) != 3 {
+			result.Errors = append(result.Errors, fmt.Sprintf("%d行目: 日付・通貨コード・レートの3列が必要です", line))
+			continue
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimPrefix(strings.TrimSpace(record[0]), "\ufeff"))
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%d行目: 日付は 2026-01-31 の形式で指定してください: %s", line, record[0]))
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%d行目: レートが数値ではありません: %s", line, record[2]))
+			continue
+		}
+		rate, err := u.newRate(record[1], date, value)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%d行目: %v", line, err))
+			continue
+		}
+		rates = append(rates, rate)
+	}
+
+	if len(rates) > 0 {
+		if err := u.rateRepo.Set(rates...); err != nil {
+			return nil, err
+		}
+	}
+	result.Imported = len(rates)
+	return result, nil
+}
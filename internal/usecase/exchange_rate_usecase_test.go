@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // レートの日付と購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 為替レートのリポジトリ
+)
+
+// TestRate は購入日以前で最新の為替レートを返し、ToBase がそのレートで換算することを確認する
+func TestRate(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	rates := repository.NewExchangeRateRepository(db)
+	if err := rates.Set(
+		&model.ExchangeRate{Currency: "USD", Base: "JPY", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Rate: 140},
+		&model.ExchangeRate{Currency: "USD", Base: "JPY", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Rate: 150},
+	); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	prices := NewExchangeRateUsecase(rates, "JPY")
+
+	tests := []struct {
+		name      string
+		code      string
+		date      time.Time
+		wantRate  float64
+		wantFound bool
+		wantPrice int // 1234セントを換算した金額
+	}{
+		{"基準通貨", "JPY", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 1, true, 1234},
+		{"通貨の指定なし", "", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 1, true, 1234},
+		{"購入日以前で最新のレート", "USD", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 140, true, 1728},
+		{"購入日当日の遅い時刻", "USD", time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC), 150, true, 1851},
+		{"購入日より前のレートがない", "USD", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), 0, false, 0},
+		{"レートのない通貨", "EUR", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 0, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, found, err := prices.Rate(tt.code, tt.date)
+			if err != nil {
+				t.Fatalf("Rate: %v", err)
+			}
+			if rate != tt.wantRate || found != tt.wantFound {
+				t.Errorf("Rate = %v, %v, want %v, %v", rate, found, tt.wantRate, tt.wantFound)
+			}
+			price, found, err := prices.ToBase(1234, tt.code, tt.date)
+			if err != nil {
+				t.Fatalf("ToBase: %v", err)
+			}
+			if price != tt.wantPrice || found != tt.wantFound {
+				t.Errorf("ToBase = %d, %v, want %d, %v", price, found, tt.wantPrice, tt.wantFound)
+			}
+		})
+	}
+}
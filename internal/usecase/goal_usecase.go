@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"fmt"     // エラーメッセージの作成
+	"math"    // 予定の量の四捨五入
+	"sort"    // 数えた書籍の並べ替え
+	"strings" // 名前・タグの前後の空白の除去
+	"time"    // 期間の計算
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// GoalUsecase は読書目標のビジネスロジックを定義するインターフェース
+type GoalUsecase interface {
+	Create(userID int, req *model.CreateGoalRequest) (*model.GoalProgress, error) // 目標を作成（作成時点の達成状況を返す）
+	List(userID int) ([]*model.GoalProgress, error)                               // 目標と達成状況の一覧
+	Progress(userID, id int) (*model.GoalProgress, error)                         // 目標の達成状況
+	Delete(userID, id int) error                                                  // 目標を削除
+}
+
+// goalUsecase はGoalUsecaseインターフェースの実装
+type goalUsecase struct {
+	goalRepo  repository.GoalRepository // 目標の保存先
+	bookRepo  repository.BookRepository // 読み終えた書籍の取得に使う（本棚に限定する前のリポジトリ）
+	validator *validator.Validate       // 入力データ検証用のバリデータ
+}
+
+// NewGoalUsecase は新しいGoalUsecaseを作成する関数
+func NewGoalUsecase(goalRepo repository.GoalRepository, bookRepo repository.BookRepository) GoalUsecase {
+	return &goalUsecase{goalRepo: goalRepo, bookRepo: bookRepo, validator: validator.New()}
+}
+
+// calendarDate は日時をその日の0時（UTC）にそろえる関数
+// 期間の比較は日付だけで行うため、サーバーのタイムゾーンでの日付を取り出し、時差の影響を受けないUTCの0時で表す
+func calendarDate(t time.Time) time.Time {
+	local := t.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween は from から to までの日数を返す関数（どちらも calendarDate でそろえた日付）
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// Create は読書目標を作成する
+// ビジネスルール：
+//   - year は1月1日〜12月31日、month はその月の初日〜末日を期間にする（年・月を省略すると今年・今月）
+//   - custom は開始日と終了日が必須で、終了日は開始日以降
+func (u *goalUsecase) Create(userID int, req *model.CreateGoalRequest) (*model.GoalProgress, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+
+	today := calendarDate(time.Now())
+	goal := &model.ReadingGoal{
+		OwnerID: userID,
+		Title:   strings.TrimSpace(req.Title),
+		Metric:  req.Metric,
+		Target:  req.Target,
+		Period:  req.Period,
+		Tag:     strings.TrimSpace(req.Tag),
+	}
+
+	year := req.Year
+	if year == 0 {
+		year = today.Year()
+	}
+	switch req.Period {
+	case model.PeriodYear:
+		goal.StartDate = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		goal.EndDate = goal.StartDate.AddDate(1, 0, -1)
+		if goal.Title == "" {
+			goal.Title = fmt.Sprintf("%d年の読書目標", year)
+		}
+	case model.PeriodMonth:
+		month := time.Month(req.Month)
+		if req.Month == 0 {
+			month = today.Month()
+		}
+		goal.StartDate = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		goal.EndDate = goal.StartDate.AddDate(0, 1, -1)
+		if goal.Title == "" {
+			goal.Title = fmt.Sprintf("%d年%d月の読書目標", year, month)
+		}
+	case model.PeriodCustom:
+		if req.StartDate == nil || req.EndDate == nil {
+			return nil, fmt.Errorf("期間を指定する目標には開始日と終了日を指定してください")
+		}
+		goal.StartDate = calendarDate(*req.StartDate)
+		goal.EndDate = calendarDate(*req.EndDate)
+		if goal.EndDate.Before(goal.StartDate) {
+			return nil, fmt.Errorf("終了日は開始日以降の日付を指定してください")
+		}
+		if goal.Title == "" {
+			goal.Title = fmt.Sprintf("%s〜%sの読書目標", goal.StartDate.Format("2006-01-02"), goal.EndDate.Format("2006-01-02"))
+		}
+	}
+
+	saved, err := u.goalRepo.Create(goal)
+	if err != nil {
+		return nil, err
+	}
+	return u.progress(userID, saved)
+}
+
+// List は目標と達成状況の一覧を返す
+func (u *goalUsecase) List(userID int) ([]*model.GoalProgress, error) {
+	goals, err := u.goalRepo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	// 読み終えた書籍は全目標で共通のため、1回だけ読み込む
+	completed, err := u.completedBooks(userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		result = append(result, calculateProgress(goal, completed, time.Now()))
+	}
+	return result, nil
+}
+
+// Progress は目標の達成状況を返す
+func (u *goalUsecase) Progress(userID, id int) (*model.GoalProgress, error) {
+	goal, err := u.goalRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.progress(userID, goal)
+}
+
+// Delete は目標を削除する（書籍は変更しない）
+func (u *goalUsecase) Delete(userID, id int) error {
+	return u.goalRepo.Delete(userID, id)
+}
+
+// progress は1つの目標の達成状況を計算する
+func (u *goalUsecase) progress(userID int, goal *model.ReadingGoal) (*model.GoalProgress, error) {
+	completed, err := u.completedBooks(userID)
+	if err != nil {
+		return nil, err
+	}
+	return calculateProgress(goal, completed, time.Now()), nil
+}
+
+// completedBooks はユーザーの本棚の読み終えた書籍をすべて返す
+func (u *goalUsecase) completedBooks(userID int) ([]*model.Book, error) {
+	status := model.StatusCompleted
+	return u.bookRepo.WithOwner(userID).List(&model.BookFilter{Status: &status}, 0, 0)
+}
+
+// calculateProgress は読み終えた書籍から目標の達成状況を計算する関数
+// 読み終えた日（end_read_date）が期間内で、目標のタグが付いている書籍を数える
+// 予定の量は期間を通して一定のペースで読む前提で、昨日までに経過した日数の割合から計算する
+func calculateProgress(goal *model.ReadingGoal, completed []*model.Book, now time.Time) *model.GoalProgress {
+	start, end := calendarDate(goal.StartDate), calendarDate(goal.EndDate)
+	p := &model.GoalProgress{Goal: goal, Books: []*model.GoalBook{}}
+
+	for _, book := range completed {
+		if book.EndReadDate == nil {
+			continue
+		}
+		finished := calendarDate(*book.EndReadDate)
+		if finished.Before(start) || finished.After(end) {
+			continue
+		}
+		if goal.Tag != "" && !book.HasTag(goal.Tag) {
+			continue
+		}
+		p.Books = append(p.Books, &model.GoalBook{ID: book.ID, Title: book.Title, PageCount: book.PageCount, EndReadDate: *book.EndReadDate})
+		if goal.Metric == model.MetricPages {
+			if book.PageCount != nil {
+				p.Current += *book.PageCount
+			}
+		} else {
+			p.Current++
+		}
+	}
+	sort.SliceStable(p.Books, func(i, j int) bool { return p.Books[i].EndReadDate.Before(p.Books[j].EndReadDate) })
+
+	totalDays := daysBetween(start, end) + 1
+	today := calendarDate(now)
+	switch {
+	case today.Before(start):
+		p.DaysElapsed, p.DaysRemaining = 0, totalDays
+	case today.After(end):
+		p.DaysElapsed, p.DaysRemaining = totalDays, 0
+	default:
+		p.DaysElapsed = daysBetween(start, today)
+		p.DaysRemaining = totalDays - p.DaysElapsed
+	}
+
+	target := float64(goal.Target)
+	p.Percent = round2(float64(p.Current) / target * 100)
+	p.Expected = round2(target * float64(p.DaysElapsed) / float64(totalDays))
+	p.Difference = round2(float64(p.Current) - p.Expected)
+
+	// 見込み：今日読み終えた分も含めて、これまでの1日あたりの量が期間の最後まで続いた場合
+	if daysSoFar := p.DaysElapsed + 1; !today.Before(start) {
+		if daysSoFar > totalDays {
+			daysSoFar = totalDays
+		}
+		p.Projected = round2(float64(p.Current) / float64(daysSoFar) * float64(totalDays))
+	}
+	if left := goal.Target - p.Current; left > 0 && p.DaysRemaining > 0 {
+		p.NeededPerDay = round2(float64(left) / float64(p.DaysRemaining))
+	}
+
+	switch expected := math.Round(p.Expected); {
+	case p.Current >= goal.Target:
+		p.Pace = model.PaceAchieved
+	case today.After(end):
+		p.Pace = model.PaceMissed
+	case today.Before(start):
+		p.Pace = model.PaceNotStarted
+	case float64(p.Current) > expected:
+		p.Pace = model.PaceAhead
+	case float64(p.Current) == expected:
+		p.Pace = model.PaceOnTrack
+	default:
+		p.Pace = model.PaceBehind
+	}
+	return p
+}
+
+// round2 は小数第2位までに四捨五入する関数（レスポンスを読みやすくするため）
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
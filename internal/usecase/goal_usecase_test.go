@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"testing" // テストの実行と結果の報告
+	"time"    // 目標の期間と読み終えた日
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// TestCalculateProgress は読み終えた書籍から、目標の期間内のペース（予定・見込み・1日あたりに必要な量）を計算することを確認する
+// 期間は2026年1月1日〜10日の10日間、目標は10冊
+func TestCalculateProgress(t *testing.T) {
+	on := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 12, 0, 0, 0, time.Local) }
+	goal := &model.ReadingGoal{Metric: model.MetricBooks, Target: 10, Period: model.PeriodCustom,
+		StartDate: on(time.January, 1), EndDate: on(time.January, 10), Tag: "SF"}
+	// finished は1月1日から n 冊を1日1冊ずつ読み終えた書籍（期間外とタグ違いの書籍も混ぜる）
+	finished := func(n int) []*model.Book {
+		books := []*model.Book{
+			{ID: 100, Title: "期間前", Tags: "SF", EndReadDate: ptrTime(on(time.December, 31).AddDate(-1, 0, 0))},
+			{ID: 101, Title: "タグ違い", Tags: "料理", EndReadDate: ptrTime(on(time.January, 2))},
+		}
+		for i := 0; i < n; i++ {
+			books = append(books, &model.Book{ID: i + 1, Title: "本", Tags: "sf, 小説", EndReadDate: ptrTime(on(time.January, 1+i%10))})
+		}
+		return books
+	}
+
+	tests := []struct {
+		name          string
+		books         int
+		now           time.Time
+		wantPace      model.GoalPace
+		wantExpected  float64
+		wantProjected float64
+		wantNeeded    float64
+		wantElapsed   int
+		wantRemaining int
+	}{
+		{"予定より遅れている", 2, on(time.January, 5), model.PaceBehind, 4, 4, 1.33, 4, 6},
+		{"予定どおり", 4, on(time.January, 5), model.PaceOnTrack, 4, 8, 1, 4, 6},
+		{"予定より進んでいる", 5, on(time.January, 5), model.PaceAhead, 4, 10, 0.83, 4, 6},
+		{"期間の初日", 1, on(time.January, 1), model.PaceAhead, 0, 10, 0.9, 0, 10},
+		{"期間がまだ始まっていない", 0, on(time.December, 31).AddDate(-1, 0, 0), model.PaceNotStarted, 0, 0, 1, 0, 10},
+		{"期間が終わったが達成できなかった", 3, on(time.January, 11), model.PaceMissed, 10, 3, 0, 10, 0},
+		{"目標を達成した", 10, on(time.January, 8), model.PaceAchieved, 7, 12.5, 0, 7, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := calculateProgress(goal, finished(tt.books), tt.now)
+			if p.Current != tt.books || len(p.Books) != tt.books {
+				t.Errorf("Current = %d（%d冊）, want %d（期間外とタグ違いの書籍は数えない）", p.Current, len(p.Books), tt.books)
+			}
+			if p.Pace != tt.wantPace || p.Expected != tt.wantExpected || p.Projected != tt.wantProjected || p.NeededPerDay != tt.wantNeeded {
+				t.Errorf("Pace = %s, Expected = %v, Projected = %v, NeededPerDay = %v, want %s, %v, %v, %v",
+					p.Pace, p.Expected, p.Projected, p.NeededPerDay, tt.wantPace, tt.wantExpected, tt.wantProjected, tt.wantNeeded)
+			}
+			if p.DaysElapsed != tt.wantElapsed || p.DaysRemaining != tt.wantRemaining {
+				t.Errorf("DaysElapsed = %d, DaysRemaining = %d, want %d, %d", p.DaysElapsed, p.DaysRemaining, tt.wantElapsed, tt.wantRemaining)
+			}
+		})
+	}
+
+	// ページ数の目標は、ページ数が未登録の書籍を0ページとして数える
+	pages := &model.ReadingGoal{Metric: model.MetricPages, Target: 1000, StartDate: goal.StartDate, EndDate: goal.EndDate}
+	count := 300
+	p := calculateProgress(pages, []*model.Book{
+		{ID: 1, PageCount: &count, EndReadDate: ptrTime(on(time.January, 2))},
+		{ID: 2, EndReadDate: ptrTime(on(time.January, 3))},
+	}, on(time.January, 5))
+	if p.Current != 300 || len(p.Books) != 2 || p.Percent != 30 {
+		t.Errorf("ページ数の目標：Current = %d（%d冊）, Percent = %v, want 300（2冊）, 30", p.Current, len(p.Books), p.Percent)
+	}
+}
+
+// ptrTime は日時のポインタを返す
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"fmt"     // エラーメッセージの作成
+	"io"      // 取り込むファイルの読み込み元
+	"strings" // タグの整形
+	"time"    // 作成日時
+
+	"book-manager/internal/ereader"          // 電子書籍リーダーのファイルの読み込み
+	"book-manager/internal/markdown"         // Markdownへの書き出し
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// HighlightUsecase はハイライト・引用のビジネスロジックを定義するインターフェース
+type HighlightUsecase interface {
+	Create(userID, bookID int, req *model.CreateHighlightRequest) (*model.Highlight, error)             // ハイライトを登録
+	List(userID, bookID int) ([]*model.Highlight, error)                                                // 書籍のハイライトの一覧（ページ順）
+	Get(userID, bookID, id int) (*model.Highlight, error)                                               // ハイライトを1件取得
+	Update(userID, bookID, id int, req *model.UpdateHighlightRequest) (*model.Highlight, error)         // ハイライトを更新
+	Delete(userID, bookID, id int) error                                                                // ハイライトを削除
+	Search(userID int, filter *model.HighlightFilter, page, limit int) ([]*model.Highlight, int, error) // 本棚のハイライトを検索
+	Markdown(userID, bookID int) (*model.Book, string, error)                                           // 書籍のハイライトをMarkdownに書き出す
+	ImportKindle(userID int, r io.Reader, dryRun bool) (*model.HighlightImport, error)                  // Kindle の My Clippings.txt を取り込む
+	ImportKobo(userID int, path string, dryRun bool) (*model.HighlightImport, error)                    // Kobo の KoboReader.sqlite を取り込む
+}
+
+// highlightUsecase はHighlightUsecaseインターフェースの実装
+type highlightUsecase struct {
+	highlightRepo repository.HighlightRepository // ハイライトの保存先
+	bookRepo      repository.BookRepository      // 取り込み先の書籍を探すための本棚
+	bookUsecase   BookUsecase                    // 書籍の権限の確認に使う（共有された書籍は役割に従う）
+	validator     *validator.Validate            // 入力データ検証用のバリデータ
+}
+
+// NewHighlightUsecase は新しいHighlightUsecaseを作成する関数
+func NewHighlightUsecase(highlightRepo repository.HighlightRepository, bookRepo repository.BookRepository, bookUsecase BookUsecase) HighlightUsecase {
+	return &highlightUsecase{highlightRepo: highlightRepo, bookRepo: bookRepo, bookUsecase: bookUsecase, validator: validator.New()}
+}
+
+// Create はハイライトを登録する
+// ビジネスルール：書籍を更新できる人（持ち主か editor として共有された人）だけが登録できる
+func (u *highlightUsecase) Create(userID, bookID int, req *model.CreateHighlightRequest) (*model.Highlight, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	if req.Kind == "" {
+		req.Kind = model.KindHighlight
+	}
+	if !req.Kind.IsValid() {
+		return nil, fmt.Errorf("無効なハイライトの種類です: %s", req.Kind)
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, fmt.Errorf("本文を入力してください")
+	}
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	highlight := &model.Highlight{
+		BookID:    book.ID,
+		Kind:      req.Kind,
+		Text:      strings.TrimSpace(req.Text),
+		Note:      strings.TrimSpace(req.Note),
+		Page:      req.Page,
+		Location:  strings.TrimSpace(req.Location),
+		Chapter:   strings.TrimSpace(req.Chapter),
+		Tags:      normalizeTags(req.Tags),
+		CreatedAt: time.Now(),
+	}
+	if req.CreatedAt != nil {
+		highlight.CreatedAt = *req.CreatedAt
+	}
+	return u.highlightRepo.Create(highlight)
+}
+
+// List は書籍のハイライトの一覧を返す（書籍を閲覧できる人なら誰でも見られる）
+func (u *highlightUsecase) List(userID, bookID int) ([]*model.Highlight, error) {
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return u.highlightRepo.ListByBook(book.ID)
+}
+
+// Get はハイライトを1件取得する
+func (u *highlightUsecase) Get(userID, bookID, id int) (*model.Highlight, error) {
+	return u.find(userID, bookID, id, model.RoleViewer)
+}
+
+// find は書籍の権限を確認してからハイライトを取得する
+// URLの書籍と違う書籍のハイライトは「見つからない」として扱う（他の書籍のハイライトを操作させないため）
+func (u *highlightUsecase) find(userID, bookID, id int, required model.ShareRole) (*model.Highlight, error) {
+	if _, err := u.bookUsecase.ForUser(userID).Authorize(bookID, required); err != nil {
+		return nil, err
+	}
+	highlight, err := u.highlightRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if highlight.BookID != bookID {
+		return nil, fmt.Errorf("ID %d のハイライトが見つかりません", id)
+	}
+	return highlight, nil
+}
+
+// Update はハイライトを更新する（書籍を更新できる人だけ）
+func (u *highlightUsecase) Update(userID, bookID, id int, req *model.UpdateHighlightRequest) (*model.Highlight, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	if req.Kind != nil && !req.Kind.IsValid() {
+		return nil, fmt.Errorf("無効なハイライトの種類です: %s", *req.Kind)
+	}
+	if req.Text != nil && strings.TrimSpace(*req.Text) == "" {
+		return nil, fmt.Errorf("本文を入力してください")
+	}
+	highlight, err := u.find(userID, bookID, id, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 指定された項目だけを更新する
+	if req.Kind != nil {
+		highlight.Kind = *req.Kind
+	}
+	if req.Text != nil {
+		highlight.Text = strings.TrimSpace(*req.Text)
+	}
+	if req.Note != nil {
+		highlight.Note = strings.TrimSpace(*req.Note)
+	}
+	if req.Page != nil {
+		highlight.Page = req.Page
+		if *req.Page == 0 {
+			highlight.Page = nil // 0は未登録に戻す
+		}
+	}
+	if req.Location != nil {
+		highlight.Location = strings.TrimSpace(*req.Location)
+	}
+	if req.Chapter != nil {
+		highlight.Chapter = strings.TrimSpace(*req.Chapter)
+	}
+	if req.Tags != nil {
+		highlight.Tags = normalizeTags(*req.Tags)
+	}
+	return u.highlightRepo.Update(highlight)
+}
+
+// Delete はハイライトを削除する（書籍を更新できる人だけ）
+func (u *highlightUsecase) Delete(userID, bookID, id int) error {
+	highlight, err := u.find(userID, bookID, id, model.RoleEditor)
+	if err != nil {
+		return err
+	}
+	return u.highlightRepo.Delete(highlight.ID)
+}
+
+// Search は自分の本棚のハイライトを検索する
+func (u *highlightUsecase) Search(userID int, filter *model.HighlightFilter, page, limit int) ([]*model.Highlight, int, error) {
+	if filter.Kind != "" && !filter.Kind.IsValid() {
+		return nil, 0, fmt.Errorf("無効なハイライトの種類です: %s", filter.Kind)
+	}
+	filter.OwnerID = userID
+	filter.Tag = strings.TrimSpace(filter.Tag)
+	return u.highlightRepo.Search(filter, (page-1)*limit, limit)
+}
+
+// Markdown は書籍のハイライトをMarkdownの文書にする（書籍を閲覧できる人なら誰でも書き出せる）
+func (u *highlightUsecase) Markdown(userID, bookID int) (*model.Book, string, error) {
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleViewer)
+	if err != nil {
+		return nil, "", err
+	}
+	highlights, err := u.highlightRepo.ListByBook(book.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return book, markdown.Highlights(book, highlights), nil
+}
+
+// ImportKindle は Kindle の My Clippings.txt のハイライトを自分の本棚の書籍に取り込む
+func (u *highlightUsecase) ImportKindle(userID int, r io.Reader, dryRun bool) (*model.HighlightImport, error) {
+	result, err := ereader.ParseKindle(r)
+	if err != nil {
+		return nil, err
+	}
+	return u.importClippings(userID, ereader.SourceKindle, result, dryRun)
+}
+
+// ImportKobo は Kobo の KoboReader.sqlite のハイライトを自分の本棚の書籍に取り込む
+func (u *highlightUsecase) ImportKobo(userID int, path string, dryRun bool) (*model.HighlightImport, error) {
+	result, err := ereader.ParseKobo(path)
+	if err != nil {
+		return nil, err
+	}
+	return u.importClippings(userID, ereader.SourceKobo, result, dryRun)
+}
+
+// importClippings は読み込んだハイライトを、タイトルと著者で結びついた書籍に登録する
+// ビジネスルール：
+// - 取り込み先は自分の本棚の書籍だけ（共有された書籍には取り込まない）
+// - 取り込み元でのキーが同じハイライトが書籍にあれば飛ばす（何度取り込んでも重複しない）
+// - 結びつく書籍がないハイライトは取り込まず、タイトルごとに件数を返す
+// - dryRun が true なら保存せずに件数だけを数える
+func (u *highlightUsecase) importClippings(userID int, source string, result *ereader.Result, dryRun bool) (*model.HighlightImport, error) {
+	books, err := u.bookRepo.WithOwner(userID).List(&model.BookFilter{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	matcher := ereader.NewMatcher(books)
+
+	report := &model.HighlightImport{
+		Source:    source,
+		DryRun:    dryRun,
+		Entries:   len(result.Clippings),
+		Books:     []*model.HighlightImportBook{},
+		Unmatched: []*model.HighlightImportUnmatched{},
+		Skipped:   result.Skipped,
+	}
+	bookReports := map[int]*model.HighlightImportBook{}
+	unmatched := map[string]*model.HighlightImportUnmatched{}
+	existing := map[int]map[string]bool{} // 書籍ごとの取り込み済みのキー
+	for _, clipping := range result.Clippings {
+		book := matcher.Match(clipping.Title, clipping.Author)
+		if book == nil {
+			name := clipping.Title + "\x00" + clipping.Author
+			if unmatched[name] == nil {
+				unmatched[name] = &model.HighlightImportUnmatched{Title: clipping.Title, Author: clipping.Author}
+				report.Unmatched = append(report.Unmatched, unmatched[name])
+			}
+			unmatched[name].Highlights++
+			continue
+		}
+
+		bookReport := bookReports[book.ID]
+		if bookReport == nil {
+			bookReport = &model.HighlightImportBook{BookID: book.ID, Title: book.Title, SourceTitle: clipping.Title}
+			bookReports[book.ID] = bookReport
+			report.Books = append(report.Books, bookReport)
+			if existing[book.ID], err = u.highlightRepo.SourceKeys(book.ID); err != nil {
+				return nil, err
+			}
+		}
+		if existing[book.ID][clipping.Key] {
+			bookReport.Duplicates++
+			report.Duplicates++
+			continue
+		}
+		existing[book.ID][clipping.Key] = true // 同じファイルの中で同じキーが続いた場合も重複させない
+
+		if !dryRun {
+			highlight := &model.Highlight{
+				BookID:    book.ID,
+				Kind:      model.KindHighlight,
+				Text:      clipping.Text,
+				Note:      clipping.Note,
+				Page:      clipping.Page,
+				Location:  clipping.Location,
+				Chapter:   clipping.Chapter,
+				SourceKey: clipping.Key,
+				CreatedAt: clipping.CreatedAt,
+			}
+			if highlight.CreatedAt.IsZero() {
+				highlight.CreatedAt = time.Now()
+			}
+			if _, err := u.highlightRepo.Create(highlight); err != nil {
+				return nil, fmt.Errorf("「%s」のハイライトの取り込みに失敗しました: %w", book.Title, err)
+			}
+		}
+		bookReport.Imported++
+		report.Imported++
+	}
+	return report, nil
+}
+
+// normalizeTags はカンマ区切りのタグの前後の空白と空のタグを取り除く関数
+// タグでの検索は「,タグ,」の完全一致で行うため、保存する前に形をそろえる
+func normalizeTags(tags string) string {
+	result := []string{}
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return strings.Join(result, ",")
+}
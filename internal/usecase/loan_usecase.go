@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"errors"  // エラーの定義
+	"fmt"     // エラーメッセージの作成
+	"strings" // 借りる人の名前の前後の空白の除去
+	"time"    // 貸し出し日時・返却日時
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// ErrAlreadyOnLoan は既に貸し出し中の書籍を貸し出そうとした場合のエラー
+var ErrAlreadyOnLoan = errors.New("この書籍は既に貸し出し中です")
+
+// ErrNotOnLoan は貸し出し中でない書籍の返却を記録しようとした場合のエラー
+var ErrNotOnLoan = errors.New("この書籍は貸し出し中ではありません")
+
+// LoanUsecase は書籍の貸し出しのビジネスロジックを定義するインターフェース
+type LoanUsecase interface {
+	Lend(userID, bookID int, req *model.LendRequest) (*model.Loan, error)     // 書籍を貸し出す
+	Return(userID, bookID int, req *model.ReturnRequest) (*model.Loan, error) // 書籍の返却を記録
+	History(userID, bookID int) ([]*model.Loan, error)                        // 書籍の貸し出し履歴
+	ListActive(userID int) ([]*model.Loan, error)                             // 本棚の貸し出し中の一覧
+	ListOverdue(userID int) ([]*model.Loan, error)                            // 本棚の返却期限を過ぎた貸し出しの一覧
+}
+
+// loanUsecase はLoanUsecaseインターフェースの実装
+type loanUsecase struct {
+	loanRepo    repository.LoanRepository // 貸し出しの保存先
+	bookUsecase BookUsecase               // 書籍の権限の確認に使う（共有された書籍は役割に従う）
+	userRepo    repository.UserRepository // 借りる人をユーザー名で指定したときの検索に使う
+	validator   *validator.Validate       // 入力データ検証用のバリデータ
+}
+
+// NewLoanUsecase は新しいLoanUsecaseを作成する関数
+func NewLoanUsecase(loanRepo repository.LoanRepository, bookUsecase BookUsecase, userRepo repository.UserRepository) LoanUsecase {
+	return &loanUsecase{loanRepo: loanRepo, bookUsecase: bookUsecase, userRepo: userRepo, validator: validator.New()}
+}
+
+// Lend は書籍を貸し出す
+// ビジネスルール：
+//   - 書籍を更新できる人（持ち主か editor として共有された人）だけが貸し出せる
+//   - 貸し出し中の書籍は、返却されるまで貸し出せない
+//   - 返却期限は貸し出し日時より後でなければならない
+func (u *loanUsecase) Lend(userID, bookID int, req *model.LendRequest) (*model.Loan, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if book.Loan != nil {
+		return nil, ErrAlreadyOnLoan
+	}
+
+	loan := &model.Loan{
+		BookID:       book.ID,
+		BorrowerName: strings.TrimSpace(req.BorrowerName),
+		LentAt:       time.Now(),
+		DueAt:        req.DueAt,
+		Notes:        req.Notes,
+	}
+	if req.LentAt != nil {
+		loan.LentAt = *req.LentAt
+	}
+	if loan.DueAt != nil && !loan.DueAt.After(loan.LentAt) {
+		return nil, fmt.Errorf("返却期限は貸し出し日時より後の日時を指定してください")
+	}
+
+	// アプリのユーザーに貸す場合は、名前を省略するとユーザー名を使う
+	if req.BorrowerUsername != "" {
+		if u.userRepo == nil {
+			return nil, fmt.Errorf("ユーザー %s が見つかりません", req.BorrowerUsername)
+		}
+		borrower, err := u.userRepo.GetByUsername(req.BorrowerUsername)
+		if err != nil {
+			return nil, fmt.Errorf("ユーザー %s が見つかりません", req.BorrowerUsername)
+		}
+		loan.BorrowerID = borrower.ID
+		if loan.BorrowerName == "" {
+			loan.BorrowerName = borrower.Username
+		}
+	}
+	if loan.BorrowerName == "" {
+		return nil, fmt.Errorf("借りる人の名前を指定してください")
+	}
+
+	created, err := u.loanRepo.Create(loan)
+	if err != nil {
+		// 確認の後に別のリクエストで貸し出された場合は、ユニークインデックスの違反になる
+		if _, activeErr := u.loanRepo.GetActive(book.ID); activeErr == nil {
+			return nil, ErrAlreadyOnLoan
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// Return は書籍の返却を記録する
+// ビジネスルール：書籍を更新できる人だけが記録でき、返却日時は貸し出し日時より前にできない
+func (u *loanUsecase) Return(userID, bookID int, req *model.ReturnRequest) (*model.Loan, error) {
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if book.Loan == nil {
+		return nil, ErrNotOnLoan
+	}
+
+	returnedAt := time.Now()
+	if req != nil && req.ReturnedAt != nil {
+		returnedAt = *req.ReturnedAt
+	}
+	if returnedAt.Before(book.Loan.LentAt) {
+		return nil, fmt.Errorf("返却日時は貸し出し日時以降の日時を指定してください")
+	}
+	return u.loanRepo.MarkReturned(book.Loan.ID, returnedAt)
+}
+
+// History は書籍の貸し出し履歴を返す（書籍を閲覧できる人なら誰でも見られる）
+func (u *loanUsecase) History(userID, bookID int) ([]*model.Loan, error) {
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return u.loanRepo.ListByBook(book.ID)
+}
+
+// ListActive は自分の本棚の貸し出し中の一覧を返す
+func (u *loanUsecase) ListActive(userID int) ([]*model.Loan, error) {
+	return u.loanRepo.ListActive(userID, nil)
+}
+
+// ListOverdue は自分の本棚の返却期限を過ぎた貸し出しの一覧を返す
+func (u *loanUsecase) ListOverdue(userID int) ([]*model.Loan, error) {
+	now := time.Now()
+	return u.loanRepo.ListActive(userID, &now)
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"errors"        // 貸し出しのエラーの判定
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 貸し出し日時・返却期限
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・貸し出しのリポジトリ
+)
+
+// TestLoanOverdue は返却期限を過ぎて返却されていない貸し出しだけが延滞の一覧に入り、
+// 貸し出し中の書籍は重ねて貸し出せないことを確認する
+func TestLoanOverdue(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	books := NewBookUsecase(repository.NewBookRepository(db), nil, nil, nil, nil)
+	loans := NewLoanUsecase(repository.NewLoanRepository(db), books, nil)
+
+	now := time.Now()
+	ago := func(days int) *time.Time { t := now.AddDate(0, 0, -days); return &t }
+	later := func(days int) *time.Time { t := now.AddDate(0, 0, days); return &t }
+	tests := []struct {
+		name     string
+		lentAt   *time.Time
+		dueAt    *time.Time
+		returned bool
+		overdue  bool
+	}{
+		{"返却期限を過ぎた", ago(10), ago(3), false, true},
+		{"返却期限前", ago(10), later(3), false, false},
+		{"返却期限なし", ago(100), nil, false, false},
+		{"返却期限を過ぎたが返却済み", ago(10), ago(3), true, false},
+	}
+	want := map[int]bool{} // 延滞の一覧に入るべき書籍
+	for _, tt := range tests {
+		book, err := books.CreateBook(&model.CreateBookRequest{Title: tt.name, Author: "著者", PurchaseDate: now.UTC()})
+		if err != nil {
+			t.Fatalf("CreateBook: %v", err)
+		}
+		if _, err := loans.Lend(0, book.ID, &model.LendRequest{BorrowerName: "友人", LentAt: tt.lentAt, DueAt: tt.dueAt}); err != nil {
+			t.Fatalf("%s: Lend: %v", tt.name, err)
+		}
+		if _, err := loans.Lend(0, book.ID, &model.LendRequest{BorrowerName: "別の友人"}); !errors.Is(err, ErrAlreadyOnLoan) {
+			t.Errorf("%s: 貸し出し中の書籍を貸し出したときのエラー = %v, want ErrAlreadyOnLoan", tt.name, err)
+		}
+		if tt.returned {
+			if _, err := loans.Return(0, book.ID, &model.ReturnRequest{ReturnedAt: ago(20)}); err == nil {
+				t.Errorf("%s: 貸し出し日時より前の返却を記録できました", tt.name)
+			}
+			if _, err := loans.Return(0, book.ID, nil); err != nil {
+				t.Fatalf("%s: Return: %v", tt.name, err)
+			}
+			if _, err := loans.Return(0, book.ID, nil); !errors.Is(err, ErrNotOnLoan) {
+				t.Errorf("%s: 返却済みの書籍の返却のエラー = %v, want ErrNotOnLoan", tt.name, err)
+			}
+		}
+		if tt.overdue {
+			want[book.ID] = true
+		}
+	}
+
+	overdue, err := loans.ListOverdue(0)
+	if err != nil {
+		t.Fatalf("ListOverdue: %v", err)
+	}
+	got := map[int]bool{}
+	for _, loan := range overdue {
+		got[loan.BookID] = true
+	}
+	if len(overdue) != len(want) || len(got) != len(want) {
+		t.Fatalf("ListOverdue = %d件, want %d件", len(overdue), len(want))
+	}
+	for id := range want {
+		if !got[id] {
+			t.Errorf("書籍 %d が延滞の一覧にありません", id)
+		}
+	}
+
+	active, err := loans.ListActive(0)
+	if err != nil {
+		t.Fatalf("ListActive: %v", err)
+	}
+	if len(active) != 3 {
+		t.Errorf("ListActive = %d件, want 3件（返却済みを除く）", len(active))
+	}
+}
@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"errors"  // エラーの定義
+	"fmt"     // エラーメッセージの作成
+	"sort"    // 場所・書籍の並べ替え
+	"strings" // 場所の名前の前後の空白の除去・道のりの連結
+	"time"    // 棚卸し表の作成日時
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// ErrLocationNotEmpty は下の場所や書籍がある場所を削除しようとした場合のエラー
+var ErrLocationNotEmpty = errors.New("この場所には下の場所または書籍があるため削除できません")
+
+// LocationUsecase は書籍の保管場所のビジネスロジックを定義するインターフェース
+type LocationUsecase interface {
+	Create(userID int, req *model.CreateLocationRequest) (*model.Location, error)     // 場所を作成
+	List(userID int) ([]*model.Location, error)                                       // 本棚の場所の一覧（階層順）
+	Get(userID, id int) (*model.Location, error)                                      // 場所を1件取得
+	Update(userID, id int, req *model.UpdateLocationRequest) (*model.Location, error) // 場所の親・種類・名前を変更
+	Delete(userID, id int) error                                                      // 空の場所を削除
+	MoveBooks(userID int, req *model.MoveBooksRequest) (int, error)                   // 書籍をまとめて別の場所に移動
+	Inventory(userID, id int) (*model.InventoryReport, error)                         // 場所の棚卸し表を作成
+}
+
+// locationUsecase はLocationUsecaseインターフェースの実装
+type locationUsecase struct {
+	locationRepo repository.LocationRepository // 場所の保存先
+	bookRepo     repository.BookRepository     // 棚卸し表の書籍の取得に使う（本棚に限定する前のリポジトリ）
+	bookUsecase  BookUsecase                   // 移動する書籍の権限の確認に使う（共有された書籍は役割に従う）
+	validator    *validator.Validate           // 入力データ検証用のバリデータ
+}
+
+// NewLocationUsecase は新しいLocationUsecaseを作成する関数
+func NewLocationUsecase(locationRepo repository.LocationRepository, bookRepo repository.BookRepository, bookUsecase BookUsecase) LocationUsecase {
+	return &locationUsecase{locationRepo: locationRepo, bookRepo: bookRepo, bookUsecase: bookUsecase, validator: validator.New()}
+}
+
+// locationTree はユーザーの場所をすべて読み込み、親子関係と道のりを計算したもの
+type locationTree struct {
+	byID     map[int]*model.Location   // IDから場所を引く
+	children map[int][]*model.Location // 親のIDから子の場所を引く（0は最上位の場所）
+}
+
+// loadTree はユーザーの場所をすべて読み込む
+// 場所の数は多くても数百件程度のため、階層をたどる処理はメモリ上で行う
+func (u *locationUsecase) loadTree(userID int) (*locationTree, error) {
+	locations, err := u.locationRepo.ListByOwner(userID)
+	if err != nil {
+		return nil, err
+	}
+	tree := &locationTree{byID: map[int]*model.Location{}, children: map[int][]*model.Location{}}
+	for _, location := range locations {
+		tree.byID[location.ID] = location
+	}
+	for _, location := range locations {
+		parentID := 0
+		if location.ParentID != nil {
+			parentID = *location.ParentID
+		}
+		tree.children[parentID] = append(tree.children[parentID], location)
+	}
+	// 同じ親の場所は名前順に並べる
+	for _, siblings := range tree.children {
+		sort.Slice(siblings, func(i, j int) bool { return siblings[i].Name < siblings[j].Name })
+	}
+	for _, location := range locations {
+		location.Path = tree.path(location)
+	}
+	return tree, nil
+}
+
+// path は最上位から場所までの道のりを返す（例：自宅 > 書斎 > 本棚A）
+func (t *locationTree) path(location *model.Location) string {
+	names := []string{location.Name}
+	current := location
+	// 親をたどる回数に上限を設けて、データの不整合で循環していても止まるようにする
+	for i := 0; i < len(t.byID) && current.ParentID != nil; i++ {
+		parent, ok := t.byID[*current.ParentID]
+		if !ok {
+			break
+		}
+		names = append([]string{parent.Name}, names...)
+		current = parent
+	}
+	return strings.Join(names, " > ")
+}
+
+// descendants は場所とその下のすべての場所を階層順（親の次にその子）に返す
+func (t *locationTree) descendants(location *model.Location) []*model.Location {
+	result := []*model.Location{location}
+	for _, child := range t.children[location.ID] {
+		result = append(result, t.descendants(child)...)
+	}
+	return result
+}
+
+// get はユーザーの場所を取得する（他のユーザーの場所は「見つからない」として扱う）
+func (t *locationTree) get(id int) (*model.Location, error) {
+	location, ok := t.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("ID %d の場所が見つかりません", id)
+	}
+	return location, nil
+}
+
+// checkKind は場所の種類が親と子の間に収まっているかを確認する
+// ビジネスルール：子の場所は親の場所より深い種類でなければならない（建物 > 部屋 > 本棚 > 位置、途中の省略は可）
+func (t *locationTree) checkKind(kind model.LocationKind, parent *model.Location, id int) error {
+	if parent != nil && kind.Level() <= parent.Kind.Level() {
+		return fmt.Errorf("%s の中に %s は置けません", parent.Kind, kind)
+	}
+	for _, child := range t.children[id] {
+		if id != 0 && child.Kind.Level() <= kind.Level() {
+			return fmt.Errorf("下の場所 %s（%s）があるため、種類を %s にできません", child.Name, child.Kind, kind)
+		}
+	}
+	return nil
+}
+
+// Create は場所を作成する
+func (u *locationUsecase) Create(userID int, req *model.CreateLocationRequest) (*model.Location, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	tree, err := u.loadTree(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *model.Location
+	if req.ParentID != nil {
+		if parent, err = tree.get(*req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tree.checkKind(req.Kind, parent, 0); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("場所の名前を指定してください")
+	}
+
+	location, err := u.locationRepo.Create(&model.Location{OwnerID: userID, ParentID: req.ParentID, Kind: req.Kind, Name: name})
+	if err != nil {
+		return nil, err
+	}
+	location.Path = name
+	if parent != nil {
+		location.Path = parent.Path + " > " + name
+	}
+	return location, nil
+}
+
+// List は本棚の場所を階層順（親の次にその子、同じ親の場所は名前順）に返す
+func (u *locationUsecase) List(userID int) ([]*model.Location, error) {
+	tree, err := u.loadTree(userID)
+	if err != nil {
+		return nil, err
+	}
+	locations := []*model.Location{}
+	for _, root := range tree.children[0] {
+		locations = append(locations, tree.descendants(root)...)
+	}
+	return locations, nil
+}
+
+// Get は場所を1件取得する
+func (u *locationUsecase) Get(userID, id int) (*model.Location, error) {
+	tree, err := u.loadTree(userID)
+	if err != nil {
+		return nil, err
+	}
+	return tree.get(id)
+}
+
+// Update は場所の親・種類・名前を変更する
+// ビジネスルール：場所を自分自身やその下の場所の中には移動できない（階層が循環してしまうため）
+func (u *locationUsecase) Update(userID, id int, req *model.UpdateLocationRequest) (*model.Location, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	tree, err := u.loadTree(userID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := tree.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *location
+	if req.Name != nil {
+		if updated.Name = strings.TrimSpace(*req.Name); updated.Name == "" {
+			return nil, fmt.Errorf("場所の名前を指定してください")
+		}
+	}
+	if req.Kind != nil {
+		updated.Kind = *req.Kind
+	}
+	if req.ParentID != nil {
+		updated.ParentID = nil // 0 は最上位
+		if *req.ParentID != 0 {
+			for _, sub := range tree.descendants(location) {
+				if sub.ID == *req.ParentID {
+					return nil, fmt.Errorf("場所を自分自身またはその下の場所の中には移動できません")
+				}
+			}
+			updated.ParentID = req.ParentID
+		}
+	}
+
+	var parent *model.Location
+	if updated.ParentID != nil {
+		if parent, err = tree.get(*updated.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tree.checkKind(updated.Kind, parent, id); err != nil {
+		return nil, err
+	}
+
+	saved, err := u.locationRepo.Update(&updated)
+	if err != nil {
+		return nil, err
+	}
+	saved.Path = saved.Name
+	if parent != nil {
+		saved.Path = parent.Path + " > " + saved.Name
+	}
+	return saved, nil
+}
+
+// Delete は場所を削除する
+// ビジネスルール：
+//   - 下の場所や書籍がある場所は削除できない（先に移動しておく）
+//   - ゴミ箱の書籍が置かれている場所も削除できない（元に戻したときに、削除した場所を指してしまうため）
+func (u *locationUsecase) Delete(userID, id int) error {
+	tree, err := u.loadTree(userID)
+	if err != nil {
+		return err
+	}
+	location, err := tree.get(id)
+	if err != nil {
+		return err
+	}
+	if len(tree.children[id]) > 0 || location.BookCount > 0 {
+		return ErrLocationNotEmpty
+	}
+	count, err := u.locationRepo.CountAllBooks(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w（ゴミ箱に%d冊あります。元に戻して別の場所に移動してください）", ErrLocationNotEmpty, count)
+	}
+	return u.locationRepo.Delete(id)
+}
+
+// MoveBooks は書籍をまとめて別の場所に移動し、移動した冊数を返す
+// ビジネスルール：
+//   - 書籍を更新できる人（持ち主か editor として共有された人）だけが移動できる
+//   - 移動先は書籍の持ち主の場所でなければならない
+//   - 1冊でも移動できない書籍があれば、どの書籍も移動しない
+func (u *locationUsecase) MoveBooks(userID int, req *model.MoveBooksRequest) (int, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return 0, fmt.Errorf("入力データが無効です: %w", err)
+	}
+
+	var location *model.Location
+	if req.LocationID != nil {
+		var err error
+		if location, err = u.locationRepo.GetByID(*req.LocationID); err != nil {
+			return 0, err
+		}
+	}
+
+	library := u.bookUsecase.ForUser(userID)
+	seen := map[int]bool{}
+	ids := []int{}
+	for _, id := range req.BookIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		book, err := library.Authorize(id, model.RoleEditor)
+		if err != nil {
+			return 0, err
+		}
+		if location != nil && location.OwnerID != book.OwnerID {
+			// 他のユーザーの場所は存在を知られないように「見つからない」として扱う
+			return 0, fmt.Errorf("ID %d の場所が見つかりません", location.ID)
+		}
+		ids = append(ids, id)
+	}
+	return u.locationRepo.MoveBooks(ids, req.LocationID)
+}
+
+// Inventory は場所とその下のすべての場所にある書籍の棚卸し表を作成する
+// 場所は階層順、各場所の書籍はタイトル順に並べる（書籍のない場所も空の棚として含める）
+func (u *locationUsecase) Inventory(userID, id int) (*model.InventoryReport, error) {
+	tree, err := u.loadTree(userID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := tree.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := u.bookRepo.WithOwner(userID).List(&model.BookFilter{LocationID: &id}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	byLocation := map[int][]*model.Book{}
+	for _, book := range books {
+		if book.LocationID != nil {
+			byLocation[*book.LocationID] = append(byLocation[*book.LocationID], book)
+		}
+	}
+
+	report := &model.InventoryReport{Location: location, TotalBooks: len(books), GeneratedAt: time.Now()}
+	for _, sub := range tree.descendants(location) {
+		shelf := byLocation[sub.ID]
+		if shelf == nil {
+			shelf = []*model.Book{}
+		}
+		sort.SliceStable(shelf, func(i, j int) bool { return shelf[i].Title < shelf[j].Title })
+		report.Sections = append(report.Sections, &model.InventorySection{Location: sub, Books: shelf})
+	}
+	return report, nil
+}
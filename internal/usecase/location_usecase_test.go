@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"path/filepath" // テスト用データベースのパス
+	"reflect"       // 書籍IDの比較
+	"sort"          // 書籍IDの並べ替え
+	"testing"       // テストの実行と結果の報告
+	"time"          // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・保管場所のリポジトリ
+)
+
+// TestLocationFilter は保管場所での絞り込みと棚卸し表に、下の場所に置かれた書籍も含まれることを確認する
+func TestLocationFilter(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	bookRepo := repository.NewBookRepository(db)
+	books := NewBookUsecase(bookRepo, nil, nil, nil, nil).ForUser(0)
+	locations := NewLocationUsecase(repository.NewLocationRepository(db), bookRepo, books)
+
+	// 自宅 > 書斎 > 本棚A > 上段、自宅 > 居間
+	create := func(parent *model.Location, kind model.LocationKind, name string) *model.Location {
+		t.Helper()
+		req := &model.CreateLocationRequest{Kind: kind, Name: name}
+		if parent != nil {
+			req.ParentID = &parent.ID
+		}
+		location, err := locations.Create(0, req)
+		if err != nil {
+			t.Fatalf("Create(%s): %v", name, err)
+		}
+		return location
+	}
+	home := create(nil, model.KindBuilding, "自宅")
+	study := create(home, model.KindRoom, "書斎")
+	shelf := create(study, model.KindShelf, "本棚A")
+	top := create(shelf, model.KindPosition, "上段")
+	living := create(home, model.KindRoom, "居間")
+	if _, err := locations.Create(0, &model.CreateLocationRequest{ParentID: &shelf.ID, Kind: model.KindRoom, Name: "部屋"}); err == nil {
+		t.Error("本棚の中に部屋を作成できました")
+	}
+
+	place := map[string]*model.Location{"上段の本": top, "本棚Aの本": shelf, "居間の本": living, "場所のない本": nil}
+	ids := map[string]int{}
+	for title, location := range place {
+		book, err := books.CreateBook(&model.CreateBookRequest{Title: title, Author: "著者", PurchaseDate: time.Now().UTC()})
+		if err != nil {
+			t.Fatalf("CreateBook: %v", err)
+		}
+		ids[title] = book.ID
+		if location != nil {
+			if _, err := locations.MoveBooks(0, &model.MoveBooksRequest{BookIDs: []int{book.ID}, LocationID: &location.ID}); err != nil {
+				t.Fatalf("MoveBooks: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		location *model.Location
+		want     []string
+	}{
+		{"建物（すべての下の場所）", home, []string{"上段の本", "本棚Aの本", "居間の本"}},
+		{"部屋（本棚と段の書籍も含む）", study, []string{"上段の本", "本棚Aの本"}},
+		{"本棚", shelf, []string{"上段の本", "本棚Aの本"}},
+		{"いちばん下の場所", top, []string{"上段の本"}},
+		{"別の部屋", living, []string{"居間の本"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []int{}
+			for _, title := range tt.want {
+				want = append(want, ids[title])
+			}
+			sort.Ints(want)
+
+			list, total, err := books.ListBooks(&model.BookFilter{LocationID: &tt.location.ID}, 1, 100)
+			if err != nil {
+				t.Fatalf("ListBooks: %v", err)
+			}
+			got := []int{}
+			for _, book := range list {
+				got = append(got, book.ID)
+			}
+			sort.Ints(got)
+			if total != len(want) || !reflect.DeepEqual(got, want) {
+				t.Errorf("ListBooks = %v（全%d冊）, want %v", got, total, want)
+			}
+
+			report, err := locations.Inventory(0, tt.location.ID)
+			if err != nil {
+				t.Fatalf("Inventory: %v", err)
+			}
+			if report.TotalBooks != len(want) {
+				t.Errorf("棚卸し表の書籍数 = %d, want %d", report.TotalBooks, len(want))
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"errors"  // エラーの定義
+	"fmt"     // エラーメッセージの作成
+	"strings" // 文字列の整形
+
+	"book-manager/internal/markdown"         // レビューのHTMLへの変換
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// ErrReviewExists は同じ書籍の同じ読書の回のレビューが既にある場合のエラー
+var ErrReviewExists = errors.New("この読書の回のレビューは既にあります")
+
+// ReviewUsecase はレビューのビジネスロジックを定義するインターフェース
+type ReviewUsecase interface {
+	Create(userID, bookID int, req *model.CreateReviewRequest) (*model.Review, error)     // レビューを書く
+	List(userID, bookID int) ([]*model.Review, error)                                     // 書籍のレビューの一覧（読書の回の順）
+	Get(userID, bookID, id int) (*model.Review, error)                                    // レビューを1件取得
+	Update(userID, bookID, id int, req *model.UpdateReviewRequest) (*model.Review, error) // レビューを更新
+	Delete(userID, bookID, id int) error                                                  // レビューを削除
+	HTML(userID, bookID, id int) (string, error)                                          // レビューを安全なHTMLにする
+	Revisions(userID, bookID, id int) ([]*model.ReviewRevision, error)                    // レビューの編集履歴（新しい順）
+	Revision(userID, bookID, id, version int) (*model.ReviewRevision, error)              // レビューの版を1件取得
+	Restore(userID, bookID, id, version int) (*model.Review, error)                       // 以前の版の内容に戻す
+	Preview(body string) string                                                           // 保存する前の本文をHTMLにする
+}
+
+// reviewUsecase はReviewUsecaseインターフェースの実装
+type reviewUsecase struct {
+	reviewRepo  repository.ReviewRepository // レビューの保存先
+	bookUsecase BookUsecase                 // 書籍の権限の確認に使う（共有された書籍は役割に従う）
+	validator   *validator.Validate         // 入力データ検証用のバリデータ
+}
+
+// NewReviewUsecase は新しいReviewUsecaseを作成する関数
+func NewReviewUsecase(reviewRepo repository.ReviewRepository, bookUsecase BookUsecase) ReviewUsecase {
+	return &reviewUsecase{reviewRepo: reviewRepo, bookUsecase: bookUsecase, validator: validator.New()}
+}
+
+// canEdit は書籍を更新できる人（持ち主か editor として共有された人）かどうかを確認する
+// 非公開のレビューを見られるか・レビューを書き換えられるかの判断に使う
+func (u *reviewUsecase) canEdit(userID, bookID int) (bool, error) {
+	_, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleEditor)
+	if errors.Is(err, ErrPermissionDenied) {
+		return false, nil // viewer として共有された人
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Create はレビューを書く
+// ビジネスルール：
+// - 書籍を更新できる人だけが書ける
+// - 1冊の書籍の1回の読書につきレビューは1件（read_number を省略すると、次の回のレビューになる）
+func (u *reviewUsecase) Create(userID, bookID int, req *model.CreateReviewRequest) (*model.Review, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	if req.Visibility == "" {
+		req.Visibility = model.VisibilityPrivate
+	}
+	if !req.Visibility.IsValid() {
+		return nil, fmt.Errorf("無効な公開範囲です: %s", req.Visibility)
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, fmt.Errorf("本文を入力してください")
+	}
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &model.Review{
+		BookID:     book.ID,
+		ReadNumber: req.ReadNumber,
+		ReadOn:     req.ReadOn,
+		Title:      strings.TrimSpace(req.Title),
+		Body:       strings.TrimSpace(req.Body),
+		Spoiler:    req.Spoiler,
+		Visibility: req.Visibility,
+	}
+	if review.ReadNumber == 0 {
+		if review.ReadNumber, err = u.reviewRepo.NextReadNumber(book.ID); err != nil {
+			return nil, err
+		}
+	} else if err := u.checkReadNumber(book.ID, review.ReadNumber, 0); err != nil {
+		return nil, err
+	}
+	return u.reviewRepo.Create(review, userID)
+}
+
+// checkReadNumber は同じ読書の回のレビューがないかを確認する
+func (u *reviewUsecase) checkReadNumber(bookID, readNumber, excludeID int) error {
+	exists, err := u.reviewRepo.ExistsReadNumber(bookID, readNumber, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%d回目の読書: %w", readNumber, ErrReviewExists)
+	}
+	return nil
+}
+
+// List は書籍のレビューの一覧を返す
+// 書籍を更新できる人にはすべてのレビューを、viewer として共有された人には公開のレビューだけを返す
+func (u *reviewUsecase) List(userID, bookID int) ([]*model.Review, error) {
+	book, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	editable, err := u.canEdit(userID, book.ID)
+	if err != nil {
+		return nil, err
+	}
+	return u.reviewRepo.ListByBook(book.ID, !editable)
+}
+
+// Get はレビューを1件取得する（非公開のレビューは書籍を更新できる人だけが見られる）
+func (u *reviewUsecase) Get(userID, bookID, id int) (*model.Review, error) {
+	if _, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	review, err := u.load(bookID, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Visibility != model.VisibilityPublic {
+		editable, err := u.canEdit(userID, bookID)
+		if err != nil {
+			return nil, err
+		}
+		if !editable {
+			// 非公開のレビューがあることを知られないよう「見つからない」として扱う
+			return nil, fmt.Errorf("ID %d のレビューが見つかりません", id)
+		}
+	}
+	return review, nil
+}
+
+// load はレビューを取得し、URLの書籍のレビューかどうかを確認する
+// 違う書籍のレビューは「見つからない」として扱う（他の書籍のレビューを操作させないため）
+func (u *reviewUsecase) load(bookID, id int) (*model.Review, error) {
+	review, err := u.reviewRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.BookID != bookID {
+		return nil, fmt.Errorf("ID %d のレビューが見つかりません", id)
+	}
+	return review, nil
+}
+
+// find は書籍を更新できるかを確認してからレビューを取得する（更新・削除・編集履歴に使う）
+func (u *reviewUsecase) find(userID, bookID, id int) (*model.Review, error) {
+	if _, err := u.bookUsecase.ForUser(userID).Authorize(bookID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	return u.load(bookID, id)
+}
+
+// Update はレビューを更新する（書籍を更新できる人だけ）
+// 見出し・本文・ネタバレの有無を変えると新しい版になり、以前の版は編集履歴に残る
+func (u *reviewUsecase) Update(userID, bookID, id int, req *model.UpdateReviewRequest) (*model.Review, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	if req.Visibility != nil && !req.Visibility.IsValid() {
+		return nil, fmt.Errorf("無効な公開範囲です: %s", *req.Visibility)
+	}
+	if req.Body != nil && strings.TrimSpace(*req.Body) == "" {
+		return nil, fmt.Errorf("本文を入力してください")
+	}
+	review, err := u.find(userID, bookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 指定された項目だけを更新する
+	if req.ReadNumber != nil && *req.ReadNumber != review.ReadNumber {
+		if err := u.checkReadNumber(bookID, *req.ReadNumber, review.ID); err != nil {
+			return nil, err
+		}
+		review.ReadNumber = *req.ReadNumber
+	}
+	if req.ReadOn != nil {
+		review.ReadOn = req.ReadOn
+	}
+	if req.Title != nil {
+		review.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Body != nil {
+		review.Body = strings.TrimSpace(*req.Body)
+	}
+	if req.Spoiler != nil {
+		review.Spoiler = *req.Spoiler
+	}
+	if req.Visibility != nil {
+		review.Visibility = *req.Visibility
+	}
+	return u.reviewRepo.Update(review, userID)
+}
+
+// Delete はレビューを編集履歴ごと削除する（書籍を更新できる人だけ）
+func (u *reviewUsecase) Delete(userID, bookID, id int) error {
+	review, err := u.find(userID, bookID, id)
+	if err != nil {
+		return err
+	}
+	return u.reviewRepo.Delete(review.ID)
+}
+
+// HTML はレビューを、そのまま表示しても安全なHTMLの断片にする（見られる人の範囲は Get と同じ）
+func (u *reviewUsecase) HTML(userID, bookID, id int) (string, error) {
+	review, err := u.Get(userID, bookID, id)
+	if err != nil {
+		return "", err
+	}
+	return markdown.Review(review), nil
+}
+
+// Revisions はレビューの編集履歴を新しい順に返す（書籍を更新できる人だけ。非公開だった頃の内容も含むため）
+func (u *reviewUsecase) Revisions(userID, bookID, id int) ([]*model.ReviewRevision, error) {
+	review, err := u.find(userID, bookID, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.reviewRepo.Revisions(review.ID)
+}
+
+// Revision はレビューの版を1件返す（書籍を更新できる人だけ）
+func (u *reviewUsecase) Revision(userID, bookID, id, version int) (*model.ReviewRevision, error) {
+	review, err := u.find(userID, bookID, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.reviewRepo.Revision(review.ID, version)
+}
+
+// Restore はレビューの見出し・本文・ネタバレの有無を以前の版の内容に戻す（書籍を更新できる人だけ）
+// 履歴は書き換えず、戻した内容を新しい版として記録する（戻す前の版も残る）
+func (u *reviewUsecase) Restore(userID, bookID, id, version int) (*model.Review, error) {
+	review, err := u.find(userID, bookID, id)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := u.reviewRepo.Revision(review.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	review.Title, review.Body, review.Spoiler = revision.Title, revision.Body, revision.Spoiler
+	return u.reviewRepo.Update(review, userID)
+}
+
+// Preview は保存する前のレビューの本文を安全なHTMLにする（編集画面のプレビュー用）
+func (u *reviewUsecase) Preview(body string) string {
+	return markdown.ToHTML(body)
+}
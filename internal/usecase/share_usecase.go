@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"encoding/json" // 変更履歴に保存する共有設定のJSON変換
+	"fmt"           // エラーメッセージの作成
+	"log"           // 変更履歴の記録失敗のログ出力
+	"strings"       // タグの前後の空白の除去
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// ShareUsecase は本棚の共有のビジネスロジックを定義するインターフェース
+type ShareUsecase interface {
+	Grant(ownerID int, req *model.CreateShareRequest) (*model.Share, error)     // 本棚（またはタグで絞り込んだ一部）を共有
+	Revoke(ownerID, id int) error                                               // 共有をやめる
+	List(userID int) (*model.ShareList, error)                                  // 自分が共有した設定と、自分に共有された設定の一覧
+	ListBooks(userID, shareID int, page, limit int) ([]*model.Book, int, error) // 自分に共有された書籍の一覧
+}
+
+// shareUsecase はShareUsecaseインターフェースの実装
+type shareUsecase struct {
+	shareRepo repository.ShareRepository // 共有設定の保存先
+	userRepo  repository.UserRepository  // 共有相手の検索に使う
+	bookRepo  repository.BookRepository  // 共有された書籍の取得に使う（本棚に限定する前のリポジトリ）
+	auditRepo repository.AuditRepository // 変更履歴の記録先
+	validator *validator.Validate        // 入力データ検証用のバリデータ
+}
+
+// NewShareUsecase は新しいShareUsecaseを作成する関数
+func NewShareUsecase(shareRepo repository.ShareRepository, userRepo repository.UserRepository, bookRepo repository.BookRepository, auditRepo repository.AuditRepository) ShareUsecase {
+	return &shareUsecase{shareRepo: shareRepo, userRepo: userRepo, bookRepo: bookRepo, auditRepo: auditRepo, validator: validator.New()}
+}
+
+// Grant は本棚を他のユーザーに共有する
+// ビジネスルール：自分自身には共有できない。同じ相手・同じ範囲の共有が既にあれば役割を更新する
+func (u *shareUsecase) Grant(ownerID int, req *model.CreateShareRequest) (*model.Share, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+
+	grantee, err := u.userRepo.GetByUsername(req.Username)
+	if err != nil {
+		return nil, fmt.Errorf("ユーザー %s が見つかりません", req.Username)
+	}
+	if grantee.ID == ownerID {
+		return nil, fmt.Errorf("自分自身には共有できません")
+	}
+
+	tag := strings.TrimSpace(req.Tag)
+	// 同じ相手・同じ範囲の共有が既にあるか（変更履歴を「作成」と「更新」で分けるため）
+	grants, err := u.shareRepo.ListGrants(ownerID, grantee.ID)
+	if err != nil {
+		return nil, err
+	}
+	var before *model.Share
+	for _, grant := range grants {
+		if grant.Tag == tag {
+			before = grant
+		}
+	}
+
+	share, err := u.shareRepo.Save(&model.Share{
+		OwnerID:   ownerID,
+		GranteeID: grantee.ID,
+		Role:      req.Role,
+		Tag:       tag,
+	})
+	if err != nil {
+		return nil, err
+	}
+	action := model.ActionCreate
+	if before != nil {
+		action = model.ActionUpdate
+	}
+	u.record(ownerID, action, before, share)
+	return share, nil
+}
+
+// Revoke は共有をやめる（持ち主だけができる）
+func (u *shareUsecase) Revoke(ownerID, id int) error {
+	share, err := u.shareRepo.GetByID(id)
+	if err != nil || share.OwnerID != ownerID {
+		return fmt.Errorf("ID %d の共有設定が見つかりません", id)
+	}
+	if err := u.shareRepo.Delete(ownerID, id); err != nil {
+		return err
+	}
+	u.record(ownerID, model.ActionDelete, share, nil)
+	return nil
+}
+
+// List は自分が共有した設定と、自分に共有された設定の一覧を返す
+func (u *shareUsecase) List(userID int) (*model.ShareList, error) {
+	given, err := u.shareRepo.ListByOwner(userID)
+	if err != nil {
+		return nil, err
+	}
+	received, err := u.shareRepo.ListByGrantee(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ShareList{Given: given, Received: received}, nil
+}
+
+// ListBooks は自分に共有された書籍の一覧を返す（ページネーション対応）
+// タグで範囲を絞った共有の場合は、そのタグが付いた書籍だけを返す
+func (u *shareUsecase) ListBooks(userID, shareID int, page, limit int) ([]*model.Book, int, error) {
+	share, err := u.shareRepo.GetByID(shareID)
+	if err != nil || share.GranteeID != userID {
+		return nil, 0, fmt.Errorf("ID %d の共有設定が見つかりません", shareID)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// タグで範囲を絞った共有は、タグが完全一致する書籍だけ（Share.Covers と同じ）をデータベースで絞り込む
+	filter := &model.BookFilter{}
+	if share.Tag != "" {
+		filter.ExactTag = &share.Tag
+	}
+	books := u.bookRepo.WithOwner(share.OwnerID)
+	total, err := books.Count(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := books.List(filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// record は共有設定の変更履歴を記録する
+func (u *shareUsecase) record(ownerID int, action string, before, after *model.Share) {
+	if u.auditRepo == nil {
+		return
+	}
+	entry := &model.AuditLog{ActorID: ownerID, OwnerID: ownerID, EntityType: model.EntityShare, Action: action}
+	var err error
+	if before != nil {
+		entry.EntityID = before.ID
+		if entry.Before, err = json.Marshal(before); err != nil {
+			log.Printf("変更履歴のJSON変換に失敗しました: %v", err)
+			return
+		}
+	}
+	if after != nil {
+		entry.EntityID = after.ID
+		if entry.After, err = json.Marshal(after); err != nil {
+			log.Printf("変更履歴のJSON変換に失敗しました: %v", err)
+			return
+		}
+	}
+	if err := u.auditRepo.Record(entry); err != nil {
+		log.Printf("%v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"errors"        // 権限エラーの判定
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・共有設定・ユーザーのリポジトリ
+)
+
+// shareFixture は共有のテストで使うユーザーとユースケース
+type shareFixture struct {
+	books     repository.BookRepository
+	shareRepo repository.ShareRepository
+	shares    ShareUsecase
+	users     map[string]int // ユーザー名 → ID
+}
+
+// newShareFixture はテスト用のデータベースに alice・bob・carol を登録する
+func newShareFixture(t *testing.T) *shareFixture {
+	t.Helper()
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	userRepo := repository.NewUserRepository(db)
+	f := &shareFixture{books: repository.NewBookRepository(db), shareRepo: repository.NewShareRepository(db), users: map[string]int{}}
+	f.shares = NewShareUsecase(f.shareRepo, userRepo, f.books, nil)
+	for _, name := range []string{"alice", "bob", "carol"} {
+		user, err := userRepo.Register(&model.User{Username: name, PasswordHash: "x"}, func(int) error { return nil })
+		if err != nil {
+			t.Fatalf("Register(%s): %v", name, err)
+		}
+		f.users[name] = user.ID
+	}
+	return f
+}
+
+// TestShareListBooks はタグで範囲を絞った共有で、タグが完全一致する書籍だけをページごとに返すことを確認する
+func TestShareListBooks(t *testing.T) {
+	f := newShareFixture(t)
+	alice := f.books.WithOwner(f.users["alice"])
+	for _, tags := range []string{"SF", "sf-short", " sf , 小説", "料理"} {
+		if _, err := alice.Create(&model.CreateBookRequest{Title: tags, Author: "著者", PurchaseDate: time.Now().UTC(), Tags: tags}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	share, err := f.shares.Grant(f.users["alice"], &model.CreateShareRequest{Username: "bob", Role: model.RoleViewer, Tag: "SF"})
+	if err != nil {
+		t.Fatalf("Grant: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		userID    int
+		page      int
+		wantBooks int
+		wantErr   bool
+	}{
+		{"1ページ目", f.users["bob"], 1, 1, false},
+		{"2ページ目", f.users["bob"], 2, 1, false},
+		{"範囲外のページ", f.users["bob"], 3, 0, false},
+		{"共有されていないユーザー", f.users["carol"], 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books, total, err := f.shares.ListBooks(tt.userID, share.ID, tt.page, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListBooks のエラー = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if total != 2 || len(books) != tt.wantBooks {
+				t.Errorf("ListBooks = %d冊（全%d冊）, want %d冊（全2冊）", len(books), total, tt.wantBooks)
+			}
+			for _, book := range books {
+				if !share.Covers(book) {
+					t.Errorf("共有の範囲外の書籍 %q（タグ %q）が含まれています", book.Title, book.Tags)
+				}
+			}
+		})
+	}
+}
+
+// TestShareRoles は共有された書籍を役割（viewer：閲覧、editor：更新・削除）と範囲（タグ）の内側でだけ操作できることを確認する
+func TestShareRoles(t *testing.T) {
+	f := newShareFixture(t)
+	alice := f.books.WithOwner(f.users["alice"])
+	sf, err := alice.Create(&model.CreateBookRequest{Title: "SFの本", Author: "著者", PurchaseDate: time.Now().UTC(), Tags: "SF"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other, err := alice.Create(&model.CreateBookRequest{Title: "料理の本", Author: "著者", PurchaseDate: time.Now().UTC(), Tags: "料理"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// bob にはSFのタグの書籍だけを閲覧者として、carol には本棚全体を編集者として共有する
+	if _, err := f.shares.Grant(f.users["alice"], &model.CreateShareRequest{Username: "bob", Role: model.RoleViewer, Tag: "sf"}); err != nil {
+		t.Fatalf("Grant(bob): %v", err)
+	}
+	if _, err := f.shares.Grant(f.users["alice"], &model.CreateShareRequest{Username: "carol", Role: model.RoleEditor}); err != nil {
+		t.Fatalf("Grant(carol): %v", err)
+	}
+	if _, err := f.shares.Grant(f.users["alice"], &model.CreateShareRequest{Username: "alice", Role: model.RoleViewer}); err == nil {
+		t.Error("自分自身に共有できました")
+	}
+
+	title := "変更後のタイトル"
+	tests := []struct {
+		name     string
+		user     string
+		bookID   int
+		required model.ShareRole
+		want     error // nil：操作できる、ErrPermissionDenied：役割が足りない、errShareNotFound：見つからない
+	}{
+		{"閲覧者は範囲内の書籍を閲覧できる", "bob", sf.ID, model.RoleViewer, nil},
+		{"閲覧者は更新できない", "bob", sf.ID, model.RoleEditor, ErrPermissionDenied},
+		{"範囲外の書籍は見つからない", "bob", other.ID, model.RoleViewer, errShareNotFound},
+		{"編集者は本棚全体を更新できる", "carol", other.ID, model.RoleEditor, nil},
+		{"共有されていない書籍は見つからない", "bob", other.ID, model.RoleEditor, errShareNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books := NewBookUsecase(f.books, f.shareRepo, nil, nil, nil).ForUser(f.users[tt.user])
+			var err error
+			if tt.required == model.RoleEditor {
+				_, err = books.UpdateBook(tt.bookID, &model.UpdateBookRequest{Title: &title})
+			} else {
+				_, err = books.GetBook(tt.bookID)
+			}
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("操作できません: %v", err)
+			case tt.want == ErrPermissionDenied && !errors.Is(err, ErrPermissionDenied):
+				t.Errorf("エラー = %v, want ErrPermissionDenied", err)
+			case tt.want == errShareNotFound && (err == nil || errors.Is(err, ErrPermissionDenied)):
+				t.Errorf("エラー = %v, want 見つからない", err)
+			}
+		})
+	}
+	got, err := alice.GetByID(sf.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Title != "SFの本" {
+		t.Errorf("閲覧者の更新が保存されました: %q", got.Title)
+	}
+}
+
+// errShareNotFound は TestShareRoles で「見つからない」エラーを期待することを表す
+var errShareNotFound = errors.New("見つからない")
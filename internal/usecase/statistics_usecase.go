@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"errors" // エラーの定義
+	"fmt"    // エラーメッセージの作成
+	"sort"   // 年間の振り返りの並べ替え
+	"time"   // 期間の計算
+
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 自作のデータアクセス層
+)
+
+// ErrInvalidStatsQuery は統計の集計の単位・値・内訳の指定が正しくない場合のエラー
+var ErrInvalidStatsQuery = errors.New("統計の指定が正しくありません")
+
+// defaultBreakdownLimit は内訳の件数を省略したときの件数
+const defaultBreakdownLimit = 20
+
+// maxBreakdownLimit は内訳の件数の上限
+const maxBreakdownLimit = 100
+
+// maxSeriesPoints は時系列の統計で返す期間の数の上限（日ごとで約10年分）
+// データのない期間を補完するため、範囲が広すぎるとレスポンスが大きくなりすぎる
+const maxSeriesPoints = 3700
+
+// reviewTopLimit は年間の振り返りに載せる、評価の高い書籍・よく読んだ著者の件数
+const reviewTopLimit = 5
+
+// StatisticsUsecase は書籍の詳しい統計のビジネスロジックを定義するインターフェース
+// 集計はすべてデータベースで行い、全書籍を読み込まない
+type StatisticsUsecase interface {
+	TimeSeries(userID int, interval model.StatsInterval, metric model.SeriesMetric, from, to *time.Time) (*model.TimeSeries, error) // 時系列の統計
+	Breakdown(userID int, q *model.BreakdownQuery) ([]*model.BreakdownItem, error)                                                  // タグ・出版社・著者・評価・店・購入方法・形態ごとの内訳
+	Insights(userID int) (*model.ReadingInsights, error)                                                                            // 読了までの日数・積読・連続読書日数
+	YearInReview(userID, year int) (*model.YearInReview, error)                                                                     // 1年間の読書の振り返り
+}
+
+// statisticsUsecase はStatisticsUsecaseインターフェースの実装
+type statisticsUsecase struct {
+	statsRepo repository.StatisticsRepository // 統計の集計
+}
+
+// NewStatisticsUsecase は新しいStatisticsUsecaseを作成する関数
+func NewStatisticsUsecase(statsRepo repository.StatisticsRepository) StatisticsUsecase {
+	return &statisticsUsecase{statsRepo: statsRepo}
+}
+
+// periodFormat は集計の単位ごとの期間の文字列の形式を返す関数（データベースの DateLabel と同じ形式）
+func periodFormat(interval model.StatsInterval) (string, bool) {
+	switch interval {
+	case model.IntervalDay, model.IntervalWeek:
+		return "2006-01-02", true
+	case model.IntervalMonth:
+		return "2006-01", true
+	case model.IntervalYear:
+		return "2006", true
+	default:
+		return "", false
+	}
+}
+
+// periodLabel は日時をその日が含まれる期間の文字列にする関数（週は月曜日の日付）
+func periodLabel(t time.Time, interval model.StatsInterval) string {
+	layout, _ := periodFormat(interval)
+	t = t.UTC()
+	if interval == model.IntervalWeek {
+		// time.Weekday は日曜日が0のため、月曜日からの日数に直して戻す
+		t = t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	}
+	return t.Format(layout)
+}
+
+// nextPeriod は期間の開始日から次の期間の開始日を返す関数
+func nextPeriod(t time.Time, interval model.StatsInterval) time.Time {
+	switch interval {
+	case model.IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case model.IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	case model.IntervalYear:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// TimeSeries は購入した冊数・読み終えた冊数・購入金額を期間ごとに返す
+// from・to を省略すると、データのある最初の期間から最後の期間までを返す
+// データのない期間も値0で補完する（補完はデータベースの集計結果を並べるだけで、書籍は読み込まない）
+func (u *statisticsUsecase) TimeSeries(userID int, interval model.StatsInterval, metric model.SeriesMetric, from, to *time.Time) (*model.TimeSeries, error) {
+	if interval == "" {
+		interval = model.IntervalMonth
+	}
+	layout, ok := periodFormat(interval)
+	if !ok {
+		return nil, fmt.Errorf("%w: interval は day・week・month・year のいずれかを指定してください（%q）", ErrInvalidStatsQuery, interval)
+	}
+	switch metric {
+	case model.SeriesPurchased, model.SeriesCompleted, model.SeriesSpent:
+	default:
+		return nil, fmt.Errorf("%w: metric は purchased・completed・spent のいずれかを指定してください（%q）", ErrInvalidStatsQuery, metric)
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, fmt.Errorf("%w: to は from 以降の日付を指定してください", ErrInvalidStatsQuery)
+	}
+
+	var fromLabel, toLabel string
+	if from != nil {
+		fromLabel = periodLabel(*from, interval)
+	}
+	if to != nil {
+		toLabel = periodLabel(*to, interval)
+	}
+	points, err := u.statsRepo.TimeSeries(userID, interval, metric, fromLabel, toLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &model.TimeSeries{Interval: interval, Metric: metric, Points: []*model.TimeSeriesPoint{}}
+	if metric == model.SeriesSpent {
+		series.Currency = u.statsRepo.BaseCurrency()
+	}
+	values := map[string]int{}
+	for _, point := range points {
+		values[point.Period] = point.Value
+		series.Total += point.Value
+	}
+	if fromLabel == "" && len(points) > 0 {
+		fromLabel = points[0].Period
+	}
+	if toLabel == "" && len(points) > 0 {
+		toLabel = points[len(points)-1].Period
+	}
+	if fromLabel == "" || toLabel == "" {
+		return series, nil // 範囲の指定もデータもない
+	}
+
+	start, err := time.Parse(layout, fromLabel)
+	if err != nil {
+		return nil, fmt.Errorf("期間の解析に失敗しました: %w", err)
+	}
+	for t := start; t.Format(layout) <= toLabel; t = nextPeriod(t, interval) {
+		if len(series.Points) >= maxSeriesPoints {
+			return nil, fmt.Errorf("%w: 期間が長すぎます（%d件まで）。from・to で範囲を絞るか、interval を大きくしてください", ErrInvalidStatsQuery, maxSeriesPoints)
+		}
+		label := t.Format(layout)
+		series.Points = append(series.Points, &model.TimeSeriesPoint{Period: label, Value: values[label]})
+	}
+	return series, nil
+}
+
+// Breakdown はタグ・出版社・著者・評価・店・購入方法・形態ごとの内訳を返す
+// q.From・q.To を指定すると、その期間に購入した書籍だけを集計する
+func (u *statisticsUsecase) Breakdown(userID int, q *model.BreakdownQuery) ([]*model.BreakdownItem, error) {
+	switch q.By {
+	case model.BreakdownTag, model.BreakdownPublisher, model.BreakdownAuthor, model.BreakdownRating,
+		model.BreakdownStore, model.BreakdownChannel, model.BreakdownFormat:
+	default:
+		return nil, fmt.Errorf("%w: by は tag・publisher・author・rating・store・purchase_channel・format のいずれかを指定してください（%q）", ErrInvalidStatsQuery, q.By)
+	}
+	switch q.Sort {
+	case "":
+		q.Sort = model.SortByBooks
+	case model.SortByBooks, model.SortBySpent:
+	default:
+		return nil, fmt.Errorf("%w: sort は books・spent のいずれかを指定してください（%q）", ErrInvalidStatsQuery, q.Sort)
+	}
+	if q.From != nil && q.To != nil && q.To.Before(*q.From) {
+		return nil, fmt.Errorf("%w: to は from 以降の日付を指定してください", ErrInvalidStatsQuery)
+	}
+	if q.Limit < 1 {
+		q.Limit = defaultBreakdownLimit
+	}
+	if q.Limit > maxBreakdownLimit {
+		q.Limit = maxBreakdownLimit
+	}
+	return u.statsRepo.Breakdown(userID, q)
+}
+
+// Insights は読了までの平均日数・積読・連続読書日数を返す
+// 連続読書日数は、データベースでまとめた「読書した日が続いた期間」から計算する
+func (u *statisticsUsecase) Insights(userID int) (*model.ReadingInsights, error) {
+	now := time.Now()
+	insights := &model.ReadingInsights{}
+
+	average, finished, err := u.statsRepo.FinishTime(userID)
+	if err != nil {
+		return nil, err
+	}
+	insights.AverageDaysToFinish, insights.FinishedBooks = average, finished
+
+	backlog, err := u.statsRepo.Backlog(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	insights.Backlog = *backlog
+
+	spans, err := u.statsRepo.ReadingSpans(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	for _, span := range spans {
+		days := int(span.To.Sub(span.From).Hours()/24) + 1
+		insights.Streaks.ReadingDays += days
+		if days > insights.Streaks.Longest {
+			insights.Streaks.Longest = days
+			insights.Streaks.LongestSpan = span
+		}
+		// 今日か昨日まで続いている期間が、現在の連続日数（未来の日付は数えない）
+		if !span.To.Before(yesterday) && !span.From.After(today) {
+			end := span.To
+			if end.After(today) {
+				end = today
+			}
+			insights.Streaks.Current = int(end.Sub(span.From).Hours()/24) + 1
+		}
+	}
+	return insights, nil
+}
+
+// YearInReview は1年間の読書の振り返りを返す
+// 月ごとの冊数・金額は TimeSeries（データベースでの集計）を使い、ランキングはその年に読み終えた書籍から作る
+func (u *statisticsUsecase) YearInReview(userID, year int) (*model.YearInReview, error) {
+	if year < 1900 || year > 9999 {
+		return nil, fmt.Errorf("%w: year は1900〜9999で指定してください（%d）", ErrInvalidStatsQuery, year)
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	review := &model.YearInReview{Year: year, GeneratedAt: time.Now(), TopRated: []*model.ReviewBook{}, TopAuthors: []*model.AuthorCount{}}
+
+	finished, err := u.TimeSeries(userID, model.IntervalMonth, model.SeriesCompleted, &from, &to)
+	if err != nil {
+		return nil, err
+	}
+	spent, err := u.TimeSeries(userID, model.IntervalMonth, model.SeriesSpent, &from, &to)
+	if err != nil {
+		return nil, err
+	}
+	purchased, err := u.TimeSeries(userID, model.IntervalMonth, model.SeriesPurchased, &from, &to)
+	if err != nil {
+		return nil, err
+	}
+	review.FinishedPerMonth, review.SpendPerMonth = finished.Points, spent.Points
+	review.TotalSpent, review.BooksPurchased = spent.Total, purchased.Total
+	review.Currency = spent.Currency
+
+	books, err := u.statsRepo.FinishedInYear(userID, year)
+	if err != nil {
+		return nil, err
+	}
+	review.BooksFinished = len(books)
+
+	ratingSum, rated := 0, 0
+	authors := map[string]int{}
+	for _, book := range books {
+		if book.PageCount != nil {
+			review.PagesRead += *book.PageCount
+		} else {
+			review.BooksWithoutPage++
+		}
+		if book.Rating != nil {
+			ratingSum += *book.Rating
+			rated++
+			review.TopRated = append(review.TopRated, book)
+		}
+		authors[book.Author]++
+		if book.Days != nil {
+			// 同じ日数なら先に読み終えた書籍を残す
+			if review.LongestRead == nil || *book.Days > *review.LongestRead.Days {
+				review.LongestRead = book
+			}
+			if review.FastestRead == nil || *book.Days < *review.FastestRead.Days {
+				review.FastestRead = book
+			}
+		}
+	}
+	if rated > 0 {
+		average := round2(float64(ratingSum) / float64(rated))
+		review.AverageRating = &average
+	}
+
+	// 評価の高い順（同じ評価なら読み終えた順）
+	sort.SliceStable(review.TopRated, func(i, j int) bool { return *review.TopRated[i].Rating > *review.TopRated[j].Rating })
+	if len(review.TopRated) > reviewTopLimit {
+		review.TopRated = review.TopRated[:reviewTopLimit]
+	}
+
+	// 読み終えた冊数の多い順（同じ冊数なら著者名の順）
+	for author, count := range authors {
+		review.TopAuthors = append(review.TopAuthors, &model.AuthorCount{Author: author, Books: count})
+	}
+	sort.Slice(review.TopAuthors, func(i, j int) bool {
+		a, b := review.TopAuthors[i], review.TopAuthors[j]
+		if a.Books != b.Books {
+			return a.Books > b.Books
+		}
+		return a.Author < b.Author
+	})
+	if len(review.TopAuthors) > reviewTopLimit {
+		review.TopAuthors = review.TopAuthors[:reviewTopLimit]
+	}
+	return review, nil
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"bytes"         // HTMLの書き出し先
+	"path/filepath" // テスト用データベースのパス
+	"strings"       // HTMLの内容の確認
+	"testing"       // テストの実行と結果の報告
+	"time"          // 購入日・読書の開始日と終了日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・統計のリポジトリ
+	"book-manager/internal/yearreview" // 年間の振り返りのHTML
+)
+
+// TestYearInReview は1年間に読み終えた書籍から振り返りを作り、
+// HTML（SVGのグラフを含む）ではタイトル・著者がエスケープされることを確認する
+func TestYearInReview(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	books := repository.NewBookRepository(db).WithOwner(0)
+	day := func(year int, month time.Month, d int) *time.Time {
+		t := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	completed := model.StatusCompleted
+	intPtr := func(n int) *int { return &n }
+	author := `<script>alert("著者")</script>`
+	for _, b := range []struct {
+		title      string
+		author     string
+		start, end *time.Time
+		rating     *int
+		pages      *int
+	}{
+		{`"><svg onload=alert(1)>`, author, day(2026, 1, 1), day(2026, 1, 11), intPtr(5), intPtr(300)},
+		{"2冊目", author, day(2026, 3, 1), day(2026, 3, 3), intPtr(3), nil},
+		{"別の著者の本", "別の著者", nil, day(2026, 3, 20), nil, intPtr(100)},
+		{"前の年の本", "別の著者", nil, day(2025, 12, 31), intPtr(1), intPtr(500)},
+	} {
+		book, err := books.Create(&model.CreateBookRequest{Title: b.title, Author: b.author, PurchaseDate: *day(2025, 12, 1), PageCount: b.pages})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := books.Update(book.ID, &model.UpdateBookRequest{Status: &completed, StartReadDate: b.start, EndReadDate: b.end, Rating: b.rating}); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	stats := NewStatisticsUsecase(repository.NewStatisticsRepository(db, "JPY"))
+	review, err := stats.YearInReview(0, 2026)
+	if err != nil {
+		t.Fatalf("YearInReview: %v", err)
+	}
+	if review.BooksFinished != 3 || review.PagesRead != 400 || review.BooksWithoutPage != 1 {
+		t.Errorf("読み終えた本 = %d冊・%dページ（未登録%d冊）, want 3冊・400ページ（未登録1冊）", review.BooksFinished, review.PagesRead, review.BooksWithoutPage)
+	}
+	if review.AverageRating == nil || *review.AverageRating != 4 {
+		t.Errorf("平均評価 = %v, want 4", review.AverageRating)
+	}
+	if len(review.FinishedPerMonth) != 12 || review.FinishedPerMonth[2].Value != 2 {
+		t.Errorf("月ごとの読み終えた冊数 = %d件（3月 %v）, want 12件（3月 2冊）", len(review.FinishedPerMonth), review.FinishedPerMonth)
+	}
+	if len(review.TopAuthors) != 2 || review.TopAuthors[0].Author != author || review.TopAuthors[0].Books != 2 {
+		t.Errorf("よく読んだ著者 = %+v, want %s（2冊）が最初", review.TopAuthors, author)
+	}
+	if review.LongestRead == nil || *review.LongestRead.Days != 10 || review.FastestRead == nil || *review.FastestRead.Days != 2 {
+		t.Errorf("最も長くかかった本・最も早く読み終えた本 = %+v, %+v, want 10日・2日", review.LongestRead, review.FastestRead)
+	}
+	if _, err := stats.YearInReview(0, 10000); err == nil {
+		t.Error("範囲外の年の振り返りを作成できました")
+	}
+
+	var out bytes.Buffer
+	if err := yearreview.WriteHTML(&out, review); err != nil {
+		t.Fatalf("WriteHTML: %v", err)
+	}
+	html := out.String()
+	for _, raw := range []string{"<script>", "<svg onload"} {
+		if strings.Contains(html, raw) {
+			t.Errorf("HTMLにエスケープされていない %q が含まれています", raw)
+		}
+	}
+	if !strings.Contains(html, "&lt;script&gt;") || !strings.Contains(html, "&lt;svg onload=alert(1)&gt;") {
+		t.Error("タイトル・著者がエスケープされて表示されていません")
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"fmt"     // エラーメッセージの作成
+	"strings" // トークンの接頭辞の判定
+	"time"    // 有効期限と最終使用日時
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// TokenPrefix は個人用アクセストークンの先頭に付ける文字列
+// セッショントークンと見分けられるようにする（ログやコードに紛れ込んだときにも気付きやすい）
+const TokenPrefix = "bmpat_"
+
+// lastUsedInterval は最終使用日時を記録し直すまでの間隔
+// リクエストのたびにデータベースへ書き込まないように、この間隔より古い場合だけ更新する
+const lastUsedInterval = time.Minute
+
+// TokenUsecase は個人用アクセストークンのビジネスロジックを定義するインターフェース
+type TokenUsecase interface {
+	Create(userID int, req *model.CreateTokenRequest) (*model.CreatedToken, error) // トークンを作成
+	List(userID int) ([]*model.APIToken, error)                                   // ユーザーのトークン一覧を取得
+	Revoke(userID, id int) error                                                  // トークンを取り消す
+	Authenticate(token string) (*model.User, *model.APIToken, error)              // トークンからユーザーと権限を特定
+}
+
+// tokenUsecase はTokenUsecaseインターフェースの実装
+type tokenUsecase struct {
+	tokenRepo repository.TokenRepository // トークンの保存先
+	userRepo  repository.UserRepository  // トークンの持ち主の取得に使う
+	validator *validator.Validate        // 入力データ検証用のバリデータ
+}
+
+// NewTokenUsecase は新しいTokenUsecaseを作成する関数
+func NewTokenUsecase(tokenRepo repository.TokenRepository, userRepo repository.UserRepository) TokenUsecase {
+	return &tokenUsecase{tokenRepo: tokenRepo, userRepo: userRepo, validator: validator.New()}
+}
+
+// Create はトークンを作成する
+// トークンそのものは戻り値でだけ返し、データベースにはハッシュだけを保存する
+func (u *tokenUsecase) Create(userID int, req *model.CreateTokenRequest) (*model.CreatedToken, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+
+	random, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	token := TokenPrefix + random
+
+	apiToken := &model.APIToken{
+		UserID:    userID,
+		Name:      req.Name,
+		TokenHash: HashToken(token),
+		Scopes:    req.Scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().UTC().AddDate(0, 0, req.ExpiresInDays)
+		apiToken.ExpiresAt = &expiresAt
+	}
+
+	created, err := u.tokenRepo.Create(apiToken)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CreatedToken{Token: token, APIToken: created}, nil
+}
+
+// List はユーザーのトークン一覧を取得する
+func (u *tokenUsecase) List(userID int) ([]*model.APIToken, error) {
+	return u.tokenRepo.ListByUser(userID)
+}
+
+// Revoke はトークンを取り消す（削除する）
+func (u *tokenUsecase) Revoke(userID, id int) error {
+	return u.tokenRepo.Delete(userID, id)
+}
+
+// Authenticate はトークンからユーザーとトークンの情報（権限）を特定する
+// 有効期限切れのトークンは使えない。使えた場合は最終使用日時を記録する
+func (u *tokenUsecase) Authenticate(token string) (*model.User, *model.APIToken, error) {
+	if !IsAPIToken(token) {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	apiToken, err := u.tokenRepo.GetByHash(HashToken(token))
+	if err != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+	now := time.Now().UTC()
+	if apiToken.IsExpired(now) {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	user, err := u.userRepo.GetByID(apiToken.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	// 最終使用日時の記録に失敗しても、認証そのものは成功として扱う
+	if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) >= lastUsedInterval {
+		if err := u.tokenRepo.UpdateLastUsed(apiToken.ID, now); err == nil {
+			apiToken.LastUsedAt = &now
+		}
+	}
+	return user, apiToken, nil
+}
+
+// IsAPIToken はトークンが個人用アクセストークンの形式かどうかを判定する関数
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, TokenPrefix)
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"errors"        // 認証エラーの判定
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // トークン・ユーザーのリポジトリ
+)
+
+// TestTokenScopes は作成したトークンで認証でき、上位の権限が下位の権限を含む（admin ⊃ write ⊃ read）ことを確認する
+func TestTokenScopes(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	userRepo := repository.NewUserRepository(db)
+	user, err := userRepo.Register(&model.User{Username: "alice", PasswordHash: "x"}, func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	tokens := NewTokenUsecase(repository.NewTokenRepository(db), userRepo)
+
+	tests := []struct {
+		name   string
+		scopes []model.Scope
+		want   map[model.Scope]bool // 権限ごとの HasScope の結果
+	}{
+		{"read", []model.Scope{model.ScopeRead}, map[model.Scope]bool{model.ScopeRead: true, model.ScopeWrite: false, model.ScopeAdmin: false}},
+		{"write", []model.Scope{model.ScopeWrite}, map[model.Scope]bool{model.ScopeRead: true, model.ScopeWrite: true, model.ScopeAdmin: false}},
+		{"admin", []model.Scope{model.ScopeAdmin}, map[model.Scope]bool{model.ScopeRead: true, model.ScopeWrite: true, model.ScopeAdmin: true}},
+		{"複数の権限は最も強い権限まで", []model.Scope{model.ScopeRead, model.ScopeWrite}, map[model.Scope]bool{model.ScopeRead: true, model.ScopeWrite: true, model.ScopeAdmin: false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := tokens.Create(user.ID, &model.CreateTokenRequest{Name: tt.name, Scopes: tt.scopes})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			got, apiToken, err := tokens.Authenticate(created.Token)
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if got.ID != user.ID {
+				t.Errorf("Authenticate のユーザー = %d, want %d", got.ID, user.ID)
+			}
+			for scope, want := range tt.want {
+				if apiToken.HasScope(scope) != want {
+					t.Errorf("HasScope(%s) = %v, want %v", scope, !want, want)
+				}
+			}
+		})
+	}
+
+	if _, err := tokens.Create(user.ID, &model.CreateTokenRequest{Name: "不明な権限", Scopes: []model.Scope{"owner"}}); err == nil {
+		t.Error("不明な権限のトークンを作成できました")
+	}
+	for _, token := range []string{"session-token", TokenPrefix + "unknown"} {
+		if _, _, err := tokens.Authenticate(token); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Authenticate(%q) のエラー = %v, want ErrInvalidCredentials", token, err)
+		}
+	}
+}
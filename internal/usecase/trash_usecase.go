@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context" // 定期実行の停止
+	"log"     // 定期実行の結果の出力
+	"time"    // 保存期間
+
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 自作のデータアクセス層
+)
+
+// ゴミ箱の定数
+const (
+	DefaultTrashRetention = 30 * 24 * time.Hour // ゴミ箱の書籍を完全に削除するまでの保存期間の既定値
+	TrashPurgeInterval    = time.Hour           // 保存期間が過ぎた書籍を削除する定期実行の間隔
+)
+
+// TrashUsecase はゴミ箱（削除した書籍の一覧・復元・完全な削除）のビジネスロジックを定義するインターフェース
+type TrashUsecase interface {
+	List(userID, page, limit int) ([]*model.TrashedBook, int, error) // ゴミ箱の書籍をゴミ箱に移した日時の新しい順に返す
+	Restore(userID, id int) (*model.Book, error)                     // ゴミ箱の書籍を元に戻す
+	Purge() (int, error)                                             // 保存期間が過ぎた書籍を全ユーザー分まとめて完全に削除する
+	Run(ctx context.Context, interval time.Duration)                 // Purge を定期的に実行する（ctx がキャンセルされるまで）
+}
+
+// trashUsecase はTrashUsecaseインターフェースの実装
+type trashUsecase struct {
+	bookRepo    repository.BookRepository // ゴミ箱の書籍の取得と完全な削除
+	bookUsecase BookUsecase               // 書籍の復元（変更履歴の記録のため、ユースケースを通す）
+	retention   time.Duration             // 完全に削除するまでの保存期間（0以下なら自動では削除しない）
+}
+
+// NewTrashUsecase は新しいTrashUsecaseを作成する関数
+// retention が0以下なら、ゴミ箱の書籍は自動では削除しない
+func NewTrashUsecase(bookRepo repository.BookRepository, bookUsecase BookUsecase, retention time.Duration) TrashUsecase {
+	return &trashUsecase{bookRepo: bookRepo, bookUsecase: bookUsecase, retention: retention}
+}
+
+// List はゴミ箱の書籍を、完全に削除される日時と一緒に返す
+func (u *trashUsecase) List(userID, page, limit int) ([]*model.TrashedBook, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	repo := u.bookRepo.WithOwner(userID)
+	books, err := repo.ListDeleted(limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := repo.CountDeleted()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	trashed := make([]*model.TrashedBook, 0, len(books))
+	for _, book := range books {
+		item := &model.TrashedBook{Book: book}
+		if u.retention > 0 && book.DeletedAt != nil {
+			purgeAt := book.DeletedAt.Add(u.retention)
+			item.PurgeAt = &purgeAt
+		}
+		trashed = append(trashed, item)
+	}
+	return trashed, total, nil
+}
+
+// Restore はゴミ箱の書籍を元に戻す
+func (u *trashUsecase) Restore(userID, id int) (*model.Book, error) {
+	return u.bookUsecase.ForUser(userID).RestoreBook(id)
+}
+
+// Purge はゴミ箱に移してから保存期間が過ぎた書籍を完全に削除し、削除した冊数を返す
+// ハイライト・レビュー・貸し出しの記録も一緒に削除する（元に戻せない）
+func (u *trashUsecase) Purge() (int, error) {
+	if u.retention <= 0 {
+		return 0, nil
+	}
+	return u.bookRepo.WithOwner(repository.AllOwners).Purge(time.Now().Add(-u.retention))
+}
+
+// Run は保存期間が過ぎた書籍の削除を、起動時と interval ごとに実行する
+// 失敗してもログに出力するだけで、次の間隔で再び実行する
+func (u *trashUsecase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := u.Purge(); err != nil {
+			log.Printf("ゴミ箱の書籍の削除に失敗しました: %v", err)
+		} else if n > 0 {
+			log.Printf("保存期間が過ぎたゴミ箱の書籍を%d冊削除しました", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
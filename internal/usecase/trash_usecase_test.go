@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"errors"        // 場所を削除できないエラーの判定
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+	"time"          // 書籍の購入日
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・場所のリポジトリ
+)
+
+// TestDeleteLocationWithTrashedBook はゴミ箱の書籍が置かれている場所は削除できず、
+// 元に戻した書籍が削除した場所を指すことがないことを確認する
+func TestDeleteLocationWithTrashedBook(t *testing.T) {
+	tests := []struct {
+		name      string
+		trash     bool // 書籍をゴミ箱に移すか
+		moveOut   bool // ゴミ箱に移す前に書籍の場所の設定を外すか
+		wantEmpty bool // 場所を削除できるか
+	}{
+		{"書籍が置かれている", false, false, false},
+		{"ゴミ箱の書籍が置かれている", true, false, false},
+		{"場所を外してからゴミ箱に移した", true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+			if err != nil {
+				t.Fatalf("NewDB: %v", err)
+			}
+			defer db.Close()
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			bookRepo := repository.NewBookRepository(db)
+			books := NewBookUsecase(bookRepo, nil, nil, nil, nil).ForUser(0)
+			locations := NewLocationUsecase(repository.NewLocationRepository(db), bookRepo, books)
+			trash := NewTrashUsecase(bookRepo, books, DefaultTrashRetention)
+
+			shelf, err := locations.Create(0, &model.CreateLocationRequest{Kind: model.KindShelf, Name: "本棚"})
+			if err != nil {
+				t.Fatalf("Create location: %v", err)
+			}
+			book, err := books.CreateBook(&model.CreateBookRequest{Title: "本", Author: "著者", PurchaseDate: time.Now().UTC()})
+			if err != nil {
+				t.Fatalf("CreateBook: %v", err)
+			}
+			if _, err := books.MoveBook(book.ID, &shelf.ID); err != nil {
+				t.Fatalf("MoveBook: %v", err)
+			}
+			if tt.moveOut {
+				if _, err := books.MoveBook(book.ID, nil); err != nil {
+					t.Fatalf("MoveBook: %v", err)
+				}
+			}
+			if tt.trash {
+				if err := books.DeleteBook(book.ID); err != nil {
+					t.Fatalf("DeleteBook: %v", err)
+				}
+			}
+
+			err = locations.Delete(0, shelf.ID)
+			if tt.wantEmpty {
+				if err != nil {
+					t.Fatalf("空の場所の削除: %v", err)
+				}
+			} else if !errors.Is(err, ErrLocationNotEmpty) {
+				t.Fatalf("Delete のエラー = %v, want ErrLocationNotEmpty", err)
+			}
+
+			if !tt.trash {
+				return
+			}
+			restored, err := trash.Restore(0, book.ID)
+			if err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+			if restored.LocationID != nil {
+				if _, err := locations.Get(0, *restored.LocationID); err != nil {
+					t.Errorf("元に戻した書籍の場所（ID %d）がありません: %v", *restored.LocationID, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"crypto/rand"   // セッショントークンの生成（推測できない乱数）
+	"crypto/sha256" // トークンのハッシュ化
+	"encoding/hex"  // トークンの16進数表記
+	"errors"        // エラーの定義
+	"fmt"           // エラーメッセージの作成
+	"time"          // セッションの有効期限
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+	"golang.org/x/crypto/bcrypt"             // パスワードのハッシュ化
+)
+
+// ErrInvalidCredentials はユーザー名・パスワード・トークンが正しくない場合のエラー
+// どれが間違っているかは教えない（アカウントの有無を推測されないようにするため）
+var ErrInvalidCredentials = errors.New("ユーザー名またはパスワードが正しくありません")
+
+// ErrSignupDisabled は新規登録が無効になっている場合のエラー
+var ErrSignupDisabled = errors.New("新規登録は無効になっています")
+
+// UserConfig はアカウント機能の設定
+type UserConfig struct {
+	AllowSignup bool          // 2人目以降のユーザー登録を許可するか（最初の1人は常に登録できる）
+	SessionTTL  time.Duration // ログインの有効期間
+}
+
+// UserUsecase はユーザー登録とログインのビジネスロジックを定義するインターフェース
+type UserUsecase interface {
+	Register(req *model.RegisterRequest) (*model.User, error)                   // ユーザーを登録
+	Login(req *model.LoginRequest) (string, *model.Session, *model.User, error) // ログインしてセッショントークンを発行
+	Logout(token string) error                                                  // セッションを削除
+	Authenticate(token string) (*model.User, error)                             // セッショントークンからユーザーを特定
+	AuthenticatePassword(username, password string) (*model.User, error)        // ユーザー名とパスワードでユーザーを特定（Basic認証用）
+}
+
+// userUsecase はUserUsecaseインターフェースの実装
+type userUsecase struct {
+	userRepo  repository.UserRepository // ユーザー・セッションの保存先
+	config    UserConfig                // アカウント機能の設定
+	validator *validator.Validate       // 入力データ検証用のバリデータ
+}
+
+// NewUserUsecase は新しいUserUsecaseを作成する関数
+func NewUserUsecase(userRepo repository.UserRepository, config UserConfig) UserUsecase {
+	return &userUsecase{userRepo: userRepo, config: config, validator: validator.New()}
+}
+
+// dummyHash はユーザーが存在しない場合にも同じ時間をかけるためのハッシュ
+// 存在しないユーザー名だとすぐに応答が返ると、応答時間からアカウントの有無が分かってしまうため
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("book-manager-dummy-password"), bcrypt.DefaultCost)
+
+// Register はユーザーを登録する
+// ビジネスルール：最初の1人は常に登録でき、2人目以降は AllowSignup が有効な場合のみ登録できる
+// 最初の1人はサーバーの管理者になり、ユーザー登録前から使っていた書籍（所有者なし）をすべて引き継ぐ
+func (u *userUsecase) Register(req *model.RegisterRequest) (*model.User, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+
+	count, err := u.userRepo.Count()
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && !u.config.AllowSignup {
+		return nil, ErrSignupDisabled
+	}
+
+	if _, err := u.userRepo.GetByUsername(req.Username); err == nil {
+		return nil, fmt.Errorf("ユーザー名 %s は既に使われています", req.Username)
+	}
+
+	// bcrypt：総当たりに強いパスワード用のハッシュ関数（ソルト付き、計算に時間がかかる）
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("パスワードのハッシュ化に失敗しました: %w", err)
+	}
+
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = req.Username
+	}
+	user := &model.User{
+		Username:     req.Username,
+		PasswordHash: string(hash),
+		DisplayName:  displayName,
+	}
+	// 上の確認の後にほかのユーザーが登録されている場合があるため、登録と同じトランザクションの中で数え直す
+	return u.userRepo.Register(user, func(count int) error {
+		if count > 0 && !u.config.AllowSignup {
+			return ErrSignupDisabled
+		}
+		user.IsAdmin = count == 0
+		return nil
+	})
+}
+
+// Login はユーザー名とパスワードを確認し、セッショントークンを発行する
+// トークンそのものは戻り値でだけ返し、データベースにはハッシュだけを保存する
+func (u *userUsecase) Login(req *model.LoginRequest) (string, *model.Session, *model.User, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return "", nil, nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+
+	user, err := u.AuthenticatePassword(req.Username, req.Password)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	session := &model.Session{
+		TokenHash: HashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(u.config.SessionTTL),
+	}
+	if err := u.userRepo.CreateSession(session); err != nil {
+		return "", nil, nil, err
+	}
+
+	// ついでに期限切れのセッションを掃除する（失敗してもログインには影響させない）
+	u.userRepo.DeleteExpiredSessions(time.Now().UTC())
+
+	return token, session, user, nil
+}
+
+// Logout はセッションを削除する
+func (u *userUsecase) Logout(token string) error {
+	return u.userRepo.DeleteSession(HashToken(token))
+}
+
+// Authenticate はセッショントークンからログイン中のユーザーを特定する
+func (u *userUsecase) Authenticate(token string) (*model.User, error) {
+	if token == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	session, err := u.userRepo.GetSession(HashToken(token))
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if time.Now().After(session.ExpiresAt) {
+		u.userRepo.DeleteSession(session.TokenHash)
+		return nil, ErrInvalidCredentials
+	}
+
+	user, err := u.userRepo.GetByID(session.UserID)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// AuthenticatePassword はユーザー名とパスワードでユーザーを特定する
+// ログインのほか、Cookieを使えないクライアント（OPDSリーダーのBasic認証など）で使う
+func (u *userUsecase) AuthenticatePassword(username, password string) (*model.User, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password)) // 応答時間を揃える
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// newToken は推測できないランダムなトークン（32バイト、16進数で64文字）を作る
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("トークンの生成に失敗しました: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken はトークンをデータベースに保存する形（SHA-256の16進数）に変換する
+// データベースが漏れても、ハッシュからトークンを復元することはできない
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
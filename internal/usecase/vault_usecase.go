@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"fmt"           // エラーメッセージの作成
+	"io/fs"         // フォルダの走査
+	"os"            // ファイルの読み書き
+	"path/filepath" // ノートのパス
+	"sort"          // ノートのパス順の並び替え
+	"strings"       // 隠しフォルダの判定
+	"time"          // ノートの更新日時
+
+	"book-manager/internal/markdown"   // ノートのファイル名
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 自作のデータアクセス層
+	"book-manager/internal/vault"      // ノートの読み書き
+)
+
+// VaultUsecase は書籍を Obsidian などの保管庫（Markdownのノートのフォルダ）と同期するビジネスロジックのインターフェース
+type VaultUsecase interface {
+	Export(userID int, dir string, opts model.VaultOptions) (*model.VaultResult, error) // 本棚の書籍をノートとして書き出す
+	Sync(userID int, dir string, opts model.VaultOptions) (*model.VaultResult, error)   // ノートのフロントマターの編集を書籍に反映する
+}
+
+// vaultUsecase はVaultUsecaseインターフェースの実装
+type vaultUsecase struct {
+	bookRepo      repository.BookRepository      // 本棚の書籍の取得
+	highlightRepo repository.HighlightRepository // ノートの本文に書くハイライト
+	bookUsecase   BookUsecase                    // 書籍の更新（入力チェックと変更履歴の記録のため、ユースケースを通す）
+}
+
+// NewVaultUsecase は新しいVaultUsecaseを作成する関数
+func NewVaultUsecase(bookRepo repository.BookRepository, highlightRepo repository.HighlightRepository, bookUsecase BookUsecase) VaultUsecase {
+	return &vaultUsecase{bookRepo: bookRepo, highlightRepo: highlightRepo, bookUsecase: bookUsecase}
+}
+
+// noteFile は保管庫から読み取ったノート1件
+type noteFile struct {
+	path    string      // 保管庫のフォルダからの相対パス
+	note    *vault.Note // フロントマターの内容
+	modTime time.Time   // ファイルの更新日時（ノートを編集した日時）
+}
+
+// noteState はノートと書籍の関係
+type noteState int
+
+const (
+	noteClean    noteState = iota // フロントマターと書籍に違いがない
+	noteEdited                    // 書き出した後にノートだけが編集された
+	noteStale                     // 書き出した後に書籍だけが更新された（ノートが古い）
+	noteConflict                  // 書き出した後にノートと書籍の両方が変わった
+)
+
+// classify はノートと書籍を比べて、どちらが変わったかを判断する
+// 書籍側：書籍の更新日時がフロントマターの updated_at（書き出したときの更新日時）より新しければ変わっている
+// ノート側：書籍が変わっていなければ、フロントマターとの違いはノートの編集。書籍も変わっていれば、
+// ファイルの更新日時（最終更新）が書籍の更新より後ならノートも編集されたとみなして競合にする
+func classify(book *model.Book, file *noteFile) (noteState, *model.UpdateBookRequest, []string, error) {
+	req, fields, err := file.note.Changes(book)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	switch {
+	case len(fields) == 0:
+		return noteClean, req, fields, nil
+	case !book.UpdatedAt.After(file.note.UpdatedAt):
+		return noteEdited, req, fields, nil
+	case file.modTime.After(book.UpdatedAt):
+		return noteConflict, req, fields, nil
+	default:
+		return noteStale, req, fields, nil
+	}
+}
+
+// scan は保管庫のノートを読み取り、書籍のIDごとにまとめる
+// 書籍のノートでないファイル（フロントマターに book_id がない）は対象外。"." で始まるフォルダ（.obsidian など）は見ない
+// 読み取れないノートや、同じ書籍のノートが複数ある場合は result にエラーとして記録する
+func (u *vaultUsecase) scan(dir string, result *model.VaultResult) (map[int]*noteFile, error) {
+	files := map[int]*noteFile{}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return files, nil // まだ書き出していない保管庫
+	}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != vault.Extension {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("ノートの読み込みに失敗しました: %w", err)
+		}
+		note, ok, err := vault.ParseNote(string(content))
+		if err != nil {
+			result.Add(&model.VaultNote{Path: rel, State: model.VaultFailed, Error: err.Error()})
+			return nil
+		}
+		if !ok {
+			return nil
+		}
+		if other, exists := files[note.BookID]; exists {
+			result.Add(&model.VaultNote{BookID: note.BookID, Path: rel, State: model.VaultFailed,
+				Error: fmt.Sprintf("同じ書籍のノートが既にあります: %s", other.path)})
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("ノートの情報の取得に失敗しました: %w", err)
+		}
+		files[note.BookID] = &noteFile{path: rel, note: note, modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保管庫の読み込みに失敗しました: %w", err)
+	}
+	return files, nil
+}
+
+// newResult は結果を初期化する
+func newResult(dir string, opts model.VaultOptions) (*model.VaultResult, error) {
+	if !opts.Prefer.IsValid() {
+		return nil, fmt.Errorf("競合の解決方法が無効です: %s（使用可能: file, db）", opts.Prefer)
+	}
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("保管庫のフォルダを指定してください")
+	}
+	return &model.VaultResult{Dir: dir, DryRun: opts.DryRun, Notes: []*model.VaultNote{}, Counts: map[model.VaultNoteState]int{}}, nil
+}
+
+// Export は本棚の書籍を1冊1ノートで保管庫に書き出す
+// 既にノートがある書籍は、そのノート（移動・名前の変更をしていても book_id で探す）を書き直す
+// ただし書籍へ反映していない編集があるノートは、編集を失わないよう書き直さない（opts.Prefer が db なら書き直す）
+func (u *vaultUsecase) Export(userID int, dir string, opts model.VaultOptions) (*model.VaultResult, error) {
+	result, err := newResult(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	files, err := u.scan(dir, result)
+	if err != nil {
+		return nil, err
+	}
+	books, err := u.bookRepo.WithOwner(userID).List(&model.BookFilter{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.DryRun {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("保管庫のフォルダの作成に失敗しました: %w", err)
+		}
+	}
+
+	used := map[string]bool{} // 新しいノートのファイル名が重ならないようにする
+	for _, file := range files {
+		used[strings.ToLower(file.path)] = true
+	}
+	for _, book := range books {
+		entry := &model.VaultNote{BookID: book.ID, Title: book.Title}
+		file, exists := files[book.ID]
+		if exists {
+			entry.Path = file.path
+			state, _, fields, err := classify(book, file)
+			if err != nil {
+				entry.State, entry.Error = model.VaultFailed, err.Error()
+				result.Add(entry)
+				continue
+			}
+			if (state == noteEdited || state == noteConflict) && opts.Prefer != model.PreferDB {
+				entry.State, entry.Fields = model.VaultPending, fields
+				if state == noteConflict {
+					entry.State = model.VaultConflict
+				}
+				result.Add(entry)
+				continue
+			}
+		} else {
+			entry.Path = uniqueName(book, used)
+		}
+
+		written, err := u.write(dir, entry.Path, book, opts.DryRun)
+		switch {
+		case err != nil:
+			entry.State, entry.Error = model.VaultFailed, err.Error()
+		case !exists:
+			entry.State = model.VaultCreated
+		case written:
+			entry.State = model.VaultWritten
+		default:
+			entry.State = model.VaultUnchanged
+		}
+		result.Add(entry)
+	}
+	return result, nil
+}
+
+// uniqueName は新しいノートのファイル名を決める（同じタイトルの書籍があれば書籍のIDを付ける）
+func uniqueName(book *model.Book, used map[string]bool) string {
+	name := markdown.Filename(book)
+	if used[strings.ToLower(name)] {
+		name = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, vault.Extension), book.ID, vault.Extension)
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// write は書籍のノートを書き出す（内容が同じなら書き直さず、written は false）
+// 書き出しの途中で失敗してもノートが壊れないよう、一時ファイルに書いてから置き換える
+func (u *vaultUsecase) write(dir, rel string, book *model.Book, dryRun bool) (written bool, err error) {
+	highlights, err := u.highlightRepo.ListByBook(book.ID)
+	if err != nil {
+		return false, err
+	}
+	content := vault.Render(book, highlights)
+	path := filepath.Join(dir, rel)
+	if current, err := os.ReadFile(path); err == nil && string(current) == content {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".book-manager-*.md")
+	if err != nil {
+		return false, fmt.Errorf("ノートの書き出しに失敗しました: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // 置き換えに成功した後は何もしない
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return false, fmt.Errorf("ノートの書き出しに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return false, fmt.Errorf("ノートの書き出しに失敗しました: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, fmt.Errorf("ノートの書き出しに失敗しました: %w", err)
+	}
+	return true, nil
+}
+
+// Sync はノートで編集したフロントマターを書籍に反映する
+// - 書き出した後にノートだけが編集されていれば、違う項目を書籍に反映し、ノートを書き直す（updated_at を新しくする）
+// - 書籍だけが更新されていれば、ノートを書籍の内容で書き直す
+// - 両方が変わっていれば競合として報告し、どちらも変えない（opts.Prefer で解決する側を選べる）
+func (u *vaultUsecase) Sync(userID int, dir string, opts model.VaultOptions) (*model.VaultResult, error) {
+	result, err := newResult(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	files, err := u.scan(dir, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// 結果が毎回同じ順になるよう、ノートのパス順に処理する
+	ordered := make([]*noteFile, 0, len(files))
+	for _, file := range files {
+		ordered = append(ordered, file)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].path < ordered[j].path })
+
+	books := u.bookRepo.WithOwner(userID)
+	for _, file := range ordered {
+		entry := &model.VaultNote{BookID: file.note.BookID, Path: file.path}
+		book, err := books.GetByID(file.note.BookID)
+		if err != nil {
+			entry.State, entry.Error = model.VaultMissing, err.Error()
+			result.Add(entry)
+			continue
+		}
+		entry.Title = book.Title
+
+		state, req, fields, err := classify(book, file)
+		if err == nil {
+			entry.Fields = fields
+			if state == noteConflict {
+				switch opts.Prefer {
+				case model.PreferFile:
+					state = noteEdited
+				case model.PreferDB:
+					state = noteStale
+				}
+			}
+			err = u.syncNote(userID, dir, entry, book, state, req, opts.DryRun)
+		}
+		if err != nil {
+			entry.State, entry.Error = model.VaultFailed, err.Error()
+		}
+		result.Add(entry)
+	}
+	return result, nil
+}
+
+// syncNote はノートと書籍の関係に応じて、書籍の更新かノートの書き直しを行う
+func (u *vaultUsecase) syncNote(userID int, dir string, entry *model.VaultNote, book *model.Book, state noteState, req *model.UpdateBookRequest, dryRun bool) error {
+	switch state {
+	case noteClean:
+		entry.State = model.VaultUnchanged
+		return nil
+	case noteConflict:
+		entry.State = model.VaultConflict
+		return nil
+	case noteStale:
+		entry.State = model.VaultRefreshed
+		_, err := u.write(dir, entry.Path, book, dryRun)
+		return err
+	}
+
+	entry.State = model.VaultApplied
+	if dryRun {
+		return nil
+	}
+	updated, err := u.bookUsecase.ForUser(userID).UpdateBook(book.ID, req)
+	if err != nil {
+		return err
+	}
+	entry.Title = updated.Title
+	_, err = u.write(dir, entry.Path, updated, false)
+	return err
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"testing" // テストの実行と結果の報告
+	"time"    // 書籍とノートの更新日時
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+	"book-manager/internal/vault" // ノートの読み書き
+)
+
+// TestClassify はノートと書籍のどちらが書き出した後に変わったかで、同期の扱い（競合など）が決まることを確認する
+// 書き出したときは、書籍もノートもタイトルが「元」、書籍の更新日時が exported の状態
+func TestClassify(t *testing.T) {
+	exported := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		bookTitle    string        // 現在の書籍のタイトル
+		bookUpdated  time.Duration // 書き出した後に書籍を更新した時間（0なら更新していない）
+		noteTitle    string        // 現在のノートのタイトル
+		noteModified time.Duration // 書き出した後にノートのファイルを更新した時間
+		want         noteState
+	}{
+		{"どちらも変わっていない", "元", 0, "元", time.Hour, noteClean},
+		{"書籍とノートを同じ内容に変えた", "新", time.Hour, "新", 2 * time.Hour, noteClean},
+		{"ノートだけを編集", "元", 0, "新", time.Hour, noteEdited},
+		{"書籍だけを更新（ノートが古い）", "新", 2 * time.Hour, "元", time.Hour, noteStale},
+		{"ノートの編集の後に書籍を更新", "書籍", 2 * time.Hour, "ノート", time.Hour, noteStale},
+		{"書籍の更新の後にノートを編集（競合）", "書籍", time.Hour, "ノート", 2 * time.Hour, noteConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := &model.Book{ID: 1, Title: "元", Author: "著者", Status: model.StatusNotStarted, UpdatedAt: exported}
+			note, ok, err := vault.ParseNote(vault.Render(book, nil))
+			if err != nil || !ok {
+				t.Fatalf("ParseNote = %v, %v", ok, err)
+			}
+			note.FrontMatter["title"] = vault.Value{Scalar: tt.noteTitle}
+			book.Title, book.UpdatedAt = tt.bookTitle, exported.Add(tt.bookUpdated)
+
+			file := &noteFile{path: "note.md", note: note, modTime: exported.Add(tt.noteModified)}
+			state, req, fields, err := classify(book, file)
+			if err != nil {
+				t.Fatalf("classify: %v", err)
+			}
+			if state != tt.want {
+				t.Errorf("classify = %d（%v）, want %d", state, fields, tt.want)
+			}
+			if state != noteClean && (req.Title == nil || *req.Title != tt.noteTitle) {
+				t.Errorf("更新リクエストのタイトル = %v, want %q", req.Title, tt.noteTitle)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context" // 価格の取得のタイムアウト
+	"errors"  // エラーの定義
+	"fmt"     // エラーメッセージの作成
+	"sort"    // 価格の取得元の名前の並べ替え
+	"strings" // タイトルなどの前後の空白の除去
+	"time"    // 購入日時・価格の記録日時
+
+	"book-manager/internal/model"            // 自作のデータ構造定義
+	"book-manager/internal/pricing"          // 価格の取得元
+	"book-manager/internal/repository"       // 自作のデータアクセス層
+	"github.com/go-playground/validator/v10" // 入力データのバリデーション
+)
+
+// ErrAlreadyPurchased は購入済みの欲しい本をもう一度購入しようとした場合のエラー
+var ErrAlreadyPurchased = errors.New("この本は既に購入済みです")
+
+// ErrUnknownPriceSource は登録されていない価格の取得元を指定した場合のエラー
+var ErrUnknownPriceSource = errors.New("価格の取得元が登録されていません")
+
+// ErrPriceSource は価格の取得元への問い合わせに失敗した場合のエラー（取得元側の障害など）
+var ErrPriceSource = errors.New("価格の取得元への問い合わせに失敗しました")
+
+// defaultWishlistPriority は優先度を省略したときの優先度（1〜5の真ん中）
+const defaultWishlistPriority = 3
+
+// priceLookupTimeout は価格の取得元への問い合わせの制限時間
+const priceLookupTimeout = 15 * time.Second
+
+// WishlistUsecase は欲しい本リストのビジネスロジックを定義するインターフェース
+type WishlistUsecase interface {
+	Create(userID int, req *model.CreateWishlistItemRequest) (*model.WishlistItem, error)        // 欲しい本を登録
+	List(userID int, includePurchased bool) ([]*model.WishlistItem, error)                       // 欲しい本リスト（優先度順）
+	Get(userID, id int) (*model.WishlistItem, error)                                             // 欲しい本を1件取得
+	Update(userID, id int, req *model.UpdateWishlistItemRequest) (*model.WishlistItem, error)    // 欲しい本を更新
+	Delete(userID, id int) error                                                                 // 欲しい本を削除
+	Purchase(userID, id int, req *model.PurchaseWishlistItemRequest) (*model.Book, error)        // 購入して書籍に変換
+	RecordPrice(userID, id int, req *model.RecordPriceRequest) (*model.PriceRecord, error)       // 価格を手入力で記録
+	RefreshPrice(ctx context.Context, userID, id int, source string) (*model.PriceRecord, error) // 価格の取得元から価格を記録
+	Prices(userID, id int) ([]*model.PriceRecord, error)                                         // 価格の履歴（新しい順）
+	Sources() []string                                                                           // 登録されている価格の取得元の名前
+}
+
+// wishlistUsecase はWishlistUsecaseインターフェースの実装
+type wishlistUsecase struct {
+	wishlistRepo repository.WishlistRepository // 欲しい本と価格の履歴の保存先
+	bookUsecase  BookUsecase                   // 購入時の書籍の作成に使う
+	sources      map[string]pricing.Source     // 価格の取得元（名前で引く）
+	validator    *validator.Validate           // 入力データ検証用のバリデータ
+}
+
+// NewWishlistUsecase は新しいWishlistUsecaseを作成する関数
+// sources は空でもよい（その場合、価格は手入力だけで記録する）
+func NewWishlistUsecase(wishlistRepo repository.WishlistRepository, bookUsecase BookUsecase, sources ...pricing.Source) WishlistUsecase {
+	u := &wishlistUsecase{wishlistRepo: wishlistRepo, bookUsecase: bookUsecase, sources: map[string]pricing.Source{}, validator: validator.New()}
+	for _, source := range sources {
+		u.sources[source.Name()] = source
+	}
+	return u
+}
+
+// Create は欲しい本を登録する
+func (u *wishlistUsecase) Create(userID int, req *model.CreateWishlistItemRequest) (*model.WishlistItem, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	item := &model.WishlistItem{
+		OwnerID:     userID,
+		Title:       strings.TrimSpace(req.Title),
+		Author:      strings.TrimSpace(req.Author),
+		ISBN:        strings.TrimSpace(req.ISBN),
+		Publisher:   strings.TrimSpace(req.Publisher),
+		Priority:    req.Priority,
+		TargetPrice: req.TargetPrice,
+		WhereSeen:   req.WhereSeen,
+		Notes:       req.Notes,
+		Tags:        req.Tags,
+	}
+	if item.Title == "" {
+		return nil, fmt.Errorf("タイトルを指定してください")
+	}
+	if item.Priority == 0 {
+		item.Priority = defaultWishlistPriority
+	}
+	return u.wishlistRepo.Create(item)
+}
+
+// List は欲しい本を優先度順に返す
+func (u *wishlistUsecase) List(userID int, includePurchased bool) ([]*model.WishlistItem, error) {
+	return u.wishlistRepo.List(userID, includePurchased)
+}
+
+// Get は欲しい本を1件取得する
+func (u *wishlistUsecase) Get(userID, id int) (*model.WishlistItem, error) {
+	return u.wishlistRepo.GetByID(userID, id)
+}
+
+// Update は欲しい本を更新する
+func (u *wishlistUsecase) Update(userID, id int, req *model.UpdateWishlistItemRequest) (*model.WishlistItem, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	item, err := u.wishlistRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		if item.Title = strings.TrimSpace(*req.Title); item.Title == "" {
+			return nil, fmt.Errorf("タイトルを指定してください")
+		}
+	}
+	if req.Author != nil {
+		item.Author = strings.TrimSpace(*req.Author)
+	}
+	if req.ISBN != nil {
+		item.ISBN = strings.TrimSpace(*req.ISBN)
+	}
+	if req.Publisher != nil {
+		item.Publisher = strings.TrimSpace(*req.Publisher)
+	}
+	if req.Priority != nil {
+		item.Priority = *req.Priority
+	}
+	if req.TargetPrice != nil {
+		item.TargetPrice = req.TargetPrice
+	}
+	if req.WhereSeen != nil {
+		item.WhereSeen = *req.WhereSeen
+	}
+	if req.Notes != nil {
+		item.Notes = *req.Notes
+	}
+	if req.Tags != nil {
+		item.Tags = *req.Tags
+	}
+	return u.wishlistRepo.Update(item)
+}
+
+// Delete は欲しい本を削除する（購入済みの本を削除しても、作成した書籍は残る）
+func (u *wishlistUsecase) Delete(userID, id int) error {
+	return u.wishlistRepo.Delete(userID, id)
+}
+
+// Purchase は欲しい本を購入済みにして、本棚に書籍を作成する
+// ビジネスルール：
+//   - 購入済みの本はもう一度購入できない
+//   - 購入価格を省略すると最後に記録した価格（記録がなければ0円）を使う
+//   - 書籍の作成は通常の書籍作成と同じ検証（購入日が未来でないことなど）を行う
+func (u *wishlistUsecase) Purchase(userID, id int, req *model.PurchaseWishlistItemRequest) (*model.Book, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	item, err := u.wishlistRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.PurchasedAt != nil {
+		return nil, ErrAlreadyPurchased
+	}
+
+	create := &model.CreateBookRequest{
+		Title:        item.Title,
+		Author:       item.Author,
+		ISBN:         item.ISBN,
+		Publisher:    item.Publisher,
+		PurchaseDate: time.Now(),
+		Tags:         item.Tags,
+		Notes:        item.Notes,
+	}
+	if req.PurchaseDate != nil {
+		create.PurchaseDate = *req.PurchaseDate
+	}
+	if req.PurchasePrice != nil {
+		create.PurchasePrice = *req.PurchasePrice
+	} else if item.LatestPrice != nil {
+		create.PurchasePrice = *item.LatestPrice
+	}
+	if req.Tags != nil {
+		create.Tags = *req.Tags
+	}
+	if req.Notes != nil {
+		create.Notes = *req.Notes
+	}
+	// 書籍は著者が必須のため、欲しい本で省略していた場合はここで指定してもらう
+	if create.Author == "" {
+		return nil, fmt.Errorf("書籍に変換するには著者を登録してください")
+	}
+
+	book, err := u.bookUsecase.ForUser(userID).CreateBook(create)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.wishlistRepo.MarkPurchased(item.ID, book.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// RecordPrice は価格を手入力で記録する
+func (u *wishlistUsecase) RecordPrice(userID, id int, req *model.RecordPriceRequest) (*model.PriceRecord, error) {
+	if err := u.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("入力データが無効です: %w", err)
+	}
+	item, err := u.wishlistRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	record := &model.PriceRecord{ItemID: item.ID, Price: *req.Price, Source: model.PriceSourceManual, URL: req.URL, RecordedAt: time.Now()}
+	if req.RecordedAt != nil {
+		record.RecordedAt = *req.RecordedAt
+	}
+	return u.wishlistRepo.AddPrice(record)
+}
+
+// RefreshPrice は価格の取得元に現在の価格を問い合わせて記録する
+// 取得元が1つだけ登録されている場合は、名前を省略できる
+func (u *wishlistUsecase) RefreshPrice(ctx context.Context, userID, id int, name string) (*model.PriceRecord, error) {
+	if name == "" && len(u.sources) == 1 {
+		for only := range u.sources {
+			name = only
+		}
+	}
+	source, ok := u.sources[name]
+	if !ok {
+		if len(u.sources) == 0 {
+			return nil, ErrUnknownPriceSource
+		}
+		return nil, fmt.Errorf("%w: %q（%s のいずれかを指定してください）", ErrUnknownPriceSource, name, strings.Join(u.Sources(), "、"))
+	}
+
+	item, err := u.wishlistRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, priceLookupTimeout)
+	defer cancel()
+	quote, err := source.Lookup(ctx, item)
+	if errors.Is(err, pricing.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPriceSource, err)
+	}
+	return u.wishlistRepo.AddPrice(&model.PriceRecord{ItemID: item.ID, Price: quote.Price, Source: source.Name(), URL: quote.URL, RecordedAt: time.Now()})
+}
+
+// Prices は価格の履歴を新しい順に返す
+func (u *wishlistUsecase) Prices(userID, id int) ([]*model.PriceRecord, error) {
+	item, err := u.wishlistRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.wishlistRepo.ListPrices(item.ID)
+}
+
+// Sources は登録されている価格の取得元の名前を名前順に返す
+func (u *wishlistUsecase) Sources() []string {
+	names := []string{}
+	for name := range u.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"errors"        // 購入済みのエラーの判定
+	"path/filepath" // テスト用データベースのパス
+	"testing"       // テストの実行と結果の報告
+
+	"book-manager/internal/database"   // データベース接続
+	"book-manager/internal/model"      // 自作のデータ構造定義
+	"book-manager/internal/repository" // 書籍・欲しい本のリポジトリ
+)
+
+// TestWishlistPurchase は欲しい本を購入すると内容と最後に記録した価格を引き継いだ書籍が作られ、
+// 欲しい本が購入済みになることを確認する
+func TestWishlistPurchase(t *testing.T) {
+	price := func(p int) *int { return &p }
+	tags := "上書きしたタグ"
+	tests := []struct {
+		name      string
+		item      model.CreateWishlistItemRequest
+		recorded  *int // 購入前に記録する価格
+		req       model.PurchaseWishlistItemRequest
+		wantPrice int
+		wantTags  string
+		wantErr   bool
+	}{
+		{"最後に記録した価格を引き継ぐ", model.CreateWishlistItemRequest{Title: "欲しい本", Author: "著者", Tags: "SF"}, price(1500),
+			model.PurchaseWishlistItemRequest{}, 1500, "SF", false},
+		{"購入価格とタグを指定する", model.CreateWishlistItemRequest{Title: "欲しい本", Author: "著者", Tags: "SF"}, price(1500),
+			model.PurchaseWishlistItemRequest{PurchasePrice: price(1200), Tags: &tags}, 1200, tags, false},
+		{"価格の記録がない", model.CreateWishlistItemRequest{Title: "欲しい本", Author: "著者"}, nil,
+			model.PurchaseWishlistItemRequest{}, 0, "", false},
+		{"著者がない", model.CreateWishlistItemRequest{Title: "欲しい本"}, nil,
+			model.PurchaseWishlistItemRequest{}, 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := database.NewDB(filepath.Join(t.TempDir(), "books.db"))
+			if err != nil {
+				t.Fatalf("NewDB: %v", err)
+			}
+			defer db.Close()
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			books := NewBookUsecase(repository.NewBookRepository(db), nil, nil, nil, nil)
+			wishlist := NewWishlistUsecase(repository.NewWishlistRepository(db), books)
+
+			item, err := wishlist.Create(0, &tt.item)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if tt.recorded != nil {
+				if _, err := wishlist.RecordPrice(0, item.ID, &model.RecordPriceRequest{Price: tt.recorded}); err != nil {
+					t.Fatalf("RecordPrice: %v", err)
+				}
+			}
+
+			book, err := wishlist.Purchase(0, item.ID, &tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Purchase のエラー = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := wishlist.Get(0, item.ID)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if tt.wantErr {
+				if got.PurchasedAt != nil || got.BookID != nil {
+					t.Errorf("変換に失敗した欲しい本が購入済みになりました: %+v", got)
+				}
+				return
+			}
+
+			if book.Title != tt.item.Title || book.Author != tt.item.Author || book.PurchasePrice != tt.wantPrice || book.Tags != tt.wantTags {
+				t.Errorf("作成した書籍 = %q・%q・%d・%q, want %q・%q・%d・%q", book.Title, book.Author, book.PurchasePrice, book.Tags,
+					tt.item.Title, tt.item.Author, tt.wantPrice, tt.wantTags)
+			}
+			if got.PurchasedAt == nil || got.BookID == nil || *got.BookID != book.ID {
+				t.Errorf("欲しい本が書籍 %d の購入済みになっていません: %+v", book.ID, got)
+			}
+			if _, err := wishlist.Purchase(0, item.ID, &model.PurchaseWishlistItemRequest{}); !errors.Is(err, ErrAlreadyPurchased) {
+				t.Errorf("2回目の Purchase のエラー = %v, want ErrAlreadyPurchased", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package vault
+
+import (
+	"fmt"     // エラーメッセージの作成
+	"strconv" // 引用符付きの文字列の解析
+	"strings" // 行の解析
+)
+
+// Value はフロントマターの1項目の値
+// Obsidian のプロパティで使われる文字列・数値・日付（Scalar）とリスト（List）だけを扱う
+type Value struct {
+	Scalar string   // 文字列の値（引用符は外した状態）
+	List   []string // リストの値
+	IsList bool     // リストかどうか
+	Null   bool     // 値が書かれていない（YAML の null）
+}
+
+// FrontMatter はフロントマターの項目名と値の組
+type FrontMatter map[string]Value
+
+// String は項目の文字列の値を返す（リストと null は空文字）
+func (f FrontMatter) String(key string) string {
+	v, ok := f[key]
+	if !ok || v.IsList || v.Null {
+		return ""
+	}
+	return v.Scalar
+}
+
+// Has は項目が書かれていて null でないかどうかを返す
+func (f FrontMatter) Has(key string) bool {
+	v, ok := f[key]
+	return ok && !v.Null
+}
+
+// Strings は項目をリストとして返す
+// Obsidian ではタグを "a, b" のように1つの文字列で書くこともできるため、文字列ならカンマで分ける
+func (f FrontMatter) Strings(key string) []string {
+	v, ok := f[key]
+	if !ok || v.Null {
+		return nil
+	}
+	items := v.List
+	if !v.IsList {
+		items = strings.Split(v.Scalar, ",")
+	}
+	result := []string{}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Split はMarkdownの文書をフロントマター（--- で囲まれた先頭の部分）と本文に分ける関数
+// フロントマターがない文書は ok が false になる
+func Split(doc string) (frontMatter, body string, ok bool) {
+	doc = strings.TrimPrefix(strings.ReplaceAll(doc, "\r\n", "\n"), "\ufeff")
+	if !strings.HasPrefix(doc, "---\n") {
+		return "", doc, false
+	}
+	rest := doc[len("---\n"):]
+	for offset := 0; offset <= len(rest); {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		if trimmed := strings.TrimRight(line, " \t"); trimmed == "---" || trimmed == "..." {
+			if end < 0 {
+				return rest[:offset], "", true
+			}
+			return rest[:offset], rest[offset+end+1:], true
+		}
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+	return "", doc, false // 閉じる --- がない
+}
+
+// Parse はフロントマターを解析する関数
+// 対応する書き方：key: 値、"…" と '…' の引用符、[a, b] のリスト、次の行からの "- a" のリスト、# のコメント
+// 入れ子のマップなど、それ以外の書き方の項目は読み飛ばす（書籍の項目では使わないため）
+func Parse(src string) (FrontMatter, error) {
+	fm := FrontMatter{}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' || strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			continue // 読み飛ばした項目の続き
+		}
+
+		colon := strings.Index(line, ":")
+		if colon <= 0 {
+			return nil, fmt.Errorf("フロントマターの %d 行目を解析できません: %s", i+1, line)
+		}
+		key := strings.TrimSpace(line[:colon])
+		raw := strings.TrimSpace(line[colon+1:])
+
+		if raw == "" {
+			// 値が空なら、次の行から "- " で始まるリストが続くかを確認する
+			items := []string{}
+			for i+1 < len(lines) {
+				next := strings.TrimSpace(lines[i+1])
+				if next == "" || strings.HasPrefix(next, "#") {
+					i++
+					continue
+				}
+				if next != "-" && !strings.HasPrefix(next, "- ") {
+					break
+				}
+				item, err := parseScalar(strings.TrimSpace(strings.TrimPrefix(next, "-")))
+				if err != nil {
+					return nil, fmt.Errorf("フロントマターの %s を解析できません: %w", key, err)
+				}
+				items = append(items, item)
+				i++
+			}
+			if len(items) > 0 {
+				fm[key] = Value{List: items, IsList: true}
+			} else {
+				fm[key] = Value{Null: true}
+			}
+			continue
+		}
+
+		if strings.HasPrefix(raw, "[") {
+			items, err := parseFlowList(raw)
+			if err != nil {
+				return nil, fmt.Errorf("フロントマターの %s を解析できません: %w", key, err)
+			}
+			fm[key] = Value{List: items, IsList: true}
+			continue
+		}
+		if strings.HasPrefix(raw, "{") || strings.HasPrefix(raw, "|") || strings.HasPrefix(raw, ">") {
+			continue // マップや複数行の文字列は使わないため読み飛ばす
+		}
+		scalar, err := parseScalar(raw)
+		if err != nil {
+			return nil, fmt.Errorf("フロントマターの %s を解析できません: %w", key, err)
+		}
+		if raw == "~" || raw == "null" || raw == "Null" || raw == "NULL" {
+			fm[key] = Value{Null: true}
+			continue
+		}
+		fm[key] = Value{Scalar: scalar}
+	}
+	return fm, nil
+}
+
+// parseScalar は1つの値を解析する（引用符を外し、引用符のない値は後ろのコメントを除く）
+func parseScalar(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := closingQuote(raw)
+		if end < 0 {
+			return "", fmt.Errorf("閉じる \" がありません: %s", raw)
+		}
+		s, err := strconv.Unquote(raw[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("文字列を解析できません: %s", raw)
+		}
+		return s, nil
+	case strings.HasPrefix(raw, "'"):
+		// 一重引用符の中では '' が ' を表す
+		end := closingSingleQuote(raw)
+		if end < 0 {
+			return "", fmt.Errorf("閉じる ' がありません: %s", raw)
+		}
+		return strings.ReplaceAll(raw[1:end], "''", "'"), nil
+	}
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw), nil
+}
+
+// closingQuote は二重引用符で始まる値の、閉じる引用符の位置を返す（\ でエスケープされた " は飛ばす）
+func closingQuote(raw string) int {
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// closingSingleQuote は一重引用符で始まる値の、閉じる引用符の位置を返す（” は ' として飛ばす）
+func closingSingleQuote(raw string) int {
+	for i := 1; i < len(raw); i++ {
+		if raw[i] != '\'' {
+			continue
+		}
+		if i+1 < len(raw) && raw[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// parseFlowList は [a, "b, c"] の形のリストを解析する
+func parseFlowList(raw string) ([]string, error) {
+	if i := strings.LastIndex(raw, "]"); i >= 0 {
+		raw = raw[:i]
+	} else {
+		return nil, fmt.Errorf("閉じる ] がありません: %s", raw)
+	}
+	rest := strings.TrimSpace(raw[1:])
+	items := []string{}
+	for rest != "" {
+		var item string
+		switch rest[0] {
+		case '"':
+			end := closingQuote(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("閉じる \" がありません: %s", rest)
+			}
+			s, err := parseScalar(rest[:end+1])
+			if err != nil {
+				return nil, err
+			}
+			item, rest = s, rest[end+1:]
+		case '\'':
+			end := closingSingleQuote(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("閉じる ' がありません: %s", rest)
+			}
+			item, rest = strings.ReplaceAll(rest[1:end], "''", "'"), rest[end+1:]
+		default:
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			item, rest = strings.TrimSpace(rest[:end]), rest[end:]
+		}
+		items = append(items, item)
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if rest != "" {
+			return nil, fmt.Errorf("リストの区切りが正しくありません: %s", rest)
+		}
+	}
+	return items, nil
+}
+
+// writer はフロントマターを組み立てる
+// 文字列は常に二重引用符で囲む（": " や "#" を含むタイトルでも壊れないようにするため）
+type writer struct {
+	b strings.Builder
+}
+
+// quoted は文字列を二重引用符で囲んだYAMLの値にする
+// strconv.Quote のエスケープ（\" \\ \n \t \uXXXX）はYAMLの二重引用符の文字列と同じ意味になる
+func quoted(s string) string {
+	return strconv.Quote(s)
+}
+
+// str は文字列の項目を書く
+func (w *writer) str(key, value string) {
+	fmt.Fprintf(&w.b, "%s: %s\n", key, quoted(value))
+}
+
+// plain は引用符の要らない値（数値・日付・決められた値）の項目を書く（空文字は null）
+func (w *writer) plain(key, value string) {
+	if value == "" {
+		fmt.Fprintf(&w.b, "%s:\n", key)
+		return
+	}
+	fmt.Fprintf(&w.b, "%s: %s\n", key, value)
+}
+
+// list はリストの項目を "- " の形で書く（Obsidian がプロパティを保存するときと同じ書き方）
+func (w *writer) list(key string, items []string) {
+	if len(items) == 0 {
+		fmt.Fprintf(&w.b, "%s: []\n", key)
+		return
+	}
+	fmt.Fprintf(&w.b, "%s:\n", key)
+	for _, item := range items {
+		fmt.Fprintf(&w.b, "  - %s\n", quoted(item))
+	}
+}
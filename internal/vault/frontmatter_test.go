@@ -0,0 +1,97 @@
+package vault
+
+import (
+	"reflect" // 解析結果の比較
+	"testing" // テストの実行と結果の報告
+)
+
+// TestSplit はフロントマターと本文を分け、フロントマターのない文書を見分けることを確認する
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name            string
+		doc             string
+		wantFrontMatter string
+		wantBody        string
+		wantOK          bool
+	}{
+		{"フロントマターと本文", "---\ntitle: a\n---\n本文\n", "title: a\n", "本文\n", true},
+		{"CRLFとBOM", "\ufeff---\r\ntitle: a\r\n---\r\n本文", "title: a\n", "本文", true},
+		{"... で閉じる", "---\ntitle: a\n...\n本文", "title: a\n", "本文", true},
+		{"本文なし", "---\ntitle: a\n---", "title: a\n", "", true},
+		{"空のフロントマター", "---\n---\n本文", "", "本文", true},
+		{"閉じていない", "---\ntitle: a\n本文", "", "---\ntitle: a\n本文", false},
+		{"フロントマターなし", "# 見出し\n---\n", "", "# 見出し\n---\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, ok := Split(tt.doc)
+			if fm != tt.wantFrontMatter || body != tt.wantBody || ok != tt.wantOK {
+				t.Errorf("Split(%q) = (%q, %q, %v), want (%q, %q, %v)", tt.doc, fm, body, ok, tt.wantFrontMatter, tt.wantBody, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestParse はフロントマターの書き方ごとの値の読み取りを確認する
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    FrontMatter
+		wantErr bool
+	}{
+		{"引用符のない値とコメント", "title: 吾輩は猫である # コメント\n# 行のコメント\nrating: 5",
+			FrontMatter{"title": {Scalar: "吾輩は猫である"}, "rating": {Scalar: "5"}}, false},
+		{"二重引用符", `title: "Go: \"入門\" #1"`, FrontMatter{"title": {Scalar: `Go: "入門" #1`}}, false},
+		{"一重引用符", `title: 'It''s # not a comment'`, FrontMatter{"title": {Scalar: "It's # not a comment"}}, false},
+		{"null", "isbn:\npublisher: ~\nauthor: null", FrontMatter{"isbn": {Null: true}, "publisher": {Null: true}, "author": {Null: true}}, false},
+		{"- のリスト", "tags:\n  - SF\n  - \"a, b\"\n\n  - 小説\nstatus: reading",
+			FrontMatter{"tags": {List: []string{"SF", "a, b", "小説"}, IsList: true}, "status": {Scalar: "reading"}}, false},
+		{"[] のリスト", `tags: [SF, "a, b", 'c']`, FrontMatter{"tags": {List: []string{"SF", "a, b", "c"}, IsList: true}}, false},
+		{"空のリスト", "tags: []", FrontMatter{"tags": {List: []string{}, IsList: true}}, false},
+		{"マップと複数行の文字列は読み飛ばす", "cover: {url: x}\nnotes: |\n  一行目\n  二行目\ntitle: a",
+			FrontMatter{"title": {Scalar: "a"}}, false},
+		{"入れ子のマップは読み飛ばす", "meta:\n  key: value\ntitle: a", FrontMatter{"meta": {Null: true}, "title": {Scalar: "a"}}, false},
+		{"コロンのない行", "title", nil, true},
+		{"閉じていない二重引用符", `title: "abc`, nil, true},
+		{"閉じていない一重引用符", "title: 'abc", nil, true},
+		{"閉じていない []", "tags: [a, b", nil, true},
+		{"[] の区切りの誤り", `tags: ["a" "b"]`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) のエラー = %v, want エラー %v", tt.src, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFrontMatterStrings はタグをリストでも、カンマ区切りの1つの文字列でも読み取れることを確認する
+func TestFrontMatterStrings(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{"tags:\n  - a\n  - \" b \"", []string{"a", "b"}},
+		{"tags: a, b,, c", []string{"a", "b", "c"}},
+		{"tags: []", []string{}},
+		{"tags:", nil},
+		{"title: x", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			fm, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := fm.Strings("tags"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Strings(tags) = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
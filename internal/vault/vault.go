@@ -0,0 +1,216 @@
+// vaultパッケージ：書籍を Obsidian などのMarkdownの保管庫（vault）のノートとして読み書きするファイル
+// 1冊を1つのノートにし、書籍の項目はフロントマター（YAML のプロパティ）に、メモとハイライトは本文に書く
+// ノートで編集したフロントマターは書籍の更新（UpdateBookRequest）として読み戻せる
+package vault
+
+import (
+	"fmt"     // 文字列フォーマット
+	"strconv" // 数値の変換
+	"strings" // 文書の組み立て
+	"time"    // 日付の変換
+
+	"book-manager/internal/markdown" // ハイライトのMarkdown
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// フロントマターの書式の定数
+const (
+	Extension = ".md" // ノートの拡張子
+
+	dateLayout = "2006-01-02" // 日付の項目の書式（Obsidian の日付のプロパティと同じ）
+)
+
+// Note は保管庫のノートから読み取った書籍の情報
+type Note struct {
+	BookID      int         // 書籍のID（book_id）
+	UpdatedAt   time.Time   // 書き出したときの書籍の更新日時（updated_at。書き出した後に書籍が変わったかの判断に使う）
+	FrontMatter FrontMatter // フロントマターの全項目
+}
+
+// Render は書籍を保管庫のノート（フロントマター付きのMarkdown）にする関数
+// highlights はページ順に並んでいるものとする
+func Render(book *model.Book, highlights []*model.Highlight) string {
+	var w writer
+	w.b.WriteString("---\n")
+	w.plain("book_id", strconv.Itoa(book.ID))
+	w.str("title", book.Title)
+	w.str("author", book.Author)
+	w.str("isbn", book.ISBN)
+	w.str("publisher", book.Publisher)
+	w.plain("status", string(book.Status))
+	rating := ""
+	if book.Rating != nil {
+		rating = strconv.Itoa(*book.Rating)
+	}
+	w.plain("rating", rating)
+	w.list("tags", splitTags(book.Tags))
+	w.plain("published_date", formatDate(book.PublishedDate))
+	w.plain("purchase_date", formatDate(&book.PurchaseDate))
+	w.plain("start_read_date", formatDate(book.StartReadDate))
+	w.plain("end_read_date", formatDate(book.EndReadDate))
+	w.str("updated_at", book.UpdatedAt.UTC().Format(time.RFC3339Nano))
+	w.b.WriteString("---\n\n")
+
+	b := &w.b
+	fmt.Fprintf(b, "# %s\n\n", strings.Join(strings.Fields(book.Title), " "))
+	// %% … %% は Obsidian のコメント（閲覧表示には出ない）
+	b.WriteString("%% 本文は書き出すたびに置き換わります。取り込まれるのはプロパティ（フロントマター）の編集だけです %%\n")
+
+	b.WriteString("\n## メモ\n")
+	if notes := strings.TrimSpace(strings.ReplaceAll(book.Notes, "\r\n", "\n")); notes != "" {
+		fmt.Fprintf(b, "\n%s\n", notes)
+	}
+
+	fmt.Fprintf(b, "\n## ハイライト（%d件）\n", len(highlights))
+	markdown.WriteHighlights(b, highlights, 3)
+	return b.String()
+}
+
+// ParseNote はノートのフロントマターを読み取る関数
+// フロントマターがないか book_id のないノート（書籍のノートではない）は ok が false になる
+func ParseNote(doc string) (note *Note, ok bool, err error) {
+	src, _, found := Split(doc)
+	if !found {
+		return nil, false, nil
+	}
+	fm, err := Parse(src)
+	if err != nil {
+		return nil, false, err
+	}
+	if !fm.Has("book_id") {
+		return nil, false, nil
+	}
+
+	note = &Note{FrontMatter: fm}
+	if note.BookID, err = strconv.Atoi(fm.String("book_id")); err != nil || note.BookID <= 0 {
+		return nil, false, fmt.Errorf("book_id が正しくありません: %s", fm.String("book_id"))
+	}
+	if fm.Has("updated_at") {
+		if note.UpdatedAt, err = time.Parse(time.RFC3339Nano, fm.String("updated_at")); err != nil {
+			return nil, false, fmt.Errorf("updated_at が正しくありません: %s", fm.String("updated_at"))
+		}
+	}
+	return note, true, nil
+}
+
+// Changes はノートのフロントマターと書籍を比べ、違う項目だけを設定した更新リクエストを返す関数
+// fields には違う項目の名前が入る（違いがなければ空）
+// 項目ごと消した場合は変更しない。また、評価と日付は UpdateBookRequest で空に戻せないため、空にしても変更しない
+// purchase_date は書籍の更新で変えられない項目のため読み戻さない
+func (n *Note) Changes(book *model.Book) (req *model.UpdateBookRequest, fields []string, err error) {
+	fm := n.FrontMatter
+	req = &model.UpdateBookRequest{}
+
+	// 文字列の項目（null は空文字として扱う。Obsidian は空のテキストのプロパティを null で保存するため）
+	for _, field := range []struct {
+		key      string
+		current  string
+		target   **string
+		required bool
+	}{
+		{"title", book.Title, &req.Title, true},
+		{"author", book.Author, &req.Author, true},
+		{"isbn", book.ISBN, &req.ISBN, false},
+		{"publisher", book.Publisher, &req.Publisher, false},
+	} {
+		if _, ok := fm[field.key]; !ok {
+			continue
+		}
+		value := strings.TrimSpace(fm.String(field.key))
+		if value == field.current {
+			continue
+		}
+		if value == "" && field.required {
+			return nil, nil, fmt.Errorf("%s は空にできません", field.key)
+		}
+		*field.target = &value
+		fields = append(fields, field.key)
+	}
+
+	if fm.Has("status") {
+		status := model.ReadingStatus(fm.String("status"))
+		switch status {
+		case model.StatusNotStarted, model.StatusReading, model.StatusCompleted, model.StatusDropped:
+		default:
+			return nil, nil, fmt.Errorf("status が正しくありません: %s", status)
+		}
+		if status != book.Status {
+			req.Status = &status
+			fields = append(fields, "status")
+		}
+	}
+
+	if fm.String("rating") != "" {
+		rating, err := strconv.Atoi(fm.String("rating"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("rating が正しくありません: %s", fm.String("rating"))
+		}
+		if book.Rating == nil || *book.Rating != rating {
+			req.Rating = &rating
+			fields = append(fields, "rating")
+		}
+	}
+
+	if _, ok := fm["tags"]; ok {
+		tags := strings.Join(fm.Strings("tags"), ",")
+		if tags != strings.Join(splitTags(book.Tags), ",") {
+			req.Tags = &tags
+			fields = append(fields, "tags")
+		}
+	}
+
+	for _, field := range []struct {
+		key     string
+		current *time.Time
+		target  **time.Time
+	}{
+		{"published_date", book.PublishedDate, &req.PublishedDate},
+		{"start_read_date", book.StartReadDate, &req.StartReadDate},
+		{"end_read_date", book.EndReadDate, &req.EndReadDate},
+	} {
+		value := fm.String(field.key)
+		if value == "" || value == formatDate(field.current) {
+			continue
+		}
+		date, err := parseDate(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s が正しくありません: %s", field.key, value)
+		}
+		if formatDate(&date) == formatDate(field.current) {
+			continue // 時刻まで書かれていても、日付が同じなら変更なし
+		}
+		*field.target = &date
+		fields = append(fields, field.key)
+	}
+	return req, fields, nil
+}
+
+// splitTags はカンマ区切りのタグをリストにする関数（前後の空白と空のタグは除く）
+func splitTags(tags string) []string {
+	result := []string{}
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// formatDate は日付をフロントマターの書式にする関数（nilは空文字）
+func formatDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// parseDate はフロントマターの日付を読み取る関数
+// Obsidian の日付（2024-01-02）と日時（2024-01-02T15:04）のプロパティ、RFC 3339 の日時を受け付ける
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{dateLayout, "2006-01-02T15:04", "2006-01-02T15:04:05", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("日付を解析できません: %s", s)
+}
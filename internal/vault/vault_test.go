@@ -0,0 +1,116 @@
+package vault
+
+import (
+	"reflect" // 変更した項目の比較
+	"strings" // ノートのフロントマターの書き換え
+	"testing" // テストの実行と結果の報告
+	"time"    // 書籍の日付
+
+	"book-manager/internal/model" // 自作のデータ構造定義
+)
+
+// testBook はテスト用の書籍を作成する（タイトルには YAML で特別な意味を持つ文字を含める）
+func testBook() *model.Book {
+	rating := 4
+	published := time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	return &model.Book{
+		ID: 7, Title: `Go: "入門" #1`, Author: "山田 太郎", ISBN: "978-4-12-345678-9", Publisher: "",
+		Status: model.StatusReading, Rating: &rating, Tags: "Go, 技術書", PublishedDate: &published,
+		PurchaseDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), StartReadDate: &start,
+		UpdatedAt: time.Date(2024, 1, 6, 12, 34, 56, 789000000, time.UTC),
+	}
+}
+
+// TestRenderParseNote は書き出したノートを読み戻すと、書籍のIDと更新日時が得られ、変更がないことを確認する
+func TestRenderParseNote(t *testing.T) {
+	book := testBook()
+	note, ok, err := ParseNote(Render(book, nil))
+	if err != nil || !ok {
+		t.Fatalf("ParseNote = %v, %v", ok, err)
+	}
+	if note.BookID != book.ID || !note.UpdatedAt.Equal(book.UpdatedAt) {
+		t.Errorf("ParseNote = book_id %d, updated_at %v, want %d, %v", note.BookID, note.UpdatedAt, book.ID, book.UpdatedAt)
+	}
+	if _, fields, err := note.Changes(book); err != nil || len(fields) != 0 {
+		t.Errorf("書き出したままのノートの変更 = %v, %v, want なし", fields, err)
+	}
+}
+
+// TestParseNoteNotBook は書籍のノートでない文書と、book_id・updated_at が正しくないノートを確認する
+func TestParseNoteNotBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantOK  bool
+		wantErr bool
+	}{
+		{"フロントマターなし", "# ただのメモ", false, false},
+		{"book_id なし", "---\ntitle: メモ\n---\n", false, false},
+		{"book_id が null", "---\nbook_id:\n---\n", false, false},
+		{"book_id が数値でない", "---\nbook_id: abc\n---\n", false, true},
+		{"book_id が0", "---\nbook_id: 0\n---\n", false, true},
+		{"updated_at が正しくない", "---\nbook_id: 1\nupdated_at: 昨日\n---\n", false, true},
+		{"updated_at なし", "---\nbook_id: 1\n---\n", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := ParseNote(tt.doc)
+			if ok != tt.wantOK || (err != nil) != tt.wantErr {
+				t.Errorf("ParseNote(%q) = %v, %v, want %v (エラー: %v)", tt.doc, ok, err, tt.wantOK, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestChanges はノートのフロントマターの編集が、違う項目だけの更新リクエストになることを確認する
+func TestChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string // 書き出したノートの行
+		to      string // 編集後の行（空なら行ごと消す）
+		want    []string
+		wantErr bool
+	}{
+		{"タイトル", `title: "Go: \"入門\" #1"`, `title: "Go: 入門 #2"`, []string{"title"}, false},
+		{"タイトルを空にする", `title: "Go: \"入門\" #1"`, "title:", nil, true},
+		{"出版社を追加", `publisher: ""`, "publisher: 技術出版社", []string{"publisher"}, false},
+		{"ISBNを null にすると空にする", `isbn: "978-4-12-345678-9"`, "isbn:", []string{"isbn"}, false},
+		{"項目ごと消すと変更しない", `isbn: "978-4-12-345678-9"`, "", nil, false},
+		{"状態", "status: reading", "status: completed", []string{"status"}, false},
+		{"正しくない状態", "status: reading", "status: finished", nil, true},
+		{"評価", "rating: 4", "rating: 5", []string{"rating"}, false},
+		{"評価を空にしても変更しない", "rating: 4", "rating:", nil, false},
+		{"正しくない評価", "rating: 4", "rating: 星5つ", nil, true},
+		{"タグを1つの文字列で書く", "tags:\n  - \"Go\"\n  - \"技術書\"", "tags: Go, 技術書, 入門", []string{"tags"}, false},
+		{"タグの順番を変える", "tags:\n  - \"Go\"\n  - \"技術書\"", "tags: [技術書, Go]", []string{"tags"}, false},
+		{"日時で書いても同じ日付なら変更しない", "start_read_date: 2024-01-05", "start_read_date: 2024-01-05T21:00", nil, false},
+		{"読書終了日", "end_read_date:", "end_read_date: 2024-02-01", []string{"end_read_date"}, false},
+		{"正しくない日付", "published_date: 2023-04-10", "published_date: 2023/04/10", nil, true},
+		{"購入日は読み戻さない", "purchase_date: 2024-01-01", "purchase_date: 2025-01-01", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := testBook()
+			doc := Render(book, nil)
+			if !strings.Contains(doc, tt.from+"\n") {
+				t.Fatalf("ノートに %q がありません\n%s", tt.from, doc)
+			}
+			to := tt.to
+			if to != "" {
+				to += "\n"
+			}
+			note, ok, err := ParseNote(strings.Replace(doc, tt.from+"\n", to, 1))
+			if err != nil || !ok {
+				t.Fatalf("ParseNote = %v, %v", ok, err)
+			}
+			_, fields, err := note.Changes(book)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Changes のエラー = %v, want エラー %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("Changes の項目 = %v, want %v", fields, tt.want)
+			}
+		})
+	}
+}
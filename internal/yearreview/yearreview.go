@@ -0,0 +1,275 @@
+// yearreviewパッケージ：1年間の読書の振り返り（Year in Books）を、そのまま開けるHTMLに変換するファイル
+// グラフはサーバー側でSVGとして作るため、外部のスクリプトやスタイルシートを読み込まずに表示・保存できる
+package yearreview
+
+import (
+	"fmt"           // 文字列フォーマット
+	"html/template" // HTMLの作成（値は自動でエスケープされる）
+	"io"            // 書き出し先
+	"math"          // 座標の丸め
+	"strings"       // 出力形式の名前の正規化
+	"time"          // 作成日時の表示
+
+	"book-manager/internal/currency" // 金額の表示
+	"book-manager/internal/model"    // 自作のデータ構造定義
+)
+
+// Format は年間の振り返りの出力形式を表す型
+type Format string
+
+// 対応している出力形式の定数定義
+const (
+	FormatHTML Format = "html" // グラフ付きのHTML（デフォルト）
+	FormatJSON Format = "json" // APIのJSONレスポンス
+)
+
+// ParseFormat は文字列を出力形式に変換する関数
+// 空文字の場合はHTMLを返す
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatHTML:
+		return FormatHTML, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("未対応の出力形式です: %s（html、json のいずれかを指定してください）", s)
+	}
+}
+
+// グラフの大きさ（SVGの座標の単位。表示時は幅に合わせて拡大・縮小される）
+const (
+	chartWidth   = 640 // グラフ全体の幅
+	chartHeight  = 220 // 縦棒グラフ全体の高さ
+	chartPadding = 28  // 上下の余白（値と月の名前を書く場所）
+	barGap       = 8   // 棒と棒の間隔
+	rowHeight    = 28  // 横棒グラフの1行の高さ
+	labelWidth   = 160 // 横棒グラフの項目名の幅
+)
+
+// bar はSVGに描く1本の棒（座標は計算済み）
+type bar struct {
+	X, Y, Width, Height float64 // 棒の位置と大きさ
+	LabelX, LabelY      float64 // 項目名の位置
+	ValueX, ValueY      float64 // 値の位置
+	Label, Value        string  // 項目名と値
+}
+
+// round は座標を小数第1位までに丸めるメソッド（SVGを読みやすく、小さくするため）
+func (b *bar) round() *bar {
+	for _, v := range []*float64{&b.X, &b.Y, &b.Width, &b.Height, &b.LabelX, &b.LabelY, &b.ValueX, &b.ValueY} {
+		*v = math.Round(*v*10) / 10
+	}
+	return b
+}
+
+// chart はSVGに描く棒グラフ
+type chart struct {
+	Width, Height int    // SVGの大きさ
+	BaseY         int    // 縦棒グラフの基準線の高さ
+	Bars          []*bar // 棒
+	Empty         bool   // 値がすべて0（棒の代わりにメッセージを表示する）
+}
+
+// monthlyChart は月ごとの値を縦棒グラフにする関数
+// 棒の高さは最大値を基準にした割合で決める
+func monthlyChart(points []*model.TimeSeriesPoint, format func(int) string) *chart {
+	c := &chart{Width: chartWidth, Height: chartHeight, BaseY: chartHeight - chartPadding, Empty: true}
+	if len(points) == 0 {
+		return c
+	}
+	maxValue := 0
+	for _, point := range points {
+		if point.Value > maxValue {
+			maxValue = point.Value
+		}
+	}
+	plotHeight := float64(chartHeight - 2*chartPadding)
+	slot := float64(chartWidth) / float64(len(points))
+	for i, point := range points {
+		height := 0.0
+		if maxValue > 0 {
+			height = plotHeight * float64(point.Value) / float64(maxValue)
+			c.Empty = false
+		}
+		x := slot*float64(i) + barGap/2
+		y := float64(c.BaseY) - height
+		c.Bars = append(c.Bars, (&bar{
+			X: x, Y: y, Width: slot - barGap, Height: height,
+			LabelX: x + (slot-barGap)/2, LabelY: float64(c.BaseY) + 18,
+			ValueX: x + (slot-barGap)/2, ValueY: y - 6,
+			Label: monthLabel(point.Period), Value: format(point.Value),
+		}).round())
+	}
+	return c
+}
+
+// authorChart はよく読んだ著者を横棒グラフにする関数
+func authorChart(authors []*model.AuthorCount) *chart {
+	c := &chart{Width: chartWidth, Height: rowHeight*len(authors) + barGap, Empty: len(authors) == 0}
+	maxBooks := 0
+	for _, author := range authors {
+		if author.Books > maxBooks {
+			maxBooks = author.Books
+		}
+	}
+	// 右端に冊数を書く余白を残す
+	plotWidth := float64(chartWidth - labelWidth - 48)
+	for i, author := range authors {
+		width := plotWidth * float64(author.Books) / float64(maxBooks)
+		y := float64(rowHeight*i + barGap)
+		name := author.Author
+		if name == "" {
+			name = "（著者未登録）"
+		}
+		c.Bars = append(c.Bars, (&bar{
+			X: labelWidth, Y: y, Width: width, Height: rowHeight - barGap,
+			LabelX: labelWidth - 8, LabelY: y + float64(rowHeight-barGap)/2 + 5,
+			ValueX: labelWidth + width + 6, ValueY: y + float64(rowHeight-barGap)/2 + 5,
+			Label: truncate(name, 14), Value: fmt.Sprintf("%d冊", author.Books),
+		}).round())
+	}
+	return c
+}
+
+// monthLabel は「2026-01」形式の期間を「1月」にする関数
+func monthLabel(period string) string {
+	t, err := time.Parse("2006-01", period)
+	if err != nil {
+		return period
+	}
+	return fmt.Sprintf("%d月", int(t.Month()))
+}
+
+// truncate は長い項目名をグラフに収まるように切り詰める関数（文字数で数える）
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// styledChart は縦棒グラフと棒の色のクラス名（テンプレートの部品に2つの値を渡すため）
+type styledChart struct {
+	Chart *chart
+	Class string
+}
+
+// page はHTMLテンプレートに渡すデータ
+type page struct {
+	*model.YearInReview
+	FinishedChart *chart // 月ごとの読み終えた冊数
+	SpendChart    *chart // 月ごとの購入金額
+	AuthorChart   *chart // よく読んだ著者
+}
+
+// WriteHTML は年間の振り返りをグラフ付きのHTMLで書き出す関数
+// スタイルとSVGをすべてHTMLに埋め込むため、このファイルだけで表示できる
+func WriteHTML(w io.Writer, review *model.YearInReview) error {
+	return htmlTemplate.Execute(w, &page{
+		YearInReview:  review,
+		FinishedChart: monthlyChart(review.FinishedPerMonth, func(v int) string { return fmt.Sprint(v) }),
+		SpendChart:    monthlyChart(review.SpendPerMonth, func(v int) string { return currency.Format(v, review.Currency) }),
+		AuthorChart:   authorChart(review.TopAuthors),
+	})
+}
+
+// htmlTemplate は年間の振り返りのHTMLテンプレート
+// 値が0の棒は描かず、値の文字も省略する（グラフが見にくくなるため）
+var htmlTemplate = template.Must(template.New("yearreview").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"datetime": func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	"money":    currency.Format,
+	"rating":   func(f *float64) string { return fmt.Sprintf("%.1f", *f) },
+	"stars":    func(n *int) string { return strings.Repeat("★", *n) + strings.Repeat("☆", 5-*n) },
+	"days":     func(n *int) int { return *n },
+	"styled":   func(c *chart, class string) *styledChart { return &styledChart{Chart: c, Class: class} },
+}).Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Year}}年の読書の振り返り</title>
+<style>
+  body { font-family: sans-serif; color: #222; max-width: 760px; margin: 2em auto; padding: 0 1em; }
+  h1 { font-size: 22pt; margin-bottom: 0.1em; }
+  h2 { font-size: 14pt; margin: 2em 0 0.6em; border-bottom: 2px solid #4a6fa5; }
+  .meta { color: #666; font-size: 9pt; }
+  .cards { display: flex; flex-wrap: wrap; gap: 0.8em; margin-top: 1.2em; }
+  .card { flex: 1 1 140px; background: #f3f6fb; border-radius: 6px; padding: 0.8em 1em; }
+  .card .value { font-size: 20pt; font-weight: bold; color: #4a6fa5; }
+  .card .label { font-size: 9pt; color: #555; }
+  svg { width: 100%; height: auto; }
+  svg text { font-size: 11px; fill: #333; }
+  svg .bar { fill: #4a6fa5; }
+  svg .bar.spend { fill: #d08c3c; }
+  svg .axis { stroke: #999; }
+  table { width: 100%; border-collapse: collapse; }
+  th, td { border-bottom: 1px solid #ddd; padding: 0.4em 0.5em; text-align: left; }
+  .stars { color: #d08c3c; white-space: nowrap; }
+  .empty { color: #777; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Year}}年の読書の振り返り</h1>
+<p class="meta">作成日時 {{datetime .GeneratedAt}}</p>
+
+<div class="cards">
+  <div class="card"><div class="value">{{.BooksFinished}}冊</div><div class="label">読み終えた本</div></div>
+  <div class="card"><div class="value">{{.PagesRead}}</div><div class="label">読んだページ数{{if .BooksWithoutPage}}（ページ数未登録の{{.BooksWithoutPage}}冊を除く）{{end}}</div></div>
+  <div class="card"><div class="value">{{if .AverageRating}}{{rating .AverageRating}}{{else}}-{{end}}</div><div class="label">平均評価</div></div>
+  <div class="card"><div class="value">{{money .TotalSpent .Currency}}</div><div class="label">購入金額（{{.BooksPurchased}}冊）</div></div>
+</div>
+
+<h2>月ごとの読み終えた本</h2>
+{{template "monthly" styled .FinishedChart ""}}
+
+<h2>月ごとの購入金額</h2>
+{{template "monthly" styled .SpendChart "spend"}}
+
+<h2>よく読んだ著者</h2>
+{{with .AuthorChart}}{{if .Empty}}<p class="empty">読み終えた本はありません</p>{{else}}
+<svg viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="よく読んだ著者">
+  {{range .Bars}}
+  <text x="{{.LabelX}}" y="{{.LabelY}}" text-anchor="end">{{.Label}}</text>
+  <rect class="bar" x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" rx="2"></rect>
+  <text x="{{.ValueX}}" y="{{.ValueY}}">{{.Value}}</text>
+  {{end}}
+</svg>
+{{end}}{{end}}
+
+<h2>評価の高かった本</h2>
+{{if .TopRated}}
+<table>
+  <thead><tr><th>評価</th><th>タイトル</th><th>著者</th><th>読み終えた日</th></tr></thead>
+  <tbody>
+  {{range .TopRated}}
+    <tr><td class="stars">{{stars .Rating}}</td><td>{{.Title}}</td><td>{{.Author}}</td><td>{{date .EndReadDate}}</td></tr>
+  {{end}}
+  </tbody>
+</table>
+{{else}}<p class="empty">評価を付けた本はありません</p>{{end}}
+
+<h2>読むのにかかった日数</h2>
+{{if .LongestRead}}
+<table>
+  <tbody>
+    <tr><th>最も長くかかった本</th><td>{{.LongestRead.Title}}（{{.LongestRead.Author}}）</td><td>{{days .LongestRead.Days}}日</td></tr>
+    <tr><th>最も早く読み終えた本</th><td>{{.FastestRead.Title}}（{{.FastestRead.Author}}）</td><td>{{days .FastestRead.Days}}日</td></tr>
+  </tbody>
+</table>
+{{else}}<p class="empty">読書開始日と読書終了日のある本はありません</p>{{end}}
+</body>
+</html>
+
+{{define "monthly"}}{{$class := .Class}}{{with .Chart}}{{if .Empty}}<p class="empty">この年のデータはありません</p>{{else}}
+<svg viewBox="0 0 {{.Width}} {{.Height}}" role="img">
+  <line class="axis" x1="0" y1="{{.BaseY}}" x2="{{.Width}}" y2="{{.BaseY}}"></line>
+  {{range .Bars}}
+  {{if .Height}}<rect class="bar {{$class}}" x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" rx="2"></rect>
+  <text x="{{.ValueX}}" y="{{.ValueY}}" text-anchor="middle">{{.Value}}</text>{{end}}
+  <text x="{{.LabelX}}" y="{{.LabelY}}" text-anchor="middle">{{.Label}}</text>
+  {{end}}
+</svg>
+{{end}}{{end}}{{end}}
+`))
@@ -379,11 +379,11 @@ class BookManager {
 
     // 書籍削除
     async deleteBook(id) {
-        if (!confirm('この書籍を削除しますか？')) return;
+        if (!confirm('この書籍を削除しますか？（ゴミ箱から元に戻せます）')) return;
         
         try {
             await this.apiCall(`/books/${id}`, { method: 'DELETE' });
-            this.showToast('書籍が削除されました', 'success');
+            this.showToast('書籍をゴミ箱に移しました', 'success');
             await this.loadBooks(this.currentPage);
             await this.loadStatistics();
         } catch (error) {